@@ -1,14 +1,19 @@
 package main
 
 import (
+	"github.com/f5/otel-arrow-adapter/collector/connector/spanmetricsconnector"
 	"github.com/f5/otel-arrow-adapter/collector/gen/exporter/otlpexporter"
+	"github.com/f5/otel-arrow-adapter/collector/gen/extension/arrowzextension"
 	"github.com/f5/otel-arrow-adapter/collector/gen/receiver/otlpreceiver"
+	"github.com/f5/otel-arrow-adapter/collector/processor/arrowbatchprocessor"
+	"github.com/f5/otel-arrow-adapter/collector/processor/arrowverifyprocessor"
 	"github.com/f5/otel-arrow-adapter/collector/processor/experimentprocessor"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/basicauthextension"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/headerssetterextension"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereceiver"
+	"go.opentelemetry.io/collector/connector"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/loggingexporter"
 	"go.opentelemetry.io/collector/exporter/otlphttpexporter"
@@ -31,6 +36,7 @@ func components() (otelcol.Factories, error) {
 		zpagesextension.NewFactory(),
 		headerssetterextension.NewFactory(),
 		basicauthextension.NewFactory(),
+		arrowzextension.NewFactory(),
 	)
 	if err != nil {
 		return otelcol.Factories{}, err
@@ -58,6 +64,15 @@ func components() (otelcol.Factories, error) {
 		batchprocessor.NewFactory(),
 		memorylimiterprocessor.NewFactory(),
 		experimentprocessor.NewFactory(),
+		arrowverifyprocessor.NewFactory(),
+		arrowbatchprocessor.NewFactory(),
+	)
+	if err != nil {
+		return otelcol.Factories{}, err
+	}
+
+	factories.Connectors, err = connector.MakeFactoryMap(
+		spanmetricsconnector.NewFactory(),
 	)
 	if err != nil {
 		return otelcol.Factories{}, err
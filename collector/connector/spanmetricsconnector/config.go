@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsconnector // import "github.com/f5/otel-arrow-adapter/collector/connector/spanmetricsconnector"
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+var (
+	errInvalidFlushInterval = errors.New("the metrics flush interval must be positive")
+	errInvalidBucket        = errors.New("the histogram buckets must be positive")
+	errInvalidMaxSeries     = errors.New("the maximum number of series must not be negative")
+	errInvalidExpiration    = errors.New("the metrics expiration must not be negative")
+)
+
+// Config defines the configuration for the span metrics connector.
+type Config struct {
+	// ServiceKey is the resource attribute identifying the service of the
+	// spans. Defaults to "service.name".
+	ServiceKey string `mapstructure:"service_key"`
+
+	// Buckets are the upper bounds of the duration histogram buckets.
+	// Defaults to the buckets of the contrib spanmetrics connector.
+	Buckets []time.Duration `mapstructure:"buckets"`
+
+	// MetricsFlushInterval is the interval at which the cumulative metrics
+	// are sent to the metrics pipeline. Defaults to 15s.
+	MetricsFlushInterval time.Duration `mapstructure:"metrics_flush_interval"`
+
+	// MaxSeries is the maximum number of series (one per service, span name,
+	// span kind and status code), the spans of the new groups beyond this
+	// limit are aggregated in an overflow series. 0 means unlimited.
+	// Defaults to 1000.
+	MaxSeries int `mapstructure:"max_series"`
+
+	// MetricsExpiration is the duration after which the series without spans
+	// are removed. 0 means never. Defaults to 0.
+	MetricsExpiration time.Duration `mapstructure:"metrics_expiration"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the connector configuration is valid.
+func (c *Config) Validate() error {
+	if c.MetricsFlushInterval <= 0 {
+		return fmt.Errorf("invalid metrics flush interval %v: %w", c.MetricsFlushInterval, errInvalidFlushInterval)
+	}
+	if c.MaxSeries < 0 {
+		return fmt.Errorf("invalid max series %d: %w", c.MaxSeries, errInvalidMaxSeries)
+	}
+	if c.MetricsExpiration < 0 {
+		return fmt.Errorf("invalid metrics expiration %v: %w", c.MetricsExpiration, errInvalidExpiration)
+	}
+	for _, bucket := range c.Buckets {
+		if bucket <= 0 {
+			return fmt.Errorf("invalid bucket %v: %w", bucket, errInvalidBucket)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsconnector // import "github.com/f5/otel-arrow-adapter/collector/connector/spanmetricsconnector"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/f5/otel-arrow-adapter/pkg/otel/spanmetrics"
+)
+
+var _ connector.Traces = (*spanMetricsConnector)(nil)
+
+type spanMetricsConnector struct {
+	logger *zap.Logger
+	config *Config
+	next   consumer.Metrics
+
+	// lock protects the aggregator.
+	lock       sync.Mutex
+	aggregator *spanmetrics.Aggregator
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newConnector(settings component.TelemetrySettings, config component.Config, next consumer.Metrics) *spanMetricsConnector {
+	cfg := config.(*Config)
+
+	options := []spanmetrics.Option{
+		spanmetrics.WithServiceKey(cfg.ServiceKey),
+		spanmetrics.WithMaxSeries(cfg.MaxSeries),
+		spanmetrics.WithExpiration(cfg.MetricsExpiration),
+	}
+	if len(cfg.Buckets) > 0 {
+		options = append(options, spanmetrics.WithBuckets(cfg.Buckets...))
+	}
+
+	return &spanMetricsConnector{
+		logger:     settings.Logger,
+		config:     cfg,
+		next:       next,
+		aggregator: spanmetrics.New(options...),
+		done:       make(chan struct{}),
+	}
+}
+
+func (c *spanMetricsConnector) Start(context.Context, component.Host) error {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		ticker := time.NewTicker(c.config.MetricsFlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.flush(context.Background()); err != nil {
+					c.logger.Error("failed to send the span metrics", zap.Error(err))
+				}
+			case <-c.done:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// ConsumeTraces aggregates the spans of the traces. The connector receives
+// pdata traces, converting them into Arrow records to aggregate their columns
+// is much slower than walking them (see spanmetrics.BenchmarkAggregator).
+func (c *spanMetricsConnector) ConsumeTraces(_ context.Context, td ptrace.Traces) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.aggregator.AddTraces(td)
+	return nil
+}
+
+// flush sends the cumulative metrics aggregated so far to the next consumer.
+func (c *spanMetricsConnector) flush(ctx context.Context) error {
+	c.lock.Lock()
+	metrics := c.aggregator.Metrics(time.Now())
+	c.lock.Unlock()
+
+	if metrics.ResourceMetrics().Len() == 0 {
+		return nil
+	}
+	return c.next.ConsumeMetrics(ctx, metrics)
+}
+
+func (c *spanMetricsConnector) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+func (c *spanMetricsConnector) Shutdown(context.Context) error {
+	select {
+	case <-c.done:
+		return nil
+	default:
+	}
+	close(c.done)
+	c.wg.Wait()
+	return nil
+}
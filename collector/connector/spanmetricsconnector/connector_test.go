@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsconnector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/connector/connectortest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/spanmetrics"
+)
+
+func makeTraces() ptrace.Traces {
+	ent := datagen.NewTestEntropy(12345)
+	traces := datagen.NewTracesGenerator(ent, ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes()).Generate(10, time.Second)
+	traces.ResourceSpans().At(0).Resource().Attributes().PutStr("service.name", "frontend")
+	return traces
+}
+
+// calls returns the total number of calls of the `calls` metrics.
+func calls(metrics pmetric.Metrics) int64 {
+	total := int64(0)
+	for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
+		rm := metrics.ResourceMetrics().At(i)
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			ms := rm.ScopeMetrics().At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				if ms.At(k).Name() != spanmetrics.CallsMetric {
+					continue
+				}
+				dps := ms.At(k).Sum().DataPoints()
+				for l := 0; l < dps.Len(); l++ {
+					total += dps.At(l).IntValue()
+				}
+			}
+		}
+	}
+	return total
+}
+
+func TestConnector(t *testing.T) {
+	t.Parallel()
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.MetricsFlushInterval = 10 * time.Millisecond
+	sink := new(consumertest.MetricsSink)
+
+	conn, err := factory.CreateTracesToMetrics(context.Background(), connectortest.NewNopCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, conn.Start(context.Background(), componenttest.NewNopHost()))
+
+	traces := makeTraces()
+	require.NoError(t, conn.ConsumeTraces(context.Background(), traces))
+	require.NoError(t, conn.ConsumeTraces(context.Background(), traces))
+
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) > 0 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, conn.Shutdown(context.Background()))
+
+	// The metrics are cumulative.
+	metrics := sink.AllMetrics()[len(sink.AllMetrics())-1]
+	assert.Equal(t, int64(2*traces.SpanCount()), calls(metrics))
+
+	service, ok := metrics.ResourceMetrics().At(0).Resource().Attributes().Get("service.name")
+	require.True(t, ok)
+	assert.Equal(t, "frontend", service.Str())
+}
+
+func TestConnectorNoSpans(t *testing.T) {
+	t.Parallel()
+
+	sink := new(consumertest.MetricsSink)
+	conn := newConnector(componenttest.NewNopTelemetrySettings(), createDefaultConfig(), sink)
+	require.NoError(t, conn.flush(context.Background()))
+	require.NoError(t, conn.Shutdown(context.Background()))
+	assert.Empty(t, sink.AllMetrics())
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Buckets = []time.Duration{time.Millisecond, 0}
+	require.True(t, errors.Is(cfg.Validate(), errInvalidBucket))
+
+	cfg.Buckets = nil
+	cfg.MetricsFlushInterval = 0
+	require.True(t, errors.Is(cfg.Validate(), errInvalidFlushInterval))
+
+	cfg.MetricsFlushInterval = time.Second
+	cfg.MaxSeries = -1
+	require.True(t, errors.Is(cfg.Validate(), errInvalidMaxSeries))
+
+	cfg.MaxSeries = 0
+	cfg.MetricsExpiration = -time.Second
+	require.True(t, errors.Is(cfg.Validate(), errInvalidExpiration))
+}
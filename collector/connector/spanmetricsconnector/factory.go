@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsconnector // import "github.com/f5/otel-arrow-adapter/collector/connector/spanmetricsconnector"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/consumer"
+
+	"github.com/f5/otel-arrow-adapter/pkg/otel/spanmetrics"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "spanmetrics"
+	// The stability level of the connector.
+	stability = component.StabilityLevelDevelopment
+)
+
+// NewFactory creates a factory for the span metrics connector.
+func NewFactory() connector.Factory {
+	return connector.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		connector.WithTracesToMetrics(createTracesToMetricsConnector, stability),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		ServiceKey:           spanmetrics.DefaultServiceKey,
+		MetricsFlushInterval: 15 * time.Second,
+		MaxSeries:            1000,
+	}
+}
+
+func createTracesToMetricsConnector(_ context.Context, params connector.CreateSettings, cfg component.Config, nextConsumer consumer.Metrics) (connector.Traces, error) {
+	return newConnector(params.TelemetrySettings, cfg, nextConsumer), nil
+}
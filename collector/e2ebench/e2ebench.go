@@ -0,0 +1,262 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package e2ebench benchmarks the OTLP exporter and receiver components
+// end to end: batches are exported by the real exporter (OTel Arrow
+// streams or plain OTLP gRPC) to the real receiver over an in-memory gRPC
+// connection (bufconn), so the results include the gRPC framing, the
+// headers, the acknowledgments and the concurrency of the streams.
+package e2ebench // import "github.com/f5/otel-arrow-adapter/collector/e2ebench"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.uber.org/multierr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/f5/otel-arrow-adapter/collector/gen/exporter/otlpexporter"
+	"github.com/f5/otel-arrow-adapter/collector/gen/receiver/otlpreceiver"
+	"github.com/f5/otel-arrow-adapter/collector/internal/testlistener"
+	"github.com/f5/otel-arrow-adapter/pkg/benchmark/stats"
+	"github.com/f5/otel-arrow-adapter/pkg/config"
+)
+
+const (
+	// bufferSize is the size of the in-memory connection buffers.
+	bufferSize = 4 << 20
+	// maxRecvMsgSizeMiB is the maximum size of the messages received, large
+	// enough for the OTLP batches of the benchmarks.
+	maxRecvMsgSizeMiB = 256
+)
+
+var errNoBatches = errors.New("no batches to export")
+
+// Config configures a benchmark run.
+type Config struct {
+	// Name identifies the run in the report.
+	Name string
+	// Arrow enables OTel Arrow in the exporter, plain OTLP gRPC is used
+	// otherwise.
+	Arrow bool
+	// NumStreams is the number of Arrow streams of the exporter.
+	NumStreams int
+	// Concurrency is the number of goroutines exporting batches.
+	Concurrency int
+	// Iterations is the number of times the batches are exported.
+	Iterations int
+	// Compression is the gRPC compression of the exporter.
+	Compression configcompression.CompressionType
+	// PayloadCompression is the Arrow IPC compression codec of the
+	// exporter (the default of the exporter if empty).
+	PayloadCompression config.IPCCompression
+	// Headers are sent with each request (or each stream with Arrow).
+	Headers map[string]string
+}
+
+// Result contains the measurements of a benchmark run. The first batch is
+// exported before the measurements, to establish the connection and the
+// streams.
+type Result struct {
+	Name     string
+	Batches  int
+	Items    int
+	Duration time.Duration
+	// The latencies between the export of a batch and its acknowledgment
+	// by the receiver.
+	LatencyP50 time.Duration
+	LatencyP99 time.Duration
+	// CPU is the user and system CPU time of the process, i.e. of both the
+	// exporter and the receiver.
+	CPU time.Duration
+	// Allocs and AllocBytes are the heap allocations of the process.
+	Allocs     uint64
+	AllocBytes uint64
+}
+
+// ItemsPerSecond returns the throughput of the run.
+func (r *Result) ItemsPerSecond() float64 {
+	return float64(r.Items) / r.Duration.Seconds()
+}
+
+// pipeline is an exporter sending to a receiver.
+type pipeline struct {
+	exporter component.Component
+	receiver component.Component
+	// export exports the batch i.
+	export func(ctx context.Context, i int) error
+}
+
+// newPipeline creates the exporter and the receiver of a signal, the
+// receiver counts the items received.
+type newPipeline func(ctx context.Context, expCfg *otlpexporter.Config, rcvCfg *otlpreceiver.Config, received *atomic.Int64) (*pipeline, error)
+
+// configs returns the configurations of an exporter and a receiver
+// connected by an in-memory listener (passed to the receiver when it is
+// started, see testlistener).
+func configs(cfg Config, listener *bufconn.Listener) (*otlpexporter.Config, *otlpreceiver.Config) {
+	rcvCfg := otlpreceiver.NewFactory().CreateDefaultConfig().(*otlpreceiver.Config)
+	rcvCfg.HTTP = nil
+	rcvCfg.GRPC.NetAddr.Endpoint = "bufconn"
+	rcvCfg.GRPC.MaxRecvMsgSizeMiB = maxRecvMsgSizeMiB
+
+	expCfg := otlpexporter.NewFactory().CreateDefaultConfig().(*otlpexporter.Config)
+	expCfg.Endpoint = "bufconn"
+	expCfg.TLSSetting.Insecure = true
+	expCfg.WaitForReady = true
+	expCfg.Compression = cfg.Compression
+	for k, v := range cfg.Headers {
+		expCfg.Headers[k] = configopaque.String(v)
+	}
+	// The batches are exported synchronously, without retries, so that
+	// the latencies are the ones of the exporter.
+	expCfg.QueueSettings.Enabled = false
+	expCfg.RetrySettings.Enabled = false
+	expCfg.TimeoutSettings.Timeout = time.Minute
+	expCfg.Arrow.Disabled = !cfg.Arrow
+	expCfg.Arrow.NumStreams = cfg.NumStreams
+	if cfg.PayloadCompression != "" {
+		expCfg.Arrow.PayloadCompression = cfg.PayloadCompression
+	}
+	expCfg.UserDialOptions = []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+	}
+	return expCfg, rcvCfg
+}
+
+// run exports the batches, items[i] is the number of items of the batch i.
+func run(ctx context.Context, cfg Config, items []int, create newPipeline) (_ *Result, err error) {
+	if len(items) == 0 {
+		return nil, errNoBatches
+	}
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+	if cfg.NumStreams < 1 {
+		cfg.NumStreams = 1
+	}
+	if cfg.Iterations < 1 {
+		cfg.Iterations = 1
+	}
+
+	listener := bufconn.Listen(bufferSize)
+	expCfg, rcvCfg := configs(cfg, listener)
+	var received atomic.Int64
+	p, err := create(ctx, expCfg, rcvCfg, &received)
+	if err != nil {
+		return nil, err
+	}
+
+	host := componenttest.NewNopHost()
+	if err := p.receiver.Start(testlistener.NewContext(ctx, listener), host); err != nil {
+		return nil, err
+	}
+	defer func() { err = multierr.Append(err, p.receiver.Shutdown(ctx)) }()
+	if err := p.exporter.Start(ctx, host); err != nil {
+		return nil, err
+	}
+	exporterStarted := true
+	defer func() {
+		if exporterStarted {
+			err = multierr.Append(err, p.exporter.Shutdown(ctx))
+		}
+	}()
+
+	// Warm up.
+	if err := p.export(ctx, 0); err != nil {
+		return nil, fmt.Errorf("warm up: %w", err)
+	}
+	expected := int64(items[0])
+
+	total := cfg.Iterations * len(items)
+	latencies := make([][]time.Duration, cfg.Concurrency)
+	errs := make([]error, cfg.Concurrency)
+	var next atomic.Int64
+	var wg sync.WaitGroup
+
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	cpuBefore := cpuTime()
+	start := time.Now()
+
+	for w := 0; w < cfg.Concurrency; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for {
+				n := int(next.Add(1) - 1)
+				if n >= total {
+					return
+				}
+				exportStart := time.Now()
+				if err := p.export(ctx, n%len(items)); err != nil {
+					errs[w] = err
+					return
+				}
+				latencies[w] = append(latencies[w], time.Since(exportStart))
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	duration := time.Since(start)
+	cpu := cpuTime() - cpuBefore
+	runtime.ReadMemStats(&after)
+
+	exporterStarted = false
+	if err := multierr.Combine(append(errs, p.exporter.Shutdown(ctx))...); err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Name:       cfg.Name,
+		Batches:    total,
+		Duration:   duration,
+		CPU:        cpu,
+		Allocs:     after.Mallocs - before.Mallocs,
+		AllocBytes: after.TotalAlloc - before.TotalAlloc,
+	}
+	latency := stats.NewMetric()
+	for n := 0; n < total; n++ {
+		result.Items += items[n%len(items)]
+	}
+	for _, ls := range latencies {
+		for _, l := range ls {
+			latency.Record(l.Seconds())
+		}
+	}
+	summary := latency.ComputeSummary()
+	result.LatencyP50 = time.Duration(summary.P50 * float64(time.Second))
+	result.LatencyP99 = time.Duration(summary.P99 * float64(time.Second))
+
+	expected += int64(result.Items)
+	if actual := received.Load(); actual != expected {
+		return nil, fmt.Errorf("%d items received, %d exported", actual, expected)
+	}
+	return result, nil
+}
@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2ebench
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/f5/otel-arrow-adapter/pkg/config"
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+)
+
+func testConfigs() []Config {
+	return []Config{
+		{Name: "OTLP", Concurrency: 2, Iterations: 2, Compression: configcompression.Gzip},
+		{Name: "OTel Arrow", Arrow: true, NumStreams: 2, Concurrency: 2, Iterations: 2, Compression: configcompression.Zstd, Headers: map[string]string{"k": "v"}},
+		{Name: "OTel Arrow LZ4", Arrow: true, Concurrency: 2, Iterations: 2, PayloadCompression: config.IPCCompressionLZ4Frame},
+	}
+}
+
+func TestRunTraces(t *testing.T) {
+	ent := datagen.NewTestEntropy(12345)
+	gen := datagen.NewTracesGenerator(ent, ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes())
+	batches := make([]ptrace.Traces, 5)
+	spans := 0
+	for i := range batches {
+		batches[i] = gen.Generate(20, time.Second)
+		spans += batches[i].SpanCount()
+	}
+
+	var results []*Result
+	for _, cfg := range testConfigs() {
+		result, err := RunTraces(context.Background(), cfg, batches)
+		require.NoError(t, err, cfg.Name)
+		assert.Equal(t, 2*len(batches), result.Batches)
+		assert.Equal(t, 2*spans, result.Items)
+		assert.Positive(t, result.LatencyP50)
+		assert.GreaterOrEqual(t, result.LatencyP99, result.LatencyP50)
+		results = append(results, result)
+	}
+
+	var buf bytes.Buffer
+	WriteResults(&buf, results)
+	assert.Contains(t, buf.String(), "OTel Arrow")
+	assert.Contains(t, buf.String(), "Ack latency p99")
+}
+
+func TestRunLogsAndMetrics(t *testing.T) {
+	ent := datagen.NewTestEntropy(12345)
+	logsGen := datagen.NewLogsGenerator(ent, ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes())
+	metricsGen := datagen.NewMetricsGenerator(ent, ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes())
+	logs := []plog.Logs{logsGen.Generate(10, time.Second), logsGen.Generate(10, time.Second)}
+	metrics := []pmetric.Metrics{metricsGen.GenerateAllKindOfMetrics(10, time.Second), metricsGen.GenerateAllKindOfMetrics(10, time.Second)}
+
+	for _, cfg := range testConfigs() {
+		result, err := RunLogs(context.Background(), cfg, logs)
+		require.NoError(t, err, cfg.Name)
+		assert.Equal(t, 2*(logs[0].LogRecordCount()+logs[1].LogRecordCount()), result.Items)
+
+		result, err = RunMetrics(context.Background(), cfg, metrics)
+		require.NoError(t, err, cfg.Name)
+		assert.Equal(t, 2*(metrics[0].DataPointCount()+metrics[1].DataPointCount()), result.Items)
+	}
+}
+
+func TestRunNoBatches(t *testing.T) {
+	_, err := RunTraces(context.Background(), Config{}, nil)
+	assert.ErrorIs(t, err, errNoBatches)
+}
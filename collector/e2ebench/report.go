@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2ebench // import "github.com/f5/otel-arrow-adapter/collector/e2ebench"
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// WriteResults writes the results side by side, one column per run, the
+// per batch values are averaged over the batches of the run.
+func WriteResults(w io.Writer, results []*Result) {
+	header := []string{"Metric"}
+	for _, r := range results {
+		header = append(header, r.Name)
+	}
+	rows := []struct {
+		name  string
+		value func(r *Result) string
+	}{
+		{"Items/s", func(r *Result) string { return fmt.Sprintf("%.0f", r.ItemsPerSecond()) }},
+		{"Batches", func(r *Result) string { return fmt.Sprintf("%d", r.Batches) }},
+		{"Items", func(r *Result) string { return fmt.Sprintf("%d", r.Items) }},
+		{"Duration", func(r *Result) string { return r.Duration.Round(time.Millisecond).String() }},
+		{"Ack latency p50", func(r *Result) string { return formatLatency(r.LatencyP50) }},
+		{"Ack latency p99", func(r *Result) string { return formatLatency(r.LatencyP99) }},
+		{"CPU/batch", func(r *Result) string { return formatLatency(r.CPU / time.Duration(r.Batches)) }},
+		{"Allocs/batch", func(r *Result) string { return fmt.Sprintf("%d", r.Allocs/uint64(r.Batches)) }},
+		{"Alloc bytes/batch", func(r *Result) string { return fmt.Sprintf("%d", r.AllocBytes/uint64(r.Batches)) }},
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	alignment := []int{tablewriter.ALIGN_LEFT}
+	for range results {
+		alignment = append(alignment, tablewriter.ALIGN_RIGHT)
+	}
+	table.SetColumnAlignment(alignment)
+	for _, row := range rows {
+		line := []string{row.name}
+		for _, r := range results {
+			line = append(line, row.value(r))
+		}
+		table.Append(line)
+	}
+	table.Render()
+}
+
+func formatLatency(d time.Duration) string {
+	return fmt.Sprintf("%.3fms", d.Seconds()*1000)
+}
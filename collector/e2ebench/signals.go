@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2ebench // import "github.com/f5/otel-arrow-adapter/collector/e2ebench"
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/f5/otel-arrow-adapter/collector/gen/exporter/otlpexporter"
+	"github.com/f5/otel-arrow-adapter/collector/gen/receiver/otlpreceiver"
+)
+
+// RunTraces exports the batches of traces, the items are the spans.
+func RunTraces(ctx context.Context, cfg Config, batches []ptrace.Traces) (*Result, error) {
+	items := make([]int, len(batches))
+	for i, td := range batches {
+		items[i] = td.SpanCount()
+	}
+	return run(ctx, cfg, items, func(ctx context.Context, expCfg *otlpexporter.Config, rcvCfg *otlpreceiver.Config, received *atomic.Int64) (*pipeline, error) {
+		sink, err := consumer.NewTraces(func(_ context.Context, td ptrace.Traces) error {
+			received.Add(int64(td.SpanCount()))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		rcv, err := otlpreceiver.NewFactory().CreateTracesReceiver(ctx, receivertest.NewNopCreateSettings(), rcvCfg, sink)
+		if err != nil {
+			return nil, err
+		}
+		exp, err := otlpexporter.NewFactory().CreateTracesExporter(ctx, exportertest.NewNopCreateSettings(), expCfg)
+		if err != nil {
+			return nil, err
+		}
+		return &pipeline{
+			exporter: exp,
+			receiver: rcv,
+			export: func(ctx context.Context, i int) error {
+				return exp.ConsumeTraces(ctx, batches[i])
+			},
+		}, nil
+	})
+}
+
+// RunLogs exports the batches of logs, the items are the log records.
+func RunLogs(ctx context.Context, cfg Config, batches []plog.Logs) (*Result, error) {
+	items := make([]int, len(batches))
+	for i, ld := range batches {
+		items[i] = ld.LogRecordCount()
+	}
+	return run(ctx, cfg, items, func(ctx context.Context, expCfg *otlpexporter.Config, rcvCfg *otlpreceiver.Config, received *atomic.Int64) (*pipeline, error) {
+		sink, err := consumer.NewLogs(func(_ context.Context, ld plog.Logs) error {
+			received.Add(int64(ld.LogRecordCount()))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		rcv, err := otlpreceiver.NewFactory().CreateLogsReceiver(ctx, receivertest.NewNopCreateSettings(), rcvCfg, sink)
+		if err != nil {
+			return nil, err
+		}
+		exp, err := otlpexporter.NewFactory().CreateLogsExporter(ctx, exportertest.NewNopCreateSettings(), expCfg)
+		if err != nil {
+			return nil, err
+		}
+		return &pipeline{
+			exporter: exp,
+			receiver: rcv,
+			export: func(ctx context.Context, i int) error {
+				return exp.ConsumeLogs(ctx, batches[i])
+			},
+		}, nil
+	})
+}
+
+// RunMetrics exports the batches of metrics, the items are the data points.
+func RunMetrics(ctx context.Context, cfg Config, batches []pmetric.Metrics) (*Result, error) {
+	items := make([]int, len(batches))
+	for i, md := range batches {
+		items[i] = md.DataPointCount()
+	}
+	return run(ctx, cfg, items, func(ctx context.Context, expCfg *otlpexporter.Config, rcvCfg *otlpreceiver.Config, received *atomic.Int64) (*pipeline, error) {
+		sink, err := consumer.NewMetrics(func(_ context.Context, md pmetric.Metrics) error {
+			received.Add(int64(md.DataPointCount()))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		rcv, err := otlpreceiver.NewFactory().CreateMetricsReceiver(ctx, receivertest.NewNopCreateSettings(), rcvCfg, sink)
+		if err != nil {
+			return nil, err
+		}
+		exp, err := otlpexporter.NewFactory().CreateMetricsExporter(ctx, exportertest.NewNopCreateSettings(), expCfg)
+		if err != nil {
+			return nil, err
+		}
+		return &pipeline{
+			exporter: exp,
+			receiver: rcv,
+			export: func(ctx context.Context, i int) error {
+				return exp.ConsumeMetrics(ctx, batches[i])
+			},
+		}, nil
+	})
+}
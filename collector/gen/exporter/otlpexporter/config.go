@@ -10,6 +10,8 @@ import (
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"google.golang.org/grpc"
+
+	"github.com/f5/otel-arrow-adapter/pkg/config"
 )
 
 // Config defines configuration for OTLP exporter.
@@ -36,6 +38,44 @@ type ArrowSettings struct {
 	NumStreams         int  `mapstructure:"num_streams"`
 	DisableDowngrade   bool `mapstructure:"disable_downgrade"`
 	EnableMixedSignals bool `mapstructure:"enable_mixed_signals"`
+
+	// ResetAfterBatches and ResetAfterDictionaryBytes configure the
+	// proactive reset of the Arrow sub-streams, which drops the
+	// dictionaries accumulated by the producer and the receiver.
+	// Zero disables the corresponding policy.
+	ResetAfterBatches         uint64 `mapstructure:"reset_after_batches"`
+	ResetAfterDictionaryBytes uint64 `mapstructure:"reset_after_dictionary_bytes"`
+
+	// PayloadCompression is the compression codec of the Arrow IPC
+	// messages: "zstd" (the default), "lz4_frame" or "none".  The
+	// receiver accepts any of them.  This is applied in addition
+	// to the gRPC compression.
+	PayloadCompression config.IPCCompression `mapstructure:"payload_compression"`
+
+	// CompressionDictionarySamples enables the compression of the
+	// Arrow payloads with a zstd dictionary trained from the first
+	// payloads of each stream and shipped once to the receiver,
+	// which is more effective than payload_compression for small
+	// batches.  It replaces payload_compression when set.  Zero
+	// disables the dictionary.  CompressionDictionarySize is the
+	// maximum size of the dictionary (64KiB by default) and
+	// CompressionDictionaryRotateAfterBatches the number of batches
+	// after which a new dictionary is trained (zero means never).
+	CompressionDictionarySamples            int    `mapstructure:"compression_dictionary_samples"`
+	CompressionDictionarySize               int    `mapstructure:"compression_dictionary_size"`
+	CompressionDictionaryRotateAfterBatches uint64 `mapstructure:"compression_dictionary_rotate_after_batches"`
+
+	// AdaptiveSortingPeriod enables the selection of the sort order
+	// of the spans, events, links and attributes of the traces every
+	// given number of batches, from the compressed size of a sample
+	// of up to AdaptiveSortingSampleSize spans (1000 by default)
+	// encoded with the candidate orders.  A candidate replaces the
+	// current order once it has been smaller by at least
+	// AdaptiveSortingMinGain (relative) on two consecutive
+	// evaluations.  Zero disables the adaptive sorting.
+	AdaptiveSortingPeriod     uint64  `mapstructure:"adaptive_sorting_period"`
+	AdaptiveSortingSampleSize int     `mapstructure:"adaptive_sorting_sample_size"`
+	AdaptiveSortingMinGain    float64 `mapstructure:"adaptive_sorting_min_gain"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -57,6 +97,21 @@ func (cfg *ArrowSettings) Validate() error {
 	if cfg.NumStreams < 1 {
 		return fmt.Errorf("stream count must be > 0: %d", cfg.NumStreams)
 	}
+	if err := cfg.PayloadCompression.Validate(); err != nil {
+		return err
+	}
+	if cfg.CompressionDictionarySamples < 0 {
+		return fmt.Errorf("compression dictionary samples must be >= 0: %d", cfg.CompressionDictionarySamples)
+	}
+	if cfg.CompressionDictionarySize < 0 {
+		return fmt.Errorf("compression dictionary size must be >= 0: %d", cfg.CompressionDictionarySize)
+	}
+	if cfg.AdaptiveSortingSampleSize < 0 {
+		return fmt.Errorf("adaptive sorting sample size must be >= 0: %d", cfg.AdaptiveSortingSampleSize)
+	}
+	if cfg.AdaptiveSortingMinGain < 0 || cfg.AdaptiveSortingMinGain >= 1 {
+		return fmt.Errorf("adaptive sorting min gain must be in [0, 1): %v", cfg.AdaptiveSortingMinGain)
+	}
 
 	return nil
 }
@@ -20,6 +20,8 @@ import (
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/f5/otel-arrow-adapter/pkg/config"
 )
 
 func TestUnmarshalDefaultConfig(t *testing.T) {
@@ -79,6 +81,14 @@ func TestUnmarshalConfig(t *testing.T) {
 			Arrow: ArrowSettings{
 				NumStreams:         2,
 				EnableMixedSignals: true,
+				ResetAfterBatches:  1000,
+				PayloadCompression: config.IPCCompressionLZ4Frame,
+
+				CompressionDictionarySamples:            100,
+				CompressionDictionaryRotateAfterBatches: 10000,
+
+				AdaptiveSortingPeriod:  100,
+				AdaptiveSortingMinGain: 0.05,
 			},
 		}, cfg)
 }
@@ -96,6 +106,18 @@ func TestArrowSettingsValidate(t *testing.T) {
 	require.Contains(t, settings(true, 0).Validate().Error(), "stream count must be")
 	require.Error(t, settings(false, -1).Validate())
 	require.Error(t, settings(true, math.MinInt).Validate())
+
+	invalid := settings(true, 1)
+	invalid.PayloadCompression = "brotli"
+	require.Error(t, invalid.Validate())
+
+	invalid = settings(true, 1)
+	invalid.CompressionDictionarySamples = -1
+	require.Error(t, invalid.Validate())
+
+	invalid = settings(true, 1)
+	invalid.AdaptiveSortingMinGain = 1
+	require.Error(t, invalid.Validate())
 }
 
 func TestDefaultSettingsValid(t *testing.T) {
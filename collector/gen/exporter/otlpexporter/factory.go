@@ -8,6 +8,7 @@ import (
 	"runtime"
 
 	arrowpb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	"github.com/f5/otel-arrow-adapter/pkg/config"
 	"google.golang.org/grpc"
 
 	"go.opentelemetry.io/collector/component"
@@ -18,6 +19,7 @@ import (
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"github.com/f5/otel-arrow-adapter/collector/gen/exporter/otlpexporter/internal/arrow"
+	"github.com/f5/otel-arrow-adapter/collector/gen/internal/netstats"
 )
 
 const (
@@ -49,7 +51,10 @@ func createDefaultConfig() component.Config {
 			WriteBufferSize: 512 * 1024,
 		},
 		Arrow: ArrowSettings{
-			NumStreams: runtime.NumCPU(),
+			NumStreams:         runtime.NumCPU(),
+			PayloadCompression: config.IPCCompressionZstd,
+
+			AdaptiveSortingMinGain: config.DefaultAdaptiveSortingMinGain,
 		},
 	}
 }
@@ -81,10 +86,14 @@ func createTracesExporter(
 	if err != nil {
 		return nil, err
 	}
-	return exporterhelper.NewTracesExporter(ctx, oce.settings, oce.config,
+	exp, err := exporterhelper.NewTracesExporter(ctx, oce.settings, oce.config,
 		oce.pushTraces,
 		oce.helperOptions()...,
 	)
+	if err != nil || oce.netStats == nil {
+		return exp, err
+	}
+	return tracesExporter{Traces: exp, wireStats: wireStats{oce.netStats}}, nil
 }
 
 func createArrowMetricsStream(cfg *Config, conn *grpc.ClientConn) func(ctx context.Context, opts ...grpc.CallOption) (arrow.AnyStreamClient, error) {
@@ -103,10 +112,14 @@ func createMetricsExporter(
 	if err != nil {
 		return nil, err
 	}
-	return exporterhelper.NewMetricsExporter(ctx, oce.settings, oce.config,
+	exp, err := exporterhelper.NewMetricsExporter(ctx, oce.settings, oce.config,
 		oce.pushMetrics,
 		oce.helperOptions()...,
 	)
+	if err != nil || oce.netStats == nil {
+		return exp, err
+	}
+	return metricsExporter{Metrics: exp, wireStats: wireStats{oce.netStats}}, nil
 }
 
 func createArrowLogsStream(cfg *Config, conn *grpc.ClientConn) func(ctx context.Context, opts ...grpc.CallOption) (arrow.AnyStreamClient, error) {
@@ -125,8 +138,38 @@ func createLogsExporter(
 	if err != nil {
 		return nil, err
 	}
-	return exporterhelper.NewLogsExporter(ctx, oce.settings, oce.config,
+	exp, err := exporterhelper.NewLogsExporter(ctx, oce.settings, oce.config,
 		oce.pushLogs,
 		oce.helperOptions()...,
 	)
+	if err != nil || oce.netStats == nil {
+		return exp, err
+	}
+	return logsExporter{Logs: exp, wireStats: wireStats{oce.netStats}}, nil
+}
+
+// wireStats exposes the number of bytes sent on the wire by an
+// exporter, e.g. to compare the routes of the experiment processor.
+type wireStats struct {
+	netStats *netstats.NetworkReporter
+}
+
+// SentWireBytes returns the total number of bytes sent on the wire.
+func (w wireStats) SentWireBytes() int64 {
+	return w.netStats.SentWireBytes()
+}
+
+type tracesExporter struct {
+	exporter.Traces
+	wireStats
+}
+
+type metricsExporter struct {
+	exporter.Metrics
+	wireStats
+}
+
+type logsExporter struct {
+	exporter.Logs
+	wireStats
 }
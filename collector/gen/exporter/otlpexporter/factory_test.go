@@ -21,6 +21,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"github.com/f5/otel-arrow-adapter/collector/gen/internal/testutil"
+	"github.com/f5/otel-arrow-adapter/pkg/config"
 )
 
 func TestCreateDefaultConfig(t *testing.T) {
@@ -34,7 +35,7 @@ func TestCreateDefaultConfig(t *testing.T) {
 	assert.Equal(t, ocfg.QueueSettings, exporterhelper.NewDefaultQueueSettings())
 	assert.Equal(t, ocfg.TimeoutSettings, exporterhelper.NewDefaultTimeoutSettings())
 	assert.Equal(t, ocfg.Compression, configcompression.Gzip)
-	assert.Equal(t, ocfg.Arrow, ArrowSettings{Disabled: false, NumStreams: runtime.NumCPU()})
+	assert.Equal(t, ocfg.Arrow, ArrowSettings{Disabled: false, NumStreams: runtime.NumCPU(), PayloadCompression: config.IPCCompressionZstd, AdaptiveSortingMinGain: config.DefaultAdaptiveSortingMinGain})
 }
 
 func TestCreateMetricsExporter(t *testing.T) {
@@ -20,12 +20,15 @@ import (
 	"sync"
 
 	arrowpb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	"github.com/f5/otel-arrow-adapter/collector/gen/internal/arrowz"
+	"github.com/f5/otel-arrow-adapter/collector/gen/internal/netstats"
 	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter"
 )
 
 // Exporter is 1:1 with exporter, isolates arrow-specific
@@ -41,12 +44,20 @@ type Exporter struct {
 	// telemetry includes logger, tracer, meter.
 	telemetry component.TelemetrySettings
 
+	// netReporter is passed to the streams for the compression
+	// accounting, or is nil.
+	netReporter *netstats.NetworkReporter
+
+	// streamTelemetry is passed to the streams, or is nil.
+	streamTelemetry *streamTelemetry
+
 	// grpcOptions includes options used by the unary RPC methods,
 	// e.g., WaitForReady.
 	grpcOptions []grpc.CallOption
 
-	// newProducer returns a real (or mock) Producer.
-	newProducer func() arrowRecord.ProducerAPI
+	// newProducer returns a real (or mock) Producer for the stream with
+	// the given index.
+	newProducer func(stream int) arrowRecord.ProducerAPI
 
 	// client is a stream corresponding with the signal's payload
 	// type. uses the exporter's gRPC ClientConn (or is a mock, in tests).
@@ -62,6 +73,11 @@ type Exporter struct {
 	// ready prioritizes streams that are ready to send
 	ready *streamPrioritizer
 
+	// streamsLock protects streams, the set of running streams
+	// reported by Streams().
+	streamsLock sync.Mutex
+	streams     map[*Stream]struct{}
+
 	// cancel cancels the background context of this
 	// Exporter, used for shutdown.
 	cancel context.CancelFunc
@@ -103,22 +119,30 @@ func MakeAnyStreamClient[T AnyStreamClient](clientFunc func(ctx context.Context,
 func NewExporter(
 	numStreams int,
 	disableDowngrade bool,
-	telemetry component.TelemetrySettings,
+	settings exporter.CreateSettings,
+	netReporter *netstats.NetworkReporter,
 	grpcOptions []grpc.CallOption,
-	newProducer func() arrowRecord.ProducerAPI,
+	newProducer func(stream int) arrowRecord.ProducerAPI,
 	streamClient StreamClientFunc,
 	perRPCCredentials credentials.PerRPCCredentials,
-) *Exporter {
+) (*Exporter, error) {
+	streamTelemetry, err := newStreamTelemetry(settings)
+	if err != nil {
+		return nil, err
+	}
 	return &Exporter{
 		numStreams:        numStreams,
 		disableDowngrade:  disableDowngrade,
-		telemetry:         telemetry,
+		telemetry:         settings.TelemetrySettings,
+		netReporter:       netReporter,
+		streamTelemetry:   streamTelemetry,
 		grpcOptions:       grpcOptions,
 		newProducer:       newProducer,
 		streamClient:      streamClient,
 		perRPCCredentials: perRPCCredentials,
 		returning:         make(chan *Stream, numStreams),
-	}
+		streams:           map[*Stream]struct{}{},
+	}, nil
 }
 
 // Start creates the background context used by all streams and starts
@@ -148,16 +172,17 @@ func (e *Exporter) runStreamController(bgctx context.Context) {
 	// Start the initial number of streams
 	for i := 0; i < running; i++ {
 		e.wg.Add(1)
-		go e.runArrowStream(bgctx)
+		go e.runArrowStream(bgctx, i)
 	}
 
 	for {
 		select {
 		case stream := <-e.returning:
 			if stream.client != nil || e.disableDowngrade {
-				// The stream closed or broken.  Restart it.
+				// The stream closed or broken.  Restart it,
+				// with the same index.
 				e.wg.Add(1)
-				go e.runArrowStream(bgctx)
+				go e.runArrowStream(bgctx, stream.index)
 				continue
 			}
 			// Otherwise, the stream never got started.  It was
@@ -182,12 +207,21 @@ func (e *Exporter) runStreamController(bgctx context.Context) {
 // If the stream connection is successful, this goroutine starts another goroutine
 // to call writeStream() and performs readStream() itself.  When the stream shuts
 // down this call synchronously waits for and unblocks the consumers.
-func (e *Exporter) runArrowStream(ctx context.Context) {
-	producer := e.newProducer()
+func (e *Exporter) runArrowStream(ctx context.Context, index int) {
+	producer := e.newProducer(index)
+
+	stream := newStream(producer, e.ready, e.telemetry, e.netReporter, e.streamTelemetry, e.perRPCCredentials)
+	stream.index = index
 
-	stream := newStream(producer, e.ready, e.telemetry, e.perRPCCredentials)
+	e.streamsLock.Lock()
+	e.streams[stream] = struct{}{}
+	e.streamsLock.Unlock()
 
 	defer func() {
+		e.streamsLock.Lock()
+		delete(e.streams, stream)
+		e.streamsLock.Unlock()
+
 		if err := producer.Close(); err != nil {
 			e.telemetry.Logger.Error("arrow producer close:", zap.Error(err))
 		}
@@ -198,6 +232,18 @@ func (e *Exporter) runArrowStream(ctx context.Context) {
 	stream.run(ctx, e.streamClient, e.grpcOptions)
 }
 
+// Streams returns a snapshot of the running streams (see arrowz).
+func (e *Exporter) Streams() []arrowz.StreamInfo {
+	e.streamsLock.Lock()
+	defer e.streamsLock.Unlock()
+
+	infos := make([]arrowz.StreamInfo, 0, len(e.streams))
+	for stream := range e.streams {
+		infos = append(infos, stream.info())
+	}
+	return infos
+}
+
 // SendAndWait tries to send using an Arrow stream.  The results are:
 //
 // (true, nil):      Arrow send: success at consumer
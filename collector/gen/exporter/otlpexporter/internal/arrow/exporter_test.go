@@ -36,6 +36,8 @@ import (
 	arrowRecordMock "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record/mock"
 	otelAssert "github.com/f5/otel-arrow-adapter/pkg/otel/assert"
 
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -65,6 +67,11 @@ func (c compareJSONLogs) MarshalJSON() ([]byte, error) {
 type exporterTestCase struct {
 	*commonTestCase
 	exporter *Exporter
+
+	// streamsLock protects streams, the indices of the streams of the
+	// producers created by the exporter.
+	streamsLock sync.Mutex
+	streams     []int
 }
 
 func newSingleStreamTestCase(t *testing.T) *exporterTestCase {
@@ -136,7 +143,18 @@ func newExporterTestCaseCommon(t *testing.T, noisy noisyTest, numStreams int, di
 		})
 	}
 
-	exp := NewExporter(numStreams, disableDowngrade, ctc.telset, nil, func() arrowRecord.ProducerAPI {
+	settings := exporter.CreateSettings{
+		ID:                component.NewID("arrow"),
+		TelemetrySettings: ctc.telset,
+	}
+	tc := &exporterTestCase{
+		commonTestCase: ctc,
+	}
+	exp, err := NewExporter(numStreams, disableDowngrade, settings, nil, nil, func(stream int) arrowRecord.ProducerAPI {
+		tc.streamsLock.Lock()
+		tc.streams = append(tc.streams, stream)
+		tc.streamsLock.Unlock()
+
 		// Mock the close function, use a real producer for testing dataflow.
 		mock := arrowRecordMock.NewMockProducerAPI(ctc.ctrl)
 		prod := arrowRecord.NewProducer()
@@ -147,14 +165,14 @@ func newExporterTestCaseCommon(t *testing.T, noisy noisyTest, numStreams int, di
 			copyBatch(prod.BatchArrowRecordsFromLogs))
 		mock.EXPECT().BatchArrowRecordsFromMetrics(gomock.Any()).AnyTimes().DoAndReturn(
 			copyBatch(prod.BatchArrowRecordsFromMetrics))
+		mock.EXPECT().ReleaseBatch(gomock.Any()).AnyTimes()
 		mock.EXPECT().Close().Times(1).Return(nil)
 		return mock
 	}, ctc.streamClient, ctc.perRPCCredentials)
+	require.NoError(t, err)
 
-	return &exporterTestCase{
-		commonTestCase: ctc,
-		exporter:       exp,
-	}
+	tc.exporter = exp
+	return tc
 }
 
 func statusOKFor(id string) *arrowpb.BatchStatus {
@@ -434,6 +452,14 @@ func TestArrowExporterStreamFailure(t *testing.T) {
 	wg.Wait()
 
 	require.NoError(t, tc.exporter.Shutdown(bg))
+
+	// The restarted stream keeps the index of the failed one.
+	tc.streamsLock.Lock()
+	defer tc.streamsLock.Unlock()
+	require.GreaterOrEqual(t, len(tc.streams), 2)
+	for _, stream := range tc.streams {
+		require.Equal(t, 0, stream)
+	}
 }
 
 // TestArrowExporterStreamRace reproduces the situation needed for a
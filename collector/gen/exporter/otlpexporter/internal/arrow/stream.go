@@ -22,6 +22,8 @@ import (
 	"io"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -30,8 +32,11 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	arrowpb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	"github.com/f5/otel-arrow-adapter/collector/gen/internal/arrowz"
+	"github.com/f5/otel-arrow-adapter/collector/gen/internal/netstats"
 	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
 
 	"go.opentelemetry.io/collector/component"
@@ -46,6 +51,10 @@ type Stream struct {
 	// producer is exclusive to the holder of the stream.
 	producer arrowRecord.ProducerAPI
 
+	// index of the stream in [0, NumStreams), a restarted stream keeps
+	// the index of the stream it replaces.
+	index int
+
 	// prioritizer has a reference to the stream, this allows it to be severed.
 	prioritizer *streamPrioritizer
 
@@ -55,6 +64,14 @@ type Stream struct {
 	// telemetry are a copy of the exporter's telemetry settings
 	telemetry component.TelemetrySettings
 
+	// netReporter measures the compression of a sample of the batches
+	// relative to OTLP, or is nil.
+	netReporter *netstats.NetworkReporter
+
+	// streamTelemetry reports the lifecycle of the stream and the
+	// latency of its batches, or is nil.
+	streamTelemetry *streamTelemetry
+
 	// client uses the exporter's grpc.ClientConn.  this is
 	// initially nil only set when ArrowStream() calls meaning the
 	// endpoint recognizes OTLP+Arrow.
@@ -64,11 +81,21 @@ type Stream struct {
 	// includes a dedicated channel for the response.
 	toWrite chan writeItem
 
-	// lock protects waiters.
+	// lock protects waiters and resets.
 	lock sync.Mutex
 
 	// waiters is the response channel for each active batch.
-	waiters map[string]chan error
+	waiters map[string]*batchWaiter
+
+	// resets are the dictionary reset requests received from the
+	// receiver, applied by the writer before encoding the next batch.
+	resets []*arrowpb.DictionaryReset
+
+	// started, batches and bytes are reported by info(), the counters
+	// are updated atomically by the writer.
+	started time.Time
+	batches uint64
+	bytes   uint64
 }
 
 // writeItem is passed from the sender (a pipeline consumer) to the
@@ -82,11 +109,26 @@ type writeItem struct {
 	errCh chan error
 }
 
+// batchWaiter is the state of a batch awaiting a response.
+type batchWaiter struct {
+	// errCh is used by the stream reader to unblock the sender
+	errCh chan error
+	// signal of the batch, see signalOf().
+	signal string
+	// sent is the time the batch was sent.
+	sent time.Time
+	// batch is released to the producer when its status is received,
+	// gRPC doesn't allow the modification of a message after Send().
+	batch *arrowpb.BatchArrowRecords
+}
+
 // newStream constructs a stream
 func newStream(
 	producer arrowRecord.ProducerAPI,
 	prioritizer *streamPrioritizer,
 	telemetry component.TelemetrySettings,
+	netReporter *netstats.NetworkReporter,
+	streamTelemetry *streamTelemetry,
 	perRPCCredentials credentials.PerRPCCredentials,
 ) *Stream {
 	return &Stream{
@@ -94,18 +136,33 @@ func newStream(
 		prioritizer:       prioritizer,
 		perRPCCredentials: perRPCCredentials,
 		telemetry:         telemetry,
+		netReporter:       netReporter,
+		streamTelemetry:   streamTelemetry,
 		toWrite:           make(chan writeItem, 1),
-		waiters:           map[string]chan error{},
+		waiters:           map[string]*batchWaiter{},
+		started:           time.Now(),
+	}
+}
+
+// info returns a snapshot of the state of the stream, it can be
+// called concurrently with the stream operations.
+func (s *Stream) info() arrowz.StreamInfo {
+	return arrowz.StreamInfo{
+		Started:    s.started,
+		Batches:    atomic.LoadUint64(&s.batches),
+		Bytes:      atomic.LoadUint64(&s.bytes),
+		SubStreams: s.producer.SubStreams(),
 	}
 }
 
 // setBatchChannel places a waiting consumer's batchID into the waiters map, where
 // the stream reader may find it.
-func (s *Stream) setBatchChannel(batchID string, errCh chan error) {
+func (s *Stream) setBatchChannel(ctx context.Context, batchID string, waiter *batchWaiter) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	s.waiters[batchID] = errCh
+	s.waiters[batchID] = waiter
+	s.streamTelemetry.batchSent(ctx, waiter.signal, len(s.waiters))
 }
 
 func (s *Stream) logStreamError(err error) {
@@ -152,6 +209,8 @@ func (s *Stream) run(bgctx context.Context, streamClient StreamClientFunc, grpcO
 	// streaming may start.  When this stream finishes, it will be
 	// restarted.
 	s.client = sc
+	s.streamTelemetry.streamStarted(ctx)
+	reason := restartUnknown
 
 	// ww is used to wait for the writer.  Since we wait for the writer,
 	// the writer's goroutine is not added to exporter waitgroup (e.wg).
@@ -192,6 +251,7 @@ func (s *Stream) run(bgctx context.Context, streamClient StreamClientFunc, grpcO
 				// take this return path.  Design a graceful
 				// recovery mechanism?
 				s.client = nil
+				reason = restartUnimplemented
 				s.telemetry.Logger.Info("arrow is not supported",
 					zap.String("message", status.Message()),
 				)
@@ -201,8 +261,10 @@ func (s *Stream) run(bgctx context.Context, streamClient StreamClientFunc, grpcO
 				// The message string will contain NO_ERROR if it's a
 				// graceful shutdown.
 				if strings.Contains(status.Message(), "NO_ERROR") {
+					reason = restartShutdown
 					s.telemetry.Logger.Debug("arrow stream shutdown")
 				} else {
+					reason = restartUnavailable
 					s.telemetry.Logger.Error("arrow stream unavailable",
 						zap.String("message", status.Message()),
 					)
@@ -216,12 +278,14 @@ func (s *Stream) run(bgctx context.Context, streamClient StreamClientFunc, grpcO
 				// writer. So if the reader's error is canceled and the
 				// writer's error is non-nil, use it instead.
 				if writeErr != nil {
+					reason = restartInternal
 					s.telemetry.Logger.Error("arrow stream internal error",
 						zap.Error(writeErr),
 					)
 					// reset the writeErr so it doesn't print below.
 					writeErr = nil
 				} else {
+					reason = restartCanceled
 					s.telemetry.Logger.Error("arrow stream canceled",
 						zap.String("message", status.Message()),
 					)
@@ -233,6 +297,9 @@ func (s *Stream) run(bgctx context.Context, streamClient StreamClientFunc, grpcO
 				)
 			}
 		} else {
+			if errors.Is(err, context.Canceled) {
+				reason = restartCanceled
+			}
 			s.logStreamError(err)
 		}
 	}
@@ -242,10 +309,13 @@ func (s *Stream) run(bgctx context.Context, streamClient StreamClientFunc, grpcO
 
 	// The reader and writer have both finished; respond to any
 	// outstanding waiters.
-	for _, ch := range s.waiters {
+	for _, waiter := range s.waiters {
 		// Note: the top-level OTLP exporter will retry.
-		ch <- ErrStreamRestarting
+		waiter.errCh <- ErrStreamRestarting
 	}
+	// Note: the stream context is canceled, the SDK would drop a
+	// measurement made with it.
+	s.streamTelemetry.streamFinished(context.Background(), reason)
 }
 
 // write repeatedly places this stream into the next-available queue, then
@@ -273,11 +343,22 @@ func (s *Stream) write(ctx context.Context) error {
 			s.prioritizer.removeReady(s)
 			return ctx.Err()
 		}
-		// Note: For the two return statements below there is no potential
+		// Note: For the return statements below there is no potential
 		// sender race because the stream is not available, as indicated by
 		// the successful <-stream.toWrite.
 
+		if err := s.applyDictionaryResets(); err != nil {
+			// The producer is in an unknown state, restart the
+			// stream.  The sender will retry on another stream.
+			err = fmt.Errorf("dictionary reset: %w", err)
+			wri.errCh <- ErrStreamRestarting
+			return err
+		}
+
+		signal := signalOf(wri.records)
+		start := time.Now()
 		batch, err := s.encode(wri.records)
+		s.streamTelemetry.batchEncoded(ctx, signal, time.Since(start))
 		if err != nil {
 			// This is some kind of internal error.  We will restart the
 			// stream and mark this record as a permanent one.
@@ -286,6 +367,15 @@ func (s *Stream) write(ctx context.Context) error {
 			return err
 		}
 
+		if s.netReporter.SampleCompression() {
+			signal, otlpLength := netstats.OTLPSize(wri.records)
+			s.netReporter.CountCompression(ctx, netstats.CompressionStruct{
+				Signal:      signal,
+				OTLPLength:  otlpLength,
+				ArrowLength: int64(proto.Size(batch)),
+			})
+		}
+
 		// Optionally include outgoing metadata, if present.
 		if len(wri.md) != 0 {
 			hdrsBuf.Reset()
@@ -306,10 +396,23 @@ func (s *Stream) write(ctx context.Context) error {
 			batch.Headers = hdrsBuf.Bytes()
 		}
 
-		// Let the receiver knows what to look for.
-		s.setBatchChannel(batch.BatchId, wri.errCh)
+		var size int
+		for _, payload := range batch.ArrowPayloads {
+			size += len(payload.Record)
+		}
+		atomic.AddUint64(&s.batches, 1)
+		atomic.AddUint64(&s.bytes, uint64(size))
 
-		if err := s.client.Send(batch); err != nil {
+		// Let the receiver knows what to look for.
+		s.setBatchChannel(ctx, batch.BatchId, &batchWaiter{
+			errCh:  wri.errCh,
+			signal: signal,
+			sent:   time.Now(),
+			batch:  batch,
+		})
+
+		err = s.client.Send(batch)
+		if err != nil {
 			// The error will be sent to errCh during cleanup for this stream.
 			// Note: do not wrap this error, it may contain a Status.
 			return err
@@ -330,12 +433,47 @@ func (s *Stream) read(_ context.Context) error {
 			return err
 		}
 
+		// Note: the reset is recorded before the senders are
+		// released, so that the next batch is encoded after it.
+		if resp.DictionaryReset != nil {
+			s.requestDictionaryReset(resp.DictionaryReset)
+		}
+
 		if err = s.processBatchStatus(resp.Statuses); err != nil {
 			return fmt.Errorf("process: %w", err)
 		}
 	}
 }
 
+// requestDictionaryReset records a dictionary reset request from the
+// receiver, it will be applied by the writer.
+func (s *Stream) requestDictionaryReset(reset *arrowpb.DictionaryReset) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.resets = append(s.resets, reset)
+}
+
+// applyDictionaryResets resets the producer's sub-streams requested by
+// the receiver since the last batch.  Called by the writer, which
+// has exclusive use of the producer.
+func (s *Stream) applyDictionaryResets() error {
+	s.lock.Lock()
+	resets := s.resets
+	s.resets = nil
+	s.lock.Unlock()
+
+	for _, reset := range resets {
+		s.telemetry.Logger.Debug("arrow dictionary reset",
+			zap.Int("payload_types", len(reset.PayloadTypes)),
+		)
+		if err := s.producer.ResetStreams(reset.PayloadTypes...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // getSenderChannels takes the stream lock and removes the
 // corresonding sender channel for each BatchId.  They are returned
 // with the same index as the original status, for correlation.  Nil
@@ -350,14 +488,18 @@ func (s *Stream) getSenderChannels(statuses []*arrowpb.StatusMessage) ([]chan er
 	defer s.lock.Unlock()
 
 	for idx, status := range statuses {
-		ch, ok := s.waiters[status.BatchId]
+		waiter, ok := s.waiters[status.BatchId]
 		if !ok {
 			// Will break the stream.
 			err = multierr.Append(err, fmt.Errorf("unrecognized batch ID: %s", status.BatchId))
 			continue
 		}
 		delete(s.waiters, status.BatchId)
-		fin[idx] = ch
+		// The payload buffers of an acknowledged batch can be reused
+		// by the producer.
+		s.producer.ReleaseBatch(waiter.batch)
+		fin[idx] = waiter.errCh
+		s.streamTelemetry.batchAcked(context.Background(), waiter.signal, time.Since(waiter.sent))
 	}
 
 	return fin, err
@@ -25,6 +25,7 @@ import (
 	arrowpb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
 	arrowRecordMock "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record/mock"
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 
@@ -39,15 +40,16 @@ type streamTestCase struct {
 	*commonTestCase
 	*commonTestStream
 
-	producer        *arrowRecordMock.MockProducerAPI
-	prioritizer     *streamPrioritizer
-	bgctx           context.Context
-	bgcancel        context.CancelFunc
-	fromTracesCall  *gomock.Call
-	fromMetricsCall *gomock.Call
-	fromLogsCall    *gomock.Call
-	stream          *Stream
-	wait            sync.WaitGroup
+	producer         *arrowRecordMock.MockProducerAPI
+	prioritizer      *streamPrioritizer
+	bgctx            context.Context
+	bgcancel         context.CancelFunc
+	fromTracesCall   *gomock.Call
+	fromMetricsCall  *gomock.Call
+	fromLogsCall     *gomock.Call
+	releaseBatchCall *gomock.Call
+	stream           *Stream
+	wait             sync.WaitGroup
 }
 
 func newStreamTestCase(t *testing.T) *streamTestCase {
@@ -63,11 +65,12 @@ func newStreamTestCase(t *testing.T) *streamTestCase {
 	// metadata functionality is tested in exporter_test.go
 	ctc.requestMetadataCall.AnyTimes().Return(nil, nil)
 
-	stream := newStream(producer, prio, ctc.telset, ctc.perRPCCredentials)
+	stream := newStream(producer, prio, ctc.telset, nil, nil, ctc.perRPCCredentials)
 
 	fromTracesCall := producer.EXPECT().BatchArrowRecordsFromTraces(gomock.Any()).Times(0)
 	fromMetricsCall := producer.EXPECT().BatchArrowRecordsFromMetrics(gomock.Any()).Times(0)
 	fromLogsCall := producer.EXPECT().BatchArrowRecordsFromLogs(gomock.Any()).Times(0)
+	releaseBatchCall := producer.EXPECT().ReleaseBatch(gomock.Any()).AnyTimes()
 
 	return &streamTestCase{
 		commonTestCase:   ctc,
@@ -80,6 +83,7 @@ func newStreamTestCase(t *testing.T) *streamTestCase {
 		fromTracesCall:   fromTracesCall,
 		fromMetricsCall:  fromMetricsCall,
 		fromLogsCall:     fromLogsCall,
+		releaseBatchCall: releaseBatchCall,
 	}
 }
 
@@ -236,6 +240,78 @@ func TestStreamStatusUnrecognized(t *testing.T) {
 	tc.waitForShutdown()
 }
 
+// TestStreamDictionaryReset verifies that a dictionary reset request
+// from the receiver is applied to the producer before the next batch
+// is encoded.
+func TestStreamDictionaryReset(t *testing.T) {
+	tc := newStreamTestCase(t)
+
+	tc.fromTracesCall.Times(1).Return(oneBatch, nil)
+	resetCall := tc.producer.EXPECT().ResetStreams(arrowpb.ArrowPayloadType_SPANS, arrowpb.ArrowPayloadType_SPAN_ATTRS).Times(1).Return(nil)
+	// The second batch is encoded after the reset.
+	tc.producer.EXPECT().BatchArrowRecordsFromTraces(gomock.Any()).Times(1).Return(oneBatch, nil).After(resetCall)
+
+	channel := newHealthyTestChannel()
+	tc.start(channel)
+	defer tc.cancelAndWaitForShutdown()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	defer wg.Wait()
+	go func() {
+		defer wg.Done()
+		batch := <-channel.sent
+		status := statusOKFor(batch.BatchId)
+		status.DictionaryReset = &arrowpb.DictionaryReset{
+			PayloadTypes: []arrowpb.ArrowPayloadType{
+				arrowpb.ArrowPayloadType_SPANS,
+				arrowpb.ArrowPayloadType_SPAN_ATTRS,
+			},
+		}
+		channel.recv <- status
+		batch = <-channel.sent
+		channel.recv <- statusOKFor(batch.BatchId)
+	}()
+
+	err := tc.get().SendAndWait(tc.bgctx, twoTraces)
+	require.NoError(t, err)
+
+	err = tc.get().SendAndWait(tc.bgctx, twoTraces)
+	require.NoError(t, err)
+}
+
+// TestStreamReleaseBatch verifies that a batch is released to the producer
+// when its status is received, not when it is sent.
+func TestStreamReleaseBatch(t *testing.T) {
+	tc := newStreamTestCase(t)
+
+	tc.fromTracesCall.Times(1).Return(oneBatch, nil)
+	released := make(chan *arrowpb.BatchArrowRecords, 1)
+	tc.releaseBatchCall.Do(func(batch *arrowpb.BatchArrowRecords) {
+		released <- batch
+	})
+
+	channel := newHealthyTestChannel()
+	tc.start(channel)
+	defer tc.cancelAndWaitForShutdown()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	defer wg.Wait()
+	go func() {
+		defer wg.Done()
+		batch := <-channel.sent
+		// Give the writer the opportunity to release the batch.
+		time.Sleep(10 * time.Millisecond)
+		assert.Empty(t, released)
+		channel.recv <- statusOKFor(batch.BatchId)
+	}()
+
+	err := tc.get().SendAndWait(tc.bgctx, twoTraces)
+	require.NoError(t, err)
+	require.Equal(t, oneBatch, <-released)
+}
+
 // TestStreamUnsupported verifies that the stream signals downgrade
 // when an Unsupported code is received, which is how the gRPC client
 // responds when the server does not support arrow.
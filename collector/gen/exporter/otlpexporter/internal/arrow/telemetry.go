@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow // import "github.com/f5/otel-arrow-adapter/collector/gen/exporter/otlpexporter/internal/arrow"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/multierr"
+
+	"github.com/f5/otel-arrow-adapter/collector/gen/internal/netstats"
+
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	// ReasonKey is an attribute name that identifies why a stream
+	// was restarted, one of the restart* values below.
+	ReasonKey = "reason"
+
+	// StreamKey is an attribute name that identifies the index of the
+	// stream of a producer, see config.WithMeterProvider.
+	StreamKey = "stream"
+
+	scopeName = "github.com/f5/otel-arrow-adapter/collector/gen/exporter/otlpexporter/internal/arrow"
+	prefix    = netstats.ExporterKey + "_arrow_"
+)
+
+// Reasons of a stream restart, following the status of the stream.
+const (
+	// restartUnimplemented: the receiver does not support Arrow.
+	restartUnimplemented = "unimplemented"
+	// restartShutdown: graceful shutdown, e.g., max connection age.
+	restartShutdown = "shutdown"
+	// restartUnavailable: the receiver is unavailable.
+	restartUnavailable = "unavailable"
+	// restartCanceled: the stream was canceled.
+	restartCanceled = "canceled"
+	// restartInternal: the stream was canceled by a local error.
+	restartInternal = "internal"
+	// restartUnknown: any other error.
+	restartUnknown = "unknown"
+)
+
+// streamTelemetry reports the lifecycle of the Arrow streams and the
+// latency of the batches.  A nil streamTelemetry is valid and reports
+// nothing, this is the case at the basic level of telemetry.
+type streamTelemetry struct {
+	component attribute.KeyValue
+
+	activeStreams   metric.Int64UpDownCounter
+	streamRestarts  metric.Int64Counter
+	inflightBatches metric.Int64Histogram
+	encodeTime      metric.Float64Histogram
+	ackTime         metric.Float64Histogram
+}
+
+// newStreamTelemetry creates the stream instruments of an exporter.
+func newStreamTelemetry(settings exporter.CreateSettings) (*streamTelemetry, error) {
+	if settings.TelemetrySettings.MetricsLevel <= configtelemetry.LevelBasic {
+		return nil, nil
+	}
+
+	meter := settings.TelemetrySettings.MeterProvider.Meter(scopeName)
+	st := &streamTelemetry{
+		component: attribute.String(netstats.ExporterKey, settings.ID.String()),
+	}
+
+	var errors, err error
+	st.activeStreams, err = meter.Int64UpDownCounter(prefix+"streams_active", metric.WithDescription("Number of active Arrow streams."))
+	errors = multierr.Append(errors, err)
+	st.streamRestarts, err = meter.Int64Counter(prefix+"stream_restarts", metric.WithDescription("Number of Arrow streams restarted, by reason."))
+	errors = multierr.Append(errors, err)
+	st.inflightBatches, err = meter.Int64Histogram(prefix+"inflight_batches", metric.WithDescription("Number of batches awaiting a response on a stream, when a batch is sent."))
+	errors = multierr.Append(errors, err)
+	st.encodeTime, err = meter.Float64Histogram(prefix+"encode_time", metric.WithDescription("Time spent encoding a batch."), metric.WithUnit("s"))
+	errors = multierr.Append(errors, err)
+	st.ackTime, err = meter.Float64Histogram(prefix+"ack_time", metric.WithDescription("Time between sending a batch and receiving its status."), metric.WithUnit("s"))
+	errors = multierr.Append(errors, err)
+	if errors != nil {
+		return nil, errors
+	}
+	return st, nil
+}
+
+// streamStarted is called when a stream is established.
+func (st *streamTelemetry) streamStarted(ctx context.Context) {
+	if st == nil {
+		return
+	}
+	st.activeStreams.Add(ctx, 1, metric.WithAttributes(st.component))
+}
+
+// streamFinished is called when an established stream returns, with
+// the reason of its restart.
+func (st *streamTelemetry) streamFinished(ctx context.Context, reason string) {
+	if st == nil {
+		return
+	}
+	st.activeStreams.Add(ctx, -1, metric.WithAttributes(st.component))
+	st.streamRestarts.Add(ctx, 1, metric.WithAttributes(st.component, attribute.String(ReasonKey, reason)))
+}
+
+// batchEncoded reports the time spent encoding a batch.
+func (st *streamTelemetry) batchEncoded(ctx context.Context, signal string, elapsed time.Duration) {
+	if st == nil {
+		return
+	}
+	st.encodeTime.Record(ctx, elapsed.Seconds(), metric.WithAttributes(st.component, attribute.String(netstats.SignalKey, signal)))
+}
+
+// batchSent reports the number of batches awaiting a response on a
+// stream, including the batch being sent.
+func (st *streamTelemetry) batchSent(ctx context.Context, signal string, inflight int) {
+	if st == nil {
+		return
+	}
+	st.inflightBatches.Record(ctx, int64(inflight), metric.WithAttributes(st.component, attribute.String(netstats.SignalKey, signal)))
+}
+
+// batchAcked reports the time between sending a batch and receiving
+// its status.
+func (st *streamTelemetry) batchAcked(ctx context.Context, signal string, elapsed time.Duration) {
+	if st == nil {
+		return
+	}
+	st.ackTime.Record(ctx, elapsed.Seconds(), metric.WithAttributes(st.component, attribute.String(netstats.SignalKey, signal)))
+}
+
+// signalOf returns the signal of a ptrace.Traces, plog.Logs, or
+// pmetric.Metrics.
+func signalOf(records interface{}) string {
+	switch records.(type) {
+	case ptrace.Traces:
+		return "traces"
+	case plog.Logs:
+		return "logs"
+	case pmetric.Metrics:
+		return "metrics"
+	}
+	return "unknown"
+}
@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/exporter"
+)
+
+// TestStreamTelemetry verifies the lifecycle and latency instruments
+// of a stream sending two batches.
+func TestStreamTelemetry(t *testing.T) {
+	rdr := metric.NewManualReader()
+	mp := metric.NewMeterProvider(
+		metric.WithResource(resource.Empty()),
+		metric.WithReader(rdr),
+	)
+
+	tc := newStreamTestCase(t)
+	telset := tc.telset
+	telset.MeterProvider = mp
+	telset.MetricsLevel = configtelemetry.LevelNormal
+	st, err := newStreamTelemetry(exporter.CreateSettings{
+		ID:                component.NewID("arrow"),
+		TelemetrySettings: telset,
+	})
+	require.NoError(t, err)
+	tc.stream.streamTelemetry = st
+
+	tc.fromTracesCall.Times(1).Return(oneBatch, nil)
+	tc.fromLogsCall.Times(1).Return(oneBatch, nil)
+
+	channel := newHealthyTestChannel()
+	tc.start(channel)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 2; i++ {
+			batch := <-channel.sent
+			channel.recv <- statusOKFor(batch.BatchId)
+		}
+	}()
+	require.NoError(t, tc.get().SendAndWait(tc.bgctx, twoTraces))
+	require.NoError(t, tc.get().SendAndWait(tc.bgctx, twoLogs))
+	wg.Wait()
+
+	ctx := context.Background()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, rdr.Collect(ctx, &rm))
+	require.Equal(t, map[string]int64{"exporter_arrow_streams_active": 1}, sums(t, rm))
+	require.Equal(t, map[string]uint64{
+		"exporter_arrow_encode_time/traces":      1,
+		"exporter_arrow_encode_time/logs":        1,
+		"exporter_arrow_inflight_batches/traces": 1,
+		"exporter_arrow_inflight_batches/logs":   1,
+		"exporter_arrow_ack_time/traces":         1,
+		"exporter_arrow_ack_time/logs":           1,
+	}, histogramCounts(rm))
+
+	tc.cancelAndWaitForShutdown()
+
+	require.NoError(t, rdr.Collect(ctx, &rm))
+	require.Equal(t, map[string]int64{
+		"exporter_arrow_streams_active":                     0,
+		"exporter_arrow_stream_restarts/" + restartCanceled: 1,
+	}, sums(t, rm))
+}
+
+// sums returns the value of the Sum data points by metric name, and
+// reason when present.
+func sums(t *testing.T, rm metricdata.ResourceMetrics) map[string]int64 {
+	res := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			data, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range data.DataPoints {
+				v, ok := dp.Attributes.Value("exporter")
+				require.True(t, ok)
+				require.Equal(t, "arrow", v.AsString())
+
+				name := m.Name
+				if reason, ok := dp.Attributes.Value(ReasonKey); ok {
+					name += "/" + reason.AsString()
+				}
+				res[name] = dp.Value
+			}
+		}
+	}
+	return res
+}
+
+// histogramCounts returns the count of the Histogram data points by
+// metric name and signal.
+func histogramCounts(rm metricdata.ResourceMetrics) map[string]uint64 {
+	res := map[string]uint64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			var counts []uint64
+			var signals []string
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[int64]:
+				for _, dp := range data.DataPoints {
+					signal, _ := dp.Attributes.Value("signal")
+					counts = append(counts, dp.Count)
+					signals = append(signals, signal.AsString())
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					signal, _ := dp.Attributes.Value("signal")
+					counts = append(counts, dp.Count)
+					signals = append(signals, signal.AsString())
+				}
+			}
+			for i := range counts {
+				res[m.Name+"/"+signals[i]] = counts[i]
+			}
+		}
+	}
+	return res
+}
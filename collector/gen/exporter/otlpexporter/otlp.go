@@ -11,7 +11,9 @@ import (
 	"time"
 
 	arrowPkg "github.com/apache/arrow/go/v12/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/config"
 	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/multierr"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
@@ -21,8 +23,10 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/f5/otel-arrow-adapter/collector/gen/exporter/otlpexporter/internal/arrow"
+	"github.com/f5/otel-arrow-adapter/collector/gen/internal/arrowz"
 	"github.com/f5/otel-arrow-adapter/collector/gen/internal/netstats"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
@@ -54,6 +58,8 @@ type baseExporter struct {
 
 	// OTLP+Arrow optional state
 	arrow *arrow.Exporter
+	// unregisterArrowz removes the Arrow streams from the arrowz page.
+	unregisterArrowz func()
 	// streamClientFunc is the stream constructor, depends on EnableMixedTelemetry.
 	streamClientFactory streamClientFactory
 }
@@ -132,13 +138,56 @@ func (e *baseExporter) start(ctx context.Context, host component.Host) (err erro
 			}
 		}
 
-		e.arrow = arrow.NewExporter(e.config.Arrow.NumStreams, e.config.Arrow.DisableDowngrade, e.settings.TelemetrySettings, e.callOptions, func() arrowRecord.ProducerAPI {
-			return arrowRecord.NewProducer()
+		producerOptions := []config.Option{
+			config.WithResetAfterBatches(e.config.Arrow.ResetAfterBatches),
+			config.WithResetAfterDictionaryBytes(e.config.Arrow.ResetAfterDictionaryBytes),
+			config.WithIPCCompression(e.config.Arrow.PayloadCompression),
+		}
+		if e.config.Arrow.CompressionDictionarySamples > 0 {
+			producerOptions = append(producerOptions,
+				config.WithCompressionDictionary(e.config.Arrow.CompressionDictionarySamples, e.config.Arrow.CompressionDictionarySize),
+				config.WithCompressionDictionaryRotation(e.config.Arrow.CompressionDictionaryRotateAfterBatches),
+			)
+		}
+		if e.config.Arrow.AdaptiveSortingPeriod > 0 {
+			producerOptions = append(producerOptions, config.WithAdaptiveSorting(
+				e.config.Arrow.AdaptiveSortingPeriod,
+				e.config.Arrow.AdaptiveSortingSampleSize,
+				e.config.Arrow.AdaptiveSortingMinGain,
+			))
+		}
+		arrowExp, err := arrow.NewExporter(e.config.Arrow.NumStreams, e.config.Arrow.DisableDowngrade, e.settings, e.netStats, e.callOptions, func(stream int) arrowRecord.ProducerAPI {
+			options := producerOptions
+			// Like the network stats, the producer stats are only reported
+			// above the basic level of telemetry. The observations of the
+			// producers (e.g. the dictionary cardinalities) are
+			// distinguished by the index of their stream.
+			if e.settings.TelemetrySettings.MetricsLevel > configtelemetry.LevelBasic {
+				options = append(options[:len(options):len(options)], config.WithMeterProvider(
+					e.settings.TelemetrySettings.MeterProvider,
+					attribute.String(netstats.ExporterKey, e.settings.ID.String()),
+					attribute.Int(arrow.StreamKey, stream),
+				))
+			}
+			return arrowRecord.NewProducerWithOptions(options...)
 		}, e.streamClientFactory(e.config, e.clientConn), perRPCCreds)
+		if err != nil {
+			return err
+		}
+		e.arrow = arrowExp
 
 		if err := e.arrow.Start(ctx); err != nil {
 			return err
 		}
+
+		endpoint := e.config.GRPCClientSettings.SanitizedEndpoint()
+		e.unregisterArrowz = arrowz.Register(arrowz.ExporterKind, e.settings.ID.String(), arrowz.SourceFunc(func() []arrowz.StreamInfo {
+			streams := e.arrow.Streams()
+			for i := range streams {
+				streams[i].Peer = endpoint
+			}
+			return streams
+		}))
 	}
 
 	return nil
@@ -146,6 +195,9 @@ func (e *baseExporter) start(ctx context.Context, host component.Host) (err erro
 
 func (e *baseExporter) shutdown(ctx context.Context) error {
 	var err error
+	if e.unregisterArrowz != nil {
+		e.unregisterArrowz()
+	}
 	if e.arrow != nil {
 		err = multierr.Append(err, e.arrow.Shutdown(ctx))
 	}
@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package arrowzextension serves a page listing the active Arrow
+// streams of the OTLP exporters and receivers of the collector.
+package arrowzextension // import "github.com/f5/otel-arrow-adapter/collector/gen/extension/arrowzextension"
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/f5/otel-arrow-adapter/collector/gen/internal/arrowz"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// arrowzPath is the path of the page, its JSON variant is served
+// with the `format=json` query parameter.
+const arrowzPath = "/debug/arrowz"
+
+type arrowzExtension struct {
+	config    *Config
+	telemetry component.TelemetrySettings
+	server    http.Server
+	stopCh    chan struct{}
+}
+
+func (ae *arrowzExtension) Start(_ context.Context, host component.Host) error {
+	mux := http.NewServeMux()
+	mux.Handle(arrowzPath, arrowz.NewHandler())
+
+	// Start the listener here so we can have earlier failure if port is
+	// already in use.
+	ln, err := ae.config.TCPAddr.Listen()
+	if err != nil {
+		return err
+	}
+
+	ae.telemetry.Logger.Info("Starting arrowz extension", zap.Any("config", ae.config))
+	ae.server = http.Server{Handler: mux}
+	ae.stopCh = make(chan struct{})
+	go func() {
+		defer close(ae.stopCh)
+
+		if errHTTP := ae.server.Serve(ln); errHTTP != nil && !errors.Is(errHTTP, http.ErrServerClosed) {
+			host.ReportFatalError(errHTTP)
+		}
+	}()
+
+	return nil
+}
+
+func (ae *arrowzExtension) Shutdown(context.Context) error {
+	err := ae.server.Close()
+	if ae.stopCh != nil {
+		<-ae.stopCh
+	}
+	return err
+}
+
+func newServer(config *Config, telemetry component.TelemetrySettings) *arrowzExtension {
+	return &arrowzExtension{
+		config:    config,
+		telemetry: telemetry,
+	}
+}
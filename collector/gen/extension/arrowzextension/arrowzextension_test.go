@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowzextension
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/f5/otel-arrow-adapter/collector/gen/internal/arrowz"
+	"github.com/f5/otel-arrow-adapter/collector/gen/internal/testutil"
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+)
+
+func get(t *testing.T, url string) string {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestArrowzPage(t *testing.T) {
+	cfg := &Config{
+		TCPAddr: confignet.TCPAddr{
+			Endpoint: testutil.GetAvailableLocalAddress(t),
+		},
+	}
+	ext, err := createExtension(context.Background(), extensiontest.NewNopCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, ext.Shutdown(context.Background()))
+	}()
+
+	started := time.Now().Add(-time.Minute)
+	unregister := arrowz.Register(arrowz.ReceiverKind, "otlp/test", arrowz.SourceFunc(func() []arrowz.StreamInfo {
+		return []arrowz.StreamInfo{{
+			Peer:    "127.0.0.1:1234",
+			Started: started,
+			Batches: 3,
+			Bytes:   1000,
+			SubStreams: []arrowRecord.SubStreamInfo{{
+				ID:           "0",
+				PayloadType:  "SPANS",
+				Schema:       "Schema {\n  name: Dictionary<key:Uint16,value:String>\n}\n",
+				Dictionaries: map[string]int{"name": 42},
+				Batches:      3,
+				LastUpdate:   started,
+			}},
+		}}
+	}))
+
+	url := "http://" + cfg.TCPAddr.Endpoint + arrowzPath
+
+	page := get(t, url)
+	for _, expected := range []string{"receiver otlp/test", "127.0.0.1:1234", "1m0s", "SPANS", "name: 42", "Dictionary&lt;key:Uint16,value:String&gt;"} {
+		require.True(t, strings.Contains(page, expected), "missing %q in %s", expected, page)
+	}
+
+	var infos []arrowz.ComponentInfo
+	require.NoError(t, json.Unmarshal([]byte(get(t, url+"?format=json")), &infos))
+	require.Equal(t, 1, len(infos))
+	require.Equal(t, "otlp/test", infos[0].ID)
+	require.Equal(t, uint64(1000), infos[0].Streams[0].Bytes)
+	require.Equal(t, 42, infos[0].Streams[0].SubStreams[0].Dictionaries["name"])
+
+	unregister()
+	require.NoError(t, json.Unmarshal([]byte(get(t, url+"?format=json")), &infos))
+	require.Equal(t, 0, len(infos))
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, createDefaultConfig().(*Config).Validate())
+	require.Error(t, (&Config{}).Validate())
+}
@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package arrowz maintains a registry of the Arrow exporters and
+// receivers of the process and renders the state of their streams
+// and sub-streams as a zpages-style page (see NewHandler).
+package arrowz // import "github.com/f5/otel-arrow-adapter/collector/gen/internal/arrowz"
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+)
+
+const (
+	// ExporterKind identifies the streams of an exporter.
+	ExporterKind = "exporter"
+
+	// ReceiverKind identifies the streams of a receiver.
+	ReceiverKind = "receiver"
+)
+
+// StreamInfo is a snapshot of the state of an Arrow stream.
+type StreamInfo struct {
+	// Peer is the endpoint of an exporter stream, or the address
+	// of the client of a receiver stream.
+	Peer string `json:"peer"`
+	// Started is the time the stream was started.
+	Started time.Time `json:"started"`
+	// Age is the time elapsed since Started, rounded to the second.
+	Age string `json:"age"`
+	// Batches is the number of batches sent or received.
+	Batches uint64 `json:"batches"`
+	// Bytes is the number of bytes of the Arrow payloads sent or
+	// received.
+	Bytes uint64 `json:"bytes"`
+	// SubStreams is the state of the Producer or Consumer of the
+	// stream.
+	SubStreams []arrowRecord.SubStreamInfo `json:"sub_streams"`
+}
+
+// ComponentInfo is a snapshot of the Arrow streams of a component.
+type ComponentInfo struct {
+	Kind    string       `json:"kind"`
+	ID      string       `json:"id"`
+	Streams []StreamInfo `json:"streams"`
+}
+
+// Source returns the active streams of a component.  Streams is
+// called concurrently with the operation of the component.
+type Source interface {
+	Streams() []StreamInfo
+}
+
+// SourceFunc is a function implementing Source.
+type SourceFunc func() []StreamInfo
+
+// Streams implements Source.
+func (f SourceFunc) Streams() []StreamInfo {
+	return f()
+}
+
+type componentKey struct {
+	kind string
+	id   string
+}
+
+// registration wraps a Source so that Register's unregister function
+// only removes its own registration (a Source may not be comparable).
+type registration struct {
+	src Source
+}
+
+var registry = struct {
+	lock    sync.Mutex
+	sources map[componentKey]*registration
+}{
+	sources: map[componentKey]*registration{},
+}
+
+// Register adds the streams of a component to the page.  The
+// returned function removes them, it must be called when the
+// component shuts down.
+func Register(kind, id string, src Source) (unregister func()) {
+	key := componentKey{kind: kind, id: id}
+
+	reg := &registration{src: src}
+
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	registry.sources[key] = reg
+
+	return func() {
+		registry.lock.Lock()
+		defer registry.lock.Unlock()
+		if registry.sources[key] == reg {
+			delete(registry.sources, key)
+		}
+	}
+}
+
+// Snapshot returns the state of the streams of the registered
+// components, sorted by kind and ID.
+func Snapshot() []ComponentInfo {
+	registry.lock.Lock()
+	keys := make([]componentKey, 0, len(registry.sources))
+	sources := make([]Source, 0, len(registry.sources))
+	for key, reg := range registry.sources {
+		keys = append(keys, key)
+		sources = append(sources, reg.src)
+	}
+	registry.lock.Unlock()
+
+	now := time.Now()
+	infos := make([]ComponentInfo, len(keys))
+	for i, key := range keys {
+		streams := sources[i].Streams()
+		if streams == nil {
+			streams = []StreamInfo{}
+		}
+		for j := range streams {
+			streams[j].Age = now.Sub(streams[j].Started).Round(time.Second).String()
+		}
+		sort.Slice(streams, func(a, b int) bool {
+			return streams[a].Started.Before(streams[b].Started)
+		})
+		infos[i] = ComponentInfo{
+			Kind:    key.kind,
+			ID:      key.id,
+			Streams: streams,
+		}
+	}
+	sort.Slice(infos, func(a, b int) bool {
+		if infos[a].Kind != infos[b].Kind {
+			return infos[a].Kind < infos[b].Kind
+		}
+		return infos[a].ID < infos[b].ID
+	})
+	return infos
+}
@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowz // import "github.com/f5/otel-arrow-adapter/collector/gen/internal/arrowz"
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+)
+
+// FormatParam is the query parameter selecting the JSON variant of
+// the page, e.g., `/debug/arrowz?format=json`.
+const FormatParam = "format"
+
+var pageTemplate = template.Must(template.New("arrowz").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>Arrow streams</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 2px 8px; text-align: left; vertical-align: top; }
+pre { margin: 0; }
+</style>
+</head>
+<body>
+<h1>Arrow streams</h1>
+<p><a href="?format=json">JSON</a></p>
+{{range .}}
+<h2>{{.Kind}} {{.ID}}</h2>
+{{if not .Streams}}<p>No active stream.</p>{{end}}
+{{range .Streams}}
+<table>
+<tr><th>Peer</th><th>Started</th><th>Age</th><th>Batches</th><th>Bytes</th></tr>
+<tr><td>{{.Peer}}</td><td>{{.Started.Format "2006-01-02T15:04:05Z07:00"}}</td><td>{{.Age}}</td><td>{{.Batches}}</td><td>{{.Bytes}}</td></tr>
+</table>
+<table>
+<tr><th>Sub-stream</th><th>Payload type</th><th>Batches</th><th>Last update</th><th>Dictionaries</th><th>Schema</th></tr>
+{{range .SubStreams}}
+<tr>
+<td>{{.ID}}</td>
+<td>{{.PayloadType}}</td>
+<td>{{.Batches}}</td>
+<td>{{.LastUpdate.Format "2006-01-02T15:04:05.000Z07:00"}}</td>
+<td>{{range $field, $size := .Dictionaries}}{{$field}}: {{$size}}<br>{{end}}</td>
+<td><pre>{{.Schema}}</pre></td>
+</tr>
+{{end}}
+</table>
+{{end}}
+{{else}}
+<p>No Arrow exporter or receiver registered.</p>
+{{end}}
+</body>
+</html>
+`))
+
+// NewHandler returns the handler of the page listing the streams of
+// the registered components, as HTML or as JSON when the `format`
+// query parameter is `json`.
+func NewHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		infos := Snapshot()
+
+		if r.URL.Query().Get(FormatParam) == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(infos); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := pageTemplate.Execute(w, infos); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
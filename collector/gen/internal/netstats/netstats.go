@@ -16,6 +16,7 @@ package netstats // import "github.com/f5/otel-arrow-adapter/collector/gen/inter
 
 import (
 	"context"
+	"sync/atomic"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -23,6 +24,9 @@ import (
 
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/receiver"
 )
 
@@ -51,6 +55,29 @@ const (
 	// (includes compression) by exporters and receivers.
 	RecvWireBytes = "recv_wire"
 
+	// OTLPBytes is used to track the OTLP protobuf size of a sample
+	// of the batches sent or received by the component, at the
+	// detailed level.
+	OTLPBytes = "otlp"
+
+	// ArrowBytes is used to track the OTLP Arrow size of the same
+	// sample of batches, the ratio of OTLPBytes to ArrowBytes is the
+	// compression ratio of OTLP Arrow relative to OTLP.
+	ArrowBytes = "arrow"
+
+	// SignalKey is an attribute name that identifies the signal
+	// (traces, logs or metrics) of the compression measurements.
+	SignalKey = "signal"
+
+	// exporterCompressionSampling is the number of batches sent by
+	// an exporter for each batch whose OTLP size is computed, since
+	// the exporter has no other use for this size.
+	exporterCompressionSampling = 10
+
+	// receiverCompressionSampling is the same for a receiver, which
+	// measures every batch because the decoded data is at hand.
+	receiverCompressionSampling = 1
+
 	scopeName = "github.com/f5/otel-arrow-adapter/collector/netstats"
 )
 
@@ -62,6 +89,17 @@ type NetworkReporter struct {
 	sentWireBytes metric.Int64Counter
 	recvBytes     metric.Int64Counter
 	recvWireBytes metric.Int64Counter
+
+	// Total of the bytes sent on the wire, see SentWireBytes().
+	sentWireTotal atomic.Int64
+
+	// Compression accounting, at the detailed level only.
+	component    attribute.KeyValue
+	otlpBytes    metric.Int64Counter
+	arrowBytes   metric.Int64Counter
+	sampleEvery  uint64
+	sampleCount  uint64
+	signalsAttrs map[string][]metric.AddOption
 }
 
 // SizesStruct is used to pass uncompressed on-wire message lengths to
@@ -71,12 +109,25 @@ type SizesStruct struct {
 	WireLength int64
 }
 
+// CompressionStruct is used to pass the sizes of a sampled batch to
+// the CountCompression() method.
+type CompressionStruct struct {
+	// Signal is one of "traces", "logs" or "metrics", see OTLPSize().
+	Signal string
+	// OTLPLength is the size of the batch encoded as OTLP protobuf.
+	OTLPLength int64
+	// ArrowLength is the size of the batch encoded as OTLP Arrow.
+	ArrowLength int64
+}
+
 const (
 	bytesUnit           = "bytes"
 	sentDescription     = "Number of bytes sent by the component."
 	sentWireDescription = "Number of bytes sent on the wire by the component."
 	recvDescription     = "Number of bytes received by the component."
 	recvWireDescription = "Number of bytes received on the wire by the component."
+	otlpDescription     = "Number of bytes of a sample of the batches, encoded as OTLP."
+	arrowDescription    = "Number of bytes of a sample of the batches, encoded as OTLP Arrow."
 )
 
 // makeSentMetrics builds the sent and sent-wire metric instruments
@@ -95,6 +146,23 @@ func makeRecvMetrics(prefix string, meter metric.Meter) (recv, recvWire metric.I
 	return recvBytes, recvWireBytes, multierr.Append(err1, err2)
 }
 
+// makeCompressionMetrics builds the OTLP and OTLP Arrow metric
+// instruments used for the compression accounting of an exporter or
+// receiver, using the corresponding `prefix` and direction.
+func (rep *NetworkReporter) makeCompressionMetrics(prefix string, meter metric.Meter, sampleEvery uint64) error {
+	var err1, err2 error
+	rep.otlpBytes, err1 = meter.Int64Counter(prefix+"_"+OTLPBytes, metric.WithDescription(otlpDescription), metric.WithUnit(bytesUnit))
+	rep.arrowBytes, err2 = meter.Int64Counter(prefix+"_"+ArrowBytes, metric.WithDescription(arrowDescription), metric.WithUnit(bytesUnit))
+	rep.sampleEvery = sampleEvery
+	rep.signalsAttrs = map[string][]metric.AddOption{}
+	for _, signal := range []string{tracesSignal, logsSignal, metricsSignal} {
+		rep.signalsAttrs[signal] = []metric.AddOption{
+			metric.WithAttributes(rep.component, attribute.String(SignalKey, signal)),
+		}
+	}
+	return multierr.Append(err1, err2)
+}
+
 // NewExporterNetworkReporter creates a new NetworkReporter configured for an exporter.
 func NewExporterNetworkReporter(settings exporter.CreateSettings) (*NetworkReporter, error) {
 	level := settings.TelemetrySettings.MetricsLevel
@@ -109,6 +177,7 @@ func NewExporterNetworkReporter(settings exporter.CreateSettings) (*NetworkRepor
 		attrs: []metric.AddOption{
 			metric.WithAttributes(attribute.String(ExporterKey, settings.ID.String())),
 		},
+		component: attribute.String(ExporterKey, settings.ID.String()),
 	}
 
 	var errors, err error
@@ -120,6 +189,7 @@ func NewExporterNetworkReporter(settings exporter.CreateSettings) (*NetworkRepor
 	if level > configtelemetry.LevelNormal {
 		rep.recvBytes, rep.recvWireBytes, err = makeRecvMetrics(ExporterKey, meter)
 		errors = multierr.Append(errors, err)
+		errors = multierr.Append(errors, rep.makeCompressionMetrics(ExporterKey+"_"+SentBytes, meter, exporterCompressionSampling))
 	}
 
 	return rep, errors
@@ -139,6 +209,7 @@ func NewReceiverNetworkReporter(settings receiver.CreateSettings) (*NetworkRepor
 		attrs: []metric.AddOption{
 			metric.WithAttributes(attribute.String(ReceiverKey, settings.ID.String())),
 		},
+		component: attribute.String(ReceiverKey, settings.ID.String()),
 	}
 
 	var errors, err error
@@ -150,6 +221,7 @@ func NewReceiverNetworkReporter(settings receiver.CreateSettings) (*NetworkRepor
 	if level > configtelemetry.LevelNormal {
 		rep.sentBytes, rep.sentWireBytes, err = makeSentMetrics(ReceiverKey, meter)
 		errors = multierr.Append(errors, err)
+		errors = multierr.Append(errors, rep.makeCompressionMetrics(ReceiverKey+"_"+RecvBytes, meter, receiverCompressionSampling))
 	}
 
 	return rep, errors
@@ -169,7 +241,18 @@ func (rep *NetworkReporter) CountSend(ctx context.Context, ss SizesStruct) {
 	}
 	if rep.sentWireBytes != nil && ss.WireLength > 0 {
 		rep.sentWireBytes.Add(ctx, ss.WireLength, rep.attrs...)
+		rep.sentWireTotal.Add(ss.WireLength)
+	}
+}
+
+// SentWireBytes returns the total number of bytes sent on the wire by
+// the component, which is only counted when sent-wire bytes are
+// reported (see CountSend()).
+func (rep *NetworkReporter) SentWireBytes() int64 {
+	if rep == nil {
+		return 0
 	}
+	return rep.sentWireTotal.Load()
 }
 
 // CountReceive is used to report a message received by the component.  For
@@ -188,3 +271,57 @@ func (rep *NetworkReporter) CountReceive(ctx context.Context, ss SizesStruct) {
 		rep.recvWireBytes.Add(ctx, ss.WireLength, rep.attrs...)
 	}
 }
+
+// SampleCompression returns true when the caller should compute the
+// sizes of the current batch and pass them to CountCompression().
+// This is false unless the telemetry level is detailed, and exporters
+// only sample a fraction of their batches to bound the CPU cost of
+// computing the OTLP size.
+func (rep *NetworkReporter) SampleCompression() bool {
+	if rep == nil || rep.otlpBytes == nil {
+		return false
+	}
+	return atomic.AddUint64(&rep.sampleCount, 1)%rep.sampleEvery == 0
+}
+
+// CountCompression is used to report the OTLP and OTLP Arrow sizes of
+// a batch sampled by SampleCompression().  For exporters, these are the
+// sizes of a request sent.  For receivers, these are the sizes of a
+// request received.
+func (rep *NetworkReporter) CountCompression(ctx context.Context, cs CompressionStruct) {
+	if rep == nil || rep.otlpBytes == nil {
+		return
+	}
+
+	attrs, ok := rep.signalsAttrs[cs.Signal]
+	if !ok {
+		return
+	}
+	rep.otlpBytes.Add(ctx, cs.OTLPLength, attrs...)
+	rep.arrowBytes.Add(ctx, cs.ArrowLength, attrs...)
+}
+
+const (
+	tracesSignal  = "traces"
+	logsSignal    = "logs"
+	metricsSignal = "metrics"
+)
+
+// OTLPSize returns the signal and the size of the OTLP protobuf
+// encoding of data, which is a ptrace.Traces, a plog.Logs or a
+// pmetric.Metrics.  The size is computed without marshaling the data.
+// An empty signal is returned for other types.
+func OTLPSize(data interface{}) (signal string, size int64) {
+	switch d := data.(type) {
+	case ptrace.Traces:
+		var sizer ptrace.ProtoMarshaler
+		return tracesSignal, int64(sizer.TracesSize(d))
+	case plog.Logs:
+		var sizer plog.ProtoMarshaler
+		return logsSignal, int64(sizer.LogsSize(d))
+	case pmetric.Metrics:
+		var sizer pmetric.ProtoMarshaler
+		return metricsSignal, int64(sizer.MetricsSize(d))
+	}
+	return "", 0
+}
@@ -26,6 +26,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/receiver"
 )
 
@@ -95,6 +96,10 @@ func testNetStatsExporter(t *testing.T, level configtelemetry.Level, expect map[
 	require.NoError(t, err)
 
 	require.Equal(t, expect, metricValues(rm))
+
+	// The sent-wire bytes are only accumulated when they are reported.
+	sentWire, _ := expect["exporter_sent_wire"].(int64)
+	require.Equal(t, sentWire, enr.SentWireBytes())
 }
 
 func TestNetStatsReceiverNone(t *testing.T) {
@@ -150,3 +155,62 @@ func testNetStatsReceiver(t *testing.T, level configtelemetry.Level, expect map[
 
 	require.Equal(t, expect, metricValues(rm))
 }
+
+func TestNetStatsCompression(t *testing.T) {
+	rdr := metric.NewManualReader()
+	mp := metric.NewMeterProvider(
+		metric.WithResource(resource.Empty()),
+		metric.WithReader(rdr),
+	)
+	enr, err := NewExporterNetworkReporter(exporter.CreateSettings{
+		ID: component.NewID("test"),
+		TelemetrySettings: component.TelemetrySettings{
+			MeterProvider: mp,
+			MetricsLevel:  configtelemetry.LevelDetailed,
+		},
+	})
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+	signal, size := OTLPSize(td)
+	require.Equal(t, "traces", signal)
+	require.Greater(t, size, int64(0))
+
+	ctx := context.Background()
+	sampled := 0
+	for i := 0; i < 10*exporterCompressionSampling; i++ {
+		if !enr.SampleCompression() {
+			continue
+		}
+		sampled++
+		enr.CountCompression(ctx, CompressionStruct{
+			Signal:      signal,
+			OTLPLength:  size,
+			ArrowLength: 10,
+		})
+	}
+	require.Equal(t, 10, sampled)
+
+	var rm metricdata.ResourceMetrics
+	err = rdr.Collect(ctx, &rm)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"exporter_sent_otlp":  10 * size,
+		"exporter_sent_arrow": int64(100),
+	}, metricValues(rm))
+
+	// Not sampled below the detailed level.
+	enr, err = NewExporterNetworkReporter(exporter.CreateSettings{
+		ID: component.NewID("test"),
+		TelemetrySettings: component.TelemetrySettings{
+			MeterProvider: mp,
+			MetricsLevel:  configtelemetry.LevelNormal,
+		},
+	})
+	require.NoError(t, err)
+	for i := 0; i < 10*exporterCompressionSampling; i++ {
+		require.False(t, enr.SampleCompression())
+	}
+}
@@ -34,6 +34,11 @@ type ArrowSettings struct {
 
 	// DisableMixedSignals when true prevents mixed-signal gRPC being served.
 	DisableMixedSignals bool `mapstructure:"disable_mixed_signals"`
+
+	// DictionaryResetThresholdMiB is the amount of memory used by a
+	// sub-stream above which the receiver asks the exporter to reset the
+	// dictionaries of this sub-stream.  Zero disables dictionary resets.
+	DictionaryResetThresholdMiB uint64 `mapstructure:"dictionary_reset_threshold_mib"`
 }
 
 // Config defines configuration for OTLP receiver.
@@ -129,7 +129,8 @@ func TestUnmarshalConfig(t *testing.T) {
 					},
 				},
 				Arrow: &ArrowSettings{
-					Disabled: false,
+					Disabled:                    false,
+					DictionaryResetThresholdMiB: 64,
 				},
 			},
 		}, cfg)
@@ -20,6 +20,9 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -27,9 +30,13 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	arrowpb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	"github.com/f5/otel-arrow-adapter/collector/gen/internal/arrowz"
+	"github.com/f5/otel-arrow-adapter/collector/gen/internal/netstats"
 	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
 
 	"go.opentelemetry.io/collector/client"
@@ -69,10 +76,27 @@ type Receiver struct {
 	arrowpb.UnsafeArrowMetricsServiceServer
 
 	telemetry   component.TelemetrySettings
+	netReporter *netstats.NetworkReporter
+	streamTel   *streamTelemetry
 	obsrecv     *obsreport.Receiver
 	gsettings   *configgrpc.GRPCServerSettings
 	authServer  auth.Server
 	newConsumer func() arrowRecord.ConsumerAPI
+
+	// sessionsLock protects sessions, the set of active streams
+	// reported by Streams().
+	sessionsLock sync.Mutex
+	sessions     map[*streamSession]struct{}
+}
+
+// streamSession is the state of an active stream reported by
+// Streams(), the counters are updated atomically by the stream.
+type streamSession struct {
+	peer     string
+	started  time.Time
+	batches  uint64
+	bytes    uint64
+	consumer arrowRecord.ConsumerAPI
 }
 
 // New creates a new Receiver reference.
@@ -83,15 +107,41 @@ func New(
 	gsettings *configgrpc.GRPCServerSettings,
 	authServer auth.Server,
 	newConsumer func() arrowRecord.ConsumerAPI,
-) *Receiver {
+	netReporter *netstats.NetworkReporter,
+) (*Receiver, error) {
+	streamTel, err := newStreamTelemetry(set)
+	if err != nil {
+		return nil, err
+	}
 	return &Receiver{
 		Consumers:   cs,
 		obsrecv:     obsrecv,
 		telemetry:   set.TelemetrySettings,
+		netReporter: netReporter,
+		streamTel:   streamTel,
 		authServer:  authServer,
 		newConsumer: newConsumer,
 		gsettings:   gsettings,
+		sessions:    map[*streamSession]struct{}{},
+	}, nil
+}
+
+// Streams returns a snapshot of the active streams (see arrowz).
+func (r *Receiver) Streams() []arrowz.StreamInfo {
+	r.sessionsLock.Lock()
+	defer r.sessionsLock.Unlock()
+
+	infos := make([]arrowz.StreamInfo, 0, len(r.sessions))
+	for session := range r.sessions {
+		infos = append(infos, arrowz.StreamInfo{
+			Peer:       session.peer,
+			Started:    session.started,
+			Batches:    atomic.LoadUint64(&session.batches),
+			Bytes:      atomic.LoadUint64(&session.bytes),
+			SubStreams: session.consumer.SubStreams(),
+		})
 	}
+	return infos
 }
 
 // headerReceiver contains the state necessary to decode per-request metadata
@@ -289,6 +339,24 @@ func (r *Receiver) anyStream(serverStream anyStreamServer) error {
 		}
 	}()
 
+	r.streamTel.streamStarted(streamCtx)
+
+	session := &streamSession{
+		started:  time.Now(),
+		consumer: ac,
+	}
+	if p, ok := peer.FromContext(streamCtx); ok && p.Addr != nil {
+		session.peer = p.Addr.String()
+	}
+	r.sessionsLock.Lock()
+	r.sessions[session] = struct{}{}
+	r.sessionsLock.Unlock()
+	defer func() {
+		r.sessionsLock.Lock()
+		delete(r.sessions, session)
+		r.sessionsLock.Unlock()
+	}()
+
 	for {
 		// Receive a batch corresponding with one ptrace.Traces, pmetric.Metrics,
 		// or plog.Logs item.
@@ -296,14 +364,23 @@ func (r *Receiver) anyStream(serverStream anyStreamServer) error {
 
 		if err != nil {
 			r.logStreamError(err)
+			r.streamTel.streamFinished(err)
 			return err
 		}
 
+		var size int
+		for _, payload := range req.GetArrowPayloads() {
+			size += len(payload.Record)
+		}
+		atomic.AddUint64(&session.batches, 1)
+		atomic.AddUint64(&session.bytes, uint64(size))
+
 		// Check for optional headers and set the incoming context.
 		thisCtx, authHdrs, err := hrcv.combineHeaders(streamCtx, req.GetHeaders())
 		if err != nil {
 			// Failing to parse the incoming headers breaks the stream.
 			r.telemetry.Logger.Error("arrow metadata error", zap.Error(err))
+			r.streamTel.streamFinished(err)
 			return err
 		}
 
@@ -347,9 +424,21 @@ func (r *Receiver) anyStream(serverStream anyStreamServer) error {
 		}
 		resp.Statuses = append(resp.Statuses, status)
 
+		// Ask the exporter to reset the sub-streams using too much
+		// memory, if any.
+		if payloadTypes := ac.StreamsToReset(); len(payloadTypes) != 0 {
+			r.telemetry.Logger.Debug("arrow dictionary reset requested",
+				zap.Int("payload_types", len(payloadTypes)),
+			)
+			resp.DictionaryReset = &arrowpb.DictionaryReset{
+				PayloadTypes: payloadTypes,
+			}
+		}
+
 		err = serverStream.Send(resp)
 		if err != nil {
 			r.logStreamError(err)
+			r.streamTel.streamFinished(err)
 			return err
 		}
 	}
@@ -372,10 +461,13 @@ func (r *Receiver) processRecords(ctx context.Context, arrowConsumer arrowRecord
 		var numPts int
 		ctx = r.obsrecv.StartMetricsOp(ctx)
 
+		start := time.Now()
 		otlp, err := arrowConsumer.MetricsFrom(records)
+		r.streamTel.batchDecoded(ctx, "metrics", time.Since(start))
 		if err != nil {
 			err = consumererror.NewPermanent(err)
 		} else {
+			countCompression(ctx, r.netReporter, records, otlp)
 			for _, metrics := range otlp {
 				numPts += metrics.DataPointCount()
 				err = multierr.Append(err,
@@ -393,10 +485,13 @@ func (r *Receiver) processRecords(ctx context.Context, arrowConsumer arrowRecord
 		var numLogs int
 		ctx = r.obsrecv.StartLogsOp(ctx)
 
+		start := time.Now()
 		otlp, err := arrowConsumer.LogsFrom(records)
+		r.streamTel.batchDecoded(ctx, "logs", time.Since(start))
 		if err != nil {
 			err = consumererror.NewPermanent(err)
 		} else {
+			countCompression(ctx, r.netReporter, records, otlp)
 			for _, logs := range otlp {
 				numLogs += logs.LogRecordCount()
 				err = multierr.Append(err,
@@ -414,10 +509,13 @@ func (r *Receiver) processRecords(ctx context.Context, arrowConsumer arrowRecord
 		var numSpans int
 		ctx = r.obsrecv.StartTracesOp(ctx)
 
+		start := time.Now()
 		otlp, err := arrowConsumer.TracesFrom(records)
+		r.streamTel.batchDecoded(ctx, "traces", time.Since(start))
 		if err != nil {
 			err = consumererror.NewPermanent(err)
 		} else {
+			countCompression(ctx, r.netReporter, records, otlp)
 			for _, traces := range otlp {
 				numSpans += traces.SpanCount()
 				err = multierr.Append(err,
@@ -432,3 +530,22 @@ func (r *Receiver) processRecords(ctx context.Context, arrowConsumer arrowRecord
 		return ErrUnrecognizedPayload
 	}
 }
+
+// countCompression reports the OTLP size of the data decoded from a
+// batch relative to the size of the batch, when sampled by the network
+// reporter.  This is called before the data is consumed, because the
+// consumers are allowed to modify it.
+func countCompression[T any](ctx context.Context, rep *netstats.NetworkReporter, records *arrowpb.BatchArrowRecords, otlp []T) {
+	if !rep.SampleCompression() {
+		return
+	}
+	cs := netstats.CompressionStruct{
+		ArrowLength: int64(proto.Size(records)),
+	}
+	for _, data := range otlp {
+		signal, size := netstats.OTLPSize(data)
+		cs.Signal = signal
+		cs.OTLPLength += size
+	}
+	rep.CountCompression(ctx, cs)
+}
@@ -308,6 +308,7 @@ func (ctc *commonTestCase) newRealConsumer() arrowRecord.ConsumerAPI {
 	mock.EXPECT().TracesFrom(gomock.Any()).AnyTimes().DoAndReturn(cons.TracesFrom)
 	mock.EXPECT().MetricsFrom(gomock.Any()).AnyTimes().DoAndReturn(cons.MetricsFrom)
 	mock.EXPECT().LogsFrom(gomock.Any()).AnyTimes().DoAndReturn(cons.LogsFrom)
+	mock.EXPECT().StreamsToReset().AnyTimes().DoAndReturn(cons.StreamsToReset)
 
 	return mock
 }
@@ -319,6 +320,7 @@ func (ctc *commonTestCase) newErrorConsumer() arrowRecord.ConsumerAPI {
 	mock.EXPECT().TracesFrom(gomock.Any()).AnyTimes().Return(nil, fmt.Errorf("test invalid error"))
 	mock.EXPECT().MetricsFrom(gomock.Any()).AnyTimes().Return(nil, fmt.Errorf("test invalid error"))
 	mock.EXPECT().LogsFrom(gomock.Any()).AnyTimes().Return(nil, fmt.Errorf("test invalid error"))
+	mock.EXPECT().StreamsToReset().AnyTimes().Return(nil)
 
 	return mock
 }
@@ -340,14 +342,16 @@ func (ctc *commonTestCase) start(newConsumer func() arrowRecord.ConsumerAPI, opt
 	})
 	require.NoError(ctc.T, err)
 
-	rcvr := New(
+	rcvr, err := New(
 		ctc.consumers,
 		rc,
 		obsrecv,
 		gsettings,
 		authServer,
 		newConsumer,
+		nil,
 	)
+	require.NoError(ctc.T, err)
 	go func() {
 		ctc.streamErr <- rcvr.ArrowStream(ctc.stream)
 	}()
@@ -417,6 +421,39 @@ func TestReceiverMetrics(t *testing.T) {
 	require.True(t, errors.Is(err, context.Canceled), "for %v", err)
 }
 
+func TestReceiverDictionaryReset(t *testing.T) {
+	tc := healthyTestChannel{}
+	ctc := newCommonTestCase(t, tc)
+
+	td := testdata.GenerateTraces(2)
+	batch, err := ctc.testProducer.BatchArrowRecordsFromTraces(td)
+	require.NoError(t, err)
+
+	status := statusOKFor(batch.BatchId)
+	status.DictionaryReset = &arrowpb.DictionaryReset{
+		PayloadTypes: []arrowpb.ArrowPayloadType{arrowpb.ArrowPayloadType_SPANS},
+	}
+	ctc.stream.EXPECT().Send(status).Times(1).Return(nil)
+
+	ctc.start(func() arrowRecord.ConsumerAPI {
+		mock := arrowRecordMock.NewMockConsumerAPI(ctc.ctrl)
+		cons := arrowRecord.NewConsumer()
+
+		mock.EXPECT().Close().Times(1).Return(nil)
+		mock.EXPECT().TracesFrom(gomock.Any()).Times(1).DoAndReturn(cons.TracesFrom)
+		mock.EXPECT().StreamsToReset().Times(1).Return([]arrowpb.ArrowPayloadType{arrowpb.ArrowPayloadType_SPANS})
+
+		return mock
+	})
+	ctc.putBatch(batch, nil)
+
+	assert.EqualValues(t, td, (<-ctc.consume).Data)
+
+	err = ctc.cancelAndWait()
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.Canceled))
+}
+
 func TestReceiverRecvError(t *testing.T) {
 	tc := healthyTestChannel{}
 	ctc := newCommonTestCase(t, tc)
@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow // import "github.com/f5/otel-arrow-adapter/collector/gen/receiver/otlpreceiver/internal/arrow"
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/multierr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/f5/otel-arrow-adapter/collector/gen/internal/netstats"
+
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/receiver"
+)
+
+const (
+	// ReasonKey is an attribute name that identifies why a stream
+	// ended, one of the end* values below.
+	ReasonKey = "reason"
+
+	scopeName = "github.com/f5/otel-arrow-adapter/collector/gen/receiver/otlpreceiver/internal/arrow"
+	prefix    = netstats.ReceiverKey + "_arrow_"
+)
+
+// Reasons of a stream end, following the error of the stream.
+const (
+	// endCanceled: the stream was canceled.
+	endCanceled = "canceled"
+	// endEOF: the exporter closed the stream.
+	endEOF = "eof"
+	// endUnknown: any other error.
+	endUnknown = "unknown"
+)
+
+// streamTelemetry reports the lifecycle of the Arrow streams and the
+// latency of the batches.  A nil streamTelemetry is valid and reports
+// nothing, this is the case at the basic level of telemetry.
+type streamTelemetry struct {
+	component attribute.KeyValue
+
+	activeStreams metric.Int64UpDownCounter
+	streamEnds    metric.Int64Counter
+	decodeTime    metric.Float64Histogram
+}
+
+// newStreamTelemetry creates the stream instruments of a receiver.
+func newStreamTelemetry(settings receiver.CreateSettings) (*streamTelemetry, error) {
+	if settings.TelemetrySettings.MetricsLevel <= configtelemetry.LevelBasic {
+		return nil, nil
+	}
+
+	meter := settings.TelemetrySettings.MeterProvider.Meter(scopeName)
+	st := &streamTelemetry{
+		component: attribute.String(netstats.ReceiverKey, settings.ID.String()),
+	}
+
+	var errors, err error
+	st.activeStreams, err = meter.Int64UpDownCounter(prefix+"streams_active", metric.WithDescription("Number of active Arrow streams."))
+	errors = multierr.Append(errors, err)
+	st.streamEnds, err = meter.Int64Counter(prefix+"stream_ends", metric.WithDescription("Number of Arrow streams ended, by reason."))
+	errors = multierr.Append(errors, err)
+	st.decodeTime, err = meter.Float64Histogram(prefix+"decode_time", metric.WithDescription("Time spent decoding a batch."), metric.WithUnit("s"))
+	errors = multierr.Append(errors, err)
+	if errors != nil {
+		return nil, errors
+	}
+	return st, nil
+}
+
+// streamStarted is called when a stream is accepted.
+func (st *streamTelemetry) streamStarted(ctx context.Context) {
+	if st == nil {
+		return
+	}
+	st.activeStreams.Add(ctx, 1, metric.WithAttributes(st.component))
+}
+
+// streamFinished is called when a stream returns with an error.
+func (st *streamTelemetry) streamFinished(err error) {
+	if st == nil {
+		return
+	}
+	// Note: the stream context is usually canceled, the SDK would
+	// drop a measurement made with it.
+	ctx := context.Background()
+	st.activeStreams.Add(ctx, -1, metric.WithAttributes(st.component))
+	st.streamEnds.Add(ctx, 1, metric.WithAttributes(st.component, attribute.String(ReasonKey, endReason(err))))
+}
+
+// batchDecoded reports the time spent decoding a batch.
+func (st *streamTelemetry) batchDecoded(ctx context.Context, signal string, elapsed time.Duration) {
+	if st == nil {
+		return
+	}
+	st.decodeTime.Record(ctx, elapsed.Seconds(), metric.WithAttributes(st.component, attribute.String(netstats.SignalKey, signal)))
+}
+
+// endReason classifies the error of a stream the same way as
+// logStreamError().
+func endReason(err error) string {
+	if status, ok := status.FromError(err); ok {
+		if status.Code() == codes.Canceled {
+			return endCanceled
+		}
+		return endUnknown
+	}
+	switch {
+	case errors.Is(err, io.EOF):
+		return endEOF
+	case errors.Is(err, context.Canceled):
+		return endCanceled
+	}
+	return endUnknown
+}
@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"go.opentelemetry.io/collector/config/configtelemetry"
+
+	"github.com/f5/otel-arrow-adapter/collector/gen/internal/testdata"
+)
+
+// TestReceiverTelemetry verifies the lifecycle and latency
+// instruments of a stream receiving one batch.
+func TestReceiverTelemetry(t *testing.T) {
+	rdr := metric.NewManualReader()
+	mp := metric.NewMeterProvider(
+		metric.WithResource(resource.Empty()),
+		metric.WithReader(rdr),
+	)
+
+	ctc := newCommonTestCase(t, healthyTestChannel{})
+	ctc.telset.MeterProvider = mp
+	ctc.telset.MetricsLevel = configtelemetry.LevelNormal
+
+	batch, err := ctc.testProducer.BatchArrowRecordsFromTraces(testdata.GenerateTraces(2))
+	require.NoError(t, err)
+
+	ctc.stream.EXPECT().Send(statusOKFor(batch.BatchId)).Times(1).Return(nil)
+
+	ctc.start(ctc.newRealConsumer)
+	ctc.putBatch(batch, nil)
+	<-ctc.consume
+
+	ctx := context.Background()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, rdr.Collect(ctx, &rm))
+	require.Equal(t, map[string]int64{"receiver_arrow_streams_active": 1}, sums(rm))
+	require.Equal(t, map[string]uint64{"receiver_arrow_decode_time/traces": 1}, histogramCounts(rm))
+
+	err = ctc.cancelAndWait()
+	require.True(t, errors.Is(err, context.Canceled))
+
+	require.NoError(t, rdr.Collect(ctx, &rm))
+	require.Equal(t, map[string]int64{
+		"receiver_arrow_streams_active":             0,
+		"receiver_arrow_stream_ends/" + endCanceled: 1,
+	}, sums(rm))
+}
+
+// sums returns the value of the Sum data points by metric name, and
+// reason when present.
+func sums(rm metricdata.ResourceMetrics) map[string]int64 {
+	res := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			data, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range data.DataPoints {
+				name := m.Name
+				if reason, ok := dp.Attributes.Value(ReasonKey); ok {
+					name += "/" + reason.AsString()
+				}
+				res[name] = dp.Value
+			}
+		}
+	}
+	return res
+}
+
+// histogramCounts returns the count of the Histogram data points by
+// metric name and signal.
+func histogramCounts(rm metricdata.ResourceMetrics) map[string]uint64 {
+	res := map[string]uint64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			data, ok := m.Data.(metricdata.Histogram[float64])
+			if !ok {
+				continue
+			}
+			for _, dp := range data.DataPoints {
+				signal, _ := dp.Attributes.Value("signal")
+				res[m.Name+"/"+signal.AsString()] = dp.Count
+			}
+		}
+	}
+	return res
+}
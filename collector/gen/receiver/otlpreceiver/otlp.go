@@ -21,7 +21,9 @@ import (
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/extension/auth"
+	"github.com/f5/otel-arrow-adapter/collector/gen/internal/arrowz"
 	"github.com/f5/otel-arrow-adapter/collector/gen/internal/netstats"
+	"github.com/f5/otel-arrow-adapter/collector/internal/testlistener"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
@@ -46,6 +48,9 @@ type otlpReceiver struct {
 	arrowReceiver   *arrow.Receiver
 	shutdownWG      sync.WaitGroup
 
+	// unregisterArrowz removes the Arrow streams from the arrowz page.
+	unregisterArrowz func()
+
 	obsrepGRPC *obsreport.Receiver
 	obsrepHTTP *obsreport.Receiver
 	netStats   *netstats.NetworkReporter
@@ -90,12 +95,16 @@ func newOtlpReceiver(cfg *Config, set receiver.CreateSettings) (*otlpReceiver, e
 	return r, nil
 }
 
-func (r *otlpReceiver) startGRPCServer(cfg *configgrpc.GRPCServerSettings, host component.Host) error {
+func (r *otlpReceiver) startGRPCServer(ctx context.Context, cfg *configgrpc.GRPCServerSettings, host component.Host) error {
 	r.settings.Logger.Info("Starting GRPC server", zap.String("endpoint", cfg.NetAddr.Endpoint))
 
-	gln, err := cfg.ToListener()
-	if err != nil {
-		return err
+	// Tests and benchmarks may serve the receiver on their own listener.
+	gln := testlistener.FromContext(ctx)
+	if gln == nil {
+		var err error
+		if gln, err = cfg.ToListener(); err != nil {
+			return err
+		}
 	}
 	r.shutdownWG.Add(1)
 	go func() {
@@ -126,7 +135,7 @@ func (r *otlpReceiver) startHTTPServer(cfg *confighttp.HTTPServerSettings, host
 	return nil
 }
 
-func (r *otlpReceiver) startProtocolServers(host component.Host) error {
+func (r *otlpReceiver) startProtocolServers(ctx context.Context, host component.Host) error {
 	var err error
 	if r.cfg.GRPC != nil {
 		var serverOpts []grpc.ServerOption
@@ -148,9 +157,14 @@ func (r *otlpReceiver) startProtocolServers(host component.Host) error {
 				}
 			}
 
-			r.arrowReceiver = arrow.New(arrow.Consumers(r), r.settings, r.obsrepGRPC, r.cfg.GRPC, authServer, func() arrowRecord.ConsumerAPI {
-				return arrowRecord.NewConsumer()
-			})
+			resetThreshold := r.cfg.Arrow.DictionaryResetThresholdMiB << 20
+			r.arrowReceiver, err = arrow.New(arrow.Consumers(r), r.settings, r.obsrepGRPC, r.cfg.GRPC, authServer, func() arrowRecord.ConsumerAPI {
+				return arrowRecord.NewConsumer(arrowRecord.WithStreamResetThreshold(resetThreshold))
+			}, r.netStats)
+			if err != nil {
+				return err
+			}
+			r.unregisterArrowz = arrowz.Register(arrowz.ReceiverKind, r.settings.ID.String(), r.arrowReceiver)
 
 			if !r.cfg.Arrow.DisableMixedSignals {
 				arrowpb.RegisterArrowStreamServiceServer(r.serverGRPC, r.arrowReceiver)
@@ -181,7 +195,7 @@ func (r *otlpReceiver) startProtocolServers(host component.Host) error {
 			}
 		}
 
-		err = r.startGRPCServer(r.cfg.GRPC, host)
+		err = r.startGRPCServer(ctx, r.cfg.GRPC, host)
 		if err != nil {
 			return err
 		}
@@ -208,14 +222,18 @@ func (r *otlpReceiver) startProtocolServers(host component.Host) error {
 
 // Start runs the trace receiver on the gRPC server. Currently
 // it also enables the metrics receiver too.
-func (r *otlpReceiver) Start(_ context.Context, host component.Host) error {
-	return r.startProtocolServers(host)
+func (r *otlpReceiver) Start(ctx context.Context, host component.Host) error {
+	return r.startProtocolServers(ctx, host)
 }
 
 // Shutdown is a method to turn off receiving.
 func (r *otlpReceiver) Shutdown(ctx context.Context) error {
 	var err error
 
+	if r.unregisterArrowz != nil {
+		r.unregisterArrowz()
+	}
+
 	if r.serverHTTP != nil {
 		err = r.serverHTTP.Shutdown(ctx)
 	}
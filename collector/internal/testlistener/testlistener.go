@@ -0,0 +1,37 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package testlistener passes a listener (e.g. an in-memory bufconn
+// listener) to the gRPC server of a receiver through the context of its
+// Start method, so that tests and benchmarks can serve a receiver without
+// exposing the listener in its configuration.
+package testlistener // import "github.com/f5/otel-arrow-adapter/collector/internal/testlistener"
+
+import (
+	"context"
+	"net"
+)
+
+type listenerKey struct{}
+
+// NewContext returns a context carrying the gRPC listener.
+func NewContext(ctx context.Context, listener net.Listener) context.Context {
+	return context.WithValue(ctx, listenerKey{}, listener)
+}
+
+// FromContext returns the gRPC listener of the context, or nil.
+func FromContext(ctx context.Context) net.Listener {
+	listener, _ := ctx.Value(listenerKey{}).(net.Listener)
+	return listener
+}
@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowbatchprocessor // import "github.com/f5/otel-arrow-adapter/collector/processor/arrowbatchprocessor"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// maxItemsLimit is the maximum number of items of a batch, the items
+// of an OTLP Arrow record are identified by uint16 IDs.
+const maxItemsLimit = math.MaxUint16
+
+var (
+	errInvalidTargetBytes = errors.New("the target size must be positive")
+	errInvalidMaxItems    = errors.New("the maximum number of items must be in [1, 65535]")
+	errInvalidTimeout     = errors.New("the timeout must be positive")
+)
+
+// Config defines the configuration for the Arrow batch processor.
+type Config struct {
+	// TargetBytes is the estimated size of the encoded OTLP Arrow
+	// batch (BatchArrowRecords) triggering a flush.  Defaults to
+	// 1MiB, to stay under the default 4MiB gRPC message limit.
+	TargetBytes uint64 `mapstructure:"target_bytes"`
+
+	// MaxItems is the maximum number of items (spans, data points or
+	// log records) of a batch, in [1, 65535].  Defaults to 65535.
+	MaxItems int `mapstructure:"max_items"`
+
+	// Timeout is the time after which a batch is flushed regardless
+	// of its size.  Defaults to 200ms.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid.
+func (c *Config) Validate() error {
+	if c.TargetBytes == 0 {
+		return fmt.Errorf("invalid target size %d: %w", c.TargetBytes, errInvalidTargetBytes)
+	}
+	if c.MaxItems < 1 || c.MaxItems > maxItemsLimit {
+		return fmt.Errorf("invalid maximum number of items %d: %w", c.MaxItems, errInvalidMaxItems)
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("invalid timeout %v: %w", c.Timeout, errInvalidTimeout)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowbatchprocessor // import "github.com/f5/otel-arrow-adapter/collector/processor/arrowbatchprocessor"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "arrowbatch"
+	// The stability level of the processor.
+	stability = component.StabilityLevelDevelopment
+)
+
+// NewFactory creates a factory for the Arrow batch processor.
+func NewFactory() processor.Factory {
+	return processor.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		processor.WithTraces(createTracesProcessor, stability),
+		processor.WithMetrics(createMetricsProcessor, stability),
+		processor.WithLogs(createLogsProcessor, stability),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		TargetBytes: 1 << 20,
+		MaxItems:    maxItemsLimit,
+		Timeout:     200 * time.Millisecond,
+	}
+}
+
+func createTracesProcessor(_ context.Context, params processor.CreateSettings, cfg component.Config, nextConsumer consumer.Traces) (processor.Traces, error) {
+	p, err := newBatchProcessor[ptrace.Traces](params, cfg, tracesSignal{next: nextConsumer}, "traces")
+	if err != nil {
+		return nil, err
+	}
+	return &tracesProcessor{p}, nil
+}
+
+func createMetricsProcessor(_ context.Context, params processor.CreateSettings, cfg component.Config, nextConsumer consumer.Metrics) (processor.Metrics, error) {
+	p, err := newBatchProcessor[pmetric.Metrics](params, cfg, metricsSignal{next: nextConsumer}, "metrics")
+	if err != nil {
+		return nil, err
+	}
+	return &metricsProcessor{p}, nil
+}
+
+func createLogsProcessor(_ context.Context, params processor.CreateSettings, cfg component.Config, nextConsumer consumer.Logs) (processor.Logs, error) {
+	p, err := newBatchProcessor[plog.Logs](params, cfg, logsSignal{next: nextConsumer}, "logs")
+	if err != nil {
+		return nil, err
+	}
+	return &logsProcessor{p}, nil
+}
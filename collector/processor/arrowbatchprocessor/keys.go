@@ -0,0 +1,33 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowbatchprocessor // import "github.com/f5/otel-arrow-adapter/collector/processor/arrowbatchprocessor"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// resourceKey identifies the resources with the same attributes, the
+// items of these resources are co-batched.
+func resourceKey(resource pcommon.Resource, schemaURL string) string {
+	return fmt.Sprintf("%q|%d|%#v", schemaURL, resource.DroppedAttributesCount(), resource.Attributes().AsRaw())
+}
+
+// scopeKey identifies the scopes with the same name, version and
+// attributes within a resource.
+func scopeKey(resourceKey string, scope pcommon.InstrumentationScope, schemaURL string) string {
+	return fmt.Sprintf("%s|%q|%q|%q|%d|%#v", resourceKey, schemaURL, scope.Name(), scope.Version(), scope.DroppedAttributesCount(), scope.Attributes().AsRaw())
+}
@@ -0,0 +1,148 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowbatchprocessor // import "github.com/f5/otel-arrow-adapter/collector/processor/arrowbatchprocessor"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/processor"
+	"google.golang.org/protobuf/proto"
+
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+)
+
+var _ processor.Logs = (*logsProcessor)(nil)
+
+type logsProcessor struct {
+	*batchProcessor[plog.Logs]
+}
+
+func (p *logsProcessor) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	return p.consume(ctx, ld)
+}
+
+type logsSignal struct {
+	next consumer.Logs
+}
+
+var _ signal[plog.Logs] = logsSignal{}
+
+func (s logsSignal) newBatch() batch[plog.Logs] {
+	return &logsBatch{
+		logs:      plog.NewLogs(),
+		resources: make(map[string]plog.ResourceLogs),
+		scopes:    make(map[string]plog.ScopeLogs),
+	}
+}
+
+func (s logsSignal) items(ld plog.Logs) int {
+	return ld.LogRecordCount()
+}
+
+func (s logsSignal) protoSize(ld plog.Logs) int {
+	return (&plog.ProtoMarshaler{}).LogsSize(ld)
+}
+
+func (s logsSignal) split(ld plog.Logs, n int) plog.Logs {
+	dest := plog.NewLogs()
+	ld.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+		if n <= 0 {
+			return false
+		}
+		destRL := dest.ResourceLogs().AppendEmpty()
+		rl.Resource().CopyTo(destRL.Resource())
+		destRL.SetSchemaUrl(rl.SchemaUrl())
+
+		rl.ScopeLogs().RemoveIf(func(sl plog.ScopeLogs) bool {
+			if n <= 0 {
+				return false
+			}
+			destSL := destRL.ScopeLogs().AppendEmpty()
+			sl.Scope().CopyTo(destSL.Scope())
+			destSL.SetSchemaUrl(sl.SchemaUrl())
+
+			if sl.LogRecords().Len() <= n {
+				n -= sl.LogRecords().Len()
+				sl.LogRecords().MoveAndAppendTo(destSL.LogRecords())
+				return true
+			}
+			sl.LogRecords().RemoveIf(func(log plog.LogRecord) bool {
+				if n <= 0 {
+					return false
+				}
+				n--
+				log.MoveTo(destSL.LogRecords().AppendEmpty())
+				return true
+			})
+			return false
+		})
+		return rl.ScopeLogs().Len() == 0
+	})
+	return dest
+}
+
+func (s logsSignal) encodedSize(producer *arrowRecord.Producer, ld plog.Logs) (int, error) {
+	bar, err := producer.BatchArrowRecordsFromLogs(ld)
+	if err != nil {
+		return 0, err
+	}
+	return proto.Size(bar), nil
+}
+
+func (s logsSignal) consume(ctx context.Context, ld plog.Logs) error {
+	return s.next.ConsumeLogs(ctx, ld)
+}
+
+// logsBatch groups the log records by resource and scope.
+type logsBatch struct {
+	logs      plog.Logs
+	resources map[string]plog.ResourceLogs
+	scopes    map[string]plog.ScopeLogs
+}
+
+func (b *logsBatch) add(ld plog.Logs) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		rKey := resourceKey(rl.Resource(), rl.SchemaUrl())
+		destRL, ok := b.resources[rKey]
+		if !ok {
+			destRL = b.logs.ResourceLogs().AppendEmpty()
+			rl.Resource().MoveTo(destRL.Resource())
+			destRL.SetSchemaUrl(rl.SchemaUrl())
+			b.resources[rKey] = destRL
+		}
+
+		sls := rl.ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			sl := sls.At(j)
+			sKey := scopeKey(rKey, sl.Scope(), sl.SchemaUrl())
+			destSL, ok := b.scopes[sKey]
+			if !ok {
+				destSL = destRL.ScopeLogs().AppendEmpty()
+				sl.Scope().MoveTo(destSL.Scope())
+				destSL.SetSchemaUrl(sl.SchemaUrl())
+				b.scopes[sKey] = destSL
+			}
+			sl.LogRecords().MoveAndAppendTo(destSL.LogRecords())
+		}
+	}
+}
+
+func (b *logsBatch) data() plog.Logs {
+	return b.logs
+}
@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowbatchprocessor // import "github.com/f5/otel-arrow-adapter/collector/processor/arrowbatchprocessor"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor"
+	"google.golang.org/protobuf/proto"
+
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+)
+
+var _ processor.Metrics = (*metricsProcessor)(nil)
+
+type metricsProcessor struct {
+	*batchProcessor[pmetric.Metrics]
+}
+
+func (p *metricsProcessor) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	return p.consume(ctx, md)
+}
+
+// metricsSignal batches the metrics by data points, the metrics
+// themselves are not split.
+type metricsSignal struct {
+	next consumer.Metrics
+}
+
+var _ signal[pmetric.Metrics] = metricsSignal{}
+
+func (s metricsSignal) newBatch() batch[pmetric.Metrics] {
+	return &metricsBatch{
+		metrics:   pmetric.NewMetrics(),
+		resources: make(map[string]pmetric.ResourceMetrics),
+		scopes:    make(map[string]pmetric.ScopeMetrics),
+	}
+}
+
+func (s metricsSignal) items(md pmetric.Metrics) int {
+	return md.DataPointCount()
+}
+
+func (s metricsSignal) protoSize(md pmetric.Metrics) int {
+	return (&pmetric.ProtoMarshaler{}).MetricsSize(md)
+}
+
+func (s metricsSignal) split(md pmetric.Metrics, n int) pmetric.Metrics {
+	dest := pmetric.NewMetrics()
+	// first is true until a metric is moved, the first metric is moved
+	// even if it has more than n data points.  full is true once a
+	// metric doesn't fit, the following metrics are kept in order.
+	first, full := true, false
+	md.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		if full {
+			return false
+		}
+		destRM := dest.ResourceMetrics().AppendEmpty()
+		rm.Resource().CopyTo(destRM.Resource())
+		destRM.SetSchemaUrl(rm.SchemaUrl())
+
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			if full {
+				return false
+			}
+			destSM := destRM.ScopeMetrics().AppendEmpty()
+			sm.Scope().CopyTo(destSM.Scope())
+			destSM.SetSchemaUrl(sm.SchemaUrl())
+
+			sm.Metrics().RemoveIf(func(m pmetric.Metric) bool {
+				if full {
+					return false
+				}
+				points := dataPointCount(m)
+				if points > n && !first {
+					full = true
+					return false
+				}
+				first = false
+				n -= points
+				full = n <= 0
+				m.MoveTo(destSM.Metrics().AppendEmpty())
+				return true
+			})
+			return sm.Metrics().Len() == 0
+		})
+		return rm.ScopeMetrics().Len() == 0
+	})
+
+	// The scope (and resource) where the first metric not fitting was
+	// found are empty.
+	dest.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			return sm.Metrics().Len() == 0
+		})
+		return rm.ScopeMetrics().Len() == 0
+	})
+	return dest
+}
+
+func (s metricsSignal) encodedSize(producer *arrowRecord.Producer, md pmetric.Metrics) (int, error) {
+	bar, err := producer.BatchArrowRecordsFromMetrics(md)
+	if err != nil {
+		return 0, err
+	}
+	return proto.Size(bar), nil
+}
+
+func (s metricsSignal) consume(ctx context.Context, md pmetric.Metrics) error {
+	return s.next.ConsumeMetrics(ctx, md)
+}
+
+// dataPointCount returns the number of data points of a metric.
+func dataPointCount(m pmetric.Metric) int {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		return m.Gauge().DataPoints().Len()
+	case pmetric.MetricTypeSum:
+		return m.Sum().DataPoints().Len()
+	case pmetric.MetricTypeHistogram:
+		return m.Histogram().DataPoints().Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		return m.ExponentialHistogram().DataPoints().Len()
+	case pmetric.MetricTypeSummary:
+		return m.Summary().DataPoints().Len()
+	default:
+		return 0
+	}
+}
+
+// metricsBatch groups the metrics by resource and scope.
+type metricsBatch struct {
+	metrics   pmetric.Metrics
+	resources map[string]pmetric.ResourceMetrics
+	scopes    map[string]pmetric.ScopeMetrics
+}
+
+func (b *metricsBatch) add(md pmetric.Metrics) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		rKey := resourceKey(rm.Resource(), rm.SchemaUrl())
+		destRM, ok := b.resources[rKey]
+		if !ok {
+			destRM = b.metrics.ResourceMetrics().AppendEmpty()
+			rm.Resource().MoveTo(destRM.Resource())
+			destRM.SetSchemaUrl(rm.SchemaUrl())
+			b.resources[rKey] = destRM
+		}
+
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			sm := sms.At(j)
+			sKey := scopeKey(rKey, sm.Scope(), sm.SchemaUrl())
+			destSM, ok := b.scopes[sKey]
+			if !ok {
+				destSM = destRM.ScopeMetrics().AppendEmpty()
+				sm.Scope().MoveTo(destSM.Scope())
+				destSM.SetSchemaUrl(sm.SchemaUrl())
+				b.scopes[sKey] = destSM
+			}
+			sm.Metrics().MoveAndAppendTo(destSM.Metrics())
+		}
+	}
+}
+
+func (b *metricsBatch) data() pmetric.Metrics {
+	return b.metrics
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowbatchprocessor // import "github.com/f5/otel-arrow-adapter/collector/processor/arrowbatchprocessor"
+
+const (
+	// warmupFlushes is the number of flushes calibrating the size model
+	// after the start of the processor.  The first one only primes the
+	// producer, its encoding contains the schemas and the complete
+	// dictionaries of the stream.
+	warmupFlushes = 8
+	// calibrationInterval is the number of flushes between two
+	// calibrations of the size model after the warmup.
+	calibrationInterval = 16
+	// smoothing is the weight of a new calibration in the size model
+	// (exponential moving average).
+	smoothing = 0.25
+)
+
+// sizeModel estimates the encoded size of the items of a signal, in
+// bytes per item.
+//
+// Until the first calibration, the model follows the size of the items
+// in the OTLP protobuf representation, an upper bound of their Arrow
+// size in most cases.  It is then calibrated with the size of batches
+// encoded by a private producer.  The producer only sees the calibration
+// batches, its dictionary deltas are larger than those of the exporter
+// and the estimate errs on the large side.
+//
+// The fixed overhead of a batch is spread over its items, it is large
+// for a small batch.  As the calibration batches are full, the capacity
+// converges to the number of items whose encoding (overhead included)
+// reaches the target size.
+type sizeModel struct {
+	bytesPerItem float64
+	calibrated   bool
+}
+
+// estimate updates the model with the protobuf size of some items,
+// ignored once the model is calibrated.
+func (m *sizeModel) estimate(bytes, items int) {
+	if m.calibrated || items == 0 {
+		return
+	}
+	m.update(float64(bytes) / float64(items))
+}
+
+// calibrating returns whether the flush-th flush (from 0) is encoded by
+// the private producer, and whether its size calibrates the model (the
+// first flush only primes the producer).
+func calibrating(flush int) (encode, calibrate bool) {
+	if flush == 0 {
+		return true, false
+	}
+	if flush < warmupFlushes || flush%calibrationInterval == 0 {
+		return true, true
+	}
+	return false, false
+}
+
+// calibrate updates the model with the encoded size of a batch.
+func (m *sizeModel) calibrate(bytes, items int) {
+	if items == 0 {
+		return
+	}
+	if !m.calibrated {
+		// The protobuf estimates are discarded.
+		m.calibrated = true
+		m.bytesPerItem = float64(bytes) / float64(items)
+		return
+	}
+	m.update(float64(bytes) / float64(items))
+}
+
+func (m *sizeModel) update(bytesPerItem float64) {
+	if m.bytesPerItem == 0 {
+		m.bytesPerItem = bytesPerItem
+		return
+	}
+	m.bytesPerItem += smoothing * (bytesPerItem - m.bytesPerItem)
+}
+
+// capacity returns the number of items of a batch of targetBytes,
+// bounded by maxItems (and at least 1).
+func (m *sizeModel) capacity(targetBytes uint64, maxItems int) (capacity int, bySize bool) {
+	if m.bytesPerItem <= 0 {
+		return maxItems, false
+	}
+	items := float64(targetBytes) / m.bytesPerItem
+	if items >= float64(maxItems) {
+		return maxItems, false
+	}
+	if items < 1 {
+		return 1, true
+	}
+	return int(items), true
+}
@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowbatchprocessor // import "github.com/f5/otel-arrow-adapter/collector/processor/arrowbatchprocessor"
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor"
+	"go.uber.org/zap"
+
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+)
+
+var errShutdown = errors.New("the Arrow batch processor is shut down")
+
+// signal abstracts the batching of the pdata type T (traces, metrics
+// or logs).
+type signal[T any] interface {
+	// newBatch returns an empty batch.
+	newBatch() batch[T]
+	// items returns the number of items of data.
+	items(data T) int
+	// protoSize returns the size of data in the OTLP protobuf
+	// representation.
+	protoSize(data T) int
+	// split removes up to n items from data and returns them.  The
+	// first unit of data (span, metric or log record) is always
+	// returned, a metric with more than n data points is not split.
+	split(data T, n int) T
+	// encodedSize returns the size of the BatchArrowRecords encoding
+	// data with producer.
+	encodedSize(producer *arrowRecord.Producer, data T) (int, error)
+	// consume sends data to the next consumer.
+	consume(ctx context.Context, data T) error
+}
+
+// batch is a batch being built.
+type batch[T any] interface {
+	// add moves the items of data into the batch, grouped with the
+	// items of the same resource and scope.
+	add(data T)
+	// data returns the batched items.
+	data() T
+}
+
+// batchProcessor batches the items of a signal until the estimated
+// size of the encoded batch reaches the target size, the number of
+// items reaches the maximum or the timeout expires.  The batches are
+// built and sent by a single goroutine.
+type batchProcessor[T any] struct {
+	logger    *zap.Logger
+	config    *Config
+	signal    signal[T]
+	telemetry *batchTelemetry
+
+	input chan T
+	done  chan struct{}
+	wg    sync.WaitGroup
+
+	// The following fields are owned by the batching goroutine.
+	model    sizeModel
+	producer *arrowRecord.Producer
+	pending  batch[T]
+	items    int
+	flushes  int
+	timer    *time.Timer
+}
+
+func newBatchProcessor[T any](settings processor.CreateSettings, config component.Config, s signal[T], signalName string) (*batchProcessor[T], error) {
+	telemetry, err := newBatchTelemetry(settings, signalName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &batchProcessor[T]{
+		logger:    settings.Logger,
+		config:    config.(*Config),
+		signal:    s,
+		telemetry: telemetry,
+		input:     make(chan T),
+		done:      make(chan struct{}),
+		producer:  arrowRecord.NewProducer(),
+		pending:   s.newBatch(),
+	}, nil
+}
+
+func (p *batchProcessor[T]) Start(context.Context, component.Host) error {
+	p.wg.Add(1)
+	go p.loop()
+	return nil
+}
+
+func (p *batchProcessor[T]) Shutdown(context.Context) error {
+	close(p.done)
+	p.wg.Wait()
+	return p.producer.Close()
+}
+
+func (p *batchProcessor[T]) Capabilities() consumer.Capabilities {
+	// The items are moved to the batches.
+	return consumer.Capabilities{MutatesData: true}
+}
+
+// consume hands data over to the batching goroutine.
+func (p *batchProcessor[T]) consume(ctx context.Context, data T) error {
+	if p.signal.items(data) == 0 {
+		return nil
+	}
+	select {
+	case p.input <- data:
+		return nil
+	case <-p.done:
+		return errShutdown
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *batchProcessor[T]) loop() {
+	defer p.wg.Done()
+
+	for {
+		var timeout <-chan time.Time
+		if p.timer != nil {
+			timeout = p.timer.C
+		}
+
+		select {
+		case data := <-p.input:
+			p.add(data)
+		case <-timeout:
+			p.timer = nil
+			p.flush(triggerTimeout)
+		case <-p.done:
+			p.flush(triggerShutdown)
+			return
+		}
+	}
+}
+
+// capacity returns the number of items of a full batch, and the
+// trigger of its flush.
+func (p *batchProcessor[T]) capacity() (int, string) {
+	capacity, bySize := p.model.capacity(p.config.TargetBytes, p.config.MaxItems)
+	if bySize {
+		return capacity, triggerSize
+	}
+	return capacity, triggerItems
+}
+
+// add adds data to the pending batch, flushing the batch each time
+// it is full.
+func (p *batchProcessor[T]) add(data T) {
+	if !p.model.calibrated {
+		p.model.estimate(p.signal.protoSize(data), p.signal.items(data))
+	}
+
+	for {
+		items := p.signal.items(data)
+		if items == 0 {
+			break
+		}
+		capacity, trigger := p.capacity()
+		room := capacity - p.items
+		if room <= 0 {
+			p.flush(trigger)
+			continue
+		}
+		if items <= room {
+			p.pending.add(data)
+			p.items += items
+			break
+		}
+		part := p.signal.split(data, room)
+		p.items += p.signal.items(part)
+		p.pending.add(part)
+		p.flush(trigger)
+	}
+
+	if capacity, trigger := p.capacity(); p.items >= capacity {
+		p.flush(trigger)
+	} else if p.items > 0 && p.timer == nil {
+		p.timer = time.NewTimer(p.config.Timeout)
+	}
+}
+
+// flush sends the pending batch to the next consumer, the size model
+// is calibrated on some flushes (see calibrating).
+func (p *batchProcessor[T]) flush(trigger string) {
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	if p.items == 0 {
+		return
+	}
+
+	data, items := p.pending.data(), p.items
+	p.pending, p.items = p.signal.newBatch(), 0
+
+	if encode, calibrate := calibrating(p.flushes); encode {
+		size, err := p.signal.encodedSize(p.producer, data)
+		if err != nil {
+			p.logger.Debug("failed to calibrate the Arrow size model", zap.Error(err))
+		} else if calibrate {
+			p.model.calibrate(size, items)
+		}
+	}
+	p.flushes++
+
+	p.telemetry.flushed(trigger, items)
+	if err := p.signal.consume(context.Background(), data); err != nil {
+		p.logger.Warn("failed to send a batch", zap.Int("items", items), zap.Error(err))
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowbatchprocessor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+)
+
+// testSettings returns the settings of a processor with its metrics
+// collected by the returned reader.
+func testSettings() (processor.CreateSettings, metric.Reader) {
+	rdr := metric.NewManualReader()
+	settings := processortest.NewNopCreateSettings()
+	settings.ID = component.NewID(typeStr)
+	settings.MeterProvider = metric.NewMeterProvider(metric.WithReader(rdr))
+	settings.MetricsLevel = configtelemetry.LevelBasic
+	return settings, rdr
+}
+
+// triggers returns the number of batches sent by trigger.
+func triggers(t *testing.T, rdr metric.Reader) map[string]int64 {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, rdr.Collect(context.Background(), &rm))
+	counts := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "processor_arrowbatch_batches" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				trigger, _ := dp.Attributes.Value(triggerKey)
+				counts[trigger.AsString()] += dp.Value
+			}
+		}
+	}
+	return counts
+}
+
+func testConfig(maxItems int) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MaxItems = maxItems
+	cfg.TargetBytes = 1 << 30
+	cfg.Timeout = time.Hour
+	return cfg
+}
+
+// makeTraces returns a batch of spans named prefix-0, prefix-1, ... of
+// a resource and a scope.
+func makeTraces(service string, prefix string, spans int) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", service)
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName("scope")
+	for i := 0; i < spans; i++ {
+		ss.Spans().AppendEmpty().SetName(fmt.Sprintf("%s-%d", prefix, i))
+	}
+	return traces
+}
+
+// spanNames returns the names of the spans of each resource (by
+// service) and checks that each resource has a single scope.
+func spanNames(t *testing.T, traces ptrace.Traces) map[string][]string {
+	names := map[string][]string{}
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		rs := traces.ResourceSpans().At(i)
+		service, _ := rs.Resource().Attributes().Get("service.name")
+		require.NotContains(t, names, service.Str())
+		require.Equal(t, 1, rs.ScopeSpans().Len())
+		spans := rs.ScopeSpans().At(0).Spans()
+		for j := 0; j < spans.Len(); j++ {
+			names[service.Str()] = append(names[service.Str()], spans.At(j).Name())
+		}
+	}
+	return names
+}
+
+func startTracesProcessor(t *testing.T, settings processor.CreateSettings, cfg *Config) (processor.Traces, *consumertest.TracesSink) {
+	sink := new(consumertest.TracesSink)
+	p, err := NewFactory().CreateTracesProcessor(context.Background(), settings, cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	return p, sink
+}
+
+func TestCoBatchByResource(t *testing.T) {
+	settings, rdr := testSettings()
+	p, sink := startTracesProcessor(t, settings, testConfig(10))
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, p.ConsumeTraces(context.Background(), makeTraces([]string{"a", "b"}[i%2], fmt.Sprint(i), 2)))
+	}
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, map[string][]string{
+		"a": {"0-0", "0-1", "2-0", "2-1", "4-0", "4-1"},
+		"b": {"1-0", "1-1", "3-0", "3-1"},
+	}, spanNames(t, sink.AllTraces()[0]))
+	assert.Equal(t, map[string]int64{triggerItems: 1}, triggers(t, rdr))
+}
+
+func TestSplitMaxItems(t *testing.T) {
+	settings, rdr := testSettings()
+	p, sink := startTracesProcessor(t, settings, testConfig(3))
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), makeTraces("a", "x", 4)))
+	require.NoError(t, p.ConsumeTraces(context.Background(), makeTraces("b", "y", 3)))
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	var batches []map[string][]string
+	for _, traces := range sink.AllTraces() {
+		batches = append(batches, spanNames(t, traces))
+	}
+	assert.Equal(t, []map[string][]string{
+		{"a": {"x-0", "x-1", "x-2"}},
+		{"a": {"x-3"}, "b": {"y-0", "y-1"}},
+		{"b": {"y-2"}},
+	}, batches)
+	assert.Equal(t, map[string]int64{triggerItems: 2, triggerShutdown: 1}, triggers(t, rdr))
+}
+
+func TestTargetBytes(t *testing.T) {
+	settings, rdr := testSettings()
+	cfg := testConfig(maxItemsLimit)
+	cfg.TargetBytes = 16 << 10
+	p, sink := startTracesProcessor(t, settings, cfg)
+
+	ent := datagen.NewTestEntropy(12345)
+	gen := datagen.NewTracesGenerator(ent, ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes())
+	spans := 0
+	for i := 0; i < 50; i++ {
+		traces := gen.Generate(20, time.Second)
+		spans += traces.SpanCount()
+		require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+	}
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	// The batches are flushed by size, way before the maximum number of
+	// items, and no span is lost.
+	batches := sink.AllTraces()
+	require.Greater(t, len(batches), 1)
+	received := 0
+	for _, traces := range batches {
+		received += traces.SpanCount()
+	}
+	assert.Equal(t, spans, received)
+	counts := triggers(t, rdr)
+	assert.Equal(t, int64(len(batches)-1), counts[triggerSize])
+	assert.Equal(t, int64(1), counts[triggerShutdown])
+}
+
+func TestTimeout(t *testing.T) {
+	settings, rdr := testSettings()
+	cfg := testConfig(10)
+	cfg.Timeout = 10 * time.Millisecond
+	p, sink := startTracesProcessor(t, settings, cfg)
+	defer func() { require.NoError(t, p.Shutdown(context.Background())) }()
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), makeTraces("a", "x", 1)))
+	require.Eventually(t, func() bool { return sink.SpanCount() == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, map[string]int64{triggerTimeout: 1}, triggers(t, rdr))
+}
+
+func makeMetrics(service string, points ...int) pmetric.Metrics {
+	metrics := pmetric.NewMetrics()
+	rm := metrics.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", service)
+	ms := rm.ScopeMetrics().AppendEmpty().Metrics()
+	for i, n := range points {
+		m := ms.AppendEmpty()
+		m.SetName(fmt.Sprintf("%s-%d", service, i))
+		dps := m.SetEmptyGauge().DataPoints()
+		for j := 0; j < n; j++ {
+			dps.AppendEmpty().SetIntValue(int64(j))
+		}
+	}
+	return metrics
+}
+
+func TestSplitMetrics(t *testing.T) {
+	settings, _ := testSettings()
+	sink := new(consumertest.MetricsSink)
+	p, err := NewFactory().CreateMetricsProcessor(context.Background(), settings, testConfig(4), sink)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+
+	// The metrics are not split: a-1 doesn't fit in the first batch,
+	// a-2 is larger than a batch.
+	require.NoError(t, p.ConsumeMetrics(context.Background(), makeMetrics("a", 2, 3, 5, 1)))
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	var batches [][]string
+	for _, metrics := range sink.AllMetrics() {
+		var names []string
+		ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		require.Equal(t, 1, metrics.ResourceMetrics().Len())
+		require.Equal(t, 1, metrics.ResourceMetrics().At(0).ScopeMetrics().Len())
+		for i := 0; i < ms.Len(); i++ {
+			names = append(names, ms.At(i).Name())
+		}
+		batches = append(batches, names)
+	}
+	assert.Equal(t, [][]string{{"a-0"}, {"a-1"}, {"a-2"}, {"a-3"}}, batches)
+}
+
+func TestLogs(t *testing.T) {
+	settings, _ := testSettings()
+	sink := new(consumertest.LogsSink)
+	p, err := NewFactory().CreateLogsProcessor(context.Background(), settings, testConfig(3), sink)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+
+	for i := 0; i < 4; i++ {
+		logs := plog.NewLogs()
+		rl := logs.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", "a")
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetInt(int64(i))
+		require.NoError(t, p.ConsumeLogs(context.Background(), logs))
+	}
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	require.Len(t, sink.AllLogs(), 2)
+	first := sink.AllLogs()[0]
+	require.Equal(t, 1, first.ResourceLogs().Len())
+	require.Equal(t, 1, first.ResourceLogs().At(0).ScopeLogs().Len())
+	assert.Equal(t, 3, first.LogRecordCount())
+	assert.Equal(t, 1, sink.AllLogs()[1].LogRecordCount())
+}
+
+func TestSizeModel(t *testing.T) {
+	var m sizeModel
+	capacity, bySize := m.capacity(1000, 100)
+	assert.Equal(t, 100, capacity)
+	assert.False(t, bySize)
+
+	// The protobuf estimates are smoothed.
+	m.estimate(1000, 10)
+	m.estimate(2000, 10)
+	assert.Equal(t, 125.0, m.bytesPerItem)
+	capacity, bySize = m.capacity(1000, 100)
+	assert.Equal(t, 8, capacity)
+	assert.True(t, bySize)
+
+	// The first calibration replaces the protobuf estimates, which are
+	// then ignored.
+	m.calibrate(200, 10)
+	m.estimate(2000, 10)
+	assert.Equal(t, 20.0, m.bytesPerItem)
+	m.calibrate(600, 10)
+	assert.Equal(t, 30.0, m.bytesPerItem)
+
+	capacity, bySize = m.capacity(10, 100)
+	assert.Equal(t, 1, capacity)
+	assert.True(t, bySize)
+	capacity, bySize = m.capacity(1<<20, 100)
+	assert.Equal(t, 100, capacity)
+	assert.False(t, bySize)
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, componenttest.CheckConfigStruct(cfg))
+	assert.NoError(t, cfg.Validate())
+
+	assert.ErrorIs(t, (&Config{MaxItems: 1, Timeout: time.Second}).Validate(), errInvalidTargetBytes)
+	assert.ErrorIs(t, (&Config{TargetBytes: 1, MaxItems: maxItemsLimit + 1, Timeout: time.Second}).Validate(), errInvalidMaxItems)
+	assert.ErrorIs(t, (&Config{TargetBytes: 1, MaxItems: 1}).Validate(), errInvalidTimeout)
+}
+
+func TestCalibrating(t *testing.T) {
+	var encoded, calibrated []int
+	for flush := 0; flush < 3*calibrationInterval+1; flush++ {
+		encode, calibrate := calibrating(flush)
+		if encode {
+			encoded = append(encoded, flush)
+		}
+		if calibrate {
+			calibrated = append(calibrated, flush)
+		}
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 16, 32, 48}, encoded)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 16, 32, 48}, calibrated)
+}
@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowbatchprocessor // import "github.com/f5/otel-arrow-adapter/collector/processor/arrowbatchprocessor"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/multierr"
+)
+
+const (
+	// processorKey identifies the processor reporting a metric.
+	processorKey = "processor"
+	// signalKey identifies the signal of the batches.
+	signalKey = "signal"
+	// triggerKey is the cause of a flush, one of the trigger* values
+	// below.
+	triggerKey = "trigger"
+
+	scopeName = "github.com/f5/otel-arrow-adapter/collector/processor/arrowbatchprocessor"
+	prefix    = "processor_arrowbatch_"
+)
+
+// Causes of a flush.
+const (
+	// triggerSize: the estimated size reached the target size.
+	triggerSize = "size"
+	// triggerItems: the number of items reached the maximum.
+	triggerItems = "items"
+	// triggerTimeout: the timeout expired.
+	triggerTimeout = "timeout"
+	// triggerShutdown: the processor is shut down.
+	triggerShutdown = "shutdown"
+)
+
+// batchTelemetry reports the flushed batches.  A nil batchTelemetry is
+// valid and reports nothing, this is the case when the telemetry is
+// disabled.
+type batchTelemetry struct {
+	component attribute.KeyValue
+	signal    attribute.KeyValue
+
+	batches    metric.Int64Counter
+	batchItems metric.Int64Histogram
+}
+
+// newBatchTelemetry creates the instruments of a processor.
+func newBatchTelemetry(settings processor.CreateSettings, signal string) (*batchTelemetry, error) {
+	if settings.TelemetrySettings.MetricsLevel <= configtelemetry.LevelNone {
+		return nil, nil
+	}
+
+	meter := settings.TelemetrySettings.MeterProvider.Meter(scopeName)
+	bt := &batchTelemetry{
+		component: attribute.String(processorKey, settings.ID.String()),
+		signal:    attribute.String(signalKey, signal),
+	}
+
+	var errors, err error
+	bt.batches, err = meter.Int64Counter(prefix+"batches", metric.WithDescription("Number of batches sent, by trigger."))
+	errors = multierr.Append(errors, err)
+	bt.batchItems, err = meter.Int64Histogram(prefix+"batch_items", metric.WithDescription("Number of items (spans, data points or log records) of the batches sent."))
+	errors = multierr.Append(errors, err)
+	if errors != nil {
+		return nil, errors
+	}
+	return bt, nil
+}
+
+// flushed reports a batch sent because of trigger.
+func (bt *batchTelemetry) flushed(trigger string, items int) {
+	if bt == nil {
+		return
+	}
+	ctx := context.Background()
+	bt.batches.Add(ctx, 1, metric.WithAttributes(bt.component, bt.signal, attribute.String(triggerKey, trigger)))
+	bt.batchItems.Record(ctx, int64(items), metric.WithAttributes(bt.component, bt.signal))
+}
@@ -0,0 +1,148 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowbatchprocessor // import "github.com/f5/otel-arrow-adapter/collector/processor/arrowbatchprocessor"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor"
+	"google.golang.org/protobuf/proto"
+
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+)
+
+var _ processor.Traces = (*tracesProcessor)(nil)
+
+type tracesProcessor struct {
+	*batchProcessor[ptrace.Traces]
+}
+
+func (p *tracesProcessor) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	return p.consume(ctx, td)
+}
+
+type tracesSignal struct {
+	next consumer.Traces
+}
+
+var _ signal[ptrace.Traces] = tracesSignal{}
+
+func (s tracesSignal) newBatch() batch[ptrace.Traces] {
+	return &tracesBatch{
+		traces:    ptrace.NewTraces(),
+		resources: make(map[string]ptrace.ResourceSpans),
+		scopes:    make(map[string]ptrace.ScopeSpans),
+	}
+}
+
+func (s tracesSignal) items(td ptrace.Traces) int {
+	return td.SpanCount()
+}
+
+func (s tracesSignal) protoSize(td ptrace.Traces) int {
+	return (&ptrace.ProtoMarshaler{}).TracesSize(td)
+}
+
+func (s tracesSignal) split(td ptrace.Traces, n int) ptrace.Traces {
+	dest := ptrace.NewTraces()
+	td.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+		if n <= 0 {
+			return false
+		}
+		destRS := dest.ResourceSpans().AppendEmpty()
+		rs.Resource().CopyTo(destRS.Resource())
+		destRS.SetSchemaUrl(rs.SchemaUrl())
+
+		rs.ScopeSpans().RemoveIf(func(ss ptrace.ScopeSpans) bool {
+			if n <= 0 {
+				return false
+			}
+			destSS := destRS.ScopeSpans().AppendEmpty()
+			ss.Scope().CopyTo(destSS.Scope())
+			destSS.SetSchemaUrl(ss.SchemaUrl())
+
+			if ss.Spans().Len() <= n {
+				n -= ss.Spans().Len()
+				ss.Spans().MoveAndAppendTo(destSS.Spans())
+				return true
+			}
+			ss.Spans().RemoveIf(func(span ptrace.Span) bool {
+				if n <= 0 {
+					return false
+				}
+				n--
+				span.MoveTo(destSS.Spans().AppendEmpty())
+				return true
+			})
+			return false
+		})
+		return rs.ScopeSpans().Len() == 0
+	})
+	return dest
+}
+
+func (s tracesSignal) encodedSize(producer *arrowRecord.Producer, td ptrace.Traces) (int, error) {
+	bar, err := producer.BatchArrowRecordsFromTraces(td)
+	if err != nil {
+		return 0, err
+	}
+	return proto.Size(bar), nil
+}
+
+func (s tracesSignal) consume(ctx context.Context, td ptrace.Traces) error {
+	return s.next.ConsumeTraces(ctx, td)
+}
+
+// tracesBatch groups the spans by resource and scope.
+type tracesBatch struct {
+	traces    ptrace.Traces
+	resources map[string]ptrace.ResourceSpans
+	scopes    map[string]ptrace.ScopeSpans
+}
+
+func (b *tracesBatch) add(td ptrace.Traces) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		rKey := resourceKey(rs.Resource(), rs.SchemaUrl())
+		destRS, ok := b.resources[rKey]
+		if !ok {
+			destRS = b.traces.ResourceSpans().AppendEmpty()
+			rs.Resource().MoveTo(destRS.Resource())
+			destRS.SetSchemaUrl(rs.SchemaUrl())
+			b.resources[rKey] = destRS
+		}
+
+		sss := rs.ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			ss := sss.At(j)
+			sKey := scopeKey(rKey, ss.Scope(), ss.SchemaUrl())
+			destSS, ok := b.scopes[sKey]
+			if !ok {
+				destSS = destRS.ScopeSpans().AppendEmpty()
+				ss.Scope().MoveTo(destSS.Scope())
+				destSS.SetSchemaUrl(ss.SchemaUrl())
+				b.scopes[sKey] = destSS
+			}
+			ss.Spans().MoveAndAppendTo(destSS.Spans())
+		}
+	}
+}
+
+func (b *tracesBatch) data() ptrace.Traces {
+	return b.traces
+}
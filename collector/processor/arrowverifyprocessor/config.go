@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowverifyprocessor // import "github.com/f5/otel-arrow-adapter/collector/processor/arrowverifyprocessor"
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+var (
+	errInvalidSamplingRatio = errors.New("the sampling ratio must be in (0, 1]")
+	errInvalidMaxDumps      = errors.New("the maximum number of dumps must not be negative")
+)
+
+// Config defines the configuration for the Arrow verification processor.
+type Config struct {
+	// SamplingRatio is the fraction of the batches verified, in (0, 1].
+	// Defaults to 0.01.
+	SamplingRatio float64 `mapstructure:"sampling_ratio"`
+
+	// DumpDirectory is the directory where the batches failing the
+	// verification are written for repro.  When empty (the default),
+	// the batches are not written.
+	DumpDirectory string `mapstructure:"dump_directory"`
+
+	// MaxDumps is the maximum number of batches written to
+	// DumpDirectory by the processor.  Defaults to 10, 0 means no
+	// limit.
+	MaxDumps int `mapstructure:"max_dumps"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid.
+func (c *Config) Validate() error {
+	if c.SamplingRatio <= 0 || c.SamplingRatio > 1 {
+		return fmt.Errorf("invalid sampling ratio %v: %w", c.SamplingRatio, errInvalidSamplingRatio)
+	}
+	if c.MaxDumps < 0 {
+		return fmt.Errorf("invalid maximum number of dumps %d: %w", c.MaxDumps, errInvalidMaxDumps)
+	}
+	return nil
+}
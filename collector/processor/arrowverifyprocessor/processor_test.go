@@ -0,0 +1,217 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowverifyprocessor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+)
+
+// testSettings returns the settings of a processor with its metrics
+// collected by the returned reader and its logs recorded by the
+// returned observer.
+func testSettings() (processor.CreateSettings, metric.Reader, *observer.ObservedLogs) {
+	rdr := metric.NewManualReader()
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	settings := processortest.NewNopCreateSettings()
+	settings.ID = component.NewID(typeStr)
+	settings.Logger = zap.New(core)
+	settings.MeterProvider = metric.NewMeterProvider(metric.WithReader(rdr))
+	settings.MetricsLevel = configtelemetry.LevelBasic
+	return settings, rdr, logs
+}
+
+// outcomes returns the number of verified batches by outcome.
+func outcomes(t *testing.T, rdr metric.Reader) map[string]int64 {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, rdr.Collect(context.Background(), &rm))
+	counts := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "processor_arrowverify_verified_batches" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				outcome, _ := dp.Attributes.Value(outcomeKey)
+				counts[outcome.AsString()] += dp.Value
+			}
+		}
+	}
+	return counts
+}
+
+func TestVerifyRoundTrip(t *testing.T) {
+	ent := datagen.NewTestEntropy(12345)
+	resources, scopes := ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes()
+
+	settings, rdr, logs := testSettings()
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SamplingRatio = 1
+
+	traces := new(consumertest.TracesSink)
+	tp, err := factory.CreateTracesProcessor(context.Background(), settings, cfg, traces)
+	require.NoError(t, err)
+	metrics := new(consumertest.MetricsSink)
+	mp, err := factory.CreateMetricsProcessor(context.Background(), settings, cfg, metrics)
+	require.NoError(t, err)
+	logsSink := new(consumertest.LogsSink)
+	lp, err := factory.CreateLogsProcessor(context.Background(), settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	tracesGen := datagen.NewTracesGenerator(ent, resources, scopes)
+	metricsGen := datagen.NewMetricsGenerator(ent, resources, scopes)
+	logsGen := datagen.NewLogsGenerator(ent, resources, scopes)
+
+	const batches = 3
+	for i := 0; i < batches; i++ {
+		require.NoError(t, tp.ConsumeTraces(context.Background(), tracesGen.Generate(20, time.Minute)))
+		require.NoError(t, mp.ConsumeMetrics(context.Background(), metricsGen.GenerateAllKindOfMetrics(20, time.Minute)))
+		require.NoError(t, lp.ConsumeLogs(context.Background(), logsGen.Generate(20, time.Minute)))
+	}
+	for _, p := range []component.Component{tp, mp, lp} {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}
+
+	// The batches are passed through and their round trip is equivalent.
+	assert.Len(t, traces.AllTraces(), batches)
+	assert.Len(t, metrics.AllMetrics(), batches)
+	assert.Len(t, logsSink.AllLogs(), batches)
+	assert.Equal(t, map[string]int64{outcomeEquivalent: 3 * batches}, outcomes(t, rdr))
+	assert.Zero(t, logs.FilterLevelExact(zapcore.ErrorLevel).Len()+logs.FilterLevelExact(zapcore.WarnLevel).Len())
+}
+
+func TestVerifySampling(t *testing.T) {
+	settings, rdr, _ := testSettings()
+	v, err := newVerifier(settings, &Config{SamplingRatio: 0.5}, "traces")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, v.shutdown(context.Background())) }()
+
+	samples := []float64{0.1, 0.7, 0.5, 0.2}
+	v.randFloat64 = func() float64 {
+		sample := samples[0]
+		samples = samples[1:]
+		return sample
+	}
+	for range samples {
+		_, err := v.processTraces(context.Background(), ptrace.NewTraces())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, map[string]int64{outcomeEquivalent: 2}, outcomes(t, rdr))
+}
+
+func makeTraces(names ...string) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "service")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	for _, name := range names {
+		spans.AppendEmpty().SetName(name)
+	}
+	return traces
+}
+
+func TestVerifyMismatch(t *testing.T) {
+	settings, rdr, logs := testSettings()
+	dir := t.TempDir()
+	v, err := newVerifier(settings, &Config{SamplingRatio: 1, DumpDirectory: dir, MaxDumps: 1}, "traces")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, v.shutdown(context.Background())) }()
+
+	// A faulty round trip renaming a span.
+	input := makeTraces("a", "b")
+	roundTrip := func() ([]json.Marshaler, error) {
+		return []json.Marshaler{ptraceotlp.NewExportRequestFromTraces(makeTraces("a", "c"))}, nil
+	}
+	v.verify(context.Background(), ptraceotlp.NewExportRequestFromTraces(input), roundTrip)
+	v.verify(context.Background(), ptraceotlp.NewExportRequestFromTraces(input), roundTrip)
+
+	assert.Equal(t, map[string]int64{outcomeMismatch: 2}, outcomes(t, rdr))
+
+	mismatches := logs.FilterMessage("Arrow round trip mismatch").All()
+	require.Len(t, mismatches, 2)
+	fields := mismatches[0].ContextMap()
+	assert.Equal(t, []interface{}{"resourceSpans[_].scopeSpans[_].spans[_].name=b"}, fields["missing"])
+	assert.Equal(t, []interface{}{"resourceSpans[_].scopeSpans[_].spans[_].name=c"}, fields["unexpected"])
+
+	// Only the first mismatch is dumped (MaxDumps), the input can be
+	// replayed from the dump.
+	prefix, ok := fields["dump"].(string)
+	require.True(t, ok)
+	assert.NotContains(t, mismatches[1].ContextMap(), "dump")
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{prefix + ".input.json", prefix + ".output.json", prefix + ".diff.json"}, files)
+
+	data, err := os.ReadFile(prefix + ".input.json")
+	require.NoError(t, err)
+	dumped, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(data)
+	require.NoError(t, err)
+	assert.Equal(t, input, dumped)
+}
+
+func TestVerifyError(t *testing.T) {
+	settings, rdr, logs := testSettings()
+	v, err := newVerifier(settings, &Config{SamplingRatio: 1}, "traces")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, v.shutdown(context.Background())) }()
+
+	producer := v.producer
+	v.verify(context.Background(), ptraceotlp.NewExportRequestFromTraces(makeTraces("a")), func() ([]json.Marshaler, error) {
+		return nil, errors.New("encoding failure")
+	})
+	assert.Equal(t, map[string]int64{outcomeError: 1}, outcomes(t, rdr))
+	assert.Equal(t, 1, logs.FilterMessage("Arrow round trip failed").Len())
+
+	// The verification continues with a new producer/consumer pair.
+	assert.NotSame(t, producer, v.producer)
+	_, err = v.processTraces(context.Background(), makeTraces("a"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{outcomeError: 1, outcomeEquivalent: 1}, outcomes(t, rdr))
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, componenttest.CheckConfigStruct(cfg))
+	assert.NoError(t, cfg.Validate())
+
+	assert.ErrorIs(t, (&Config{}).Validate(), errInvalidSamplingRatio)
+	assert.ErrorIs(t, (&Config{SamplingRatio: 1.5}).Validate(), errInvalidSamplingRatio)
+	assert.ErrorIs(t, (&Config{SamplingRatio: 1, MaxDumps: -1}).Validate(), errInvalidMaxDumps)
+}
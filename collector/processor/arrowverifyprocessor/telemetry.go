@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowverifyprocessor // import "github.com/f5/otel-arrow-adapter/collector/processor/arrowverifyprocessor"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/multierr"
+)
+
+const (
+	// processorKey identifies the processor reporting a metric.
+	processorKey = "processor"
+	// signalKey identifies the signal of the verified batches.
+	signalKey = "signal"
+	// outcomeKey is the outcome of a verification, one of the outcome*
+	// values below.
+	outcomeKey = "outcome"
+
+	scopeName = "github.com/f5/otel-arrow-adapter/collector/processor/arrowverifyprocessor"
+	prefix    = "processor_arrowverify_"
+)
+
+// Outcomes of the verification of a batch.
+const (
+	// outcomeEquivalent: the round trip preserved the batch.
+	outcomeEquivalent = "equivalent"
+	// outcomeMismatch: the batch and its round trip differ.
+	outcomeMismatch = "mismatch"
+	// outcomeError: the batch failed to be encoded or decoded.
+	outcomeError = "error"
+)
+
+// verifierTelemetry reports the outcome of the verifications.  A nil
+// verifierTelemetry is valid and reports nothing, this is the case
+// when the telemetry is disabled.
+type verifierTelemetry struct {
+	component attribute.KeyValue
+	signal    attribute.KeyValue
+
+	verifiedBatches metric.Int64Counter
+	mismatchedPaths metric.Int64Counter
+}
+
+// newVerifierTelemetry creates the instruments of a processor.
+func newVerifierTelemetry(settings processor.CreateSettings, signal string) (*verifierTelemetry, error) {
+	if settings.TelemetrySettings.MetricsLevel <= configtelemetry.LevelNone {
+		return nil, nil
+	}
+
+	meter := settings.TelemetrySettings.MeterProvider.Meter(scopeName)
+	vt := &verifierTelemetry{
+		component: attribute.String(processorKey, settings.ID.String()),
+		signal:    attribute.String(signalKey, signal),
+	}
+
+	var errors, err error
+	vt.verifiedBatches, err = meter.Int64Counter(prefix+"verified_batches", metric.WithDescription("Number of batches verified through an Arrow round trip, by outcome."))
+	errors = multierr.Append(errors, err)
+	vt.mismatchedPaths, err = meter.Int64Counter(prefix+"mismatched_paths", metric.WithDescription("Number of vPaths missing or unexpected after an Arrow round trip."))
+	errors = multierr.Append(errors, err)
+	if errors != nil {
+		return nil, errors
+	}
+	return vt, nil
+}
+
+// verified reports the outcome of a verification, with the number of
+// mismatched vPaths.
+func (vt *verifierTelemetry) verified(ctx context.Context, outcome string, mismatchedPaths int) {
+	if vt == nil {
+		return
+	}
+	vt.verifiedBatches.Add(ctx, 1, metric.WithAttributes(vt.component, vt.signal, attribute.String(outcomeKey, outcome)))
+	if mismatchedPaths > 0 {
+		vt.mismatchedPaths.Add(ctx, int64(mismatchedPaths), metric.WithAttributes(vt.component, vt.signal))
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrowverifyprocessor // import "github.com/f5/otel-arrow-adapter/collector/processor/arrowverifyprocessor"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.opentelemetry.io/collector/processor"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/equiv"
+)
+
+// maxLoggedPaths is the maximum number of missing (and unexpected)
+// vPaths logged for a mismatch, all of them are written in the dump.
+const maxLoggedPaths = 10
+
+// verifier runs a sample of the batches through a private
+// Producer/Consumer pair and compares the decoded batches with the
+// original ones (see equiv.Diff).  The batches themselves are passed
+// through unchanged.
+type verifier struct {
+	logger    *zap.Logger
+	config    *Config
+	telemetry *verifierTelemetry
+	id        component.ID
+	signal    string
+
+	// lock protects the producer/consumer pair, which are stateful
+	// like the two ends of an Arrow stream, and the dump counter.
+	lock     sync.Mutex
+	producer *arrowRecord.Producer
+	consumer *arrowRecord.Consumer
+	dumps    int
+
+	// randFloat64 samples the batches, replaced by the tests.
+	randFloat64 func() float64
+}
+
+func newVerifier(settings processor.CreateSettings, config component.Config, signal string) (*verifier, error) {
+	telemetry, err := newVerifierTelemetry(settings, signal)
+	if err != nil {
+		return nil, err
+	}
+
+	return &verifier{
+		logger:      settings.Logger,
+		config:      config.(*Config),
+		telemetry:   telemetry,
+		id:          settings.ID,
+		signal:      signal,
+		producer:    arrowRecord.NewProducer(),
+		consumer:    arrowRecord.NewConsumer(),
+		randFloat64: rand.Float64,
+	}, nil
+}
+
+func (v *verifier) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	if v.sampled() {
+		v.verify(ctx, ptraceotlp.NewExportRequestFromTraces(td), func() ([]json.Marshaler, error) {
+			bar, err := v.producer.BatchArrowRecordsFromTraces(td)
+			if err != nil {
+				return nil, err
+			}
+			traces, err := v.consumer.TracesFrom(bar)
+			if err != nil {
+				return nil, err
+			}
+			output := make([]json.Marshaler, len(traces))
+			for i, t := range traces {
+				output[i] = ptraceotlp.NewExportRequestFromTraces(t)
+			}
+			return output, nil
+		})
+	}
+	return td, nil
+}
+
+func (v *verifier) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	if v.sampled() {
+		v.verify(ctx, pmetricotlp.NewExportRequestFromMetrics(md), func() ([]json.Marshaler, error) {
+			bar, err := v.producer.BatchArrowRecordsFromMetrics(md)
+			if err != nil {
+				return nil, err
+			}
+			metrics, err := v.consumer.MetricsFrom(bar)
+			if err != nil {
+				return nil, err
+			}
+			output := make([]json.Marshaler, len(metrics))
+			for i, m := range metrics {
+				output[i] = pmetricotlp.NewExportRequestFromMetrics(m)
+			}
+			return output, nil
+		})
+	}
+	return md, nil
+}
+
+func (v *verifier) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+	if v.sampled() {
+		v.verify(ctx, plogotlp.NewExportRequestFromLogs(ld), func() ([]json.Marshaler, error) {
+			bar, err := v.producer.BatchArrowRecordsFromLogs(ld)
+			if err != nil {
+				return nil, err
+			}
+			logs, err := v.consumer.LogsFrom(bar)
+			if err != nil {
+				return nil, err
+			}
+			output := make([]json.Marshaler, len(logs))
+			for i, l := range logs {
+				output[i] = plogotlp.NewExportRequestFromLogs(l)
+			}
+			return output, nil
+		})
+	}
+	return ld, nil
+}
+
+func (v *verifier) sampled() bool {
+	return v.randFloat64() < v.config.SamplingRatio
+}
+
+// verify compares input with the batches returned by roundTrip, which
+// is called with the lock held.  The failures are logged and counted,
+// they are not returned to the pipeline.
+func (v *verifier) verify(ctx context.Context, input json.Marshaler, roundTrip func() ([]json.Marshaler, error)) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	output, err := roundTrip()
+	if err != nil {
+		v.logger.Warn("Arrow round trip failed", zap.String("signal", v.signal), zap.Error(err))
+		v.telemetry.verified(ctx, outcomeError, 0)
+		// The state of the pair is unknown after a failure, the
+		// verification restarts with a new pair.
+		v.reset()
+		return
+	}
+
+	diff, err := equiv.Diff([]json.Marshaler{input}, output)
+	if err != nil {
+		v.logger.Warn("Arrow round trip comparison failed", zap.String("signal", v.signal), zap.Error(err))
+		v.telemetry.verified(ctx, outcomeError, 0)
+		return
+	}
+	if diff.Equiv() {
+		v.telemetry.verified(ctx, outcomeEquivalent, 0)
+		return
+	}
+
+	v.telemetry.verified(ctx, outcomeMismatch, len(diff.Missing)+len(diff.Unexpected))
+	fields := []zap.Field{
+		zap.String("signal", v.signal),
+		zap.Int("missing_count", len(diff.Missing)),
+		zap.Strings("missing", truncate(diff.Missing)),
+		zap.Int("unexpected_count", len(diff.Unexpected)),
+		zap.Strings("unexpected", truncate(diff.Unexpected)),
+	}
+	if path, err := v.dump(input, output, diff); err != nil {
+		fields = append(fields, zap.NamedError("dump_error", err))
+	} else if path != "" {
+		fields = append(fields, zap.String("dump", path))
+	}
+	v.logger.Error("Arrow round trip mismatch", fields...)
+}
+
+// dump writes the input batch (as OTLP JSON, one line), the decoded
+// batches (one per line) and the vPaths of a mismatch in the dump
+// directory.  It returns the prefix of the written files, or an empty
+// string when nothing is written.
+func (v *verifier) dump(input json.Marshaler, output []json.Marshaler, diff *equiv.Difference) (string, error) {
+	if v.config.DumpDirectory == "" || (v.config.MaxDumps > 0 && v.dumps >= v.config.MaxDumps) {
+		return "", nil
+	}
+	v.dumps++
+
+	name := fmt.Sprintf("%s-%s-%d", strings.ReplaceAll(v.id.String(), "/", "_"), v.signal, time.Now().UnixNano())
+	prefix := filepath.Join(v.config.DumpDirectory, name)
+
+	var inputBuf, outputBuf bytes.Buffer
+	if err := writeJSONLines(&inputBuf, []json.Marshaler{input}); err != nil {
+		return "", err
+	}
+	if err := writeJSONLines(&outputBuf, output); err != nil {
+		return "", err
+	}
+	diffBuf, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	err = multierr.Combine(
+		os.WriteFile(prefix+".input.json", inputBuf.Bytes(), 0o600),
+		os.WriteFile(prefix+".output.json", outputBuf.Bytes(), 0o600),
+		os.WriteFile(prefix+".diff.json", diffBuf, 0o600),
+	)
+	return prefix, err
+}
+
+// reset replaces the producer/consumer pair.
+func (v *verifier) reset() {
+	if err := multierr.Append(v.producer.Close(), v.consumer.Close()); err != nil {
+		v.logger.Debug("failed to close the Arrow producer/consumer", zap.Error(err))
+	}
+	v.producer = arrowRecord.NewProducer()
+	v.consumer = arrowRecord.NewConsumer()
+}
+
+func (v *verifier) shutdown(context.Context) error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	return multierr.Append(v.producer.Close(), v.consumer.Close())
+}
+
+func writeJSONLines(buf *bytes.Buffer, batches []json.Marshaler) error {
+	for _, batch := range batches {
+		data, err := batch.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return nil
+}
+
+func truncate(paths []string) []string {
+	if len(paths) > maxLoggedPaths {
+		return paths[:maxLoggedPaths]
+	}
+	return paths
+}
@@ -24,6 +24,18 @@ var (
 	errNoTableItems    = errors.New("the routing table is empty")
 	errZeroTableWeight = errors.New("zero weight table")
 	errInvalidWeight   = errors.New("negative weight is invalid")
+	errInvalidSource   = errors.New("invalid attribute source")
+	errNoAttribute     = errors.New("the attribute to hash is missing")
+	errShadowWeight    = errors.New("a shadow route has no weight")
+)
+
+const (
+	// resourceAttributeSource hashes a resource attribute, the
+	// resources of a batch mapping to different routes are split.
+	resourceAttributeSource = "resource"
+	// contextAttributeSource hashes a client metadata key, the whole
+	// batch is sent to the same route.
+	contextAttributeSource = "context"
 )
 
 // Config defines configuration for the Routing processor.
@@ -31,6 +43,16 @@ type Config struct {
 	// Table contains the routing table for this processor.
 	// Required, must be non-empty.
 	Table []RoutingTableItem `mapstructure:"table"`
+
+	// FromAttribute is the attribute hashed to select a stable route
+	// for the data, e.g. "service.name".  When empty (the default),
+	// a random route is selected for each batch.
+	FromAttribute string `mapstructure:"from_attribute"`
+
+	// AttributeSource defines where FromAttribute is looked up, either
+	// "resource" (resource attributes, the default) or "context"
+	// (client metadata).
+	AttributeSource string `mapstructure:"attribute_source"`
 }
 
 // Validate checks if the processor configuration is valid.
@@ -50,12 +72,24 @@ func (c *Config) Validate() error {
 		if len(item.Exporters) == 0 {
 			return fmt.Errorf("invalid route entry: %w", errNoExporters)
 		}
+		if item.Shadow && item.Weight != 0 {
+			return fmt.Errorf("invalid shadow route weight %d: %w", item.Weight, errShadowWeight)
+		}
 		total += item.Weight
 	}
 	if total == 0 {
 		return fmt.Errorf("invalid route table: %w", errZeroTableWeight)
 	}
 
+	switch c.AttributeSource {
+	case "", resourceAttributeSource, contextAttributeSource:
+	default:
+		return fmt.Errorf("invalid attribute source %q: %w", c.AttributeSource, errInvalidSource)
+	}
+	if c.AttributeSource != "" && c.FromAttribute == "" {
+		return fmt.Errorf("invalid attribute source %q: %w", c.AttributeSource, errNoAttribute)
+	}
+
 	return nil
 }
 
@@ -67,4 +101,10 @@ type RoutingTableItem struct {
 	// Exporters contains the list of exporters to use when this
 	// table item is selected.  Must be non-empty.
 	Exporters []string `mapstructure:"exporters"`
+
+	// Shadow designates a shadow route, which receives a copy of
+	// every batch in addition to the route selected by weight (the
+	// primary route).  The result of a shadow route is not returned
+	// to the pipeline.  The weight of a shadow route must be 0.
+	Shadow bool `mapstructure:"shadow"`
 }
@@ -47,17 +47,29 @@ func createDefaultConfig() component.Config {
 
 func createTracesProcessor(_ context.Context, params processor.CreateSettings, cfg component.Config, nextConsumer consumer.Traces) (processor.Traces, error) {
 	warnIfNotLastInPipeline(nextConsumer, params.Logger)
-	return newTracesProcessor(params.TelemetrySettings, cfg), nil
+	p, err := newTracesProcessor(params, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
 func createMetricsProcessor(_ context.Context, params processor.CreateSettings, cfg component.Config, nextConsumer consumer.Metrics) (processor.Metrics, error) {
 	warnIfNotLastInPipeline(nextConsumer, params.Logger)
-	return newMetricProcessor(params.TelemetrySettings, cfg), nil
+	p, err := newMetricProcessor(params, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
 func createLogsProcessor(_ context.Context, params processor.CreateSettings, cfg component.Config, nextConsumer consumer.Logs) (processor.Logs, error) {
 	warnIfNotLastInPipeline(nextConsumer, params.Logger)
-	return newLogProcessor(params.TelemetrySettings, cfg), nil
+	p, err := newLogProcessor(params, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
 func warnIfNotLastInPipeline(nextConsumer interface{}, logger *zap.Logger) {
@@ -20,6 +20,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/processor"
 	"go.uber.org/multierr"
@@ -32,20 +33,22 @@ type logProcessor struct {
 	logger *zap.Logger
 	config *Config
 
-	router router[exporter.Logs]
+	router *router[exporter.Logs]
 }
 
-func newLogProcessor(settings component.TelemetrySettings, config component.Config) *logProcessor {
+func newLogProcessor(settings processor.CreateSettings, config component.Config) (*logProcessor, error) {
 	cfg := config.(*Config)
 
+	r, err := newRouter[exporter.Logs](cfg, settings, component.DataTypeLogs)
+	if err != nil {
+		return nil, err
+	}
+
 	return &logProcessor{
 		logger: settings.Logger,
 		config: cfg,
-		router: newRouter[exporter.Logs](
-			cfg.Table,
-			settings,
-		),
-	}
+		router: r,
+	}, nil
 }
 
 func (p *logProcessor) Start(_ context.Context, host component.Host) error {
@@ -53,17 +56,53 @@ func (p *logProcessor) Start(_ context.Context, host component.Host) error {
 }
 
 func (p *logProcessor) ConsumeLogs(ctx context.Context, l plog.Logs) error {
-	exporters := p.router.getExporters()
+	p.router.shadow(ctx, func() (int, func(context.Context, exporter.Logs) error) {
+		batch := plog.NewLogs()
+		l.CopyTo(batch)
+		return batch.LogRecordCount(), func(ctx context.Context, e exporter.Logs) error {
+			return e.ConsumeLogs(ctx, batch)
+		}
+	})
+
+	if route, ok := p.router.batchRoute(ctx); ok {
+		return p.route(ctx, route, l)
+	}
+
+	rss := l.ResourceLogs()
+	routes, route, same := p.router.resourceRoutes(rss.Len(), func(i int) pcommon.Resource { return rss.At(i).Resource() })
+	if same {
+		return p.route(ctx, route, l)
+	}
+
+	// Split the batch by route, keeping the order of the resources.
+	batches := make(map[int]plog.Logs)
+	for i, route := range routes {
+		batch, ok := batches[route]
+		if !ok {
+			batch = plog.NewLogs()
+			batches[route] = batch
+		}
+		rss.At(i).CopyTo(batch.ResourceLogs().AppendEmpty())
+	}
 
 	var errs error
-	for _, e := range exporters {
-		errs = multierr.Append(errs, e.ConsumeLogs(ctx, l))
+	for route := range p.router.routes {
+		if batch, ok := batches[route]; ok {
+			errs = multierr.Append(errs, p.route(ctx, route, batch))
+		}
 	}
 	return errs
 }
 
+// route sends a batch to the exporters of a route.
+func (p *logProcessor) route(ctx context.Context, route int, l plog.Logs) error {
+	return p.router.send(ctx, route, l.LogRecordCount(), func(ctx context.Context, e exporter.Logs) error {
+		return e.ConsumeLogs(ctx, l)
+	})
+}
+
 func (p *logProcessor) Shutdown(context.Context) error {
-	return nil
+	return p.router.shutdown()
 }
 
 func (p *logProcessor) Capabilities() consumer.Capabilities {
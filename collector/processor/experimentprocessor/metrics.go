@@ -20,6 +20,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/processor"
 	"go.uber.org/multierr"
@@ -32,20 +33,22 @@ type metricsProcessor struct {
 	logger *zap.Logger
 	config *Config
 
-	router router[exporter.Metrics]
+	router *router[exporter.Metrics]
 }
 
-func newMetricProcessor(settings component.TelemetrySettings, config component.Config) *metricsProcessor {
+func newMetricProcessor(settings processor.CreateSettings, config component.Config) (*metricsProcessor, error) {
 	cfg := config.(*Config)
 
+	r, err := newRouter[exporter.Metrics](cfg, settings, component.DataTypeMetrics)
+	if err != nil {
+		return nil, err
+	}
+
 	return &metricsProcessor{
 		logger: settings.Logger,
 		config: cfg,
-		router: newRouter[exporter.Metrics](
-			cfg.Table,
-			settings,
-		),
-	}
+		router: r,
+	}, nil
 }
 
 func (p *metricsProcessor) Start(_ context.Context, host component.Host) error {
@@ -53,19 +56,55 @@ func (p *metricsProcessor) Start(_ context.Context, host component.Host) error {
 }
 
 func (p *metricsProcessor) ConsumeMetrics(ctx context.Context, m pmetric.Metrics) error {
-	exporters := p.router.getExporters()
+	p.router.shadow(ctx, func() (int, func(context.Context, exporter.Metrics) error) {
+		batch := pmetric.NewMetrics()
+		m.CopyTo(batch)
+		return batch.DataPointCount(), func(ctx context.Context, e exporter.Metrics) error {
+			return e.ConsumeMetrics(ctx, batch)
+		}
+	})
+
+	if route, ok := p.router.batchRoute(ctx); ok {
+		return p.route(ctx, route, m)
+	}
+
+	rss := m.ResourceMetrics()
+	routes, route, same := p.router.resourceRoutes(rss.Len(), func(i int) pcommon.Resource { return rss.At(i).Resource() })
+	if same {
+		return p.route(ctx, route, m)
+	}
+
+	// Split the batch by route, keeping the order of the resources.
+	batches := make(map[int]pmetric.Metrics)
+	for i, route := range routes {
+		batch, ok := batches[route]
+		if !ok {
+			batch = pmetric.NewMetrics()
+			batches[route] = batch
+		}
+		rss.At(i).CopyTo(batch.ResourceMetrics().AppendEmpty())
+	}
 
 	var errs error
-	for _, e := range exporters {
-		errs = multierr.Append(errs, e.ConsumeMetrics(ctx, m))
+	for route := range p.router.routes {
+		if batch, ok := batches[route]; ok {
+			errs = multierr.Append(errs, p.route(ctx, route, batch))
+		}
 	}
 	return errs
 }
 
+// route sends a batch to the exporters of a route.
+func (p *metricsProcessor) route(ctx context.Context, route int, m pmetric.Metrics) error {
+	return p.router.send(ctx, route, m.DataPointCount(), func(ctx context.Context, e exporter.Metrics) error {
+		return e.ConsumeMetrics(ctx, m)
+	})
+}
+
 func (p *metricsProcessor) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
 
 func (p *metricsProcessor) Shutdown(context.Context) error {
-	return nil
+	return p.router.shutdown()
 }
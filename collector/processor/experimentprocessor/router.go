@@ -15,43 +15,101 @@
 package experimentprocessor // import "github.com/f5/otel-arrow-adapter/collector/processor/experimentprocessor"
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"math/rand"
+	"strings"
+	"sync"
+	"time"
 
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/processor"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 )
 
 // This code is derived from collector-contrib/processor/routingprocessor.
 // It is substantially simpler because it routes whole data items, not individual
-// points, therefore there is no regrouping step.
+// points.  When routing by resource attribute, the resources of a batch are
+// regrouped by route (see routeResources).
 
 var errExporterNotFound = errors.New("exporter not found")
 
+// maxShadowBatches is the maximum number of batches in flight to the
+// shadow routes, the copies of the next batches are dropped until the
+// shadow routes catch up.
+const maxShadowBatches = 64
+
+// wireBytesReporter is implemented by the exporters reporting the
+// number of bytes they sent on the wire, e.g. the otlp exporter of
+// this repository when its network statistics are enabled.
+type wireBytesReporter interface {
+	SentWireBytes() int64
+}
+
 // router registers exporters and default exporters for an exporter. router can
 // be instantiated with exporter.Traces, exporter.Metrics, and
 // exporter.Logs type arguments.
 type router[E component.Component] struct {
-	logger *zap.Logger
+	logger    *zap.Logger
+	telemetry *routerTelemetry
 
 	randIntn func(int) int
 	table    []RoutingTableItem
 	routes   []routingItem[E]
+
+	// fromAttribute is the attribute hashed to select a route, from
+	// the resource or the client metadata (see attributeSource).
+	// Empty for random routing.
+	fromAttribute   string
+	attributeSource string
+
+	// Indexes of the shadow routes, each one receives a copy of every
+	// batch asynchronously.
+	shadows     []int
+	shadowSlots chan struct{}
+	wg          sync.WaitGroup
 }
 
 // newRouter creates a new router instance with its type parameter constrained
 // to component.Component.
 func newRouter[E component.Component](
-	table []RoutingTableItem,
-	settings component.TelemetrySettings,
-) router[E] {
-	return router[E]{
-		logger:   settings.Logger,
-		randIntn: rand.New(rand.NewSource(rand.Int63())).Intn,
-		table:    table,
-		routes:   make([]routingItem[E], len(table)),
+	cfg *Config,
+	settings processor.CreateSettings,
+	dataType component.DataType,
+) (*router[E], error) {
+	telemetry, err := newRouterTelemetry(settings, string(dataType))
+	if err != nil {
+		return nil, err
+	}
+
+	attributeSource := cfg.AttributeSource
+	if attributeSource == "" {
+		attributeSource = resourceAttributeSource
+	}
+
+	var shadows []int
+	for idx, item := range cfg.Table {
+		if item.Shadow {
+			shadows = append(shadows, idx)
+		}
 	}
+
+	return &router[E]{
+		logger:          settings.Logger,
+		telemetry:       telemetry,
+		randIntn:        rand.New(rand.NewSource(rand.Int63())).Intn,
+		table:           cfg.Table,
+		routes:          make([]routingItem[E], len(cfg.Table)),
+		fromAttribute:   cfg.FromAttribute,
+		attributeSource: attributeSource,
+		shadows:         shadows,
+		shadowSlots:     make(chan struct{}, maxShadowBatches),
+	}, nil
 }
 
 type routingItem[E component.Component] struct {
@@ -77,7 +135,22 @@ func (r *router[E]) registerExporters(available map[component.ID]component.Compo
 			route.exporters = append(route.exporters, e)
 		}
 	}
-	return nil
+	return r.telemetry.observeSentWireBytes(r.sentWireBytes)
+}
+
+// sentWireBytes returns the number of bytes sent on the wire by the
+// exporters of each route, for the routes having exporters reporting
+// it.
+func (r *router[E]) sentWireBytes() map[int]int64 {
+	sent := make(map[int]int64)
+	for idx, route := range r.routes {
+		for _, e := range route.exporters {
+			if reporter, ok := any(e).(wireBytesReporter); ok {
+				sent[idx] += reporter.SentWireBytes()
+			}
+		}
+	}
+	return sent
 }
 
 // extractExporter returns an exporter for the given name (type/name) and type
@@ -112,19 +185,123 @@ func (r *router[E]) extractExporter(name string, available map[component.ID]comp
 	return exporter, nil
 }
 
-func (r *router[E]) getExporters() []E {
+// routeOf returns the index of the route selected by x, in the range
+// [0, totalWeight).
+func (r *router[E]) routeOf(x int) int {
 	n := len(r.routes)
 
-	// Generate a random number in the range [0, totalWeight)
-	// using the last item's cumulative weight.
-	x := r.randIntn(r.routes[n-1].cumWeight)
-
 	// Note: linear search. We could use sort.Search() but with a
 	// small routing table this is likely faster.
-	for _, route := range r.routes[:n-1] {
+	for idx, route := range r.routes[:n-1] {
 		if route.cumWeight > x {
-			return route.exporters
+			return idx
+		}
+	}
+	return n - 1
+}
+
+// hashRoute returns the stable route of an attribute value.
+func (r *router[E]) hashRoute(value string) int {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(value))
+	return r.routeOf(int(h.Sum64() % uint64(r.routes[len(r.routes)-1].cumWeight)))
+}
+
+// batchRoute returns the route of a whole batch.  It returns false when
+// the route depends on the resources of the batch (see resourceRoute).
+func (r *router[E]) batchRoute(ctx context.Context) (int, bool) {
+	switch {
+	case r.fromAttribute == "":
+		// Generate a random number in the range [0, totalWeight)
+		// using the last item's cumulative weight.
+		return r.routeOf(r.randIntn(r.routes[len(r.routes)-1].cumWeight)), true
+	case r.attributeSource == contextAttributeSource:
+		values := client.FromContext(ctx).Metadata.Get(r.fromAttribute)
+		return r.hashRoute(strings.Join(values, ",")), true
+	default:
+		return 0, false
+	}
+}
+
+// resourceRoute returns the route of a resource.  The resources without
+// the attribute share the same route.
+func (r *router[E]) resourceRoute(resource pcommon.Resource) int {
+	value, ok := resource.Attributes().Get(r.fromAttribute)
+	if !ok {
+		return r.hashRoute("")
+	}
+	return r.hashRoute(value.AsString())
+}
+
+// resourceRoutes returns the route of each of the n resources of a
+// batch.  When they all share the same route (or the batch is empty),
+// it returns this route and true instead.
+func (r *router[E]) resourceRoutes(n int, resource func(int) pcommon.Resource) ([]int, int, bool) {
+	if n == 0 {
+		return nil, r.hashRoute(""), true
+	}
+
+	routes := make([]int, n)
+	same := true
+	for i := range routes {
+		routes[i] = r.resourceRoute(resource(i))
+		same = same && routes[i] == routes[0]
+	}
+	if same {
+		return nil, routes[0], true
+	}
+	return routes, 0, false
+}
+
+// send sends a batch of items to the exporters of a route.
+func (r *router[E]) send(ctx context.Context, route int, items int, consume func(context.Context, E) error) error {
+	start := time.Now()
+
+	var errs error
+	for _, e := range r.routes[route].exporters {
+		errs = multierr.Append(errs, consume(ctx, e))
+	}
+
+	r.telemetry.routed(ctx, route, items, time.Since(start), errs)
+	return errs
+}
+
+// shadow sends a copy of a batch to each shadow route without waiting
+// for the result.  batch returns a new copy of the batch, as its number
+// of items and the function sending it to an exporter.
+func (r *router[E]) shadow(ctx context.Context, batch func() (int, func(context.Context, E) error)) {
+	if len(r.shadows) == 0 {
+		return
+	}
+
+	// The shadow routes outlive the request, only its client
+	// information is kept.
+	shadowCtx := client.NewContext(context.Background(), client.FromContext(ctx))
+
+	for _, route := range r.shadows {
+		select {
+		case r.shadowSlots <- struct{}{}:
+		default:
+			r.telemetry.dropped(ctx, route)
+			continue
 		}
+
+		items, consume := batch()
+		r.wg.Add(1)
+		go func(route int) {
+			defer func() {
+				<-r.shadowSlots
+				r.wg.Done()
+			}()
+			if err := r.send(shadowCtx, route, items, consume); err != nil {
+				r.logger.Debug("shadow route failed", zap.Int("route", route), zap.Error(err))
+			}
+		}(route)
 	}
-	return r.routes[n-1].exporters
+}
+
+// shutdown waits for the batches in flight to the shadow routes.
+func (r *router[E]) shutdown() error {
+	r.wg.Wait()
+	return r.telemetry.close()
 }
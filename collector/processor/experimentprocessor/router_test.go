@@ -16,15 +16,22 @@ package experimentprocessor
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/ptrace"
-	"go.uber.org/zap"
+	"go.opentelemetry.io/collector/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 type mockHost struct {
@@ -42,6 +49,24 @@ type mockTracesExporter struct {
 	consumertest.TracesSink
 }
 
+// mockWireTracesExporter is a traces exporter reporting the number of
+// bytes it sent on the wire.
+type mockWireTracesExporter struct {
+	mockTracesExporter
+	wireBytes int64
+}
+
+func (m *mockWireTracesExporter) SentWireBytes() int64 {
+	return m.wireBytes
+}
+
+// mockFailingTracesExporter is a traces exporter failing to consume
+// traces.
+type mockFailingTracesExporter struct {
+	mockComponent
+	consumer.Traces
+}
+
 type mockComponent struct {
 	component.StartFunc
 	component.ShutdownFunc
@@ -70,9 +95,10 @@ func TestTraces(t *testing.T) {
 			},
 		})
 
-		eproc := newTracesProcessor(component.TelemetrySettings{Logger: zap.NewNop()}, &Config{
+		eproc, err := newTracesProcessor(processortest.NewNopCreateSettings(), &Config{
 			Table: table,
 		})
+		require.NoError(t, err)
 		// Use a deterministic uniform random source.
 		index := new(int)
 		eproc.router.randIntn = func(d int) int {
@@ -158,3 +184,282 @@ func TestTraces(t *testing.T) {
 		assert.Len(t, exps[2].AllTraces(), 3000)
 	})
 }
+
+// services returns the service names of the resources of some traces.
+func services(traces []ptrace.Traces) map[string]int {
+	result := map[string]int{}
+	for _, tr := range traces {
+		for i := 0; i < tr.ResourceSpans().Len(); i++ {
+			rs := tr.ResourceSpans().At(i)
+			name, _ := rs.Resource().Attributes().Get("service.name")
+			result[name.AsString()] += rs.ScopeSpans().At(0).Spans().Len()
+		}
+	}
+	return result
+}
+
+func TestHashedTraces(t *testing.T) {
+	exps := []*mockTracesExporter{{}, {}}
+	host := newMockHost(map[component.DataType]map[component.ID]component.Component{
+		component.DataTypeTraces: {
+			component.NewIDWithName("otlp", "arrow"): exps[0],
+			component.NewIDWithName("otlp", "std"):   exps[1],
+		},
+	})
+
+	rdr := metric.NewManualReader()
+	settings := processortest.NewNopCreateSettings()
+	settings.ID = component.NewID(typeStr)
+	settings.MeterProvider = metric.NewMeterProvider(metric.WithReader(rdr))
+	settings.MetricsLevel = configtelemetry.LevelBasic
+
+	eproc, err := newTracesProcessor(settings, &Config{
+		Table: []RoutingTableItem{
+			{Weight: 50, Exporters: []string{"otlp/arrow"}},
+			{Weight: 50, Exporters: []string{"otlp/std"}},
+		},
+		FromAttribute: "service.name",
+	})
+	require.NoError(t, err)
+	require.NoError(t, eproc.Start(context.Background(), host))
+
+	const batches = 10
+	const serviceCount = 20
+	for count := 0; count < batches; count++ {
+		tr := ptrace.NewTraces()
+		for s := 0; s < serviceCount; s++ {
+			rs := tr.ResourceSpans().AppendEmpty()
+			rs.Resource().Attributes().PutStr("service.name", fmt.Sprintf("service-%d", s))
+			spans := rs.ScopeSpans().AppendEmpty().Spans()
+			for i := 0; i <= s; i++ {
+				spans.AppendEmpty().SetName("span")
+			}
+		}
+		require.NoError(t, eproc.ConsumeTraces(context.Background(), tr))
+	}
+
+	// Each batch is split, a service is always sent to the same route.
+	arrowServices := services(exps[0].AllTraces())
+	stdServices := services(exps[1].AllTraces())
+	assert.Len(t, exps[0].AllTraces(), batches)
+	assert.Len(t, exps[1].AllTraces(), batches)
+	assert.NotEmpty(t, arrowServices)
+	assert.NotEmpty(t, stdServices)
+	assert.Equal(t, serviceCount, len(arrowServices)+len(stdServices))
+	for name := range arrowServices {
+		assert.NotContains(t, stdServices, name)
+	}
+
+	// The routed spans are counted by route.
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, rdr.Collect(context.Background(), &rm))
+	routed := map[int64]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "processor_experiment_routed_items" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				route, _ := dp.Attributes.Value(routeKey)
+				signal, _ := dp.Attributes.Value(signalKey)
+				processor, _ := dp.Attributes.Value(processorKey)
+				assert.Equal(t, "traces", signal.AsString())
+				assert.Equal(t, typeStr, processor.AsString())
+				routed[route.AsInt64()] += dp.Value
+			}
+		}
+	}
+	arrowSpans, stdSpans := 0, 0
+	for _, spans := range arrowServices {
+		arrowSpans += spans
+	}
+	for _, spans := range stdServices {
+		stdSpans += spans
+	}
+	assert.Equal(t, map[int64]int64{0: int64(arrowSpans), 1: int64(stdSpans)}, routed)
+}
+
+func TestContextHashedTraces(t *testing.T) {
+	exps := []*mockTracesExporter{{}, {}}
+	host := newMockHost(map[component.DataType]map[component.ID]component.Component{
+		component.DataTypeTraces: {
+			component.NewIDWithName("otlp", "arrow"): exps[0],
+			component.NewIDWithName("otlp", "std"):   exps[1],
+		},
+	})
+
+	eproc, err := newTracesProcessor(processortest.NewNopCreateSettings(), &Config{
+		Table: []RoutingTableItem{
+			{Weight: 50, Exporters: []string{"otlp/arrow"}},
+			{Weight: 50, Exporters: []string{"otlp/std"}},
+		},
+		FromAttribute:   "tenant",
+		AttributeSource: contextAttributeSource,
+	})
+	require.NoError(t, err)
+	require.NoError(t, eproc.Start(context.Background(), host))
+
+	// The route of each tenant is stable.
+	routes := map[string]int{}
+	for count := 0; count < 100; count++ {
+		tenant := fmt.Sprintf("tenant-%d", count%10)
+		ctx := client.NewContext(context.Background(), client.Info{
+			Metadata: client.NewMetadata(map[string][]string{"tenant": {tenant}}),
+		})
+
+		tr := ptrace.NewTraces()
+		tr.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+
+		before := len(exps[0].AllTraces())
+		require.NoError(t, eproc.ConsumeTraces(ctx, tr))
+		route := 1
+		if len(exps[0].AllTraces()) > before {
+			route = 0
+		}
+
+		if expected, ok := routes[tenant]; ok {
+			assert.Equal(t, expected, route)
+		}
+		routes[tenant] = route
+	}
+	assert.Equal(t, 100, len(exps[0].AllTraces())+len(exps[1].AllTraces()))
+	assert.NotEmpty(t, exps[0].AllTraces())
+	assert.NotEmpty(t, exps[1].AllTraces())
+}
+
+func TestConfigValidate(t *testing.T) {
+	table := []RoutingTableItem{{Weight: 1, Exporters: []string{"otlp"}}}
+
+	assert.NoError(t, (&Config{Table: table}).Validate())
+	assert.NoError(t, (&Config{Table: table, FromAttribute: "service.name"}).Validate())
+	assert.NoError(t, (&Config{Table: table, FromAttribute: "tenant", AttributeSource: contextAttributeSource}).Validate())
+	assert.ErrorIs(t, (&Config{Table: table, FromAttribute: "tenant", AttributeSource: "header"}).Validate(), errInvalidSource)
+	assert.ErrorIs(t, (&Config{Table: table, AttributeSource: resourceAttributeSource}).Validate(), errNoAttribute)
+
+	shadow := append(table, RoutingTableItem{Shadow: true, Exporters: []string{"otlp/shadow"}})
+	assert.NoError(t, (&Config{Table: shadow}).Validate())
+	shadow[1].Weight = 1
+	assert.ErrorIs(t, (&Config{Table: shadow}).Validate(), errShadowWeight)
+}
+
+func TestShadowTraces(t *testing.T) {
+	primary := &mockTracesExporter{}
+	shadow := &mockWireTracesExporter{wireBytes: 1234}
+	failing := &mockFailingTracesExporter{Traces: consumertest.NewErr(errors.New("shadow failure"))}
+	host := newMockHost(map[component.DataType]map[component.ID]component.Component{
+		component.DataTypeTraces: {
+			component.NewIDWithName("otlp", "primary"): primary,
+			component.NewIDWithName("otlp", "shadow"):  shadow,
+			component.NewIDWithName("otlp", "failing"): failing,
+		},
+	})
+
+	rdr := metric.NewManualReader()
+	settings := processortest.NewNopCreateSettings()
+	settings.ID = component.NewID(typeStr)
+	settings.MeterProvider = metric.NewMeterProvider(metric.WithReader(rdr))
+	settings.MetricsLevel = configtelemetry.LevelBasic
+
+	eproc, err := newTracesProcessor(settings, &Config{
+		Table: []RoutingTableItem{
+			{Weight: 1, Exporters: []string{"otlp/primary"}},
+			{Shadow: true, Exporters: []string{"otlp/shadow"}},
+			{Shadow: true, Exporters: []string{"otlp/failing"}},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, eproc.Start(context.Background(), host))
+
+	const batches = 10
+	for count := 0; count < batches; count++ {
+		tr := ptrace.NewTraces()
+		spans := tr.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+		spans.AppendEmpty().SetName("span")
+		spans.AppendEmpty().SetName("span")
+
+		// The failure of a shadow route is not returned.
+		require.NoError(t, eproc.ConsumeTraces(context.Background(), tr))
+	}
+	// Wait for the shadow copies, the metrics are collected before the
+	// shutdown unregisters the wire bytes callback.
+	eproc.router.wg.Wait()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, rdr.Collect(context.Background(), &rm))
+	require.NoError(t, eproc.Shutdown(context.Background()))
+
+	// Every batch is sent to the primary route and copied to the shadow
+	// routes.
+	require.Len(t, primary.AllTraces(), batches)
+	require.Len(t, shadow.AllTraces(), batches)
+	for i, tr := range shadow.AllTraces() {
+		assert.Equal(t, 2, tr.SpanCount())
+		assert.NotSame(t, &primary.AllTraces()[i], &tr)
+	}
+
+	outcomes := map[string]int64{}
+	items := map[int64]int64{}
+	latencies := map[int64]uint64{}
+	wireBytes := map[int64]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch m.Name {
+			case "processor_experiment_routed_items":
+				for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					route, _ := dp.Attributes.Value(routeKey)
+					items[route.AsInt64()] += dp.Value
+				}
+			case "processor_experiment_routed_batches":
+				for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					route, _ := dp.Attributes.Value(routeKey)
+					outcome, _ := dp.Attributes.Value(outcomeKey)
+					outcomes[fmt.Sprintf("%d/%s", route.AsInt64(), outcome.AsString())] += dp.Value
+				}
+			case "processor_experiment_route_latency":
+				for _, dp := range m.Data.(metricdata.Histogram[float64]).DataPoints {
+					route, _ := dp.Attributes.Value(routeKey)
+					latencies[route.AsInt64()] += dp.Count
+				}
+			case "processor_experiment_route_sent_wire":
+				for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					route, _ := dp.Attributes.Value(routeKey)
+					wireBytes[route.AsInt64()] += dp.Value
+				}
+			}
+		}
+	}
+
+	assert.Equal(t, map[int64]int64{0: 2 * batches, 1: 2 * batches, 2: 2 * batches}, items)
+	assert.Equal(t, map[string]int64{"0/success": batches, "1/success": batches, "2/failure": batches}, outcomes)
+	assert.Equal(t, map[int64]uint64{0: batches, 1: batches, 2: batches}, latencies)
+	assert.Equal(t, map[int64]int64{1: 1234}, wireBytes)
+}
+
+func TestShadowDropped(t *testing.T) {
+	shadow := &mockTracesExporter{}
+	host := newMockHost(map[component.DataType]map[component.ID]component.Component{
+		component.DataTypeTraces: {
+			component.NewIDWithName("otlp", "primary"): &mockTracesExporter{},
+			component.NewIDWithName("otlp", "shadow"):  shadow,
+		},
+	})
+
+	eproc, err := newTracesProcessor(processortest.NewNopCreateSettings(), &Config{
+		Table: []RoutingTableItem{
+			{Weight: 1, Exporters: []string{"otlp/primary"}},
+			{Shadow: true, Exporters: []string{"otlp/shadow"}},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, eproc.Start(context.Background(), host))
+
+	// The shadow route is saturated, the copies are dropped.
+	for i := 0; i < maxShadowBatches; i++ {
+		eproc.router.shadowSlots <- struct{}{}
+	}
+	require.NoError(t, eproc.ConsumeTraces(context.Background(), ptrace.NewTraces()))
+	for i := 0; i < maxShadowBatches; i++ {
+		<-eproc.router.shadowSlots
+	}
+	require.NoError(t, eproc.Shutdown(context.Background()))
+	assert.Empty(t, shadow.AllTraces())
+}
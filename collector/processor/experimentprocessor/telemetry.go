@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package experimentprocessor // import "github.com/f5/otel-arrow-adapter/collector/processor/experimentprocessor"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/multierr"
+)
+
+const (
+	// processorKey identifies the processor reporting a metric.
+	processorKey = "processor"
+	// signalKey identifies the signal of the routed items.
+	signalKey = "signal"
+	// routeKey is the index of the route in the routing table.
+	routeKey = "route"
+	// outcomeKey is the outcome of a batch sent to a route, one of
+	// the outcome* values below.
+	outcomeKey = "outcome"
+
+	scopeName = "github.com/f5/otel-arrow-adapter/collector/processor/experimentprocessor"
+	prefix    = "processor_experiment_"
+)
+
+// Outcomes of a batch sent to a route.
+const (
+	// outcomeSuccess: all the exporters of the route succeeded.
+	outcomeSuccess = "success"
+	// outcomeFailure: at least one exporter of the route failed.
+	outcomeFailure = "failure"
+	// outcomeDropped: the copy of a batch was not sent to a shadow
+	// route because too many copies were in flight.
+	outcomeDropped = "dropped"
+)
+
+// routerTelemetry reports the number of items sent to each route, and
+// the latency, the outcome and the wire bytes of each route to compare
+// them.  A nil routerTelemetry is valid and reports nothing, this is
+// the case when the telemetry is disabled.
+type routerTelemetry struct {
+	meter     metric.Meter
+	component attribute.KeyValue
+	signal    attribute.KeyValue
+
+	routedItems   metric.Int64Counter
+	routedBatches metric.Int64Counter
+	routeLatency  metric.Float64Histogram
+	sentWireBytes metric.Int64ObservableCounter
+
+	registration metric.Registration
+}
+
+// newRouterTelemetry creates the instruments of a processor.
+func newRouterTelemetry(settings processor.CreateSettings, signal string) (*routerTelemetry, error) {
+	if settings.TelemetrySettings.MetricsLevel <= configtelemetry.LevelNone {
+		return nil, nil
+	}
+
+	meter := settings.TelemetrySettings.MeterProvider.Meter(scopeName)
+	rt := &routerTelemetry{
+		meter:     meter,
+		component: attribute.String(processorKey, settings.ID.String()),
+		signal:    attribute.String(signalKey, signal),
+	}
+
+	var errors, err error
+	rt.routedItems, err = meter.Int64Counter(prefix+"routed_items", metric.WithDescription("Number of items (spans, data points or log records) sent to a route."))
+	errors = multierr.Append(errors, err)
+	rt.routedBatches, err = meter.Int64Counter(prefix+"routed_batches", metric.WithDescription("Number of batches sent to a route, by outcome."))
+	errors = multierr.Append(errors, err)
+	rt.routeLatency, err = meter.Float64Histogram(prefix+"route_latency", metric.WithDescription("Time spent by the exporters of a route to consume a batch."), metric.WithUnit("s"))
+	errors = multierr.Append(errors, err)
+	rt.sentWireBytes, err = meter.Int64ObservableCounter(prefix+"route_sent_wire", metric.WithDescription("Number of bytes sent on the wire by the exporters of a route that report it."), metric.WithUnit("bytes"))
+	errors = multierr.Append(errors, err)
+	if errors != nil {
+		return nil, errors
+	}
+	return rt, nil
+}
+
+// routed reports a batch sent to a route, with the time spent by the
+// exporters of the route and their error.
+func (rt *routerTelemetry) routed(ctx context.Context, route int, items int, elapsed time.Duration, err error) {
+	if rt == nil {
+		return
+	}
+	attrs := metric.WithAttributes(rt.component, rt.signal, attribute.Int(routeKey, route))
+	outcome := outcomeSuccess
+	if err != nil {
+		outcome = outcomeFailure
+	}
+	rt.routedItems.Add(ctx, int64(items), attrs)
+	rt.routedBatches.Add(ctx, 1, metric.WithAttributes(rt.component, rt.signal, attribute.Int(routeKey, route), attribute.String(outcomeKey, outcome)))
+	rt.routeLatency.Record(ctx, elapsed.Seconds(), attrs)
+}
+
+// dropped reports a batch not sent to a shadow route.
+func (rt *routerTelemetry) dropped(ctx context.Context, route int) {
+	if rt == nil {
+		return
+	}
+	rt.routedBatches.Add(ctx, 1, metric.WithAttributes(rt.component, rt.signal, attribute.Int(routeKey, route), attribute.String(outcomeKey, outcomeDropped)))
+}
+
+// observeSentWireBytes registers the callback reporting the bytes sent
+// on the wire by each route, given by sentWireBytes (only the routes
+// with an exporter reporting it are returned).
+func (rt *routerTelemetry) observeSentWireBytes(sentWireBytes func() map[int]int64) error {
+	if rt == nil {
+		return nil
+	}
+	var err error
+	rt.registration, err = rt.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for route, bytes := range sentWireBytes() {
+			o.ObserveInt64(rt.sentWireBytes, bytes, metric.WithAttributes(rt.component, rt.signal, attribute.Int(routeKey, route)))
+		}
+		return nil
+	}, rt.sentWireBytes)
+	return err
+}
+
+// close unregisters the callback of the observable instruments.
+func (rt *routerTelemetry) close() error {
+	if rt == nil || rt.registration == nil {
+		return nil
+	}
+	return rt.registration.Unregister()
+}
@@ -20,6 +20,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor"
 	"go.uber.org/multierr"
@@ -32,20 +33,22 @@ type tracesProcessor struct {
 	logger *zap.Logger
 	config *Config
 
-	router router[exporter.Traces]
+	router *router[exporter.Traces]
 }
 
-func newTracesProcessor(settings component.TelemetrySettings, config component.Config) *tracesProcessor {
+func newTracesProcessor(settings processor.CreateSettings, config component.Config) (*tracesProcessor, error) {
 	cfg := config.(*Config)
 
+	r, err := newRouter[exporter.Traces](cfg, settings, component.DataTypeTraces)
+	if err != nil {
+		return nil, err
+	}
+
 	return &tracesProcessor{
 		logger: settings.Logger,
 		config: cfg,
-		router: newRouter[exporter.Traces](
-			cfg.Table,
-			settings,
-		),
-	}
+		router: r,
+	}, nil
 }
 
 func (p *tracesProcessor) Start(_ context.Context, host component.Host) error {
@@ -53,19 +56,55 @@ func (p *tracesProcessor) Start(_ context.Context, host component.Host) error {
 }
 
 func (p *tracesProcessor) ConsumeTraces(ctx context.Context, t ptrace.Traces) error {
-	exporters := p.router.getExporters()
+	p.router.shadow(ctx, func() (int, func(context.Context, exporter.Traces) error) {
+		batch := ptrace.NewTraces()
+		t.CopyTo(batch)
+		return batch.SpanCount(), func(ctx context.Context, e exporter.Traces) error {
+			return e.ConsumeTraces(ctx, batch)
+		}
+	})
+
+	if route, ok := p.router.batchRoute(ctx); ok {
+		return p.route(ctx, route, t)
+	}
+
+	rss := t.ResourceSpans()
+	routes, route, same := p.router.resourceRoutes(rss.Len(), func(i int) pcommon.Resource { return rss.At(i).Resource() })
+	if same {
+		return p.route(ctx, route, t)
+	}
+
+	// Split the batch by route, keeping the order of the resources.
+	batches := make(map[int]ptrace.Traces)
+	for i, route := range routes {
+		batch, ok := batches[route]
+		if !ok {
+			batch = ptrace.NewTraces()
+			batches[route] = batch
+		}
+		rss.At(i).CopyTo(batch.ResourceSpans().AppendEmpty())
+	}
 
 	var errs error
-	for _, e := range exporters {
-		errs = multierr.Append(errs, e.ConsumeTraces(ctx, t))
+	for route := range p.router.routes {
+		if batch, ok := batches[route]; ok {
+			errs = multierr.Append(errs, p.route(ctx, route, batch))
+		}
 	}
 	return errs
 }
 
+// route sends a batch to the exporters of a route.
+func (p *tracesProcessor) route(ctx context.Context, route int, t ptrace.Traces) error {
+	return p.router.send(ctx, route, t.SpanCount(), func(ctx context.Context, e exporter.Traces) error {
+		return e.ConsumeTraces(ctx, t)
+	})
+}
+
 func (p *tracesProcessor) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
 
 func (p *tracesProcessor) Shutdown(context.Context) error {
-	return nil
+	return p.router.shutdown()
 }
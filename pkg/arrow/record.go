@@ -22,7 +22,9 @@ package arrow
 
 import (
 	"fmt"
+	"io"
 	"math"
+	"os"
 	"strings"
 
 	"github.com/apache/arrow/go/v12/arrow"
@@ -45,49 +47,62 @@ const (
 	MaxBinSize = "%20x"
 )
 
-// PrintRecord prints the contents of an Arrow record to stdout.
+// PrintRecord prints the contents of an Arrow record to stdout, the frame of
+// the table (separators and line breaks) being printed to stderr.
 func PrintRecord(name string, record arrow.Record, maxRows, countPrints, maxPrints int) {
-	println()
+	writeRecord(os.Stdout, os.Stderr, name, record, maxRows, countPrints, maxPrints)
+}
+
+// FprintRecord prints the first maxRows rows of an Arrow record to w, as a
+// table with one column per leaf field.
+func FprintRecord(w io.Writer, name string, record arrow.Record, maxRows, countPrints, maxPrints int) {
+	writeRecord(w, w, name, record, maxRows, countPrints, maxPrints)
+}
+
+// writeRecord writes the header and the values of the record to out, and the
+// frame of the table to frame.
+func writeRecord(out, frame io.Writer, name string, record arrow.Record, maxRows, countPrints, maxPrints int) {
+	fmt.Fprintln(frame)
 
 	if record.NumRows() > int64(maxRows) {
-		fmt.Printf("Record %q -> #rows: %d/%d, prints: %d/%d\n", name, maxRows, record.NumRows(), countPrints, maxPrints)
+		fmt.Fprintf(out, "Record %q -> #rows: %d/%d, prints: %d/%d\n", name, maxRows, record.NumRows(), countPrints, maxPrints)
 	} else {
-		fmt.Printf("Record %q -> #rows: %d, prints: %d/%d\n", name, record.NumRows(), countPrints, maxPrints)
+		fmt.Fprintf(out, "Record %q -> #rows: %d, prints: %d/%d\n", name, record.NumRows(), countPrints, maxPrints)
 	}
 
 	schema := record.Schema()
 	colNames := schemaColNames(schema)
 
 	for i := 0; i < len(colNames); i++ {
-		print(strings.Repeat("-", MaxColSize), "-+")
+		fmt.Fprint(frame, strings.Repeat("-", MaxColSize), "-+")
 	}
-	println()
+	fmt.Fprintln(frame)
 
 	for _, colName := range colNames {
 		if len(colName) > MaxColSize {
 			colName = colName[:MaxColSize]
 		}
-		fmt.Printf(MaxStrSize, colName)
-		print(" |")
+		fmt.Fprintf(out, MaxStrSize, colName)
+		fmt.Fprint(frame, " |")
 	}
-	println()
+	fmt.Fprintln(frame)
 
 	for i := 0; i < len(colNames); i++ {
-		print(strings.Repeat("-", MaxColSize), "-+")
+		fmt.Fprint(frame, strings.Repeat("-", MaxColSize), "-+")
 	}
-	println()
+	fmt.Fprintln(frame)
 
-	rows := int(math.Min(500.0, float64(record.NumRows())))
+	rows := int(math.Min(float64(maxRows), float64(record.NumRows())))
 	for row := 0; row < rows; row++ {
 		values := recordColValues(record, row)
 		for _, value := range values {
 			if len(value) > MaxColSize {
 				value = value[:MaxColSize]
 			}
-			fmt.Printf(MaxStrSize, value)
-			print(" |")
+			fmt.Fprintf(out, MaxStrSize, value)
+			fmt.Fprint(frame, " |")
 		}
-		println()
+		fmt.Fprintln(frame)
 	}
 }
 
@@ -223,7 +238,6 @@ func arrayColValues(arr arrow.Array, row int) []string {
 	default:
 		panic(fmt.Sprintf("unsupported array type %T", arr))
 	}
-	return []string{}
 }
 
 func sparseUnionValue(union *array.SparseUnion, row int) string {
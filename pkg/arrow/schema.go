@@ -19,7 +19,10 @@ package arrow
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"sort"
+	"strings"
 
 	"github.com/apache/arrow/go/v12/arrow"
 )
@@ -201,82 +204,122 @@ func ShowSchema(schema *arrow.Schema, prefix string) {
 	println(prefix + "}")
 }
 
+// SchemaToString returns the representation of the schema printed by
+// ShowSchema.
+func SchemaToString(schema *arrow.Schema, prefix string) string {
+	var sb strings.Builder
+	WriteSchema(&sb, schema, prefix)
+	return sb.String()
+}
+
+// WriteSchema writes a representation of the schema to w. Unlike ShowSchema,
+// the data types not supported by ShowDataType are rendered by Arrow.
+func WriteSchema(w io.Writer, schema *arrow.Schema, prefix string) {
+	fmt.Fprintln(w, prefix+"Schema {")
+	for _, f := range schema.Fields() {
+		WriteField(w, &f, prefix+"  ")
+	}
+	fmt.Fprintln(w, prefix+"}")
+}
+
 func ShowField(field *arrow.Field, prefix string) {
-	fmt.Printf("%s%s: ", prefix, field.Name)
-	ShowDataType(field.Type, prefix)
-	fmt.Println()
+	writeField(os.Stdout, field, prefix, true)
+}
+
+// WriteField writes a representation of the field to w.
+func WriteField(w io.Writer, field *arrow.Field, prefix string) {
+	writeField(w, field, prefix, false)
 }
 
 func ShowDataType(dt arrow.DataType, prefix string) {
+	writeDataType(os.Stdout, dt, prefix, true)
+}
+
+// WriteDataType writes a representation of the data type to w.
+func WriteDataType(w io.Writer, dt arrow.DataType, prefix string) {
+	writeDataType(w, dt, prefix, false)
+}
+
+func writeField(w io.Writer, field *arrow.Field, prefix string, strict bool) {
+	fmt.Fprintf(w, "%s%s: ", prefix, field.Name)
+	writeDataType(w, field.Type, prefix, strict)
+	fmt.Fprintln(w)
+}
+
+// writeDataType panics on the unsupported data types when strict is set.
+func writeDataType(w io.Writer, dt arrow.DataType, prefix string, strict bool) {
 	switch t := dt.(type) {
 	case *arrow.BooleanType:
-		fmt.Printf("Bool")
+		fmt.Fprintf(w, "Bool")
 	case *arrow.Int8Type:
-		fmt.Printf("Int8")
+		fmt.Fprintf(w, "Int8")
 	case *arrow.Int16Type:
-		fmt.Printf("Int16")
+		fmt.Fprintf(w, "Int16")
 	case *arrow.Int32Type:
-		fmt.Printf("Int32")
+		fmt.Fprintf(w, "Int32")
 	case *arrow.Int64Type:
-		fmt.Printf("Int64")
+		fmt.Fprintf(w, "Int64")
 	case *arrow.Uint8Type:
-		fmt.Printf("Uint8")
+		fmt.Fprintf(w, "Uint8")
 	case *arrow.Uint16Type:
-		fmt.Printf("Uint16")
+		fmt.Fprintf(w, "Uint16")
 	case *arrow.Uint32Type:
-		fmt.Printf("Uint32")
+		fmt.Fprintf(w, "Uint32")
 	case *arrow.Uint64Type:
-		fmt.Printf("Uint64")
+		fmt.Fprintf(w, "Uint64")
 	case *arrow.Float32Type:
-		fmt.Printf("Float32")
+		fmt.Fprintf(w, "Float32")
 	case *arrow.Float64Type:
-		fmt.Printf("Float64")
+		fmt.Fprintf(w, "Float64")
 	case *arrow.StringType:
-		fmt.Printf("String")
+		fmt.Fprintf(w, "String")
 	case *arrow.BinaryType:
-		fmt.Printf("Binary")
+		fmt.Fprintf(w, "Binary")
 	case *arrow.TimestampType:
-		fmt.Printf("Timestamp")
+		fmt.Fprintf(w, "Timestamp")
 	case *arrow.DurationType:
-		fmt.Printf("Duration")
+		fmt.Fprintf(w, "Duration")
 	case *arrow.StructType:
-		fmt.Printf("Struct {\n")
+		fmt.Fprintf(w, "Struct {\n")
 		for _, field := range t.Fields() {
-			ShowField(&field, prefix+"  ")
+			writeField(w, &field, prefix+"  ", strict)
 		}
-		fmt.Printf("%s}", prefix)
+		fmt.Fprintf(w, "%s}", prefix)
 	case *arrow.ListType:
-		fmt.Printf("[")
+		fmt.Fprintf(w, "[")
 		elemField := t.ElemField()
-		ShowDataType(elemField.Type, prefix)
-		fmt.Printf("]")
+		writeDataType(w, elemField.Type, prefix, strict)
+		fmt.Fprintf(w, "]")
 	case *arrow.DictionaryType:
-		fmt.Printf("Dictionary<key:")
-		ShowDataType(t.IndexType, prefix)
-		fmt.Printf(",value:")
-		ShowDataType(t.ValueType, prefix)
-		fmt.Printf(">")
+		fmt.Fprintf(w, "Dictionary<key:")
+		writeDataType(w, t.IndexType, prefix, strict)
+		fmt.Fprintf(w, ",value:")
+		writeDataType(w, t.ValueType, prefix, strict)
+		fmt.Fprintf(w, ">")
 	case *arrow.DenseUnionType:
-		fmt.Printf("DenseUnion {\n")
+		fmt.Fprintf(w, "DenseUnion {\n")
 		for _, field := range t.Fields() {
-			ShowField(&field, prefix+"  ")
+			writeField(w, &field, prefix+"  ", strict)
 		}
-		fmt.Printf("%s}", prefix)
+		fmt.Fprintf(w, "%s}", prefix)
 	case *arrow.SparseUnionType:
-		fmt.Printf("SparseUnion {\n")
+		fmt.Fprintf(w, "SparseUnion {\n")
 		for _, field := range t.Fields() {
-			ShowField(&field, prefix+"  ")
+			writeField(w, &field, prefix+"  ", strict)
 		}
-		fmt.Printf("%s}", prefix)
+		fmt.Fprintf(w, "%s}", prefix)
 	case *arrow.MapType:
-		fmt.Printf("Map<")
-		ShowDataType(t.KeyType(), prefix)
-		fmt.Printf(",")
-		ShowDataType(t.ItemType(), prefix)
-		fmt.Printf(">")
+		fmt.Fprintf(w, "Map<")
+		writeDataType(w, t.KeyType(), prefix, strict)
+		fmt.Fprintf(w, ",")
+		writeDataType(w, t.ItemType(), prefix, strict)
+		fmt.Fprintf(w, ">")
 	case *arrow.FixedSizeBinaryType:
-		fmt.Printf("FixedSizeBinary<%d>", t.ByteWidth)
+		fmt.Fprintf(w, "FixedSizeBinary<%d>", t.ByteWidth)
 	default:
-		panic("unsupported data type " + dt.String())
+		if strict {
+			panic("unsupported data type " + dt.String())
+		}
+		fmt.Fprintf(w, "%s", dt)
 	}
 }
@@ -16,10 +16,17 @@ package benchmark
 
 import (
 	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io"
+	"strings"
 
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/snappy"
 	"github.com/klauspost/compress/zstd"
 	"github.com/pierrec/lz4"
+
+	"github.com/f5/otel-arrow-adapter/pkg/zstddict"
 )
 
 type CompressionAlgorithm interface {
@@ -73,10 +80,16 @@ func (c *Lz4CompressionAlgo) String() string {
 type ZstdCompressionAlgo struct {
 	encoder *zstd.Encoder
 	decoder *zstd.Decoder
+	level   zstd.EncoderLevel
 }
 
 func Zstd() CompressionAlgorithm {
-	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	return ZstdLevel(zstd.SpeedDefault)
+}
+
+// ZstdLevel returns a Zstd compression algorithm with the given level.
+func ZstdLevel(level zstd.EncoderLevel) CompressionAlgorithm {
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(level))
 	if err != nil {
 		panic(err)
 	}
@@ -86,7 +99,7 @@ func Zstd() CompressionAlgorithm {
 		panic(err)
 	}
 
-	return &ZstdCompressionAlgo{encoder: encoder, decoder: decoder}
+	return &ZstdCompressionAlgo{encoder: encoder, decoder: decoder, level: level}
 }
 
 func (c *ZstdCompressionAlgo) Compress(data []byte) ([]byte, error) {
@@ -112,7 +125,158 @@ func (c *ZstdCompressionAlgo) Decompress(data []byte) ([]byte, error) {
 }
 
 func (c *ZstdCompressionAlgo) String() string {
-	return "Zstd"
+	if c.level == zstd.SpeedDefault {
+		return "Zstd"
+	}
+	return "Zstd-" + c.level.String()
+}
+
+// ZstdDictCompressionAlgo is a Zstd compression algorithm with a dictionary
+// trained from the first buffers compressed, like a sender would do with the
+// first messages of a stream. The training buffers are compressed without
+// dictionary.
+type ZstdDictCompressionAlgo struct {
+	samples    [][]byte
+	maxSamples int
+	dictSize   int
+
+	encoder     *zstd.Encoder
+	decoder     *zstd.Decoder
+	dictEncoder *zstd.Encoder
+	dictDecoder *zstd.Decoder
+}
+
+// ZstdDict returns a Zstd compression algorithm training a dictionary of at
+// most dictSize bytes from the first samples buffers compressed.
+func ZstdDict(samples, dictSize int) CompressionAlgorithm {
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	if err != nil {
+		panic(err)
+	}
+
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	return &ZstdDictCompressionAlgo{
+		maxSamples: samples,
+		dictSize:   dictSize,
+		encoder:    encoder,
+		decoder:    decoder,
+	}
+}
+
+func (c *ZstdDictCompressionAlgo) Compress(data []byte) ([]byte, error) {
+	if c.dictEncoder != nil {
+		return c.dictEncoder.EncodeAll(data, nil), nil
+	}
+
+	c.samples = append(c.samples, append([]byte(nil), data...))
+	if len(c.samples) >= c.maxSamples {
+		dict := zstddict.Train(c.samples, c.dictSize, zstddict.ID)
+		encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault), zstddict.EncoderDict(zstddict.ID, dict))
+		if err != nil {
+			return nil, err
+		}
+		decoder, err := zstd.NewReader(nil, zstddict.DecoderDict(zstddict.ID, dict))
+		if err != nil {
+			return nil, err
+		}
+		c.dictEncoder, c.dictDecoder = encoder, decoder
+		c.samples = nil
+	}
+	return c.encoder.EncodeAll(data, nil), nil
+}
+
+func (c *ZstdDictCompressionAlgo) Decompress(data []byte) ([]byte, error) {
+	// The decoder with the dictionary also decodes the frames compressed
+	// without dictionary.
+	decoder := c.decoder
+	if c.dictDecoder != nil {
+		decoder = c.dictDecoder
+	}
+	return decoder.DecodeAll(data, nil)
+}
+
+func (c *ZstdDictCompressionAlgo) String() string {
+	return "ZstdDict"
+}
+
+type S2CompressionAlgo struct{}
+
+// S2 returns the S2 compression algorithm (a faster extension of Snappy).
+func S2() CompressionAlgorithm {
+	return &S2CompressionAlgo{}
+}
+
+func (c *S2CompressionAlgo) Compress(data []byte) ([]byte, error) {
+	return s2.Encode(nil, data), nil
+}
+
+func (c *S2CompressionAlgo) Decompress(data []byte) ([]byte, error) {
+	return s2.Decode(nil, data)
+}
+
+func (c *S2CompressionAlgo) String() string {
+	return "S2"
+}
+
+type SnappyCompressionAlgo struct{}
+
+// Snappy returns the Snappy compression algorithm (block format).
+func Snappy() CompressionAlgorithm {
+	return &SnappyCompressionAlgo{}
+}
+
+func (c *SnappyCompressionAlgo) Compress(data []byte) ([]byte, error) {
+	return snappy.Encode(nil, data), nil
+}
+
+func (c *SnappyCompressionAlgo) Decompress(data []byte) ([]byte, error) {
+	return snappy.Decode(nil, data)
+}
+
+func (c *SnappyCompressionAlgo) String() string {
+	return "Snappy"
+}
+
+type GzipCompressionAlgo struct {
+	level int
+}
+
+// Gzip returns the Gzip compression algorithm with the default level, the
+// default compression of gRPC.
+func Gzip() CompressionAlgorithm {
+	return &GzipCompressionAlgo{level: gzip.DefaultCompression}
+}
+
+func (c *GzipCompressionAlgo) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&buf, c.level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *GzipCompressionAlgo) Decompress(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func (c *GzipCompressionAlgo) String() string {
+	return "Gzip"
 }
 
 type NoCompressionAlgo struct{}
@@ -132,3 +296,65 @@ func (c *NoCompressionAlgo) Decompress(data []byte) ([]byte, error) {
 func (c *NoCompressionAlgo) String() string {
 	return ""
 }
+
+// Default settings of the dictionary of the "zstd-dict" algorithm.
+const (
+	DefaultZstdDictSamples = 10
+	DefaultZstdDictSize    = 64 << 10
+)
+
+// CompressionAlgorithmNames lists the names accepted by CompressionAlgorithmByName.
+var CompressionAlgorithmNames = []string{
+	"none", "lz4", "zstd", "zstd-fastest", "zstd-better", "zstd-best", "zstd-dict", "s2", "snappy", "gzip",
+}
+
+// CompressionAlgorithmByName returns a new compression algorithm from its
+// name (see CompressionAlgorithmNames), e.g. for a command line flag.
+func CompressionAlgorithmByName(name string) (CompressionAlgorithm, error) {
+	switch strings.ToLower(name) {
+	case "none":
+		return NoCompression(), nil
+	case "lz4":
+		return Lz4(), nil
+	case "zstd":
+		return Zstd(), nil
+	case "zstd-fastest":
+		return ZstdLevel(zstd.SpeedFastest), nil
+	case "zstd-better":
+		return ZstdLevel(zstd.SpeedBetterCompression), nil
+	case "zstd-best":
+		return ZstdLevel(zstd.SpeedBestCompression), nil
+	case "zstd-dict":
+		return ZstdDict(DefaultZstdDictSamples, DefaultZstdDictSize), nil
+	case "s2":
+		return S2(), nil
+	case "snappy":
+		return Snappy(), nil
+	case "gzip":
+		return Gzip(), nil
+	default:
+		return nil, fmt.Errorf("unknown compression algorithm %q (expected one of %s)", name, strings.Join(CompressionAlgorithmNames, ", "))
+	}
+}
+
+// CompressionAlgorithmFactory creates instances of a compression algorithm,
+// the algorithms with a state (e.g. ZstdDict) can't be shared by several
+// profileables.
+type CompressionAlgorithmFactory func() CompressionAlgorithm
+
+// ParseCompressionAlgorithms returns the factories of a comma separated list
+// of compression algorithm names.
+func ParseCompressionAlgorithms(names string) ([]CompressionAlgorithmFactory, error) {
+	var factories []CompressionAlgorithmFactory
+	for _, name := range strings.Split(names, ",") {
+		name := strings.TrimSpace(name)
+		if _, err := CompressionAlgorithmByName(name); err != nil {
+			return nil, err
+		}
+		factories = append(factories, func() CompressionAlgorithm {
+			algo, _ := CompressionAlgorithmByName(name)
+			return algo
+		})
+	}
+	return factories, nil
+}
@@ -15,7 +15,10 @@
 package benchmark
 
 import (
+	"fmt"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestLz4(t *testing.T) {
@@ -51,3 +54,67 @@ func TestZstd(t *testing.T) {
 		t.Errorf("expected decompressed data to be 'test', got %v", string(decompressed))
 	}
 }
+
+func TestCompressionAlgorithmByName(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("This is an example of text to compress.This is an example of text to compress.This is an example of text to compress.")
+	for _, name := range CompressionAlgorithmNames {
+		algo, err := CompressionAlgorithmByName(name)
+		require.NoError(t, err, name)
+		// Several buffers for the algorithms with a state (zstd-dict).
+		for i := 0; i < 2*DefaultZstdDictSamples; i++ {
+			compressed, err := algo.Compress(data)
+			require.NoError(t, err, name)
+			decompressed, err := algo.Decompress(compressed)
+			require.NoError(t, err, name)
+			require.Equal(t, data, decompressed, name)
+		}
+	}
+
+	_, err := CompressionAlgorithmByName("brotli")
+	require.Error(t, err)
+}
+
+func TestParseCompressionAlgorithms(t *testing.T) {
+	t.Parallel()
+
+	factories, err := ParseCompressionAlgorithms("zstd, zstd-dict,gzip")
+	require.NoError(t, err)
+	require.Len(t, factories, 3)
+	require.Equal(t, "ZstdDict", factories[1]().String())
+	// Each call returns a new instance.
+	require.NotSame(t, factories[1](), factories[1]())
+
+	_, err = ParseCompressionAlgorithms("zstd,brotli")
+	require.Error(t, err)
+}
+
+func TestZstdDict(t *testing.T) {
+	t.Parallel()
+
+	// Small buffers sharing most of their content, like small batches of
+	// similar telemetry.
+	buffer := func(i int) []byte {
+		return []byte(fmt.Sprintf(`{"resource":{"service.name":"frontend","host.name":"host-42","k8s.pod.name":"frontend-5d8f7c9b6-x2x7z"},"span":{"name":"GET /api/products","id":%d}}`, i))
+	}
+
+	dict := ZstdDict(5, 1024)
+	plain := Zstd()
+	var dictSize, plainSize int
+	for i := 0; i < 20; i++ {
+		compressed, err := dict.Compress(buffer(i))
+		require.NoError(t, err)
+		decompressed, err := dict.Decompress(compressed)
+		require.NoError(t, err)
+		require.Equal(t, buffer(i), decompressed)
+
+		plainCompressed, err := plain.Compress(buffer(i))
+		require.NoError(t, err)
+		if i >= 5 {
+			dictSize += len(compressed)
+			plainSize += len(plainCompressed)
+		}
+	}
+	require.Less(t, dictSize, plainSize/2)
+}
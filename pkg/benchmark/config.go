@@ -14,7 +14,60 @@
 
 package benchmark
 
+import (
+	cfg "github.com/f5/otel-arrow-adapter/pkg/config"
+)
+
 type Config struct {
 	Compression bool
 	Stats       bool
+
+	// IPCCompression is the Arrow IPC compression codec of the OTel Arrow
+	// profileables when Compression is enabled (zstd if empty).
+	IPCCompression cfg.IPCCompression
+
+	// CompressionAlgorithm compresses the serialized batches of the OTel
+	// Arrow profileables, like the gRPC compression (zstd if nil).
+	CompressionAlgorithm CompressionAlgorithm
+
+	// CompressionDictionarySamples enables the compression of the Arrow
+	// payloads with a zstd dictionary trained from this number of payloads
+	// (see cfg.WithCompressionDictionary), in place of the IPC compression.
+	CompressionDictionarySamples int
+	// CompressionDictionarySize is the maximum size of the dictionary
+	// (cfg.DefaultCompressionDictionarySize if 0).
+	CompressionDictionarySize int
+
+	// AdaptiveSortingPeriod enables the selection of the sort strategy of
+	// the traces every this number of batches (see cfg.WithAdaptiveSorting).
+	AdaptiveSortingPeriod uint64
+
+	// ProtoOutput makes the OTel Arrow profileables decode the Arrow records
+	// directly into OTLP protobuf bytes (i.e. without building the pdata
+	// representation) in the OtlpConversionSection.
+	ProtoOutput bool
+}
+
+// IPCCompressionOption returns the producer option setting the compression of
+// the Arrow payloads, i.e. the Arrow IPC compression codec or the zstd
+// dictionary.
+func (c *Config) IPCCompressionOption() cfg.Option {
+	switch {
+	case c.CompressionDictionarySamples > 0:
+		return cfg.WithCompressionDictionary(c.CompressionDictionarySamples, c.CompressionDictionarySize)
+	case !c.Compression:
+		return cfg.WithNoZstd()
+	case c.IPCCompression == "":
+		return cfg.WithZstd()
+	default:
+		return cfg.WithIPCCompression(c.IPCCompression)
+	}
+}
+
+// Algorithm returns the compression algorithm of the serialized batches.
+func (c *Config) Algorithm() CompressionAlgorithm {
+	if c.CompressionAlgorithm == nil {
+		return Zstd()
+	}
+	return c.CompressionAlgorithm
 }
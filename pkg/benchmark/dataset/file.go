@@ -0,0 +1,188 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataset
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+
+	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/capture"
+)
+
+// Format is the encoding of a dataset file.
+type Format int
+
+const (
+	// FormatProto is an OTLP export request encoded in protobuf.
+	FormatProto Format = iota
+	// FormatJSON is an OTLP export request encoded in JSON.
+	FormatJSON
+	// FormatArrow is a captured OTLP Arrow stream (see pkg/otel/capture),
+	// the batches of the dataset are encoded by a single producer.
+	FormatArrow
+)
+
+// Compression is the compression of a dataset file.
+type Compression int
+
+const (
+	CompressionNone Compression = iota
+	CompressionGzip
+	CompressionZstd
+)
+
+// FileFormat returns the format and the compression of a dataset file from
+// its extensions, e.g. "traces.json.zst" is a zstd-compressed OTLP JSON
+// file. The compression extensions are ".gz" and ".zst", the format
+// extensions are ".json" and ".arrow", the other files are OTLP protobuf.
+func FileFormat(file string) (Format, Compression) {
+	compression := CompressionNone
+	switch ext := filepath.Ext(file); ext {
+	case ".gz":
+		compression = CompressionGzip
+		file = strings.TrimSuffix(file, ext)
+	case ".zst":
+		compression = CompressionZstd
+		file = strings.TrimSuffix(file, ext)
+	}
+
+	switch filepath.Ext(file) {
+	case ".json":
+		return FormatJSON, compression
+	case ".arrow":
+		return FormatArrow, compression
+	default:
+		return FormatProto, compression
+	}
+}
+
+// ReadFile reads a dataset file, decompressed according to its extension.
+func ReadFile(file string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, err
+	}
+
+	_, compression := FileFormat(file)
+	switch compression {
+	case CompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		defer r.Close()
+		data, err = io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	case CompressionZstd:
+		r, err := zstd.NewReader(nil)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		data, err = r.DecodeAll(data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return data, nil
+}
+
+// WriteFile writes a dataset file, compressed according to its extension.
+// The directory of the file is created if needed.
+func WriteFile(file string, data []byte) error {
+	_, compression := FileFormat(file)
+	switch compression {
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	case CompressionZstd:
+		w, err := zstd.NewWriter(nil)
+		if err != nil {
+			return err
+		}
+		data = w.EncodeAll(data, nil)
+		if err := w.Close(); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0600)
+}
+
+// readArrow decodes the batches of a captured OTLP Arrow stream with a
+// single consumer.
+func readArrow(data []byte, decode func(consumer *arrowRecord.Consumer, bar *colarspb.BatchArrowRecords) error) error {
+	consumer := arrowRecord.NewConsumer()
+	defer func() { _ = consumer.Close() }()
+
+	reader := capture.NewReader(bytes.NewReader(data))
+	for {
+		bar, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := decode(consumer, bar); err != nil {
+			return err
+		}
+	}
+}
+
+// writeArrow encodes n batches as a captured OTLP Arrow stream with a
+// single producer.
+func writeArrow(n int, encode func(producer *arrowRecord.Producer, i int) (*colarspb.BatchArrowRecords, error)) ([]byte, error) {
+	producer := arrowRecord.NewProducer()
+	defer func() { _ = producer.Close() }()
+
+	var buf bytes.Buffer
+	writer := capture.NewWriter(&buf)
+	for i := 0; i < n; i++ {
+		bar, err := encode(producer, i)
+		if err != nil {
+			return nil, err
+		}
+		if err := writer.Write(bar); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
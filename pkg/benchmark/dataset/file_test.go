@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataset
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/assert"
+)
+
+func TestFileFormat(t *testing.T) {
+	t.Parallel()
+
+	for file, expected := range map[string]struct {
+		format      Format
+		compression Compression
+	}{
+		"traces.pb":          {FormatProto, CompressionNone},
+		"traces.bin":         {FormatProto, CompressionNone},
+		"traces.pb.gz":       {FormatProto, CompressionGzip},
+		"logs.json":          {FormatJSON, CompressionNone},
+		"dir.arrow/logs.zst": {FormatProto, CompressionZstd},
+		"metrics.arrow.zst":  {FormatArrow, CompressionZstd},
+	} {
+		format, compression := FileFormat(file)
+		require.Equal(t, expected.format, format, file)
+		require.Equal(t, expected.compression, compression, file)
+	}
+}
+
+func TestReadWriteTraces(t *testing.T) {
+	t.Parallel()
+
+	entropy := datagen.NewTestEntropy(int64(42))
+	generator := datagen.NewTracesGenerator(entropy, entropy.NewStandardResourceAttributes(), entropy.NewStandardInstrumentationScopes())
+	batches := []ptrace.Traces{generator.Generate(20, time.Minute), generator.Generate(30, time.Minute)}
+	expected := mergeTraces(batches)
+
+	dir := t.TempDir()
+	for _, name := range []string{"traces.pb", "traces.pb.gz", "traces.json.zst", "traces.arrow", "traces.arrow.gz"} {
+		file := filepath.Join(dir, "sub", name)
+		require.NoError(t, WriteTraces(file, batches), name)
+
+		actual, size, err := ReadTraces(file)
+		require.NoError(t, err, name)
+		require.Positive(t, size)
+		require.Equal(t, expected.SpanCount(), actual.SpanCount(), name)
+		assert.Equiv(t, []json.Marshaler{ptraceotlp.NewExportRequestFromTraces(expected)}, []json.Marshaler{ptraceotlp.NewExportRequestFromTraces(actual)})
+	}
+
+	ds := NewRealTraceDataset(filepath.Join(dir, "sub", "traces.arrow.gz"), []string{"trace_id"})
+	require.Equal(t, expected.SpanCount(), ds.Len())
+}
+
+func TestReadWriteLogs(t *testing.T) {
+	t.Parallel()
+
+	entropy := datagen.NewTestEntropy(int64(42))
+	generator := datagen.NewLogsGenerator(entropy, entropy.NewStandardResourceAttributes(), entropy.NewStandardInstrumentationScopes())
+	batches := []plog.Logs{generator.Generate(20, time.Minute)}
+
+	file := filepath.Join(t.TempDir(), "logs.json.gz")
+	require.NoError(t, WriteLogs(file, batches))
+	actual, _, err := ReadLogs(file)
+	require.NoError(t, err)
+	assert.Equiv(t, []json.Marshaler{plogotlp.NewExportRequestFromLogs(batches[0])}, []json.Marshaler{plogotlp.NewExportRequestFromLogs(actual)})
+
+	_, _, err = ReadLogs(filepath.Join(t.TempDir(), "missing.pb"))
+	require.Error(t, err)
+}
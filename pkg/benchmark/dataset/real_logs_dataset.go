@@ -15,14 +15,15 @@
 package dataset
 
 import (
+	"fmt"
 	"log"
-	"os"
-	"path/filepath"
 
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 
+	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
 	"github.com/f5/otel-arrow-adapter/pkg/benchmark/stats"
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
 )
 
 // RealLogsDataset represents a dataset of real logs read from a Logs serialized to a binary file.
@@ -40,22 +41,16 @@ type logUnit struct {
 
 // NewRealLogsDataset creates a new RealLogsDataset from a binary file.
 func NewRealLogsDataset(path string) *RealLogsDataset {
-	data, err := os.ReadFile(filepath.Clean(path))
+	logs, size, err := ReadLogs(path)
 	if err != nil {
 		log.Fatal("read file:", err)
 	}
-	otlp := plogotlp.NewExportRequest()
-
-	if err := otlp.UnmarshalProto(data); err != nil {
-		log.Fatal("unmarshal:", err)
-	}
 
 	ds := &RealLogsDataset{
 		logs:        []logUnit{},
-		sizeInBytes: len(data),
+		sizeInBytes: size,
 		logsStats:   stats.NewLogsStats(),
 	}
-	logs := otlp.Logs()
 	ds.logsStats.Analyze(logs)
 
 	for ri := 0; ri < logs.ResourceLogs().Len(); ri++ {
@@ -72,6 +67,86 @@ func NewRealLogsDataset(path string) *RealLogsDataset {
 	return ds
 }
 
+// ReadLogs reads a logs file in one of the dataset formats (see
+// FileFormat), the batches of an Arrow file are merged. It also returns the
+// size of the decompressed file.
+func ReadLogs(file string) (plog.Logs, int, error) {
+	data, err := ReadFile(file)
+	if err != nil {
+		return plog.Logs{}, 0, err
+	}
+
+	format, _ := FileFormat(file)
+	switch format {
+	case FormatJSON:
+		request := plogotlp.NewExportRequest()
+		if err := request.UnmarshalJSON(data); err != nil {
+			return plog.Logs{}, 0, fmt.Errorf("%s: %w", file, err)
+		}
+		return request.Logs(), len(data), nil
+	case FormatArrow:
+		ld := plog.NewLogs()
+		err := readArrow(data, func(consumer *arrowRecord.Consumer, bar *colarspb.BatchArrowRecords) error {
+			batches, err := consumer.LogsFrom(bar)
+			if err != nil {
+				return err
+			}
+			for _, batch := range batches {
+				batch.ResourceLogs().MoveAndAppendTo(ld.ResourceLogs())
+			}
+			return nil
+		})
+		if err != nil {
+			return plog.Logs{}, 0, fmt.Errorf("%s: %w", file, err)
+		}
+		return ld, len(data), nil
+	default:
+		request := plogotlp.NewExportRequest()
+		if err := request.UnmarshalProto(data); err != nil {
+			return plog.Logs{}, 0, fmt.Errorf("%s: %w", file, err)
+		}
+		return request.Logs(), len(data), nil
+	}
+}
+
+// WriteLogs writes a logs file in the format of its extensions (see
+// FileFormat). The batches are encoded one by one in an Arrow file and
+// merged in the other formats.
+func WriteLogs(file string, batches []plog.Logs) error {
+	var data []byte
+	var err error
+
+	format, _ := FileFormat(file)
+	switch format {
+	case FormatJSON:
+		data, err = plogotlp.NewExportRequestFromLogs(mergeLogs(batches)).MarshalJSON()
+	case FormatArrow:
+		data, err = writeArrow(len(batches), func(producer *arrowRecord.Producer, i int) (*colarspb.BatchArrowRecords, error) {
+			return producer.BatchArrowRecordsFromLogs(batches[i])
+		})
+	default:
+		data, err = plogotlp.NewExportRequestFromLogs(mergeLogs(batches)).MarshalProto()
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return WriteFile(file, data)
+}
+
+// mergeLogs copies the resources of several batches into one.
+func mergeLogs(batches []plog.Logs) plog.Logs {
+	if len(batches) == 1 {
+		return batches[0]
+	}
+	ld := plog.NewLogs()
+	for _, batch := range batches {
+		for i := 0; i < batch.ResourceLogs().Len(); i++ {
+			batch.ResourceLogs().At(i).CopyTo(ld.ResourceLogs().AppendEmpty())
+		}
+	}
+	return ld
+}
+
 func (d *RealLogsDataset) SizeInBytes() int {
 	return d.sizeInBytes
 }
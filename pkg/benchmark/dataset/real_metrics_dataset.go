@@ -15,14 +15,15 @@
 package dataset
 
 import (
+	"fmt"
 	"log"
-	"os"
-	"path/filepath"
 
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
 
+	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
 	"github.com/f5/otel-arrow-adapter/pkg/benchmark/stats"
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
 )
 
 // RealMetricsDataset represents a dataset of real metrics read from a Metrics serialized to a binary file.
@@ -40,19 +41,14 @@ type metrics struct {
 
 // NewRealMetricsDataset creates a new RealMetricsDataset from a binary file.
 func NewRealMetricsDataset(path string) *RealMetricsDataset {
-	data, err := os.ReadFile(filepath.Clean(path))
+	mdata, size, err := ReadMetrics(path)
 	if err != nil {
 		log.Fatal("read file:", err)
 	}
-	otlp := pmetricotlp.NewExportRequest()
-	if err := otlp.UnmarshalProto(data); err != nil {
-		log.Fatal("unmarshal:", err)
-	}
-	mdata := otlp.Metrics()
 
 	ds := &RealMetricsDataset{
 		metrics:      []metrics{},
-		sizeInBytes:  len(data),
+		sizeInBytes:  size,
 		metricsStats: stats.NewMetricsStats(),
 	}
 	ds.metricsStats.Analyze(mdata)
@@ -71,6 +67,86 @@ func NewRealMetricsDataset(path string) *RealMetricsDataset {
 	return ds
 }
 
+// ReadMetrics reads a metrics file in one of the dataset formats (see
+// FileFormat), the batches of an Arrow file are merged. It also returns the
+// size of the decompressed file.
+func ReadMetrics(file string) (pmetric.Metrics, int, error) {
+	data, err := ReadFile(file)
+	if err != nil {
+		return pmetric.Metrics{}, 0, err
+	}
+
+	format, _ := FileFormat(file)
+	switch format {
+	case FormatJSON:
+		request := pmetricotlp.NewExportRequest()
+		if err := request.UnmarshalJSON(data); err != nil {
+			return pmetric.Metrics{}, 0, fmt.Errorf("%s: %w", file, err)
+		}
+		return request.Metrics(), len(data), nil
+	case FormatArrow:
+		md := pmetric.NewMetrics()
+		err := readArrow(data, func(consumer *arrowRecord.Consumer, bar *colarspb.BatchArrowRecords) error {
+			batches, err := consumer.MetricsFrom(bar)
+			if err != nil {
+				return err
+			}
+			for _, batch := range batches {
+				batch.ResourceMetrics().MoveAndAppendTo(md.ResourceMetrics())
+			}
+			return nil
+		})
+		if err != nil {
+			return pmetric.Metrics{}, 0, fmt.Errorf("%s: %w", file, err)
+		}
+		return md, len(data), nil
+	default:
+		request := pmetricotlp.NewExportRequest()
+		if err := request.UnmarshalProto(data); err != nil {
+			return pmetric.Metrics{}, 0, fmt.Errorf("%s: %w", file, err)
+		}
+		return request.Metrics(), len(data), nil
+	}
+}
+
+// WriteMetrics writes a metrics file in the format of its extensions (see
+// FileFormat). The batches are encoded one by one in an Arrow file and
+// merged in the other formats.
+func WriteMetrics(file string, batches []pmetric.Metrics) error {
+	var data []byte
+	var err error
+
+	format, _ := FileFormat(file)
+	switch format {
+	case FormatJSON:
+		data, err = pmetricotlp.NewExportRequestFromMetrics(mergeMetrics(batches)).MarshalJSON()
+	case FormatArrow:
+		data, err = writeArrow(len(batches), func(producer *arrowRecord.Producer, i int) (*colarspb.BatchArrowRecords, error) {
+			return producer.BatchArrowRecordsFromMetrics(batches[i])
+		})
+	default:
+		data, err = pmetricotlp.NewExportRequestFromMetrics(mergeMetrics(batches)).MarshalProto()
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return WriteFile(file, data)
+}
+
+// mergeMetrics copies the resources of several batches into one.
+func mergeMetrics(batches []pmetric.Metrics) pmetric.Metrics {
+	if len(batches) == 1 {
+		return batches[0]
+	}
+	md := pmetric.NewMetrics()
+	for _, batch := range batches {
+		for i := 0; i < batch.ResourceMetrics().Len(); i++ {
+			batch.ResourceMetrics().At(i).CopyTo(md.ResourceMetrics().AppendEmpty())
+		}
+	}
+	return md
+}
+
 func (d *RealMetricsDataset) SizeInBytes() int {
 	return d.sizeInBytes
 }
@@ -18,8 +18,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log"
-	"os"
-	"path/filepath"
 	"sort"
 	"strings"
 
@@ -28,6 +26,8 @@ import (
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	"golang.org/x/exp/rand"
 
+	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
 	carrow "github.com/f5/otel-arrow-adapter/pkg/otel/common/otlp"
 )
 
@@ -48,21 +48,16 @@ type spanSorter struct {
 var _ sort.Interface = spanSorter{}
 
 func NewRealTraceDataset(path string, sortOrder []string) *RealTraceDataset {
-	data, err := os.ReadFile(filepath.Clean(path))
+	traces, size, err := ReadTraces(path)
 	if err != nil {
 		log.Fatal("read file:", err)
 	}
-	otlp := ptraceotlp.NewExportRequest()
-	if err := otlp.UnmarshalProto(data); err != nil {
-		log.Fatalf("in %q unmarshal: %v", path, err)
-	}
 
 	ds := &RealTraceDataset{
 		s2r:         map[ptrace.Span]pcommon.Resource{},
 		s2s:         map[ptrace.Span]pcommon.InstrumentationScope{},
-		sizeInBytes: len(data),
+		sizeInBytes: size,
 	}
-	traces := otlp.Traces()
 
 	for i := 0; i < traces.ResourceSpans().Len(); i++ {
 		rs := traces.ResourceSpans().At(i)
@@ -91,6 +86,86 @@ func NewRealTraceDataset(path string, sortOrder []string) *RealTraceDataset {
 	return ds
 }
 
+// ReadTraces reads a traces file in one of the dataset formats (see
+// FileFormat), the batches of an Arrow file are merged. It also returns the
+// size of the decompressed file.
+func ReadTraces(file string) (ptrace.Traces, int, error) {
+	data, err := ReadFile(file)
+	if err != nil {
+		return ptrace.Traces{}, 0, err
+	}
+
+	format, _ := FileFormat(file)
+	switch format {
+	case FormatJSON:
+		request := ptraceotlp.NewExportRequest()
+		if err := request.UnmarshalJSON(data); err != nil {
+			return ptrace.Traces{}, 0, fmt.Errorf("%s: %w", file, err)
+		}
+		return request.Traces(), len(data), nil
+	case FormatArrow:
+		td := ptrace.NewTraces()
+		err := readArrow(data, func(consumer *arrowRecord.Consumer, bar *colarspb.BatchArrowRecords) error {
+			batches, err := consumer.TracesFrom(bar)
+			if err != nil {
+				return err
+			}
+			for _, batch := range batches {
+				batch.ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+			}
+			return nil
+		})
+		if err != nil {
+			return ptrace.Traces{}, 0, fmt.Errorf("%s: %w", file, err)
+		}
+		return td, len(data), nil
+	default:
+		request := ptraceotlp.NewExportRequest()
+		if err := request.UnmarshalProto(data); err != nil {
+			return ptrace.Traces{}, 0, fmt.Errorf("%s: %w", file, err)
+		}
+		return request.Traces(), len(data), nil
+	}
+}
+
+// WriteTraces writes a traces file in the format of its extensions (see
+// FileFormat). The batches are encoded one by one in an Arrow file and
+// merged in the other formats.
+func WriteTraces(file string, batches []ptrace.Traces) error {
+	var data []byte
+	var err error
+
+	format, _ := FileFormat(file)
+	switch format {
+	case FormatJSON:
+		data, err = ptraceotlp.NewExportRequestFromTraces(mergeTraces(batches)).MarshalJSON()
+	case FormatArrow:
+		data, err = writeArrow(len(batches), func(producer *arrowRecord.Producer, i int) (*colarspb.BatchArrowRecords, error) {
+			return producer.BatchArrowRecordsFromTraces(batches[i])
+		})
+	default:
+		data, err = ptraceotlp.NewExportRequestFromTraces(mergeTraces(batches)).MarshalProto()
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return WriteFile(file, data)
+}
+
+// mergeTraces copies the resources of several batches into one.
+func mergeTraces(batches []ptrace.Traces) ptrace.Traces {
+	if len(batches) == 1 {
+		return batches[0]
+	}
+	td := ptrace.NewTraces()
+	for _, batch := range batches {
+		for i := 0; i < batch.ResourceSpans().Len(); i++ {
+			batch.ResourceSpans().At(i).CopyTo(td.ResourceSpans().AppendEmpty())
+		}
+	}
+	return td
+}
+
 func (d *RealTraceDataset) Resize(size int) {
 	d.spans = d.spans[:size]
 }
@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/f5/otel-arrow-adapter/pkg/config"
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+)
+
+// BenchmarkMetricsEncodingConcurrency compares the sequential encoding of
+// large metrics batches with the concurrent encoding of their related
+// records.
+//
+// go test -run=^$ -bench=EncodingConcurrency ./pkg/benchmark
+func BenchmarkMetricsEncodingConcurrency(b *testing.B) {
+	entropy := datagen.NewTestEntropy(12345)
+	generator := datagen.NewMetricsGenerator(entropy, entropy.NewStandardResourceAttributes(), entropy.NewStandardInstrumentationScopes())
+	metrics := generator.GenerateAllKindOfMetrics(5000, time.Minute)
+
+	for _, concurrency := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			producer := arrow_record.NewProducerWithOptions(config.WithEncodingConcurrency(concurrency))
+			defer func() {
+				if err := producer.Close(); err != nil {
+					b.Fatal(err)
+				}
+			}()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := producer.BatchArrowRecordsFromMetrics(metrics); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -49,13 +49,7 @@ type LogsProfileable struct {
 }
 
 func NewLogsProfileable(tags []string, dataset dataset.LogsDataset, config *benchmark.Config) *LogsProfileable {
-	var logsProducerOptions []cfg.Option
-
-	if config.Compression {
-		logsProducerOptions = append(logsProducerOptions, cfg.WithZstd())
-	} else {
-		logsProducerOptions = append(logsProducerOptions, cfg.WithNoZstd())
-	}
+	logsProducerOptions := []cfg.Option{config.IPCCompressionOption()}
 	if config.Stats {
 		logsProducerOptions = append(logsProducerOptions, cfg.WithStats())
 	}
@@ -65,7 +59,7 @@ func NewLogsProfileable(tags []string, dataset dataset.LogsDataset, config *benc
 	return &LogsProfileable{
 		tags:                tags,
 		dataset:             dataset,
-		compression:         benchmark.Zstd(),
+		compression:         config.Algorithm(),
 		producer:            producer,
 		consumer:            arrow_record.NewConsumer(),
 		batchArrowRecords:   make([]*v1.BatchArrowRecords, 0, 10),
@@ -173,6 +167,18 @@ func (s *LogsProfileable) Deserialize(_ io.Writer, buffers [][]byte) {
 }
 func (s *LogsProfileable) ConvertOtlpArrowToOtlp(_ io.Writer) {
 	for _, batchArrowRecords := range s.batchArrowRecords {
+		if s.config.ProtoOutput {
+			// Decode directly into OTLP protobuf messages (no pdata).
+			buffers, err := s.consumer.LogsProtoFrom(batchArrowRecords)
+			if err != nil {
+				panic(err)
+			}
+			if len(buffers) == 0 {
+				println("no logs")
+			}
+			continue
+		}
+
 		logs, err := s.consumer.LogsFrom(batchArrowRecords)
 		if err != nil {
 			panic(err)
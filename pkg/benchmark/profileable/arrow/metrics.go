@@ -46,13 +46,7 @@ type MetricsProfileable struct {
 }
 
 func NewMetricsProfileable(tags []string, dataset dataset.MetricsDataset, config *benchmark.Config) *MetricsProfileable {
-	var options []cfg.Option
-
-	if config.Compression {
-		options = append(options, cfg.WithZstd())
-	} else {
-		options = append(options, cfg.WithNoZstd())
-	}
+	options := []cfg.Option{config.IPCCompressionOption()}
 	if config.Stats {
 		options = append(options, cfg.WithStats())
 	}
@@ -62,7 +56,7 @@ func NewMetricsProfileable(tags []string, dataset dataset.MetricsDataset, config
 	return &MetricsProfileable{
 		tags:              tags,
 		dataset:           dataset,
-		compression:       benchmark.Zstd(),
+		compression:       config.Algorithm(),
 		producer:          producer,
 		consumer:          arrow_record.NewConsumer(),
 		batchArrowRecords: make([]*colarspb.BatchArrowRecords, 0, 10),
@@ -169,6 +163,18 @@ func (s *MetricsProfileable) Deserialize(_ io.Writer, buffers [][]byte) {
 
 func (s *MetricsProfileable) ConvertOtlpArrowToOtlp(_ io.Writer) {
 	for _, batchArrowRecords := range s.batchArrowRecords {
+		if s.config.ProtoOutput {
+			// Decode directly into OTLP protobuf messages (no pdata).
+			buffers, err := s.consumer.MetricsProtoFrom(batchArrowRecords)
+			if err != nil {
+				panic(err)
+			}
+			if len(buffers) == 0 {
+				panic("no metrics")
+			}
+			continue
+		}
+
 		metrics, err := s.consumer.MetricsFrom(batchArrowRecords)
 		if err != nil {
 			panic(err)
@@ -193,4 +199,7 @@ func (s *MetricsProfileable) Clear() {
 	}
 }
 func (s *MetricsProfileable) ShowStats() {
+	if s.config.Stats {
+		s.producer.ShowStats()
+	}
 }
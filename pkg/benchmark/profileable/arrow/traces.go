@@ -47,21 +47,18 @@ type TracesProfileable struct {
 }
 
 func NewTraceProfileable(tags []string, dataset dataset.TraceDataset, config *benchmark.Config) *TracesProfileable {
-	var tracesProducerOptions []cfg.Option
-
-	if config.Compression {
-		tracesProducerOptions = append(tracesProducerOptions, cfg.WithZstd())
-	} else {
-		tracesProducerOptions = append(tracesProducerOptions, cfg.WithNoZstd())
-	}
+	tracesProducerOptions := []cfg.Option{config.IPCCompressionOption()}
 	if config.Stats {
 		tracesProducerOptions = append(tracesProducerOptions, cfg.WithStats())
 	}
+	if config.AdaptiveSortingPeriod > 0 {
+		tracesProducerOptions = append(tracesProducerOptions, cfg.WithAdaptiveSorting(config.AdaptiveSortingPeriod, 0, cfg.DefaultAdaptiveSortingMinGain))
+	}
 
 	return &TracesProfileable{
 		tags:                  tags,
 		dataset:               dataset,
-		compression:           benchmark.Zstd(),
+		compression:           config.Algorithm(),
 		producer:              arrow_record.NewProducerWithOptions(tracesProducerOptions...),
 		consumer:              arrow_record.NewConsumer(),
 		batchArrowRecords:     make([]*v1.BatchArrowRecords, 0, 10),
@@ -172,6 +169,18 @@ func (s *TracesProfileable) Deserialize(_ io.Writer, buffers [][]byte) {
 
 func (s *TracesProfileable) ConvertOtlpArrowToOtlp(_ io.Writer) {
 	for _, batchArrowRecords := range s.batchArrowRecords {
+		if s.config.ProtoOutput {
+			// Decode directly into OTLP protobuf messages (no pdata).
+			buffers, err := s.consumer.TracesProtoFrom(batchArrowRecords)
+			if err != nil {
+				panic(err)
+			}
+			if len(buffers) == 0 {
+				println("no traces")
+			}
+			continue
+		}
+
 		traces, err := s.consumer.TracesFrom(batchArrowRecords)
 		if err != nil {
 			panic(err)
@@ -45,10 +45,11 @@ var (
 	TotalEncodingTimeSection   = NewSectionConfig("total_encoding_time_sec", "Sub total", false)
 	TotalDecodingTimeSection   = NewSectionConfig("total_decoding_time_sec", "Sub total", false)
 	Phase1TotalTimeSection     = NewSectionConfig("total_time_sec", "Total", true)
-	Phase2TotalTimeSection     = NewSectionConfig("total_time_sec", "Total", false)
+	Phase2TotalTimeSection     = NewSectionConfig("phase2_total_time_sec", "Total", false)
 	ProcessingSection          = NewSectionConfig("processing_sec", "Batch processing", false)
 	UncompressedSizeSection    = NewSectionConfig("uncompressed_size", "Uncompressed (bytes)", false)
 	CompressedSizeSection      = NewSectionConfig("compressed_size", "Compressed (bytes)", false)
+	CompressionRatioSection    = NewSectionConfig("compression_ratio", "Compression ratio", false)
 )
 
 // Profiler is the main profiler object used to implement benchmarks.
@@ -427,7 +428,7 @@ func (p *Profiler) PrintPhase2StepsTiming(_ uint64) {
 			values[key] = stats.AddSummaries(summary.SerializationSec, summary.CompressionSec)
 			key = fmt.Sprintf("%s:%s:%d:%s", result.BenchName, result.Tags, summary.BatchSize, TotalDecodingTimeSection.ID)
 			values[key] = stats.AddSummaries(summary.DeserializationSec, summary.DecompressionSec)
-			key = fmt.Sprintf("%s:%s:%d:%s", result.BenchName, result.Tags, summary.BatchSize, Phase1TotalTimeSection.ID)
+			key = fmt.Sprintf("%s:%s:%d:%s", result.BenchName, result.Tags, summary.BatchSize, Phase2TotalTimeSection.ID)
 			values[key] = stats.AddSummaries(summary.SerializationSec, summary.CompressionSec, summary.DeserializationSec, summary.DecompressionSec)
 		}
 	}
@@ -0,0 +1,321 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/f5/otel-arrow-adapter/pkg/benchmark/stats"
+)
+
+// Units of the results.
+const (
+	UnitSeconds = "s"
+	UnitBytes   = "bytes"
+	UnitRatio   = "ratio"
+)
+
+// Result is the summary of a section measured for a profileable system
+// (benchmark name and tags) and a batch size.
+type Result struct {
+	Dataset   string  `json:"dataset"`
+	Benchmark string  `json:"benchmark"`
+	Tags      string  `json:"tags"`
+	BatchSize int     `json:"batch_size"`
+	Section   string  `json:"section"`
+	Unit      string  `json:"unit"`
+	Mean      float64 `json:"mean"`
+	Min       float64 `json:"min"`
+	Max       float64 `json:"max"`
+	Stddev    float64 `json:"stddev"`
+	P50       float64 `json:"p50"`
+	P90       float64 `json:"p90"`
+	P99       float64 `json:"p99"`
+	// Total is the sum of the measurements per iteration (the ratio itself
+	// for a compression ratio).
+	Total float64 `json:"total"`
+}
+
+// Key identifies the measurement of a result across runs.
+func (r *Result) Key() string {
+	return fmt.Sprintf("%s %s [%s] batch_size=%d %s", r.Dataset, r.Benchmark, r.Tags, r.BatchSize, r.Section)
+}
+
+// Results contains the results of a benchmark run, written to a JSON or
+// CSV file to be compared with the results of another run.
+type Results struct {
+	Timestamp time.Time `json:"timestamp"`
+	Results   []Result  `json:"results"`
+}
+
+// resultSection is a section reported in the results.
+type resultSection struct {
+	section *SectionConfig
+	unit    string
+	summary func(s *stats.BatchSummary) *stats.Summary
+}
+
+// resultSections are the sections of the results. The sub totals are the
+// ones of the phase 1 (with the OTLP conversions).
+var resultSections = []resultSection{
+	{UncompressedSizeSection, UnitBytes, func(s *stats.BatchSummary) *stats.Summary { return s.UncompressedSizeByte }},
+	{CompressedSizeSection, UnitBytes, func(s *stats.BatchSummary) *stats.Summary { return s.CompressedSizeByte }},
+	{CompressionRatioSection, UnitRatio, compressionRatio},
+	{OtlpArrowConversionSection, UnitSeconds, func(s *stats.BatchSummary) *stats.Summary { return s.OtlpArrowConversionSec }},
+	{ProcessingSection, UnitSeconds, func(s *stats.BatchSummary) *stats.Summary { return s.ProcessingSec }},
+	{SerializationSection, UnitSeconds, func(s *stats.BatchSummary) *stats.Summary { return s.SerializationSec }},
+	{CompressionSection, UnitSeconds, func(s *stats.BatchSummary) *stats.Summary { return s.CompressionSec }},
+	{DecompressionSection, UnitSeconds, func(s *stats.BatchSummary) *stats.Summary { return s.DecompressionSec }},
+	{DeserializationSection, UnitSeconds, func(s *stats.BatchSummary) *stats.Summary { return s.DeserializationSec }},
+	{OtlpConversionSection, UnitSeconds, func(s *stats.BatchSummary) *stats.Summary { return s.OtlpConversionSec }},
+	{TotalEncodingTimeSection, UnitSeconds, func(s *stats.BatchSummary) *stats.Summary {
+		return stats.AddSummaries(s.OtlpArrowConversionSec, s.SerializationSec, s.CompressionSec)
+	}},
+	{TotalDecodingTimeSection, UnitSeconds, func(s *stats.BatchSummary) *stats.Summary {
+		return stats.AddSummaries(s.DeserializationSec, s.DecompressionSec, s.OtlpConversionSec)
+	}},
+	{Phase1TotalTimeSection, UnitSeconds, func(s *stats.BatchSummary) *stats.Summary { return s.TotalTimeSec }},
+	{Phase2TotalTimeSection, UnitSeconds, func(s *stats.BatchSummary) *stats.Summary {
+		return stats.AddSummaries(s.SerializationSec, s.CompressionSec, s.DeserializationSec, s.DecompressionSec)
+	}},
+}
+
+// compressionRatio returns the ratio between the total uncompressed and
+// compressed sizes. The values of the size summaries are sorted, so the
+// ratio isn't computed per batch.
+func compressionRatio(s *stats.BatchSummary) *stats.Summary {
+	var uncompressed, compressed float64
+	for _, value := range s.UncompressedSizeByte.Values {
+		uncompressed += value
+	}
+	for _, value := range s.CompressedSizeByte.Values {
+		compressed += value
+	}
+	ratio := 0.0
+	if compressed > 0 {
+		ratio = uncompressed / compressed
+	}
+	return &stats.Summary{Min: ratio, Max: ratio, Mean: ratio, P50: ratio, P90: ratio, P95: ratio, P99: ratio}
+}
+
+// NewResults creates an empty set of results.
+func NewResults() *Results {
+	return &Results{Timestamp: time.Now().UTC()}
+}
+
+// Add adds the results of the systems profiled on a dataset. The sections
+// configured as not applicable to a system are skipped.
+func (r *Results) Add(dataset string, p *Profiler, maxIter uint64) {
+	for _, benchmark := range p.benchmarks {
+		columnID := fmt.Sprintf("%s:%s", benchmark.BenchName, benchmark.Tags)
+		for i := range benchmark.Summaries {
+			summary := &benchmark.Summaries[i]
+			for _, rs := range resultSections {
+				if !rs.section.MetricNotApplicable(columnID) {
+					continue
+				}
+				s := rs.summary(summary)
+				total := s.Total(maxIter)
+				if rs.unit == UnitRatio {
+					total = s.Mean
+				}
+				r.Results = append(r.Results, Result{
+					Dataset:   dataset,
+					Benchmark: benchmark.BenchName,
+					Tags:      benchmark.Tags,
+					BatchSize: summary.BatchSize,
+					Section:   rs.section.ID,
+					Unit:      rs.unit,
+					Mean:      s.Mean,
+					Min:       s.Min,
+					Max:       s.Max,
+					Stddev:    s.Stddev,
+					P50:       s.P50,
+					P90:       s.P90,
+					P99:       s.P99,
+					Total:     total,
+				})
+			}
+		}
+	}
+}
+
+// WriteFile writes the results to a JSON file, or to a CSV file if the
+// extension of the file is ".csv".
+func (r *Results) WriteFile(file string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	f, err := os.Create(filepath.Clean(file))
+	if err != nil {
+		return err
+	}
+
+	if filepath.Ext(file) == ".csv" {
+		err = r.WriteCSV(f)
+	} else {
+		err = r.WriteJSON(f)
+	}
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteJSON writes the results as an indented JSON document.
+func (r *Results) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+var csvHeader = []string{"dataset", "benchmark", "tags", "batch_size", "section", "unit", "mean", "min", "max", "stddev", "p50", "p90", "p99", "total"}
+
+// WriteCSV writes the results as CSV, one row per result. The timestamp
+// isn't written.
+func (r *Results) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, result := range r.Results {
+		row := []string{result.Dataset, result.Benchmark, result.Tags, strconv.Itoa(result.BatchSize), result.Section, result.Unit}
+		for _, value := range []float64{result.Mean, result.Min, result.Max, result.Stddev, result.P50, result.P90, result.P99, result.Total} {
+			row = append(row, strconv.FormatFloat(value, 'g', -1, 64))
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadResultsFile reads the results written by WriteFile.
+func ReadResultsFile(file string) (*Results, error) {
+	f, err := os.Open(filepath.Clean(file))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	results := &Results{}
+	if filepath.Ext(file) != ".csv" {
+		if err := json.NewDecoder(f).Decode(results); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		return results, nil
+	}
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if len(rows) == 0 || strings.Join(rows[0], ",") != strings.Join(csvHeader, ",") {
+		return nil, fmt.Errorf("%s: unexpected CSV header", file)
+	}
+	for i, row := range rows[1:] {
+		result := Result{Dataset: row[0], Benchmark: row[1], Tags: row[2], Section: row[4], Unit: row[5]}
+		result.BatchSize, err = strconv.Atoi(row[3])
+		if err != nil {
+			return nil, fmt.Errorf("%s: row %d: %w", file, i+2, err)
+		}
+		for j, value := range []*float64{&result.Mean, &result.Min, &result.Max, &result.Stddev, &result.P50, &result.P90, &result.P99, &result.Total} {
+			*value, err = strconv.ParseFloat(row[6+j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: row %d: %w", file, i+2, err)
+			}
+		}
+		results.Results = append(results.Results, result)
+	}
+	return results, nil
+}
+
+// Thresholds are the maximum relative changes of the means accepted by
+// CompareResults, e.g. 0.1 accepts a compressed size 10% larger.
+type Thresholds struct {
+	// Time applies to the durations.
+	Time float64
+	// Size applies to the sizes and the compression ratios.
+	Size float64
+}
+
+// Comparison compares the mean of a result to its baseline.
+type Comparison struct {
+	Key      string
+	Unit     string
+	Baseline float64
+	Current  float64
+	// Change is the relative change of the mean, positive when the current
+	// result is worse (slower, larger or less compressed).
+	Change float64
+	// Regression is true if the change is above the threshold.
+	Regression bool
+}
+
+// CompareResults compares the results present in both sets. The results of
+// one set missing in the other are returned as missing.
+func CompareResults(baseline, current *Results, thresholds Thresholds) (comparisons []Comparison, missing []string) {
+	baselines := make(map[string]*Result, len(baseline.Results))
+	for i := range baseline.Results {
+		baselines[baseline.Results[i].Key()] = &baseline.Results[i]
+	}
+
+	seen := make(map[string]bool, len(current.Results))
+	for i := range current.Results {
+		result := &current.Results[i]
+		key := result.Key()
+		seen[key] = true
+		base, ok := baselines[key]
+		if !ok {
+			missing = append(missing, key)
+			continue
+		}
+
+		c := Comparison{Key: key, Unit: result.Unit, Baseline: base.Mean, Current: result.Mean}
+		if base.Mean != 0 {
+			c.Change = (result.Mean - base.Mean) / base.Mean
+		} else if result.Mean != 0 {
+			c.Change = math.Inf(1)
+		}
+		threshold := thresholds.Size
+		switch result.Unit {
+		case UnitSeconds:
+			threshold = thresholds.Time
+		case UnitRatio:
+			// A lower compression ratio is worse.
+			c.Change = -c.Change
+		}
+		c.Regression = c.Change > threshold
+		comparisons = append(comparisons, c)
+	}
+
+	for _, result := range baseline.Results {
+		if key := result.Key(); !seen[key] {
+			missing = append(missing, key)
+		}
+	}
+	return comparisons, missing
+}
@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/f5/otel-arrow-adapter/pkg/benchmark/stats"
+)
+
+func summaryOf(values ...float64) *stats.Summary {
+	m := stats.NewMetric()
+	for _, v := range values {
+		m.Record(v)
+	}
+	return m.ComputeSummary()
+}
+
+// testProfiler returns a profiler with the results of a system, its sizes
+// and durations are scaled by the given factors.
+func testProfiler(sizeFactor, timeFactor float64) *Profiler {
+	seconds := summaryOf(0.001*timeFactor, 0.002*timeFactor)
+	return &Profiler{
+		batchSizes: []int{100},
+		benchmarks: []*stats.ProfilerResult{{
+			BenchName: "OTel_ARROW",
+			Tags:      "stream mode",
+			Summaries: []stats.BatchSummary{{
+				BatchSize:              100,
+				UncompressedSizeByte:   summaryOf(1000, 1200),
+				CompressedSizeByte:     summaryOf(100*sizeFactor, 120*sizeFactor),
+				OtlpArrowConversionSec: seconds,
+				ProcessingSec:          seconds,
+				SerializationSec:       seconds,
+				DeserializationSec:     seconds,
+				CompressionSec:         seconds,
+				DecompressionSec:       seconds,
+				TotalTimeSec:           seconds,
+				OtlpConversionSec:      seconds,
+			}},
+		}},
+	}
+}
+
+func findResult(t *testing.T, results *Results, section string) Result {
+	for _, result := range results.Results {
+		if result.Section == section {
+			return result
+		}
+	}
+	t.Fatalf("missing section %s", section)
+	return Result{}
+}
+
+func TestResultsWriteRead(t *testing.T) {
+	t.Parallel()
+
+	results := NewResults()
+	results.Add("traces.pb", testProfiler(1, 1), 1)
+	require.Len(t, results.Results, len(resultSections))
+
+	ratio := findResult(t, results, CompressionRatioSection.ID)
+	require.Equal(t, UnitRatio, ratio.Unit)
+	require.InDelta(t, 10, ratio.Mean, 1e-9)
+	compressed := findResult(t, results, CompressedSizeSection.ID)
+	require.Equal(t, 110.0, compressed.Mean)
+	require.Equal(t, 220.0, compressed.Total)
+
+	for _, name := range []string{"results.json", "results.csv"} {
+		file := filepath.Join(t.TempDir(), "out", name)
+		require.NoError(t, results.WriteFile(file))
+		actual, err := ReadResultsFile(file)
+		require.NoError(t, err, name)
+		require.Equal(t, results.Results, actual.Results, name)
+	}
+}
+
+func TestCompareResults(t *testing.T) {
+	t.Parallel()
+
+	baseline := NewResults()
+	baseline.Add("traces.pb", testProfiler(1, 1), 1)
+
+	thresholds := Thresholds{Time: 0.25, Size: 0.05}
+
+	// Faster and within the size threshold.
+	current := NewResults()
+	current.Add("traces.pb", testProfiler(1.04, 0.5), 1)
+	comparisons, missing := CompareResults(baseline, current, thresholds)
+	require.Empty(t, missing)
+	require.Len(t, comparisons, len(resultSections))
+	for _, c := range comparisons {
+		require.False(t, c.Regression, c.Key)
+	}
+
+	// Larger compressed messages: the compressed size and the compression
+	// ratio regress.
+	current = NewResults()
+	current.Add("traces.pb", testProfiler(1.2, 1), 1)
+	comparisons, _ = CompareResults(baseline, current, thresholds)
+	var regressed []string
+	for _, c := range comparisons {
+		if c.Regression {
+			regressed = append(regressed, c.Key)
+			require.Positive(t, c.Change)
+		}
+	}
+	require.Equal(t, []string{
+		"traces.pb OTel_ARROW [stream mode] batch_size=100 compressed_size",
+		"traces.pb OTel_ARROW [stream mode] batch_size=100 compression_ratio",
+	}, regressed)
+
+	// Slower.
+	current = NewResults()
+	current.Add("traces.pb", testProfiler(1, 1.5), 1)
+	comparisons, _ = CompareResults(baseline, current, thresholds)
+	regressions := 0
+	for _, c := range comparisons {
+		if c.Regression {
+			regressions++
+			require.Equal(t, UnitSeconds, c.Unit)
+		}
+	}
+	require.Equal(t, 11, regressions)
+
+	// Different datasets.
+	current = NewResults()
+	current.Add("logs.pb", testProfiler(1, 1), 1)
+	comparisons, missing = CompareResults(baseline, current, thresholds)
+	require.Empty(t, comparisons)
+	require.Len(t, missing, 2*len(resultSections))
+}
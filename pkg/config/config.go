@@ -20,9 +20,12 @@ package config
 // Main configuration object in the package.
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/apache/arrow/go/v12/arrow/memory"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 )
 
 type Config struct {
@@ -33,20 +36,129 @@ type Config struct {
 	// LimitIndexSize sets the maximum size of a dictionary index
 	// before it is no longer encoded as a dictionary.
 	LimitIndexSize uint64
+	// Compression is the compression codec of the IPC messages. When empty,
+	// the codec is derived from Zstd (see IPCCompressionCodec).
+	Compression IPCCompression
 	// Zstd enables the use of ZSTD compression for IPC messages.
-	Zstd bool // Use IPC ZSTD compression
+	//
+	// Deprecated: Use Compression instead, which takes precedence when set.
+	Zstd bool
 	// Stats enables the collection of statistics about the data being encoded.
 	Stats bool
+
+	// ResetAfterBatches sets the number of batches after which the
+	// sub-stream of a payload type is reset, i.e. its IPC writer is re-created
+	// and its dictionaries are dropped (0 means never).
+	ResetAfterBatches uint64
+	// ResetAfterDictionaryBytes sets the size of the dictionaries (in bytes)
+	// above which the sub-stream of a payload type is reset (0 means never).
+	ResetAfterDictionaryBytes uint64
+
+	// CompressionDictionarySamples sets the number of payloads from which the
+	// Producer trains the zstd dictionary of the stream, the records of the
+	// following payloads are compressed with it (0 means no dictionary).
+	CompressionDictionarySamples int
+	// CompressionDictionarySize sets the maximum size of the zstd dictionary.
+	CompressionDictionarySize int
+	// CompressionDictionaryRotation sets the number of batches compressed
+	// with a dictionary after which a new dictionary is trained (0 means
+	// never).
+	CompressionDictionaryRotation uint64
+
+	// AdaptiveSortingPeriod sets the number of trace batches after which the
+	// Producer trial-encodes a sample of the batch with candidate sort
+	// strategies to select the most compact one (0 means a static strategy).
+	AdaptiveSortingPeriod uint64
+
+	// AdaptiveSortingSampleSize sets the maximum number of spans of the sample.
+	AdaptiveSortingSampleSize int
+
+	// AdaptiveSortingMinGain sets the minimum relative size reduction of a
+	// candidate strategy over the current one for the Producer to switch to
+	// it.
+	AdaptiveSortingMinGain float64
+
+	// EncodingConcurrency sets the maximum number of related records built
+	// and IPC encoded concurrently for a batch (0 or 1 means sequential).
+	EncodingConcurrency int
+
+	// MeterProvider is used to report the stats of the Producer as
+	// OpenTelemetry metrics (nil means no reporting).
+	MeterProvider metric.MeterProvider
+	// MeterAttributes are added to all the measurements reported with the
+	// MeterProvider.
+	MeterAttributes []attribute.KeyValue
 }
 
 type Option func(*Config)
 
+// DefaultCompressionDictionarySize is the default maximum size of the zstd
+// dictionary of a stream.
+const DefaultCompressionDictionarySize = 64 << 10
+
+const (
+	// DefaultAdaptiveSortingSampleSize is the default maximum number of spans
+	// trial-encoded with each candidate sort strategy.
+	DefaultAdaptiveSortingSampleSize = 1000
+
+	// DefaultAdaptiveSortingMinGain is the default minimum relative size
+	// reduction of a candidate sort strategy for the Producer to switch to it.
+	DefaultAdaptiveSortingMinGain = 0.02
+)
+
+// IPCCompression is a compression codec of the Arrow IPC messages, applied
+// by the Producer to the buffers of the records. The Consumer decodes the
+// messages whatever their codec.
+type IPCCompression string
+
+const (
+	// IPCCompressionNone disables the compression.
+	IPCCompressionNone IPCCompression = "none"
+	// IPCCompressionZstd is the ZSTD codec, the best ratio.
+	IPCCompressionZstd IPCCompression = "zstd"
+	// IPCCompressionLZ4Frame is the LZ4_FRAME codec, faster but with a
+	// lower ratio than ZSTD.
+	IPCCompressionLZ4Frame IPCCompression = "lz4_frame"
+)
+
+// Validate returns an error if the codec is unknown.
+func (c IPCCompression) Validate() error {
+	switch c {
+	case "", IPCCompressionNone, IPCCompressionZstd, IPCCompressionLZ4Frame:
+		return nil
+	default:
+		return fmt.Errorf("unknown IPC compression %q (expected %s, %s or %s)", string(c),
+			IPCCompressionNone, IPCCompressionZstd, IPCCompressionLZ4Frame)
+	}
+}
+
+// IPCCompressionCodec returns the compression codec of the IPC messages, i.e.
+// Compression when set, ZSTD or none depending on the deprecated Zstd field
+// otherwise.
+func (cfg *Config) IPCCompressionCodec() IPCCompression {
+	switch {
+	case cfg.Compression != "":
+		return cfg.Compression
+	case cfg.Zstd:
+		return IPCCompressionZstd
+	default:
+		return IPCCompressionNone
+	}
+}
+
 // DefaultConfig returns a Config with the following default values:
 //  - Pool: memory.NewGoAllocator()
 //  - InitIndexSize: math.MaxUint16
 //  - LimitIndexSize: math.MaxUint32
 //  - Stats: false
+//  - Compression: "" (derived from Zstd)
 //  - Zstd: true
+//  - ResetAfterBatches: 0 (never)
+//  - ResetAfterDictionaryBytes: 0 (never)
+//  - CompressionDictionarySamples: 0 (no dictionary)
+//  - AdaptiveSortingPeriod: 0 (static sort strategy)
+//  - EncodingConcurrency: 0 (sequential)
+//  - MeterProvider: nil (no reporting)
 func DefaultConfig() *Config {
 	return &Config{
 		Pool:           memory.NewGoAllocator(),
@@ -130,15 +242,68 @@ func WithUint64LimitDictIndex() Option {
 
 // WithZstd sets the Producer to use Zstd compression at the Arrow IPC level.
 func WithZstd() Option {
+	return WithIPCCompression(IPCCompressionZstd)
+}
+
+// WithNoZstd sets the Producer to not use compression at the Arrow IPC level.
+func WithNoZstd() Option {
+	return WithIPCCompression(IPCCompressionNone)
+}
+
+// WithLZ4Frame sets the Producer to use LZ4_FRAME compression at the Arrow
+// IPC level.
+func WithLZ4Frame() Option {
+	return WithIPCCompression(IPCCompressionLZ4Frame)
+}
+
+// WithIPCCompression sets the compression codec used by the Producer at the
+// Arrow IPC level.
+func WithIPCCompression(compression IPCCompression) Option {
 	return func(cfg *Config) {
-		cfg.Zstd = true
+		cfg.Compression = compression
 	}
 }
 
-// WithNoZstd sets the Producer to not use Zstd compression at the Arrow IPC level.
-func WithNoZstd() Option {
+// WithCompressionDictionary sets the Producer to train a zstd dictionary from
+// the first `samples` payloads of the stream and to compress the records of
+// the following payloads with it. The dictionary is shipped once to the
+// Consumer with the batch of the last sample. The records are small (a few
+// KB at low batch sizes), this is where a dictionary helps the most.
+//
+// The Producer disables the compression at the Arrow IPC level whatever the
+// order of the options, the IPC compressed buffers don't compress further.
+func WithCompressionDictionary(samples, size int) Option {
 	return func(cfg *Config) {
-		cfg.Zstd = false
+		cfg.CompressionDictionarySamples = samples
+		cfg.CompressionDictionarySize = size
+	}
+}
+
+// WithCompressionDictionaryRotation sets the Producer to train a new zstd
+// dictionary (with a new id) every n batches, from the payloads following the
+// rotation, for the data whose content drifts over time.
+func WithCompressionDictionaryRotation(n uint64) Option {
+	return func(cfg *Config) {
+		cfg.CompressionDictionaryRotation = n
+	}
+}
+
+// WithAdaptiveSorting sets the Producer to select the sort strategy of the
+// traces (the sort orders of the spans, events, links and attributes) every
+// `period` trace batches: a sample of up to `sampleSize` spans of the batch is
+// encoded and zstd compressed with the current strategy and the candidate
+// ones, the most compact candidate replaces the current strategy once it has
+// been smaller by at least `minGain` (relative) on two consecutive
+// evaluations, to avoid flapping. The chosen strategy is reported in the
+// stats of the Producer.
+//
+// The candidate strategies are all decoded by the Consumer without any
+// change.
+func WithAdaptiveSorting(period uint64, sampleSize int, minGain float64) Option {
+	return func(cfg *Config) {
+		cfg.AdaptiveSortingPeriod = period
+		cfg.AdaptiveSortingSampleSize = sampleSize
+		cfg.AdaptiveSortingMinGain = minGain
 	}
 }
 
@@ -148,3 +313,43 @@ func WithStats() Option {
 		cfg.Stats = true
 	}
 }
+
+// WithResetAfterBatches sets the Producer to reset the sub-stream of a payload
+// type (IPC writer and dictionaries) every n batches.
+func WithResetAfterBatches(n uint64) Option {
+	return func(cfg *Config) {
+		cfg.ResetAfterBatches = n
+	}
+}
+
+// WithResetAfterDictionaryBytes sets the Producer to reset the sub-stream of a
+// payload type (IPC writer and dictionaries) when the size of its dictionaries
+// exceeds n bytes.
+func WithResetAfterDictionaryBytes(n uint64) Option {
+	return func(cfg *Config) {
+		cfg.ResetAfterDictionaryBytes = n
+	}
+}
+
+// WithEncodingConcurrency sets the Producer to build and encode up to n
+// related records (e.g. attributes, data points, ...) of a batch concurrently.
+// Each sub-stream has its own IPC writer so these records are independent.
+// The order of the payloads in the produced batches is preserved.
+func WithEncodingConcurrency(n int) Option {
+	return func(cfg *Config) {
+		cfg.EncodingConcurrency = n
+	}
+}
+
+// WithMeterProvider sets the Producer to report its stats (batches produced,
+// stream producers created/closed, schema updates, dictionary events, encoded
+// bytes and dictionary cardinalities) as metrics created from the given
+// MeterProvider. The given attributes are added to all the measurements, they
+// must distinguish the producers sharing the MeterProvider (e.g. the index of
+// their stream) as the dictionary cardinalities are observed per producer.
+func WithMeterProvider(mp metric.MeterProvider, attrs ...attribute.KeyValue) Option {
+	return func(cfg *Config) {
+		cfg.MeterProvider = mp
+		cfg.MeterAttributes = attrs
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/brianvoe/gofakeit/v6"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 )
 
@@ -78,6 +79,9 @@ type DataGenerator struct {
 
 	config Config
 
+	// Generator of the fake texts, seeded by the entropy.
+	faker *gofakeit.Faker
+
 	resourceAttributes    []pcommon.Map
 	instrumentationScopes []pcommon.InstrumentationScope
 }
@@ -88,6 +92,7 @@ func NewDataGenerator(entropy TestEntropy, resourceAttributes []pcommon.Map, ins
 		prevTime:              pcommon.Timestamp(entropy.Start()),
 		currentTime:           pcommon.Timestamp(entropy.Start()),
 		config:                NewDefaultConfig(),
+		faker:                 gofakeit.NewUnlocked(entropy.Start()),
 		resourceAttributes:    resourceAttributes,
 		instrumentationScopes: instrumentationScopes,
 	}
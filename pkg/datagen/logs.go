@@ -17,8 +17,6 @@ package datagen
 import (
 	"time"
 
-	"github.com/brianvoe/gofakeit/v6"
-
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 )
@@ -89,7 +87,7 @@ func (dg *DataGenerator) logRecord(log plog.LogRecord, sev plog.SeverityNumber,
 	log.SetObservedTimestamp(dg.CurrentTime())
 	log.SetSeverityNumber(sev)
 	log.SetSeverityText(txt)
-	log.Body().SetStr(gofakeit.LoremIpsumSentence(10))
+	log.Body().SetStr(dg.faker.LoremIpsumSentence(10))
 	dg.NewStandardAttributes().CopyTo(log.Attributes())
 	log.SetTraceID(dg.Id16Bytes())
 	log.SetSpanID(dg.Id8Bytes())
@@ -101,7 +99,7 @@ func (dg *DataGenerator) complexLogRecord(log plog.LogRecord, sev plog.SeverityN
 	log.SetSeverityNumber(sev)
 	log.SetSeverityText(txt)
 	obj := log.Body().SetEmptyMap()
-	obj.PutStr("attr1", gofakeit.LoremIpsumSentence(10))
+	obj.PutStr("attr1", dg.faker.LoremIpsumSentence(10))
 	obj.PutInt("attr2", 1)
 	obj.PutDouble("attr3", 2.0)
 	obj.PutBool("attr4", true)
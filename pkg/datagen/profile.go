@@ -0,0 +1,620 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datagen
+
+// Declarative description of a synthetic workload (see Profile), loaded from
+// a YAML or JSON file and consumed by the ProfileGenerator.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Attribute value types of an AttributeProfile.
+const (
+	AttrTypeString = "string"
+	AttrTypeInt    = "int"
+	AttrTypeDouble = "double"
+	AttrTypeBool   = "bool"
+	AttrTypeBytes  = "bytes"
+)
+
+// Value distributions of an AttributeProfile (and of the resources).
+const (
+	DistributionUniform = "uniform"
+	DistributionZipf    = "zipf"
+)
+
+// Body types of a BodyProfile.
+const (
+	BodyTypeString = "string"
+	BodyTypeMap    = "map"
+	BodyTypeJSON   = "json"
+)
+
+// Latency distributions of a LatencyProfile.
+const (
+	LatencyExponential = "exponential"
+	LatencyUniform     = "uniform"
+	LatencyLognormal   = "lognormal"
+)
+
+// Metric types of a MetricProfile.
+const (
+	MetricTypeGauge     = "gauge"
+	MetricTypeSum       = "sum"
+	MetricTypeHistogram = "histogram"
+)
+
+type (
+	// Profile describes the shape of a synthetic workload: the resources and
+	// the scopes, the attributes of each signal (keys, value types,
+	// cardinalities and distributions), the log bodies, the span trees,
+	// events and links, and the timing of the generated items.
+	//
+	// The zero value of a field selects its default, so a profile only
+	// describes what differs from the default workload.
+	Profile struct {
+		Resources ResourcesProfile `yaml:"resources" json:"resources"`
+		Scopes    ScopesProfile    `yaml:"scopes" json:"scopes"`
+		Time      TimeProfile      `yaml:"time" json:"time"`
+		Traces    TracesProfile    `yaml:"traces" json:"traces"`
+		Logs      LogsProfile      `yaml:"logs" json:"logs"`
+		Metrics   MetricsProfile   `yaml:"metrics" json:"metrics"`
+	}
+
+	// ResourcesProfile describes the distinct resources of the workload, each
+	// generated item (trace, log record or collection of metrics) is attached
+	// to one of them.
+	ResourcesProfile struct {
+		// Number of distinct resources (1 by default).
+		Count int `yaml:"count" json:"count"`
+		// Distribution of the items over the resources (uniform by default).
+		Distribution string             `yaml:"distribution" json:"distribution"`
+		Attributes   []AttributeProfile `yaml:"attributes" json:"attributes"`
+	}
+
+	// ScopesProfile describes the distinct instrumentation scopes of the
+	// workload, picked uniformly for each generated item.
+	ScopesProfile struct {
+		// Number of distinct scopes (1 by default).
+		Count int `yaml:"count" json:"count"`
+		// Name of the scopes ("datagen" by default), suffixed with the index
+		// of the scope when there are several scopes.
+		Name       string             `yaml:"name" json:"name"`
+		Version    string             `yaml:"version" json:"version"`
+		Attributes []AttributeProfile `yaml:"attributes" json:"attributes"`
+	}
+
+	// AttributeProfile describes an attribute (or a set of similar
+	// attributes) and the distribution of its values.
+	AttributeProfile struct {
+		Key string `yaml:"key" json:"key"`
+		// Number of attributes described by this profile, named <key>.0 to
+		// <key>.<count-1> when greater than 1 (1 by default).
+		Count int `yaml:"count" json:"count"`
+		// Value type (string by default).
+		Type string `yaml:"type" json:"type"`
+		// Number of distinct values, 0 means a new random value for each
+		// occurrence. Ignored for the bool values. The int values are in
+		// [0, cardinality) (or [0, 1000000) without cardinality).
+		Cardinality int `yaml:"cardinality" json:"cardinality"`
+		// Distribution of the values (uniform by default).
+		Distribution string `yaml:"distribution" json:"distribution"`
+		// Exponent of the zipf distribution, greater than 1 (1.1 by
+		// default).
+		Skew float64 `yaml:"skew" json:"skew"`
+		// Length of the string and bytes values (16 by default).
+		Length int `yaml:"length" json:"length"`
+		// Probability that the attribute is present (1 by default).
+		Probability *float64 `yaml:"probability" json:"probability"`
+	}
+
+	// Range is an inclusive range of counts, a value is picked uniformly in
+	// it. A Max lower than Min is replaced by Min.
+	Range struct {
+		Min int `yaml:"min" json:"min"`
+		Max int `yaml:"max" json:"max"`
+	}
+
+	// TimeProfile describes the temporal pattern of the generated items.
+	TimeProfile struct {
+		// Interval between two items (1s by default).
+		Interval Duration `yaml:"interval" json:"interval"`
+		// Random variation of the interval, as a fraction of it ([0, 1]).
+		Jitter float64 `yaml:"jitter" json:"jitter"`
+		// Pause inserted after every BurstSize items (no burst by default).
+		BurstSize  int      `yaml:"burst_size" json:"burst_size"`
+		BurstPause Duration `yaml:"burst_pause" json:"burst_pause"`
+		// Period of a sinusoidal variation of the rate of the items (e.g.
+		// 24h for a daily pattern) and its relative amplitude ([0, 1)).
+		Period    Duration `yaml:"period" json:"period"`
+		Amplitude float64  `yaml:"amplitude" json:"amplitude"`
+	}
+
+	// TracesProfile describes the traces, a trace being a tree of spans.
+	TracesProfile struct {
+		// Number of spans of each trace (1 by default).
+		SpansPerTrace Range `yaml:"spans_per_trace" json:"spans_per_trace"`
+		// Maximum depth of the span trees, the root span having the depth 0
+		// (1 by default). The parent of each span is picked randomly.
+		MaxDepth int `yaml:"max_depth" json:"max_depth"`
+		// Names of the spans, picked uniformly ("span" by default).
+		SpanNames  []string           `yaml:"span_names" json:"span_names"`
+		Attributes []AttributeProfile `yaml:"attributes" json:"attributes"`
+		// Probability of a span with an error status.
+		ErrorRate float64       `yaml:"error_rate" json:"error_rate"`
+		Events    EventsProfile `yaml:"events" json:"events"`
+		Links     LinksProfile  `yaml:"links" json:"links"`
+		// ServiceGraph replaces the random span trees by the simulation of
+		// the calls between the services of a service graph.
+		ServiceGraph *ServiceGraphProfile `yaml:"service_graph" json:"service_graph"`
+	}
+
+	// EventsProfile describes the events of each span.
+	EventsProfile struct {
+		Count Range `yaml:"count" json:"count"`
+		// Names of the events, picked uniformly ("event" by default).
+		Names      []string           `yaml:"names" json:"names"`
+		Attributes []AttributeProfile `yaml:"attributes" json:"attributes"`
+	}
+
+	// LinksProfile describes the links of each span, to spans of the
+	// previous traces.
+	LinksProfile struct {
+		Count      Range              `yaml:"count" json:"count"`
+		Attributes []AttributeProfile `yaml:"attributes" json:"attributes"`
+	}
+
+	// ServiceGraphProfile describes a graph of services calling each other.
+	// Each trace starts with an entry point and follows the calls of the
+	// operations, with a client and a server span per synchronous call.
+	// An asynchronous call (e.g. through a message queue) has a producer
+	// span and starts a new trace, whose root consumer span is linked to the
+	// producer span.
+	//
+	// ProfileGenerator.GenerateSpans emits the spans when they end, like an
+	// SDK would export them, so the spans of a trace may be split across
+	// batches, and ProfileGenerator.GenerateTraces emits whole traces. Each
+	// service is a resource (with the service.name
+	// attribute) and the resources of the profile are not used. The span
+	// attributes of the traces profile are added to every span.
+	ServiceGraphProfile struct {
+		Services []ServiceProfile `yaml:"services" json:"services"`
+		// Operations starting the traces, as "<service>/<operation>" (or
+		// "<service>" for the first operation of the service), picked
+		// uniformly (the first operation of the first service by default).
+		Entrypoints []string `yaml:"entrypoints" json:"entrypoints"`
+		// Maximum depth of the call chains (10 by default), which also
+		// breaks the cycles of the graph.
+		MaxDepth int `yaml:"max_depth" json:"max_depth"`
+		// Probability that the failure of a call makes the caller fail.
+		ErrorPropagation float64 `yaml:"error_propagation" json:"error_propagation"`
+		// Mean network latency of a call (1ms by default).
+		NetworkLatency Duration `yaml:"network_latency" json:"network_latency"`
+	}
+
+	// ServiceProfile describes a service of a service graph.
+	ServiceProfile struct {
+		Name string `yaml:"name" json:"name"`
+		// Resource attributes, in addition to service.name.
+		Attributes []AttributeProfile `yaml:"attributes" json:"attributes"`
+		Operations []OperationProfile `yaml:"operations" json:"operations"`
+	}
+
+	// OperationProfile describes an operation served by a service.
+	OperationProfile struct {
+		Name    string         `yaml:"name" json:"name"`
+		Latency LatencyProfile `yaml:"latency" json:"latency"`
+		// Probability that the operation fails by itself, a failed span has
+		// an error status and an exception event.
+		ErrorRate  float64            `yaml:"error_rate" json:"error_rate"`
+		Attributes []AttributeProfile `yaml:"attributes" json:"attributes"`
+		Events     EventsProfile      `yaml:"events" json:"events"`
+		// Calls made by the operation, sequentially and in order.
+		Calls []CallProfile `yaml:"calls" json:"calls"`
+	}
+
+	// LatencyProfile describes the time spent by an operation by itself,
+	// i.e. without the calls it makes.
+	LatencyProfile struct {
+		// Distribution of the latency, exponential (the default), uniform
+		// (between Min and Max) or lognormal.
+		Distribution string `yaml:"distribution" json:"distribution"`
+		// Mean of the exponential distribution and median of the lognormal
+		// one (10ms by default).
+		Mean Duration `yaml:"mean" json:"mean"`
+		// Sigma of the lognormal distribution (0.5 by default).
+		Sigma float64 `yaml:"sigma" json:"sigma"`
+		// Bounds of the latency (Max is ignored when 0).
+		Min Duration `yaml:"min" json:"min"`
+		Max Duration `yaml:"max" json:"max"`
+	}
+
+	// CallProfile describes the calls of an operation to another one.
+	CallProfile struct {
+		// Called operation, "<service>/<operation>" (or "<service>" for the
+		// first operation of the service).
+		Target string `yaml:"target" json:"target"`
+		// Probability of the call (1 by default).
+		Probability *float64 `yaml:"probability" json:"probability"`
+		// Number of calls, i.e. the fan-out (1 by default).
+		Count Range `yaml:"count" json:"count"`
+		// Async makes the call through a message queue.
+		Async bool `yaml:"async" json:"async"`
+	}
+
+	// LogsProfile describes the log records.
+	LogsProfile struct {
+		// Severity texts (TRACE, DEBUG, INFO, WARN, ERROR or FATAL), picked
+		// uniformly (INFO by default).
+		Severities []string           `yaml:"severities" json:"severities"`
+		Attributes []AttributeProfile `yaml:"attributes" json:"attributes"`
+		Body       BodyProfile        `yaml:"body" json:"body"`
+		// Probability that a log record has a trace and a span id.
+		TraceContext float64 `yaml:"trace_context" json:"trace_context"`
+	}
+
+	// BodyProfile describes the bodies of the log records.
+	BodyProfile struct {
+		// Body type (string by default). The json bodies are strings
+		// containing the JSON encoding of a map.
+		Type string `yaml:"type" json:"type"`
+		// Number of words of the string bodies (10 by default).
+		Words int `yaml:"words" json:"words"`
+		// Fields of the map and json bodies.
+		Fields []AttributeProfile `yaml:"fields" json:"fields"`
+	}
+
+	// MetricsProfile describes the metrics generated at each collection.
+	MetricsProfile struct {
+		// Metrics (a single gauge by default).
+		Metrics []MetricProfile `yaml:"metrics" json:"metrics"`
+	}
+
+	// MetricProfile describes a metric (or a set of similar metrics).
+	MetricProfile struct {
+		Name string `yaml:"name" json:"name"`
+		// Number of metrics described by this profile, named <name>.0 to
+		// <name>.<count-1> when greater than 1 (1 by default).
+		Count int `yaml:"count" json:"count"`
+		// Metric type (gauge by default).
+		Type string `yaml:"type" json:"type"`
+		Unit string `yaml:"unit" json:"unit"`
+		// Number of data points of the metric at each collection (1 by
+		// default).
+		DataPoints Range              `yaml:"data_points" json:"data_points"`
+		Attributes []AttributeProfile `yaml:"attributes" json:"attributes"`
+		// Number of buckets of the histograms (8 by default).
+		Buckets int `yaml:"buckets" json:"buckets"`
+	}
+
+	// Duration is a time.Duration encoded as a string (e.g. "1.5s") in the
+	// profile files.
+	Duration time.Duration
+)
+
+// LoadProfile loads and validates a profile from a YAML or JSON (with the
+// .json extension) file. Unknown fields are rejected.
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read profile: %w", err)
+	}
+
+	profile := &Profile{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(profile)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(profile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode profile %q: %w", path, err)
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile %q: %w", path, err)
+	}
+	return profile, nil
+}
+
+// Validate returns an error when a field of the profile has an invalid value.
+func (p *Profile) Validate() error {
+	if p.Resources.Count < 0 {
+		return fmt.Errorf("resources: count must be >= 0: %d", p.Resources.Count)
+	}
+	if err := validateDistribution(p.Resources.Distribution); err != nil {
+		return fmt.Errorf("resources: %w", err)
+	}
+	if err := validateAttributes("resources", p.Resources.Attributes); err != nil {
+		return err
+	}
+	if p.Scopes.Count < 0 {
+		return fmt.Errorf("scopes: count must be >= 0: %d", p.Scopes.Count)
+	}
+	if err := validateAttributes("scopes", p.Scopes.Attributes); err != nil {
+		return err
+	}
+	if err := p.Time.validate(); err != nil {
+		return fmt.Errorf("time: %w", err)
+	}
+	if err := p.Traces.validate(); err != nil {
+		return err
+	}
+	if err := p.Logs.validate(); err != nil {
+		return err
+	}
+	return p.Metrics.validate()
+}
+
+func (t *TimeProfile) validate() error {
+	switch {
+	case t.Interval < 0 || t.BurstPause < 0 || t.Period < 0:
+		return fmt.Errorf("durations must be >= 0")
+	case t.Jitter < 0 || t.Jitter > 1:
+		return fmt.Errorf("jitter must be in [0, 1]: %v", t.Jitter)
+	case t.BurstSize < 0:
+		return fmt.Errorf("burst_size must be >= 0: %d", t.BurstSize)
+	case t.Amplitude < 0 || t.Amplitude >= 1:
+		return fmt.Errorf("amplitude must be in [0, 1): %v", t.Amplitude)
+	}
+	return nil
+}
+
+func (t *TracesProfile) validate() error {
+	if err := t.SpansPerTrace.validate(); err != nil {
+		return fmt.Errorf("traces: spans_per_trace: %w", err)
+	}
+	if t.MaxDepth < 0 {
+		return fmt.Errorf("traces: max_depth must be >= 0: %d", t.MaxDepth)
+	}
+	if t.ErrorRate < 0 || t.ErrorRate > 1 {
+		return fmt.Errorf("traces: error_rate must be in [0, 1]: %v", t.ErrorRate)
+	}
+	if err := validateAttributes("traces", t.Attributes); err != nil {
+		return err
+	}
+	if err := t.Events.Count.validate(); err != nil {
+		return fmt.Errorf("traces: events: count: %w", err)
+	}
+	if err := validateAttributes("traces: events", t.Events.Attributes); err != nil {
+		return err
+	}
+	if err := t.Links.Count.validate(); err != nil {
+		return fmt.Errorf("traces: links: count: %w", err)
+	}
+	if err := validateAttributes("traces: links", t.Links.Attributes); err != nil {
+		return err
+	}
+	if t.ServiceGraph != nil {
+		if err := t.ServiceGraph.validate(); err != nil {
+			return fmt.Errorf("traces: service_graph: %w", err)
+		}
+	}
+	return nil
+}
+
+func (sg *ServiceGraphProfile) validate() error {
+	if len(sg.Services) == 0 {
+		return fmt.Errorf("no services")
+	}
+	if sg.MaxDepth < 0 {
+		return fmt.Errorf("max_depth must be >= 0: %d", sg.MaxDepth)
+	}
+	if sg.ErrorPropagation < 0 || sg.ErrorPropagation > 1 {
+		return fmt.Errorf("error_propagation must be in [0, 1]: %v", sg.ErrorPropagation)
+	}
+	if sg.NetworkLatency < 0 {
+		return fmt.Errorf("network_latency must be >= 0")
+	}
+	names := map[string]bool{}
+	for _, service := range sg.Services {
+		if service.Name == "" || names[service.Name] {
+			return fmt.Errorf("missing or duplicate service name %q", service.Name)
+		}
+		names[service.Name] = true
+		if len(service.Operations) == 0 {
+			return fmt.Errorf("%s: no operations", service.Name)
+		}
+		if err := validateAttributes(service.Name, service.Attributes); err != nil {
+			return err
+		}
+		for _, op := range service.Operations {
+			if err := op.validate(sg); err != nil {
+				return fmt.Errorf("%s/%s: %w", service.Name, op.Name, err)
+			}
+		}
+	}
+	for _, entrypoint := range sg.Entrypoints {
+		if _, err := sg.resolve(entrypoint); err != nil {
+			return fmt.Errorf("entrypoints: %w", err)
+		}
+	}
+	return nil
+}
+
+func (op *OperationProfile) validate(sg *ServiceGraphProfile) error {
+	if op.Name == "" {
+		return fmt.Errorf("missing operation name")
+	}
+	if op.ErrorRate < 0 || op.ErrorRate > 1 {
+		return fmt.Errorf("error_rate must be in [0, 1]: %v", op.ErrorRate)
+	}
+	switch op.Latency.Distribution {
+	case "", LatencyExponential, LatencyUniform, LatencyLognormal:
+	default:
+		return fmt.Errorf("unknown latency distribution %q", op.Latency.Distribution)
+	}
+	if op.Latency.Mean < 0 || op.Latency.Min < 0 || op.Latency.Max < 0 || op.Latency.Sigma < 0 {
+		return fmt.Errorf("latency: mean, sigma, min and max must be >= 0")
+	}
+	if op.Latency.Distribution == LatencyUniform && op.Latency.Max <= op.Latency.Min {
+		return fmt.Errorf("latency: max must be > min for the uniform distribution")
+	}
+	if err := validateAttributes("attributes", op.Attributes); err != nil {
+		return err
+	}
+	if err := op.Events.Count.validate(); err != nil {
+		return fmt.Errorf("events: count: %w", err)
+	}
+	if err := validateAttributes("events", op.Events.Attributes); err != nil {
+		return err
+	}
+	for _, call := range op.Calls {
+		if _, err := sg.resolve(call.Target); err != nil {
+			return fmt.Errorf("calls: %w", err)
+		}
+		if call.Probability != nil && (*call.Probability < 0 || *call.Probability > 1) {
+			return fmt.Errorf("calls: %s: probability must be in [0, 1]: %v", call.Target, *call.Probability)
+		}
+		if err := call.Count.validate(); err != nil {
+			return fmt.Errorf("calls: %s: count: %w", call.Target, err)
+		}
+	}
+	return nil
+}
+
+// resolve returns the indexes of the service and of the operation of a
+// "<service>/<operation>" (or "<service>") reference.
+func (sg *ServiceGraphProfile) resolve(ref string) ([2]int, error) {
+	serviceName, opName, hasOp := strings.Cut(ref, "/")
+	for s, service := range sg.Services {
+		if service.Name != serviceName {
+			continue
+		}
+		if !hasOp {
+			return [2]int{s, 0}, nil
+		}
+		for o, op := range service.Operations {
+			if op.Name == opName {
+				return [2]int{s, o}, nil
+			}
+		}
+	}
+	return [2]int{}, fmt.Errorf("unknown operation %q", ref)
+}
+
+func (l *LogsProfile) validate() error {
+	for _, severity := range l.Severities {
+		if _, ok := severityNumbers[strings.ToUpper(severity)]; !ok {
+			return fmt.Errorf("logs: unknown severity %q", severity)
+		}
+	}
+	if l.TraceContext < 0 || l.TraceContext > 1 {
+		return fmt.Errorf("logs: trace_context must be in [0, 1]: %v", l.TraceContext)
+	}
+	switch l.Body.Type {
+	case "", BodyTypeString, BodyTypeMap, BodyTypeJSON:
+	default:
+		return fmt.Errorf("logs: unknown body type %q", l.Body.Type)
+	}
+	if l.Body.Words < 0 {
+		return fmt.Errorf("logs: body: words must be >= 0: %d", l.Body.Words)
+	}
+	if err := validateAttributes("logs: body", l.Body.Fields); err != nil {
+		return err
+	}
+	return validateAttributes("logs", l.Attributes)
+}
+
+func (m *MetricsProfile) validate() error {
+	for _, metric := range m.Metrics {
+		if metric.Name == "" {
+			return fmt.Errorf("metrics: missing metric name")
+		}
+		switch metric.Type {
+		case "", MetricTypeGauge, MetricTypeSum, MetricTypeHistogram:
+		default:
+			return fmt.Errorf("metrics: %s: unknown type %q", metric.Name, metric.Type)
+		}
+		if metric.Count < 0 || metric.Buckets < 0 {
+			return fmt.Errorf("metrics: %s: count and buckets must be >= 0", metric.Name)
+		}
+		if err := metric.DataPoints.validate(); err != nil {
+			return fmt.Errorf("metrics: %s: data_points: %w", metric.Name, err)
+		}
+		if err := validateAttributes("metrics: "+metric.Name, metric.Attributes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r Range) validate() error {
+	if r.Min < 0 || r.Max < 0 {
+		return fmt.Errorf("min and max must be >= 0: [%d, %d]", r.Min, r.Max)
+	}
+	return nil
+}
+
+func validateDistribution(distribution string) error {
+	switch distribution {
+	case "", DistributionUniform, DistributionZipf:
+		return nil
+	default:
+		return fmt.Errorf("unknown distribution %q", distribution)
+	}
+}
+
+func validateAttributes(section string, attributes []AttributeProfile) error {
+	for _, attr := range attributes {
+		if attr.Key == "" {
+			return fmt.Errorf("%s: missing attribute key", section)
+		}
+		switch attr.Type {
+		case "", AttrTypeString, AttrTypeInt, AttrTypeDouble, AttrTypeBool, AttrTypeBytes:
+		default:
+			return fmt.Errorf("%s: %s: unknown attribute type %q", section, attr.Key, attr.Type)
+		}
+		if err := validateDistribution(attr.Distribution); err != nil {
+			return fmt.Errorf("%s: %s: %w", section, attr.Key, err)
+		}
+		if attr.Count < 0 || attr.Cardinality < 0 || attr.Length < 0 {
+			return fmt.Errorf("%s: %s: count, cardinality and length must be >= 0", section, attr.Key)
+		}
+		if attr.Skew != 0 && attr.Skew <= 1 {
+			return fmt.Errorf("%s: %s: skew must be > 1: %v", section, attr.Key, attr.Skew)
+		}
+		if attr.Probability != nil && (*attr.Probability < 0 || *attr.Probability > 1) {
+			return fmt.Errorf("%s: %s: probability must be in [0, 1]: %v", section, attr.Key, *attr.Probability)
+		}
+	}
+	return nil
+}
+
+// MarshalText encodes the duration as a string (e.g. "1.5s").
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText decodes a duration string (e.g. "1.5s").
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
@@ -0,0 +1,628 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datagen
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// maxLinkTargets is the number of spans of the previous traces kept as
+// targets of the span links.
+const maxLinkTargets = 64
+
+// maxIntValue bounds the int values of the attributes without cardinality.
+const maxIntValue = 1_000_000
+
+var severityNumbers = map[string]plog.SeverityNumber{
+	"TRACE": plog.SeverityNumberTrace,
+	"DEBUG": plog.SeverityNumberDebug,
+	"INFO":  plog.SeverityNumberInfo,
+	"WARN":  plog.SeverityNumberWarn,
+	"ERROR": plog.SeverityNumberError,
+	"FATAL": plog.SeverityNumberFatal,
+}
+
+type (
+	// ProfileGenerator generates traces, logs and metrics shaped by a
+	// Profile. The generated data only depends on the profile and on the
+	// seed of the entropy.
+	ProfileGenerator struct {
+		*DataGenerator
+
+		time TimeProfile
+		// Number of items generated so far.
+		items int
+
+		resources      []pcommon.Map
+		resourcePicker picker
+		scopes         []pcommon.InstrumentationScope
+
+		spansPerTrace Range
+		maxDepth      int
+		spanNames     []string
+		spanAttrs     []*attrGenerator
+		errorRate     float64
+		eventCount    Range
+		eventNames    []string
+		eventAttrs    []*attrGenerator
+		linkCount     Range
+		linkAttrs     []*attrGenerator
+		// Spans of the previous traces, targets of the links.
+		linkTargets []spanRef
+		// Simulated service graph (nil when the traces are random span
+		// trees).
+		graph *serviceGraph
+
+		severities   []string
+		logAttrs     []*attrGenerator
+		body         BodyProfile
+		bodyFields   []*attrGenerator
+		traceContext float64
+
+		metrics []*metricGenerator
+	}
+
+	// attrGenerator generates the values of an attribute. The value of a
+	// given index is derived from the index and the key, so the attributes
+	// with a cardinality have a stable set of values.
+	attrGenerator struct {
+		key         string
+		typ         string
+		cardinality int
+		length      int
+		probability float64
+		seed        uint64
+		zipf        *rand.Zipf
+	}
+
+	// picker picks an index in [0, n) uniformly or with a zipf distribution.
+	picker struct {
+		n    int
+		zipf *rand.Zipf
+	}
+
+	metricGenerator struct {
+		name       string
+		typ        string
+		unit       string
+		dataPoints Range
+		attrs      []*attrGenerator
+		bounds     []float64
+	}
+
+	spanRef struct {
+		traceID pcommon.TraceID
+		spanID  pcommon.SpanID
+	}
+
+	// spanNode is a span of the trace being generated.
+	spanNode struct {
+		id         pcommon.SpanID
+		parent     int
+		depth      int
+		start, end pcommon.Timestamp
+	}
+)
+
+// NewProfileGenerator creates a generator of the workload described by the
+// profile (see Profile), all the random draws come from the entropy.
+func NewProfileGenerator(entropy TestEntropy, profile *Profile) *ProfileGenerator {
+	g := &ProfileGenerator{
+		DataGenerator: NewDataGenerator(entropy, nil, nil),
+		time:          profile.Time,
+
+		spansPerTrace: orDefaultRange(profile.Traces.SpansPerTrace, 1),
+		maxDepth:      orDefault(profile.Traces.MaxDepth, 1),
+		spanNames:     orDefaultNames(profile.Traces.SpanNames, "span"),
+		errorRate:     profile.Traces.ErrorRate,
+		eventCount:    orDefaultRange(profile.Traces.Events.Count, 0),
+		eventNames:    orDefaultNames(profile.Traces.Events.Names, "event"),
+		linkCount:     orDefaultRange(profile.Traces.Links.Count, 0),
+
+		severities:   orDefaultNames(profile.Logs.Severities, "INFO"),
+		body:         profile.Logs.Body,
+		traceContext: profile.Logs.TraceContext,
+	}
+	if g.time.Interval == 0 {
+		g.time.Interval = Duration(time.Second)
+	}
+	g.body.Type = orDefault(g.body.Type, BodyTypeString)
+	g.body.Words = orDefault(g.body.Words, 10)
+
+	rng := entropy.rng
+	resourceAttrs := newAttrGenerators(rng, profile.Resources.Attributes)
+	g.resourcePicker = newPicker(rng, orDefault(profile.Resources.Count, 1), profile.Resources.Distribution, 0)
+	for i := 0; i < g.resourcePicker.n; i++ {
+		attrs := pcommon.NewMap()
+		putAttributes(rng, resourceAttrs, attrs)
+		g.resources = append(g.resources, attrs)
+	}
+
+	scopeAttrs := newAttrGenerators(rng, profile.Scopes.Attributes)
+	scopeCount := orDefault(profile.Scopes.Count, 1)
+	for i := 0; i < scopeCount; i++ {
+		scope := pcommon.NewInstrumentationScope()
+		name := orDefault(profile.Scopes.Name, "datagen")
+		if scopeCount > 1 {
+			name = fmt.Sprintf("%s.%d", name, i)
+		}
+		scope.SetName(name)
+		scope.SetVersion(orDefault(profile.Scopes.Version, "1.0.0"))
+		putAttributes(rng, scopeAttrs, scope.Attributes())
+		g.scopes = append(g.scopes, scope)
+	}
+
+	g.spanAttrs = newAttrGenerators(rng, profile.Traces.Attributes)
+	g.eventAttrs = newAttrGenerators(rng, profile.Traces.Events.Attributes)
+	g.linkAttrs = newAttrGenerators(rng, profile.Traces.Links.Attributes)
+	if profile.Traces.ServiceGraph != nil {
+		g.graph = newServiceGraph(rng, profile.Traces.ServiceGraph, g.scopes)
+	}
+	g.logAttrs = newAttrGenerators(rng, profile.Logs.Attributes)
+	g.bodyFields = newAttrGenerators(rng, profile.Logs.Body.Fields)
+
+	metrics := profile.Metrics.Metrics
+	if len(metrics) == 0 {
+		metrics = []MetricProfile{{Name: "datagen.gauge"}}
+	}
+	for _, metric := range metrics {
+		attrs := newAttrGenerators(rng, metric.Attributes)
+		bounds := make([]float64, orDefault(metric.Buckets, 8)-1)
+		for i := range bounds {
+			bounds[i] = math.Pow(2, float64(i))
+		}
+		count := orDefault(metric.Count, 1)
+		for i := 0; i < count; i++ {
+			name := metric.Name
+			if count > 1 {
+				name = fmt.Sprintf("%s.%d", name, i)
+			}
+			g.metrics = append(g.metrics, &metricGenerator{
+				name:       name,
+				typ:        orDefault(metric.Type, MetricTypeGauge),
+				unit:       metric.Unit,
+				dataPoints: orDefaultRange(metric.DataPoints, 1),
+				attrs:      attrs,
+				bounds:     bounds,
+			})
+		}
+	}
+
+	return g
+}
+
+// GenerateTraces generates batchSize traces. With a service graph (see
+// ServiceGraphProfile), the traces are simulated whole, with the traces
+// started by their asynchronous calls and the spans not yet emitted by
+// GenerateSpans.
+func (g *ProfileGenerator) GenerateTraces(batchSize int) ptrace.Traces {
+	if g.graph != nil {
+		return g.generateGraphTraces(batchSize)
+	}
+
+	result := ptrace.NewTraces()
+	resourceSpans := map[int]ptrace.ResourceSpans{}
+	scopeSpans := map[[2]int]ptrace.SpanSlice{}
+
+	for i := 0; i < batchSize; i++ {
+		g.advance()
+		r, s := g.resourcePicker.pick(g.rng), g.rng.Intn(len(g.scopes))
+		spans, ok := scopeSpans[[2]int{r, s}]
+		if !ok {
+			rs, ok := resourceSpans[r]
+			if !ok {
+				rs = result.ResourceSpans().AppendEmpty()
+				g.resources[r].CopyTo(rs.Resource().Attributes())
+				resourceSpans[r] = rs
+			}
+			ss := rs.ScopeSpans().AppendEmpty()
+			g.scopes[s].CopyTo(ss.Scope())
+			spans = ss.Spans()
+			scopeSpans[[2]int{r, s}] = spans
+		}
+		g.trace(spans)
+	}
+
+	return result
+}
+
+// GenerateLogs generates batchSize log records.
+func (g *ProfileGenerator) GenerateLogs(batchSize int) plog.Logs {
+	result := plog.NewLogs()
+	resourceLogs := map[int]plog.ResourceLogs{}
+	scopeLogs := map[[2]int]plog.LogRecordSlice{}
+
+	for i := 0; i < batchSize; i++ {
+		g.advance()
+		r, s := g.resourcePicker.pick(g.rng), g.rng.Intn(len(g.scopes))
+		logRecords, ok := scopeLogs[[2]int{r, s}]
+		if !ok {
+			rl, ok := resourceLogs[r]
+			if !ok {
+				rl = result.ResourceLogs().AppendEmpty()
+				g.resources[r].CopyTo(rl.Resource().Attributes())
+				resourceLogs[r] = rl
+			}
+			sl := rl.ScopeLogs().AppendEmpty()
+			g.scopes[s].CopyTo(sl.Scope())
+			logRecords = sl.LogRecords()
+			scopeLogs[[2]int{r, s}] = logRecords
+		}
+		g.logRecord(logRecords.AppendEmpty())
+	}
+
+	return result
+}
+
+// GenerateMetrics generates batchSize collections of the metrics of the
+// profile.
+func (g *ProfileGenerator) GenerateMetrics(batchSize int) pmetric.Metrics {
+	result := pmetric.NewMetrics()
+	resourceMetrics := map[int]pmetric.ResourceMetrics{}
+	scopeMetrics := map[[2]int]pmetric.MetricSlice{}
+
+	for i := 0; i < batchSize; i++ {
+		g.advance()
+		r, s := g.resourcePicker.pick(g.rng), g.rng.Intn(len(g.scopes))
+		metrics, ok := scopeMetrics[[2]int{r, s}]
+		if !ok {
+			rm, ok := resourceMetrics[r]
+			if !ok {
+				rm = result.ResourceMetrics().AppendEmpty()
+				g.resources[r].CopyTo(rm.Resource().Attributes())
+				resourceMetrics[r] = rm
+			}
+			sm := rm.ScopeMetrics().AppendEmpty()
+			g.scopes[s].CopyTo(sm.Scope())
+			metrics = sm.Metrics()
+			scopeMetrics[[2]int{r, s}] = metrics
+		}
+		for _, metric := range g.metrics {
+			g.metric(metric, metrics.AppendEmpty())
+		}
+	}
+
+	return result
+}
+
+// advance advances the current time by the interval of the time profile.
+func (g *ProfileGenerator) advance() {
+	interval := float64(g.time.Interval)
+	if g.time.Jitter > 0 {
+		interval *= 1 + g.time.Jitter*(2*g.rng.Float64()-1)
+	}
+	if g.time.Period > 0 && g.time.Amplitude > 0 {
+		elapsed := float64(g.CurrentTime()) - float64(g.Start())
+		interval /= 1 + g.time.Amplitude*math.Sin(2*math.Pi*elapsed/float64(g.time.Period))
+	}
+	g.items++
+	if g.time.BurstSize > 0 && g.items%g.time.BurstSize == 0 {
+		interval += float64(g.time.BurstPause)
+	}
+	g.AdvanceTime(time.Duration(interval))
+}
+
+// trace appends the spans of a new trace. The parent of each span is picked
+// randomly among the previous spans, above the maximum depth.
+func (g *ProfileGenerator) trace(spans ptrace.SpanSlice) {
+	g.NextId16Bytes()
+	traceID := g.Id16Bytes()
+
+	count := g.spansPerTrace.pick(g.rng)
+	nodes := make([]spanNode, 0, count)
+	for i := 0; i < count; i++ {
+		g.NextId8Bytes()
+		node := spanNode{id: g.Id8Bytes(), parent: -1}
+		span := spans.AppendEmpty()
+		span.SetTraceID(traceID)
+		span.SetSpanID(node.id)
+
+		if i == 0 {
+			node.start = g.CurrentTime()
+			node.end = node.start + pcommon.Timestamp(time.Millisecond) + pcommon.Timestamp(g.rng.Int63n(int64(500*time.Millisecond)))
+			span.SetKind(ptrace.SpanKindServer)
+		} else {
+			node.parent = g.rng.Intn(len(nodes))
+			for nodes[node.parent].depth >= g.maxDepth && node.parent > 0 {
+				node.parent = nodes[node.parent].parent
+			}
+			parent := nodes[node.parent]
+			node.depth = parent.depth + 1
+			duration := int64(parent.end-parent.start) + 1
+			node.start = parent.start + pcommon.Timestamp(g.rng.Int63n(duration/2+1))
+			node.end = node.start + pcommon.Timestamp(g.rng.Int63n(int64(parent.end-node.start)+1))
+			span.SetParentSpanID(parent.id)
+			span.SetKind(pick(g.TestEntropy, []ptrace.SpanKind{ptrace.SpanKindInternal, ptrace.SpanKindClient}))
+		}
+		nodes = append(nodes, node)
+
+		span.SetName(pick(g.TestEntropy, g.spanNames))
+		span.SetStartTimestamp(node.start)
+		span.SetEndTimestamp(node.end)
+		putAttributes(g.rng, g.spanAttrs, span.Attributes())
+		if g.rng.Float64() < g.errorRate {
+			span.Status().SetCode(ptrace.StatusCodeError)
+			span.Status().SetMessage("Error")
+		}
+
+		for e, n := 0, g.eventCount.pick(g.rng); e < n; e++ {
+			event := span.Events().AppendEmpty()
+			event.SetName(pick(g.TestEntropy, g.eventNames))
+			event.SetTimestamp(node.start + pcommon.Timestamp(g.rng.Int63n(int64(node.end-node.start)+1)))
+			putAttributes(g.rng, g.eventAttrs, event.Attributes())
+		}
+
+		if len(g.linkTargets) > 0 {
+			for l, n := 0, g.linkCount.pick(g.rng); l < n; l++ {
+				target := pick(g.TestEntropy, g.linkTargets)
+				link := span.Links().AppendEmpty()
+				link.SetTraceID(target.traceID)
+				link.SetSpanID(target.spanID)
+				putAttributes(g.rng, g.linkAttrs, link.Attributes())
+			}
+		}
+	}
+
+	// The spans of this trace become targets of the links of the next ones.
+	for _, node := range nodes {
+		ref := spanRef{traceID: traceID, spanID: node.id}
+		if len(g.linkTargets) < maxLinkTargets {
+			g.linkTargets = append(g.linkTargets, ref)
+		} else {
+			g.linkTargets[g.rng.Intn(maxLinkTargets)] = ref
+		}
+	}
+}
+
+func (g *ProfileGenerator) logRecord(log plog.LogRecord) {
+	log.SetTimestamp(g.CurrentTime())
+	log.SetObservedTimestamp(g.CurrentTime())
+	severity := strings.ToUpper(pick(g.TestEntropy, g.severities))
+	log.SetSeverityText(severity)
+	log.SetSeverityNumber(severityNumbers[severity])
+
+	switch g.body.Type {
+	case BodyTypeMap:
+		putAttributes(g.rng, g.bodyFields, log.Body().SetEmptyMap())
+	case BodyTypeJSON:
+		fields := pcommon.NewMap()
+		putAttributes(g.rng, g.bodyFields, fields)
+		body, err := json.Marshal(fields.AsRaw())
+		if err != nil {
+			panic(err)
+		}
+		log.Body().SetStr(string(body))
+	default:
+		log.Body().SetStr(g.faker.LoremIpsumSentence(g.body.Words))
+	}
+
+	putAttributes(g.rng, g.logAttrs, log.Attributes())
+	if g.rng.Float64() < g.traceContext {
+		g.NextId16Bytes()
+		g.NextId8Bytes()
+		log.SetTraceID(g.Id16Bytes())
+		log.SetSpanID(g.Id8Bytes())
+	}
+}
+
+func (g *ProfileGenerator) metric(mg *metricGenerator, metric pmetric.Metric) {
+	metric.SetName(mg.name)
+	metric.SetUnit(mg.unit)
+
+	count := mg.dataPoints.pick(g.rng)
+	switch mg.typ {
+	case MetricTypeSum:
+		sum := metric.SetEmptySum()
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		sum.SetIsMonotonic(true)
+		for i := 0; i < count; i++ {
+			dp := sum.DataPoints().AppendEmpty()
+			g.numberDataPoint(mg, dp)
+			dp.SetIntValue(g.rng.Int63n(1000))
+		}
+	case MetricTypeHistogram:
+		histogram := metric.SetEmptyHistogram()
+		histogram.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		for i := 0; i < count; i++ {
+			dp := histogram.DataPoints().AppendEmpty()
+			dp.SetStartTimestamp(g.PrevTime())
+			dp.SetTimestamp(g.CurrentTime())
+			putAttributes(g.rng, mg.attrs, dp.Attributes())
+			dp.ExplicitBounds().FromRaw(mg.bounds)
+			var total uint64
+			var sum float64
+			counts := make([]uint64, len(mg.bounds)+1)
+			for b := range counts {
+				counts[b] = uint64(g.rng.Intn(100))
+				total += counts[b]
+				if b < len(mg.bounds) {
+					sum += float64(counts[b]) * mg.bounds[b]
+				}
+			}
+			dp.BucketCounts().FromRaw(counts)
+			dp.SetCount(total)
+			dp.SetSum(sum)
+		}
+	default:
+		gauge := metric.SetEmptyGauge()
+		for i := 0; i < count; i++ {
+			dp := gauge.DataPoints().AppendEmpty()
+			g.numberDataPoint(mg, dp)
+			dp.SetDoubleValue(g.rng.Float64() * 100)
+		}
+	}
+}
+
+func (g *ProfileGenerator) numberDataPoint(mg *metricGenerator, dp pmetric.NumberDataPoint) {
+	dp.SetStartTimestamp(g.PrevTime())
+	dp.SetTimestamp(g.CurrentTime())
+	putAttributes(g.rng, mg.attrs, dp.Attributes())
+}
+
+func newAttrGenerators(rng *rand.Rand, profiles []AttributeProfile) []*attrGenerator {
+	var generators []*attrGenerator
+	for _, profile := range profiles {
+		count := orDefault(profile.Count, 1)
+		for i := 0; i < count; i++ {
+			key := profile.Key
+			if count > 1 {
+				key = fmt.Sprintf("%s.%d", key, i)
+			}
+			g := &attrGenerator{
+				key:         key,
+				typ:         orDefault(profile.Type, AttrTypeString),
+				cardinality: profile.Cardinality,
+				length:      orDefault(profile.Length, 16),
+				probability: 1,
+				seed:        hashString(key),
+			}
+			if profile.Probability != nil {
+				g.probability = *profile.Probability
+			}
+			if profile.Distribution == DistributionZipf && g.cardinality > 1 {
+				g.zipf = rand.NewZipf(rng, orDefault(profile.Skew, 1.1), 1, uint64(g.cardinality-1))
+			}
+			generators = append(generators, g)
+		}
+	}
+	return generators
+}
+
+func putAttributes(rng *rand.Rand, generators []*attrGenerator, attrs pcommon.Map) {
+	attrs.EnsureCapacity(attrs.Len() + len(generators))
+	for _, g := range generators {
+		if g.probability < 1 && rng.Float64() >= g.probability {
+			continue
+		}
+		g.put(rng, attrs)
+	}
+}
+
+// put adds a value of the attribute to the map.
+func (g *attrGenerator) put(rng *rand.Rand, attrs pcommon.Map) {
+	if g.typ == AttrTypeBool {
+		attrs.PutBool(g.key, rng.Intn(2) == 0)
+		return
+	}
+
+	// The hash of the index of the value (or a random number for the
+	// attributes without cardinality) seeds the value.
+	var index, h uint64
+	switch {
+	case g.cardinality == 0:
+		h = rng.Uint64()
+		index = h % maxIntValue
+	case g.zipf != nil:
+		index = g.zipf.Uint64()
+		h = splitmix64(g.seed ^ index)
+	default:
+		index = uint64(rng.Intn(g.cardinality))
+		h = splitmix64(g.seed ^ index)
+	}
+
+	switch g.typ {
+	case AttrTypeInt:
+		attrs.PutInt(g.key, int64(index))
+	case AttrTypeDouble:
+		attrs.PutDouble(g.key, float64(h>>11)/(1<<53)*1000)
+	case AttrTypeBytes:
+		attrs.PutEmptyBytes(g.key).FromRaw(randomBytes(h, g.length))
+	default:
+		attrs.PutStr(g.key, string(randomBytes(h, g.length)))
+	}
+}
+
+func newPicker(rng *rand.Rand, n int, distribution string, skew float64) picker {
+	p := picker{n: n}
+	if distribution == DistributionZipf && n > 1 {
+		p.zipf = rand.NewZipf(rng, orDefault(skew, 1.1), 1, uint64(n-1))
+	}
+	return p
+}
+
+func (p picker) pick(rng *rand.Rand) int {
+	if p.zipf != nil {
+		return int(p.zipf.Uint64())
+	}
+	return rng.Intn(p.n)
+}
+
+func (r Range) pick(rng *rand.Rand) int {
+	if r.Max <= r.Min {
+		return r.Min
+	}
+	return r.Min + rng.Intn(r.Max-r.Min+1)
+}
+
+const alphanumeric = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// randomBytes returns length alphanumeric characters derived from the seed.
+func randomBytes(seed uint64, length int) []byte {
+	b := make([]byte, length)
+	for i := range b {
+		seed = splitmix64(seed)
+		b[i] = alphanumeric[seed%uint64(len(alphanumeric))]
+	}
+	return b
+}
+
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// hashString is the FNV-1a hash of a string.
+func hashString(s string) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+func orDefault[T comparable](value, defaultValue T) T {
+	var zero T
+	if value == zero {
+		return defaultValue
+	}
+	return value
+}
+
+func orDefaultRange(r Range, min int) Range {
+	if r.Min == 0 && r.Max == 0 {
+		return Range{Min: min, Max: min}
+	}
+	return r
+}
+
+func orDefaultNames(names []string, name string) []string {
+	if len(names) == 0 {
+		return []string{name}
+	}
+	return names
+}
@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datagen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// generateAll generates a batch of each signal from a profile and returns
+// their protobuf encoding.
+func generateAll(t *testing.T, profile *Profile, seed int64) [][]byte {
+	g := NewProfileGenerator(NewTestEntropy(seed), profile)
+
+	traces, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(g.GenerateTraces(20))
+	require.NoError(t, err)
+	logs, err := (&plog.ProtoMarshaler{}).MarshalLogs(g.GenerateLogs(20))
+	require.NoError(t, err)
+	metrics, err := (&pmetric.ProtoMarshaler{}).MarshalMetrics(g.GenerateMetrics(20))
+	require.NoError(t, err)
+	return [][]byte{traces, logs, metrics}
+}
+
+// TestExampleProfiles checks that the example profiles of the tools are valid
+// and that the generated data only depends on the seed.
+func TestExampleProfiles(t *testing.T) {
+	t.Parallel()
+
+	paths, err := filepath.Glob("../../tools/profiles/*")
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		profile, err := LoadProfile(path)
+		require.NoError(t, err, path)
+
+		require.Equal(t, generateAll(t, profile, 42), generateAll(t, profile, 42), path)
+		require.NotEqual(t, generateAll(t, profile, 42), generateAll(t, profile, 43), path)
+	}
+}
+
+func TestProfileTraces(t *testing.T) {
+	t.Parallel()
+
+	probability := 0.5
+	profile := &Profile{
+		Resources: ResourcesProfile{Count: 3},
+		Traces: TracesProfile{
+			SpansPerTrace: Range{Min: 10, Max: 30},
+			MaxDepth:      4,
+			Attributes: []AttributeProfile{
+				{Key: "attr", Count: 200, Cardinality: 5},
+				{Key: "user.id", Cardinality: 100, Distribution: DistributionZipf},
+				{Key: "optional", Type: AttrTypeInt, Probability: &probability},
+			},
+			Events: EventsProfile{Count: Range{Min: 1, Max: 3}},
+			Links:  LinksProfile{Count: Range{Min: 1, Max: 1}},
+		},
+	}
+	require.NoError(t, profile.Validate())
+
+	traces := NewProfileGenerator(NewTestEntropy(1), profile).GenerateTraces(50)
+	require.LessOrEqual(t, traces.ResourceSpans().Len(), 3)
+
+	spans := map[pcommon.SpanID]ptrace.Span{}
+	traceSpans := map[pcommon.TraceID]int{}
+	userIDs := map[string]bool{}
+	optional := 0
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		sss := traces.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			for k := 0; k < sss.At(j).Spans().Len(); k++ {
+				span := sss.At(j).Spans().At(k)
+				spans[span.SpanID()] = span
+				traceSpans[span.TraceID()]++
+
+				require.GreaterOrEqual(t, span.Attributes().Len(), 201)
+				userID, ok := span.Attributes().Get("user.id")
+				require.True(t, ok)
+				userIDs[userID.Str()] = true
+				if _, ok := span.Attributes().Get("optional"); ok {
+					optional++
+				}
+				require.GreaterOrEqual(t, span.Events().Len(), 1)
+				require.LessOrEqual(t, span.Events().Len(), 3)
+			}
+		}
+	}
+	require.Len(t, traceSpans, 50)
+	for _, count := range traceSpans {
+		require.GreaterOrEqual(t, count, 10)
+		require.LessOrEqual(t, count, 30)
+	}
+	require.LessOrEqual(t, len(userIDs), 100)
+	require.Greater(t, optional, len(spans)/4)
+	require.Less(t, optional, len(spans)*3/4)
+
+	// The parents belong to the same trace and the depth of the trees is
+	// bounded.
+	for _, span := range spans {
+		depth := 0
+		for !span.ParentSpanID().IsEmpty() {
+			parent, ok := spans[span.ParentSpanID()]
+			require.True(t, ok)
+			require.Equal(t, span.TraceID(), parent.TraceID())
+			require.GreaterOrEqual(t, span.StartTimestamp(), parent.StartTimestamp())
+			require.LessOrEqual(t, span.EndTimestamp(), parent.EndTimestamp())
+			span = parent
+			depth++
+		}
+		require.LessOrEqual(t, depth, 4)
+	}
+}
+
+func TestProfileLogBodies(t *testing.T) {
+	t.Parallel()
+
+	profile := &Profile{
+		Logs: LogsProfile{
+			Severities: []string{"warn", "ERROR"},
+			Body: BodyProfile{
+				Type:   BodyTypeJSON,
+				Fields: []AttributeProfile{{Key: "a"}, {Key: "b", Type: AttrTypeDouble}},
+			},
+		},
+	}
+	logs := NewProfileGenerator(NewTestEntropy(1), profile).GenerateLogs(10)
+	records := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	for i := 0; i < records.Len(); i++ {
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(records.At(i).Body().Str()), &body))
+		require.Len(t, body, 2)
+		require.Contains(t, []plog.SeverityNumber{plog.SeverityNumberWarn, plog.SeverityNumberError}, records.At(i).SeverityNumber())
+	}
+
+	profile.Logs.Body.Type = BodyTypeMap
+	logs = NewProfileGenerator(NewTestEntropy(1), profile).GenerateLogs(1)
+	require.Equal(t, 2, logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Map().Len())
+}
+
+func TestLoadProfileErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"unknown_field.yaml": "traces:\n  spans: 3\n",
+		"unknown_field.json": `{"traces": {"spans": 3}}`,
+		"bad_type.yaml":      "logs:\n  attributes:\n    - key: a\n      type: uuid\n",
+		"bad_skew.yaml":      "logs:\n  attributes:\n    - key: a\n      skew: 0.5\n",
+		"bad_severity.yaml":  "logs:\n  severities: [LOUD]\n",
+		"bad_duration.yaml":  "time:\n  interval: often\n",
+		"bad_jitter.json":    `{"time": {"jitter": 2}}`,
+		"missing_name.yaml":  "metrics:\n  metrics:\n    - type: sum\n",
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, err := LoadProfile(path)
+		require.Error(t, err, name)
+	}
+
+	_, err := LoadProfile(filepath.Join(dir, "missing.yaml"))
+	require.Error(t, err)
+}
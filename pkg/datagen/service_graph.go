@@ -0,0 +1,379 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datagen
+
+// Simulation of the calls between the services of a service graph (see
+// ServiceGraphProfile), producing whole traces whose spans are emitted when
+// they end.
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+type (
+	serviceGraph struct {
+		services         []*graphService
+		entrypoints      [][2]int
+		maxDepth         int
+		errorPropagation float64
+		networkLatency   int64
+
+		// Spans ended or to be ended, emitted by end time.
+		pending pendingSpans
+		// Sequence number of the pending spans, orders the spans ending at
+		// the same time.
+		seq uint64
+	}
+
+	graphService struct {
+		name       string
+		resource   pcommon.Map
+		scope      pcommon.InstrumentationScope
+		operations []*graphOperation
+	}
+
+	graphOperation struct {
+		name       string
+		latency    LatencyProfile
+		errorRate  float64
+		attrs      []*attrGenerator
+		eventCount Range
+		eventNames []string
+		eventAttrs []*attrGenerator
+		calls      []graphCall
+	}
+
+	graphCall struct {
+		target      [2]int
+		probability float64
+		count       Range
+		async       bool
+	}
+
+	pendingSpan struct {
+		span    ptrace.Span
+		service int
+		end     pcommon.Timestamp
+		seq     uint64
+	}
+
+	// pendingSpans is a min-heap of spans ordered by end time.
+	pendingSpans []pendingSpan
+)
+
+func newServiceGraph(rng *rand.Rand, profile *ServiceGraphProfile, scopes []pcommon.InstrumentationScope) *serviceGraph {
+	sg := &serviceGraph{
+		maxDepth:         orDefault(profile.MaxDepth, 10),
+		errorPropagation: profile.ErrorPropagation,
+		networkLatency:   int64(orDefault(profile.NetworkLatency, Duration(time.Millisecond))),
+	}
+
+	for s, service := range profile.Services {
+		gs := &graphService{
+			name:     service.Name,
+			resource: pcommon.NewMap(),
+			scope:    scopes[s%len(scopes)],
+		}
+		gs.resource.PutStr("service.name", service.Name)
+		putAttributes(rng, newAttrGenerators(rng, service.Attributes), gs.resource)
+
+		for _, op := range service.Operations {
+			gop := &graphOperation{
+				name:       op.Name,
+				latency:    op.Latency,
+				errorRate:  op.ErrorRate,
+				attrs:      newAttrGenerators(rng, op.Attributes),
+				eventCount: orDefaultRange(op.Events.Count, 0),
+				eventNames: orDefaultNames(op.Events.Names, "event"),
+				eventAttrs: newAttrGenerators(rng, op.Events.Attributes),
+			}
+			for _, call := range op.Calls {
+				gop.calls = append(gop.calls, graphCall{
+					target:      mustResolve(profile, call.Target),
+					probability: probabilityOrOne(call.Probability),
+					count:       orDefaultRange(call.Count, 1),
+					async:       call.Async,
+				})
+			}
+			gs.operations = append(gs.operations, gop)
+		}
+		sg.services = append(sg.services, gs)
+	}
+
+	for _, entrypoint := range profile.Entrypoints {
+		sg.entrypoints = append(sg.entrypoints, mustResolve(profile, entrypoint))
+	}
+	if len(sg.entrypoints) == 0 {
+		sg.entrypoints = [][2]int{{0, 0}}
+	}
+
+	return sg
+}
+
+// GenerateSpans generates the batchSize next spans ending in the simulated
+// service graph, starting new traces as the time advances. The spans of a
+// trace may be split across batches, like an SDK would export them. The
+// profile must have a service graph (see ServiceGraphProfile).
+func (g *ProfileGenerator) GenerateSpans(batchSize int) ptrace.Traces {
+	sg := g.graph
+	if sg == nil {
+		panic("GenerateSpans requires a service graph profile, use GenerateTraces")
+	}
+	batch := newGraphBatch(sg)
+
+	for emitted := 0; emitted < batchSize; emitted++ {
+		for sg.pending.Len() == 0 || sg.pending[0].end > g.CurrentTime() {
+			g.advance()
+			g.startGraphTrace()
+		}
+		batch.emit(heap.Pop(&sg.pending).(pendingSpan))
+	}
+
+	return batch.traces
+}
+
+// generateGraphTraces simulates batchSize whole traces and emits their spans,
+// and the pending spans of the previous traces.
+func (g *ProfileGenerator) generateGraphTraces(batchSize int) ptrace.Traces {
+	sg := g.graph
+	batch := newGraphBatch(sg)
+
+	for i := 0; i < batchSize; i++ {
+		g.advance()
+		g.startGraphTrace()
+	}
+	for sg.pending.Len() > 0 {
+		batch.emit(heap.Pop(&sg.pending).(pendingSpan))
+	}
+
+	return batch.traces
+}
+
+// startGraphTrace simulates a trace starting at the current time from one of
+// the entrypoints, its spans are pending until they are emitted.
+func (g *ProfileGenerator) startGraphTrace() {
+	g.NextId16Bytes()
+	entrypoint := pick(g.TestEntropy, g.graph.entrypoints)
+	g.serve(g.Id16Bytes(), pcommon.NewSpanIDEmpty(), entrypoint, ptrace.SpanKindServer, g.CurrentTime(), 0, nil)
+}
+
+// graphBatch groups the emitted spans by service.
+type graphBatch struct {
+	graph        *serviceGraph
+	traces       ptrace.Traces
+	serviceSpans map[int]ptrace.SpanSlice
+}
+
+func newGraphBatch(sg *serviceGraph) *graphBatch {
+	return &graphBatch{
+		graph:        sg,
+		traces:       ptrace.NewTraces(),
+		serviceSpans: map[int]ptrace.SpanSlice{},
+	}
+}
+
+func (b *graphBatch) emit(ps pendingSpan) {
+	spans, ok := b.serviceSpans[ps.service]
+	if !ok {
+		service := b.graph.services[ps.service]
+		rs := b.traces.ResourceSpans().AppendEmpty()
+		service.resource.CopyTo(rs.Resource().Attributes())
+		ss := rs.ScopeSpans().AppendEmpty()
+		service.scope.CopyTo(ss.Scope())
+		spans = ss.Spans()
+		b.serviceSpans[ps.service] = spans
+	}
+	ps.span.MoveTo(spans.AppendEmpty())
+}
+
+// serve simulates an operation served by a service from the start time, and
+// returns its end time and whether it failed. The operation spends half of
+// its own latency before its calls and the other half after them.
+func (g *ProfileGenerator) serve(traceID pcommon.TraceID, parentID pcommon.SpanID, target [2]int, kind ptrace.SpanKind, start pcommon.Timestamp, depth int, link *spanRef) (pcommon.Timestamp, bool) {
+	sg := g.graph
+	op := sg.services[target[0]].operations[target[1]]
+
+	g.NextId8Bytes()
+	spanID := g.Id8Bytes()
+	span := ptrace.NewSpan()
+	span.SetTraceID(traceID)
+	span.SetSpanID(spanID)
+	span.SetParentSpanID(parentID)
+	span.SetName(op.name)
+	span.SetKind(kind)
+	span.SetStartTimestamp(start)
+	putAttributes(g.rng, g.spanAttrs, span.Attributes())
+	putAttributes(g.rng, op.attrs, span.Attributes())
+	if link != nil {
+		l := span.Links().AppendEmpty()
+		l.SetTraceID(link.traceID)
+		l.SetSpanID(link.spanID)
+	}
+
+	latency := g.latency(op.latency)
+	now := start + pcommon.Timestamp(latency/2)
+	failed := false
+	if depth < sg.maxDepth {
+		for _, call := range op.calls {
+			if call.probability < 1 && g.rng.Float64() >= call.probability {
+				continue
+			}
+			for i, n := 0, call.count.pick(g.rng); i < n; i++ {
+				end, callFailed := g.call(traceID, spanID, target[0], call, now, depth)
+				now = end
+				if callFailed && g.rng.Float64() < sg.errorPropagation {
+					failed = true
+				}
+			}
+		}
+	}
+	end := now + pcommon.Timestamp(latency-latency/2)
+	if g.rng.Float64() < op.errorRate {
+		failed = true
+	}
+
+	span.SetEndTimestamp(end)
+	for i, n := 0, op.eventCount.pick(g.rng); i < n; i++ {
+		event := span.Events().AppendEmpty()
+		event.SetName(pick(g.TestEntropy, op.eventNames))
+		event.SetTimestamp(start + pcommon.Timestamp(g.rng.Int63n(int64(end-start)+1)))
+		putAttributes(g.rng, op.eventAttrs, event.Attributes())
+	}
+	if failed {
+		setError(span, end, fmt.Sprintf("%s failed", op.name))
+	}
+	g.pushSpan(span, target[0], end)
+
+	return end, failed
+}
+
+// call simulates a call of the caller service to the target of the call,
+// and returns the end time of the call and whether it failed. The failures of
+// the asynchronous calls are not reported to the caller.
+func (g *ProfileGenerator) call(traceID pcommon.TraceID, parentID pcommon.SpanID, caller int, call graphCall, start pcommon.Timestamp, depth int) (pcommon.Timestamp, bool) {
+	sg := g.graph
+	callee := sg.services[call.target[0]]
+
+	g.NextId8Bytes()
+	spanID := g.Id8Bytes()
+	span := ptrace.NewSpan()
+	span.SetTraceID(traceID)
+	span.SetSpanID(spanID)
+	span.SetParentSpanID(parentID)
+	span.SetName(callee.operations[call.target[1]].name)
+	span.SetStartTimestamp(start)
+	span.Attributes().PutStr("peer.service", callee.name)
+
+	network := pcommon.Timestamp(g.rng.Int63n(2*sg.networkLatency + 1))
+	var end pcommon.Timestamp
+	failed := false
+	if call.async {
+		span.SetKind(ptrace.SpanKindProducer)
+		end = start + network
+		g.NextId16Bytes()
+		link := &spanRef{traceID: traceID, spanID: spanID}
+		g.serve(g.Id16Bytes(), pcommon.NewSpanIDEmpty(), call.target, ptrace.SpanKindConsumer, end+network, depth+1, link)
+	} else {
+		span.SetKind(ptrace.SpanKindClient)
+		calleeEnd, calleeFailed := g.serve(traceID, spanID, call.target, ptrace.SpanKindServer, start+network/2, depth+1, nil)
+		end = calleeEnd + network - network/2
+		if calleeFailed {
+			failed = true
+			span.Status().SetCode(ptrace.StatusCodeError)
+		}
+	}
+
+	span.SetEndTimestamp(end)
+	g.pushSpan(span, caller, end)
+	return end, failed
+}
+
+// latency returns a latency (in nanoseconds) drawn from the distribution.
+func (g *ProfileGenerator) latency(profile LatencyProfile) int64 {
+	mean := float64(orDefault(profile.Mean, Duration(10*time.Millisecond)))
+	var latency float64
+	switch profile.Distribution {
+	case LatencyUniform:
+		latency = float64(profile.Min) + g.rng.Float64()*float64(profile.Max-profile.Min)
+	case LatencyLognormal:
+		latency = mean * math.Exp(orDefault(profile.Sigma, 0.5)*g.rng.NormFloat64())
+	default:
+		latency = mean * g.rng.ExpFloat64()
+	}
+	latency = math.Max(latency, float64(profile.Min))
+	if profile.Max > 0 {
+		latency = math.Min(latency, float64(profile.Max))
+	}
+	return int64(latency)
+}
+
+func (g *ProfileGenerator) pushSpan(span ptrace.Span, service int, end pcommon.Timestamp) {
+	g.graph.seq++
+	heap.Push(&g.graph.pending, pendingSpan{span: span, service: service, end: end, seq: g.graph.seq})
+}
+
+// setError sets the error status of a span and adds an exception event.
+func setError(span ptrace.Span, timestamp pcommon.Timestamp, message string) {
+	span.Status().SetCode(ptrace.StatusCodeError)
+	span.Status().SetMessage(message)
+	event := span.Events().AppendEmpty()
+	event.SetName("exception")
+	event.SetTimestamp(timestamp)
+	event.Attributes().PutStr("exception.type", "Error")
+	event.Attributes().PutStr("exception.message", message)
+}
+
+func mustResolve(profile *ServiceGraphProfile, ref string) [2]int {
+	target, err := profile.resolve(ref)
+	if err != nil {
+		panic(fmt.Errorf("invalid service graph (see Profile.Validate): %w", err))
+	}
+	return target
+}
+
+func probabilityOrOne(probability *float64) float64 {
+	if probability == nil {
+		return 1
+	}
+	return *probability
+}
+
+func (p pendingSpans) Len() int { return len(p) }
+
+func (p pendingSpans) Less(i, j int) bool {
+	if p[i].end != p[j].end {
+		return p[i].end < p[j].end
+	}
+	return p[i].seq < p[j].seq
+}
+
+func (p pendingSpans) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+
+func (p *pendingSpans) Push(x any) { *p = append(*p, x.(pendingSpan)) }
+
+func (p *pendingSpans) Pop() any {
+	old := *p
+	n := len(old)
+	x := old[n-1]
+	*p = old[:n-1]
+	return x
+}
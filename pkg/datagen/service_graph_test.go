@@ -0,0 +1,195 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datagen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+type emittedSpan struct {
+	ptrace.Span
+	service string
+	index   int
+}
+
+// TestServiceGraph checks the consistency of the traces simulated from a
+// service graph: a -> b (x2) -> c (async, new linked trace), b always fails
+// and its failures are propagated to a.
+func TestServiceGraph(t *testing.T) {
+	t.Parallel()
+
+	profile := &Profile{
+		Time: TimeProfile{Interval: Duration(100 * time.Millisecond)},
+		Traces: TracesProfile{
+			ServiceGraph: &ServiceGraphProfile{
+				ErrorPropagation: 1,
+				Services: []ServiceProfile{
+					{Name: "a", Operations: []OperationProfile{{Name: "A", Calls: []CallProfile{{Target: "b/B", Count: Range{Min: 2, Max: 2}}}}}},
+					{Name: "b", Operations: []OperationProfile{{Name: "B", ErrorRate: 1, Calls: []CallProfile{{Target: "c", Async: true}}}}},
+					{Name: "c", Operations: []OperationProfile{{Name: "C", Latency: LatencyProfile{Distribution: LatencyUniform, Min: 10, Max: 20}}}},
+				},
+			},
+		},
+	}
+	require.NoError(t, profile.Validate())
+
+	g := NewProfileGenerator(NewTestEntropy(1), profile)
+	var spans []emittedSpan
+	byID := map[pcommon.SpanID]emittedSpan{}
+	for batch := 0; batch < 5; batch++ {
+		traces := g.GenerateSpans(50)
+		require.Equal(t, 50, traces.SpanCount())
+		for i := 0; i < traces.ResourceSpans().Len(); i++ {
+			rs := traces.ResourceSpans().At(i)
+			service, ok := rs.Resource().Attributes().Get("service.name")
+			require.True(t, ok)
+			ss := rs.ScopeSpans().At(0).Spans()
+			for j := 0; j < ss.Len(); j++ {
+				span := emittedSpan{Span: ss.At(j), service: service.Str(), index: len(spans)}
+				spans = append(spans, span)
+				byID[span.SpanID()] = span
+			}
+		}
+	}
+
+	// The spans are grouped by service within a batch, so the emission order
+	// (children first) is only checked across batches.
+	withParent, parentEmitted := 0, 0
+	for _, span := range spans {
+		switch span.Kind() {
+		case ptrace.SpanKindServer:
+			if span.service == "a" {
+				require.True(t, span.ParentSpanID().IsEmpty())
+			} else {
+				require.Equal(t, "b", span.service)
+			}
+			// b always fails, and its failure is propagated.
+			require.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+		case ptrace.SpanKindClient:
+			require.Equal(t, "a", span.service)
+			require.Equal(t, "B", span.Name())
+		case ptrace.SpanKindProducer:
+			require.Equal(t, "b", span.service)
+		case ptrace.SpanKindConsumer:
+			require.Equal(t, "c", span.service)
+			require.True(t, span.ParentSpanID().IsEmpty())
+			require.Equal(t, 1, span.Links().Len())
+			if producer, ok := byID[span.Links().At(0).SpanID()]; ok {
+				require.Equal(t, ptrace.SpanKindProducer, producer.Kind())
+				require.NotEqual(t, producer.TraceID(), span.TraceID())
+				require.Less(t, producer.EndTimestamp(), span.StartTimestamp())
+			}
+		default:
+			t.Fatalf("unexpected span kind %v", span.Kind())
+		}
+
+		if span.ParentSpanID().IsEmpty() {
+			continue
+		}
+		withParent++
+		parent, ok := byID[span.ParentSpanID()]
+		if !ok {
+			continue
+		}
+		parentEmitted++
+		require.Equal(t, span.TraceID(), parent.TraceID())
+		require.GreaterOrEqual(t, span.StartTimestamp(), parent.StartTimestamp())
+		require.LessOrEqual(t, span.EndTimestamp(), parent.EndTimestamp())
+		require.LessOrEqual(t, span.index/50, parent.index/50)
+		if span.Kind() == ptrace.SpanKindServer {
+			require.Equal(t, ptrace.SpanKindClient, parent.Kind())
+		}
+	}
+	// Only the parents of the last spans may not be emitted yet.
+	require.Greater(t, parentEmitted, withParent*9/10)
+}
+
+// TestServiceGraphWholeTraces checks that GenerateTraces emits whole traces,
+// with the pending spans of the traces started by GenerateSpans.
+func TestServiceGraphWholeTraces(t *testing.T) {
+	t.Parallel()
+
+	profile := &Profile{
+		Traces: TracesProfile{
+			ServiceGraph: &ServiceGraphProfile{
+				Services: []ServiceProfile{
+					{Name: "a", Operations: []OperationProfile{{Name: "A", Calls: []CallProfile{{Target: "b", Count: Range{Min: 2, Max: 2}}}}}},
+					{Name: "b", Operations: []OperationProfile{{Name: "B", Calls: []CallProfile{{Target: "c", Async: true}}}}},
+					{Name: "c", Operations: []OperationProfile{{Name: "C"}}},
+				},
+			},
+		},
+	}
+	require.NoError(t, profile.Validate())
+
+	g := NewProfileGenerator(NewTestEntropy(1), profile)
+	// A trace has 7 spans (a, 2 x (client, b, producer)) and starts 2 linked
+	// traces of 1 span (c).
+	traces := g.GenerateTraces(3)
+	require.Equal(t, 3*9, traces.SpanCount())
+
+	g.GenerateSpans(5)
+	traces = g.GenerateTraces(2)
+	spans := map[pcommon.SpanID]ptrace.Span{}
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		ss := traces.ResourceSpans().At(i).ScopeSpans().At(0).Spans()
+		for j := 0; j < ss.Len(); j++ {
+			spans[ss.At(j).SpanID()] = ss.At(j)
+		}
+	}
+	require.Greater(t, len(spans), 2*9)
+	for _, span := range spans {
+		if !span.ParentSpanID().IsEmpty() {
+			require.Contains(t, spans, span.ParentSpanID())
+		}
+	}
+
+	require.Panics(t, func() {
+		NewProfileGenerator(NewTestEntropy(1), &Profile{}).GenerateSpans(1)
+	})
+}
+
+func TestServiceGraphValidate(t *testing.T) {
+	t.Parallel()
+
+	graph := func(calls ...CallProfile) *Profile {
+		return &Profile{Traces: TracesProfile{ServiceGraph: &ServiceGraphProfile{
+			Services: []ServiceProfile{
+				{Name: "a", Operations: []OperationProfile{{Name: "A", Calls: calls}}},
+				{Name: "b", Operations: []OperationProfile{{Name: "B"}}},
+			},
+		}}}
+	}
+	require.NoError(t, graph(CallProfile{Target: "b"}, CallProfile{Target: "b/B"}, CallProfile{Target: "a"}).Validate())
+	require.Error(t, graph(CallProfile{Target: "c"}).Validate())
+	require.Error(t, graph(CallProfile{Target: "b/C"}).Validate())
+
+	profile := graph()
+	profile.Traces.ServiceGraph.Entrypoints = []string{"b/X"}
+	require.Error(t, profile.Validate())
+
+	profile = graph()
+	profile.Traces.ServiceGraph.Services[1].Name = "a"
+	require.Error(t, profile.Validate())
+
+	profile = graph()
+	profile.Traces.ServiceGraph.Services[1].Operations[0].Latency.Distribution = LatencyUniform
+	require.Error(t, profile.Validate())
+}
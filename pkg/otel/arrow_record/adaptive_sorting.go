@@ -0,0 +1,245 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+// Periodic selection of the sort strategy of the traces from the size of a
+// sample of the batches encoded with candidate strategies (see
+// config.WithAdaptiveSorting).
+
+import (
+	"github.com/klauspost/compress/zstd"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	cfg "github.com/f5/otel-arrow-adapter/pkg/config"
+	tracesarrow "github.com/f5/otel-arrow-adapter/pkg/otel/traces/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/werror"
+)
+
+// adaptiveSortingConfirmations is the number of consecutive evaluations a
+// candidate strategy must win before replacing the current one.
+const adaptiveSortingConfirmations = 2
+
+// adaptiveSorting selects the sort strategy of the traces. Every period
+// batches, a sample of the batch is encoded with the current strategy, with
+// each strategy differing from it by a single sort order and with the
+// combination of the best sort orders. The most compact candidate becomes the
+// challenger of the current strategy, and replaces it once it has been
+// smaller by at least minGain on adaptiveSortingConfirmations consecutive
+// evaluations.
+type adaptiveSorting struct {
+	period     uint64
+	sampleSize int
+	minGain    float64
+
+	// Options of the producers encoding the samples, and compression of
+	// their payloads (a single encoder is much cheaper than the IPC
+	// compression of each buffer).
+	trialOptions []cfg.Option
+	encoder      *zstd.Encoder
+	compressed   []byte
+
+	batches    uint64
+	current    tracesarrow.SortStrategy
+	challenger tracesarrow.SortStrategy
+	wins       int
+}
+
+func newAdaptiveSorting(conf *cfg.Config) *adaptiveSorting {
+	sampleSize := conf.AdaptiveSortingSampleSize
+	if sampleSize <= 0 {
+		sampleSize = cfg.DefaultAdaptiveSortingSampleSize
+	}
+	initIndexSize, limitIndexSize := conf.InitIndexSize, conf.LimitIndexSize
+
+	return &adaptiveSorting{
+		period:     conf.AdaptiveSortingPeriod,
+		sampleSize: sampleSize,
+		minGain:    conf.AdaptiveSortingMinGain,
+		trialOptions: []cfg.Option{
+			cfg.WithAllocator(conf.Pool),
+			cfg.WithNoZstd(),
+			func(c *cfg.Config) {
+				c.InitIndexSize = initIndexSize
+				c.LimitIndexSize = limitIndexSize
+			},
+		},
+		current: tracesarrow.DefaultSortStrategy(),
+	}
+}
+
+// close releases the zstd encoder. A nil adaptiveSorting is valid.
+func (s *adaptiveSorting) close() error {
+	if s == nil || s.encoder == nil {
+		return nil
+	}
+	return s.encoder.Close()
+}
+
+// due counts a new batch of traces and returns true if the strategy must be
+// evaluated on it. A nil adaptiveSorting is never due.
+func (s *adaptiveSorting) due() bool {
+	if s == nil {
+		return false
+	}
+	s.batches++
+	return (s.batches-1)%s.period == 0
+}
+
+// adaptSortStrategy evaluates the candidate sort strategies on a sample of
+// the given traces, and switches the traces builder to the challenger once
+// confirmed.
+func (p *Producer) adaptSortStrategy(traces ptrace.Traces) error {
+	s := p.adaptiveSorting
+	sample := sampleTraces(traces, s.sampleSize)
+	if sample.SpanCount() == 0 {
+		return nil
+	}
+
+	// Strategies in the order of their evaluation, and their size.
+	var strategies []tracesarrow.SortStrategy
+	sizes := make(map[tracesarrow.SortStrategy]int)
+	measure := func(strategy tracesarrow.SortStrategy) error {
+		if _, ok := sizes[strategy]; ok {
+			return nil
+		}
+		size, err := s.trialSize(sample, strategy)
+		if err != nil {
+			return werror.Wrap(err)
+		}
+		strategies = append(strategies, strategy)
+		sizes[strategy] = size
+		return nil
+	}
+
+	if err := measure(s.current); err != nil {
+		return werror.Wrap(err)
+	}
+	// Combination of the best sort order of each dimension, measured
+	// independently.
+	combined := s.current
+	spanSize, eventSize, linkSize, attrsSize := sizes[s.current], sizes[s.current], sizes[s.current], sizes[s.current]
+	for _, strategy := range s.current.Neighbors() {
+		if err := measure(strategy); err != nil {
+			return werror.Wrap(err)
+		}
+		size := sizes[strategy]
+		switch {
+		case strategy.Span != s.current.Span && size < spanSize:
+			spanSize, combined.Span = size, strategy.Span
+		case strategy.Event != s.current.Event && size < eventSize:
+			eventSize, combined.Event = size, strategy.Event
+		case strategy.Link != s.current.Link && size < linkSize:
+			linkSize, combined.Link = size, strategy.Link
+		case strategy.Attrs != s.current.Attrs && size < attrsSize:
+			attrsSize, combined.Attrs = size, strategy.Attrs
+		}
+	}
+	if err := measure(combined); err != nil {
+		return werror.Wrap(err)
+	}
+
+	best := s.current
+	for _, strategy := range strategies {
+		if sizes[strategy] < sizes[best] {
+			best = strategy
+		}
+	}
+	p.stats.TracesSortEvaluations++
+
+	if best == s.current || float64(sizes[best]) > float64(sizes[s.current])*(1-s.minGain) {
+		s.wins = 0
+		return nil
+	}
+	if best == s.challenger {
+		s.wins++
+	} else {
+		s.challenger = best
+		s.wins = 1
+	}
+	if s.wins < adaptiveSortingConfirmations {
+		return nil
+	}
+
+	if err := p.tracesBuilder.SetSortStrategy(best); err != nil {
+		return werror.Wrap(err)
+	}
+	s.current = best
+	s.wins = 0
+	p.stats.TracesSortStrategy = best.String()
+	p.stats.TracesSortStrategyChanges++
+	return nil
+}
+
+// trialSize returns the size of the zstd compressed payloads of the sample
+// encoded by a new producer with the given strategy.
+func (s *adaptiveSorting) trialSize(sample ptrace.Traces, strategy tracesarrow.SortStrategy) (size int, err error) {
+	producer := NewProducerWithOptions(s.trialOptions...)
+	defer func() {
+		if closeErr := producer.Close(); closeErr != nil && err == nil {
+			err = werror.Wrap(closeErr)
+		}
+	}()
+
+	if s.encoder == nil {
+		if s.encoder, err = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1)); err != nil {
+			return 0, werror.Wrap(err)
+		}
+	}
+	if err = producer.tracesBuilder.SetSortStrategy(strategy); err != nil {
+		return 0, werror.Wrap(err)
+	}
+	bar, err := producer.BatchArrowRecordsFromTraces(sample)
+	if err != nil {
+		return 0, werror.Wrap(err)
+	}
+	for _, payload := range bar.ArrowPayloads {
+		s.compressed = s.encoder.EncodeAll(payload.Record, s.compressed[:0])
+		size += len(s.compressed)
+	}
+	return size, nil
+}
+
+// sampleTraces returns the first spans of each scope of the traces, in
+// proportion to their number of spans, about size spans in total (rounded up
+// for each scope). The contiguous spans of the traces are kept together.
+func sampleTraces(traces ptrace.Traces, size int) ptrace.Traces {
+	total := traces.SpanCount()
+	if total <= size {
+		return traces
+	}
+
+	sample := ptrace.NewTraces()
+	rss := traces.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		sampleRs := sample.ResourceSpans().AppendEmpty()
+		rs.Resource().CopyTo(sampleRs.Resource())
+		sampleRs.SetSchemaUrl(rs.SchemaUrl())
+
+		sss := rs.ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			ss := sss.At(j)
+			sampleSs := sampleRs.ScopeSpans().AppendEmpty()
+			ss.Scope().CopyTo(sampleSs.Scope())
+			sampleSs.SetSchemaUrl(ss.SchemaUrl())
+
+			n := (ss.Spans().Len()*size + total - 1) / total
+			for k := 0; k < n; k++ {
+				ss.Spans().At(k).CopyTo(sampleSs.Spans().AppendEmpty())
+			}
+		}
+	}
+	return sample
+}
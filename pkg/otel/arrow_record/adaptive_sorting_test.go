@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/stretchr/testify/require"
+
+	"github.com/f5/otel-arrow-adapter/pkg/config"
+	tracesarrow "github.com/f5/otel-arrow-adapter/pkg/otel/traces/arrow"
+)
+
+// TestSortStrategiesRoundTrip checks that the consumer decodes the traces
+// whatever the sort strategy, including when it changes between two batches
+// of the same stream.
+func TestSortStrategiesRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+
+	strategies := append([]tracesarrow.SortStrategy{tracesarrow.DefaultSortStrategy()}, tracesarrow.DefaultSortStrategy().Neighbors()...)
+	strategies = append(strategies, tracesarrow.SortStrategy{
+		Span:  "StartTimestampTraceIdName",
+		Event: "NameTimeUnixNano",
+		Link:  "ParentId",
+		Attrs: "ParentId",
+	})
+
+	producer := NewProducerWithOptions(config.WithAllocator(pool))
+	consumer := NewConsumer()
+	gen := newResetTestTraces()
+	for _, strategy := range strategies {
+		require.NoError(t, producer.tracesBuilder.SetSortStrategy(strategy))
+		traces := gen.Generate(10, time.Minute)
+		batch, err := producer.BatchArrowRecordsFromTraces(traces)
+		require.NoError(t, err, strategy.String())
+		checkRoundTrip(t, consumer, batch, traces)
+	}
+
+	err := producer.tracesBuilder.SetSortStrategy(tracesarrow.SortStrategy{Span: "Random"})
+	require.True(t, errors.Is(err, tracesarrow.ErrUnknownSortOrder))
+
+	require.NoError(t, producer.Close())
+	require.NoError(t, consumer.Close())
+}
+
+// TestAdaptiveSorting checks that the strategy is evaluated every period
+// batches, that the consumer follows the changes of strategy, and that a
+// challenger must win two consecutive evaluations.
+func TestAdaptiveSorting(t *testing.T) {
+	t.Parallel()
+
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+
+	producer := NewProducerWithOptions(config.WithAllocator(pool), config.WithAdaptiveSorting(2, 20, 0))
+	consumer := NewConsumer()
+	require.Equal(t, tracesarrow.DefaultSortStrategy().String(), producer.stats.TracesSortStrategy)
+
+	// The same batch is produced again and again, the winner of the first
+	// evaluation (batch 0) is confirmed by the second one (batch 2) and used
+	// from this batch.
+	traces := newResetTestTraces().Generate(10, time.Minute)
+	var strategies []string
+	for i := 0; i < 4; i++ {
+		batch, err := producer.BatchArrowRecordsFromTraces(traces)
+		require.NoError(t, err)
+		checkRoundTrip(t, consumer, batch, traces)
+		strategies = append(strategies, producer.stats.TracesSortStrategy)
+	}
+
+	stats := producer.GetAndResetStats()
+	require.Equal(t, uint64(2), stats.TracesSortEvaluations)
+	require.GreaterOrEqual(t, stats.TracesSortStrategyChanges, uint64(1))
+	require.Equal(t, strategies[0], strategies[1])
+	require.NotEqual(t, strategies[1], strategies[2])
+	require.Equal(t, producer.adaptiveSorting.current.String(), stats.TracesSortStrategy)
+	require.Equal(t, stats.TracesSortStrategy, producer.GetAndResetStats().TracesSortStrategy)
+
+	require.NoError(t, producer.Close())
+	require.NoError(t, consumer.Close())
+}
+
+func TestAdaptiveSortingMinGain(t *testing.T) {
+	t.Parallel()
+
+	// No strategy can be 100% smaller than the current one.
+	producer := NewProducerWithOptions(config.WithAdaptiveSorting(1, 20, 1))
+	traces := newResetTestTraces().Generate(10, time.Minute)
+	for i := 0; i < 2; i++ {
+		_, err := producer.BatchArrowRecordsFromTraces(traces)
+		require.NoError(t, err)
+	}
+
+	stats := producer.GetAndResetStats()
+	require.Equal(t, uint64(2), stats.TracesSortEvaluations)
+	require.Equal(t, uint64(0), stats.TracesSortStrategyChanges)
+	require.Equal(t, tracesarrow.DefaultSortStrategy().String(), stats.TracesSortStrategy)
+	require.NoError(t, producer.Close())
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+// Infrastructure used to recycle the buffers referenced by the ArrowPayloads
+// produced by the Producer.
+
+import (
+	"sync"
+)
+
+// maxPooledBufferSize is the capacity above which a buffer is not returned to
+// the pool, so a few exceptionally large batches can't retain a lot of memory.
+const maxPooledBufferSize = 64 << 20
+
+type (
+	// bufferPool is a pool of byte slices used to store the IPC encoded
+	// records. It is safe for concurrent use.
+	bufferPool struct {
+		pool sync.Pool
+	}
+
+	// payloadBuffer is the io.Writer of an IPC writer. The bytes written for a
+	// record are detached from the payloadBuffer after each write so they can
+	// be referenced by an ArrowPayload without being copied.
+	payloadBuffer struct {
+		pool *bufferPool
+		buf  []byte
+
+		// Size of the last detached buffer, used to size the new buffers
+		// when the pool is empty.
+		sizeHint int
+	}
+)
+
+// get returns an empty buffer from the pool (or nil if the pool is empty).
+func (p *bufferPool) get() []byte {
+	if buf, ok := p.pool.Get().(*[]byte); ok {
+		return (*buf)[:0]
+	}
+	return nil
+}
+
+// put returns a buffer to the pool.
+func (p *bufferPool) put(buf []byte) {
+	if cap(buf) == 0 || cap(buf) > maxPooledBufferSize {
+		return
+	}
+	p.pool.Put(&buf)
+}
+
+func newPayloadBuffer(pool *bufferPool) *payloadBuffer {
+	return &payloadBuffer{pool: pool}
+}
+
+// Write appends the given bytes to the current buffer.
+func (b *payloadBuffer) Write(p []byte) (int, error) {
+	if b.buf == nil {
+		b.buf = b.pool.get()
+		if b.buf == nil {
+			b.buf = make([]byte, 0, b.sizeHint)
+		}
+	}
+	b.buf = append(b.buf, p...)
+	return len(p), nil
+}
+
+// detach returns the bytes written since the previous call to detach. The
+// ownership of the returned slice is transferred to the caller.
+func (b *payloadBuffer) detach() []byte {
+	buf := b.buf
+	b.buf = nil
+	b.sizeHint = len(buf)
+	return buf
+}
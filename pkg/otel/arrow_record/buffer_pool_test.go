@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/stretchr/testify/require"
+
+	"github.com/f5/otel-arrow-adapter/pkg/config"
+)
+
+func TestPayloadBuffer(t *testing.T) {
+	t.Parallel()
+
+	pool := &bufferPool{}
+	pb := newPayloadBuffer(pool)
+
+	_, err := pb.Write([]byte("hello "))
+	require.NoError(t, err)
+	_, err = pb.Write([]byte("world"))
+	require.NoError(t, err)
+
+	buf := pb.detach()
+	require.Equal(t, []byte("hello world"), buf)
+
+	// The detached buffer is no longer referenced by the payload buffer.
+	_, err = pb.Write([]byte("!"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello world"), buf)
+	require.Equal(t, []byte("!"), pb.detach())
+
+	pool.put(buf)
+	pool.put(make([]byte, 0, maxPooledBufferSize+1)) // too large, not pooled
+}
+
+// TestProducerReleaseBatch checks that the batches produced after the release
+// of the previous ones (i.e. with recycled buffers) are still valid.
+func TestProducerReleaseBatch(t *testing.T) {
+	t.Parallel()
+
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+
+	producer := NewProducerWithOptions(config.WithAllocator(pool))
+	defer func() {
+		require.NoError(t, producer.Close())
+	}()
+
+	tg := newResetTestTraces()
+	consumer := NewConsumer()
+
+	for i := 0; i < 5; i++ {
+		traces := tg.Generate(100, time.Minute)
+
+		batch, err := producer.BatchArrowRecordsFromTraces(traces)
+		require.NoError(t, err)
+		checkRoundTrip(t, consumer, batch, traces)
+
+		producer.ReleaseBatch(batch)
+		for _, payload := range batch.ArrowPayloads {
+			require.Nil(t, payload.Record)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+// Compression of the records of the payloads with a zstd dictionary trained
+// on the first payloads of a stream (see config.WithCompressionDictionary).
+
+import (
+	"errors"
+
+	"github.com/klauspost/compress/zstd"
+
+	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	"github.com/f5/otel-arrow-adapter/pkg/werror"
+	"github.com/f5/otel-arrow-adapter/pkg/zstddict"
+)
+
+var (
+	// ErrInvalidCompressionDictionary is returned for a dictionary with the
+	// id 0, reserved for the records compressed without dictionary.
+	ErrInvalidCompressionDictionary = errors.New("invalid compression dictionary id 0")
+	// ErrUnknownCompressionDictionary is returned for a record compressed
+	// with a dictionary not received (or already rotated).
+	ErrUnknownCompressionDictionary = errors.New("unknown compression dictionary")
+)
+
+type (
+	// dictionaryCompressor compresses the records produced by a Producer
+	// with the current dictionary of the stream. It collects the samples of
+	// the next dictionary when there is no dictionary yet or when a rotation
+	// is due.
+	dictionaryCompressor struct {
+		maxSamples int
+		dictSize   int
+		// Number of batches after which a new dictionary is trained (0
+		// means never).
+		rotation uint64
+
+		samples [][]byte
+		// Current dictionary (nil before the first training).
+		dict    *colarspb.CompressionDictionary
+		encoder *zstd.Encoder
+		// Number of batches compressed with the current dictionary.
+		batches uint64
+	}
+
+	// dictionaryDecompressor decompresses the records of the payloads with
+	// the dictionaries received on a stream.
+	dictionaryDecompressor struct {
+		// Decoders of the current and the previous dictionaries, the
+		// payloads of the batch shipping a new dictionary may still be
+		// compressed with the previous one.
+		decoders map[uint32]*zstd.Decoder
+		maxSize  uint64
+		// Buffer of the decompressed records, reused from one record to
+		// the next.
+		buf []byte
+	}
+)
+
+func newDictionaryCompressor(samples, size int, rotation uint64) *dictionaryCompressor {
+	return &dictionaryCompressor{
+		maxSamples: samples,
+		dictSize:   size,
+		rotation:   rotation,
+	}
+}
+
+// compress compresses the records of the payloads of a batch in place and
+// returns the dictionary to ship with the batch, if a new one has been
+// trained. The uncompressed records are returned to the pool of buffers.
+func (c *dictionaryCompressor) compress(payloads []*colarspb.ArrowPayload, buffers *bufferPool) (*colarspb.CompressionDictionary, error) {
+	if c.dict == nil || (c.rotation > 0 && c.batches >= c.rotation) {
+		for _, payload := range payloads {
+			if len(c.samples) < c.maxSamples {
+				c.samples = append(c.samples, append([]byte(nil), payload.Record...))
+			}
+		}
+	}
+
+	if c.encoder != nil {
+		for _, payload := range payloads {
+			record := c.encoder.EncodeAll(payload.Record, buffers.get())
+			buffers.put(payload.Record)
+			payload.Record = record
+			payload.CompressionDictionaryId = c.dict.Id
+		}
+		c.batches++
+	}
+
+	if len(c.samples) < c.maxSamples {
+		return nil, nil
+	}
+
+	// The next batches are compressed with a new dictionary.
+	id := uint32(1)
+	if c.dict != nil {
+		id = c.dict.Id + 1
+	}
+	dict := &colarspb.CompressionDictionary{
+		Id:   id,
+		Data: zstddict.Train(c.samples, c.dictSize, id),
+	}
+	encoder, err := zstd.NewWriter(nil, zstddict.EncoderDict(dict.Id, dict.Data), zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+	if c.encoder != nil {
+		if err := c.encoder.Close(); err != nil {
+			return nil, werror.Wrap(err)
+		}
+	}
+	c.dict, c.encoder = dict, encoder
+	c.samples = nil
+	c.batches = 0
+	return dict, nil
+}
+
+func (c *dictionaryCompressor) close() error {
+	if c.encoder == nil {
+		return nil
+	}
+	return c.encoder.Close()
+}
+
+func newDictionaryDecompressor(maxSize uint64) *dictionaryDecompressor {
+	return &dictionaryDecompressor{
+		decoders: make(map[uint32]*zstd.Decoder),
+		maxSize:  maxSize,
+	}
+}
+
+// add registers a new dictionary, the dictionaries older than the previous
+// one are dropped.
+func (d *dictionaryDecompressor) add(dict *colarspb.CompressionDictionary) error {
+	if dict.Id == 0 {
+		return werror.Wrap(ErrInvalidCompressionDictionary)
+	}
+	decoder, err := zstd.NewReader(nil,
+		zstddict.DecoderDict(dict.Id, dict.Data),
+		zstd.WithDecoderMaxMemory(d.maxSize),
+		zstd.WithDecoderConcurrency(1),
+	)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	for id, decoder := range d.decoders {
+		if id+1 < dict.Id || id >= dict.Id {
+			decoder.Close()
+			delete(d.decoders, id)
+		}
+	}
+	d.decoders[dict.Id] = decoder
+	return nil
+}
+
+// decompress returns the decompressed record of a payload. The record is only
+// valid until the next call to decompress, the IPC reader copies the messages
+// it reads.
+func (d *dictionaryDecompressor) decompress(payload *colarspb.ArrowPayload) ([]byte, error) {
+	decoder, ok := d.decoders[payload.CompressionDictionaryId]
+	if !ok {
+		return nil, werror.WrapWithContext(ErrUnknownCompressionDictionary, map[string]interface{}{"id": payload.CompressionDictionaryId})
+	}
+	record, err := decoder.DecodeAll(payload.Record, d.buf[:0])
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+	// An exceptionally large buffer is not kept, see maxPooledBufferSize.
+	if cap(record) <= maxPooledBufferSize {
+		d.buf = record
+	}
+	return record, nil
+}
+
+func (d *dictionaryDecompressor) close() {
+	for id, decoder := range d.decoders {
+		decoder.Close()
+		delete(d.decoders, id)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"google.golang.org/protobuf/proto"
+
+	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	"github.com/f5/otel-arrow-adapter/pkg/config"
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/assert"
+)
+
+// produceSmallTraces produces and consumes batches of a few spans and returns
+// the size of the batches and the ids of the dictionaries shipped.
+func produceSmallTraces(t *testing.T, batches int, options ...config.Option) (int, []uint32) {
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	producer := NewProducerWithOptions(append([]config.Option{config.WithAllocator(pool)}, options...)...)
+	consumer := NewConsumer()
+
+	ent := datagen.NewTestEntropy(12345)
+	dg := datagen.NewTracesGenerator(ent, ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes())
+
+	size := 0
+	var dictionaries []uint32
+	for i := 0; i < batches; i++ {
+		traces := dg.Generate(5, time.Minute)
+
+		batch, err := producer.BatchArrowRecordsFromTraces(traces)
+		require.NoError(t, err)
+		size += proto.Size(batch)
+		if batch.CompressionDictionary != nil {
+			dictionaries = append(dictionaries, batch.CompressionDictionary.Id)
+		}
+
+		received, err := consumer.TracesFrom(batch)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(received))
+
+		assert.Equiv(
+			t,
+			[]json.Marshaler{ptraceotlp.NewExportRequestFromTraces(traces)},
+			[]json.Marshaler{ptraceotlp.NewExportRequestFromTraces(received[0])},
+		)
+	}
+	require.NoError(t, consumer.Close())
+	require.NoError(t, producer.Close())
+	pool.AssertSize(t, 0)
+	return size, dictionaries
+}
+
+// TestCompressionDictionary checks that small batches compressed with a
+// dictionary are decoded by the consumer and are smaller than the
+// batches compressed with the IPC zstd codec.
+func TestCompressionDictionary(t *testing.T) {
+	t.Parallel()
+
+	zstdSize, dictionaries := produceSmallTraces(t, 30, config.WithZstd())
+	require.Empty(t, dictionaries)
+
+	dictSize, dictionaries := produceSmallTraces(t, 30, config.WithCompressionDictionary(10, 16<<10))
+	require.Equal(t, []uint32{1}, dictionaries)
+	t.Logf("zstd: %d bytes, zstd dictionary: %d bytes", zstdSize, dictSize)
+	require.Less(t, dictSize, zstdSize)
+}
+
+// TestCompressionDictionaryRotation checks that the payloads of the batch
+// shipping a new dictionary, still compressed with the previous one, are
+// decoded by the consumer.
+func TestCompressionDictionaryRotation(t *testing.T) {
+	t.Parallel()
+
+	_, dictionaries := produceSmallTraces(t, 40,
+		config.WithCompressionDictionary(10, 8<<10),
+		config.WithCompressionDictionaryRotation(10),
+	)
+	require.Greater(t, len(dictionaries), 2)
+	for i, id := range dictionaries {
+		require.Equal(t, uint32(i+1), id)
+	}
+}
+
+// TestCompressionDictionaryOptionsOrder checks that the IPC compression is
+// disabled with a dictionary whatever the order of the options.
+func TestCompressionDictionaryOptionsOrder(t *testing.T) {
+	t.Parallel()
+
+	for _, options := range [][]config.Option{
+		{config.WithZstd(), config.WithCompressionDictionary(10, 8<<10)},
+		{config.WithCompressionDictionary(10, 8<<10), config.WithZstd()},
+	} {
+		producer := NewProducerWithOptions(options...)
+		require.Empty(t, producer.ipcCodec)
+		require.NoError(t, producer.Close())
+	}
+}
+
+func TestUnknownCompressionDictionary(t *testing.T) {
+	t.Parallel()
+
+	consumer := NewConsumer()
+	defer func() { require.NoError(t, consumer.Close()) }()
+
+	_, err := consumer.Consume(&colarspb.BatchArrowRecords{
+		ArrowPayloads: []*colarspb.ArrowPayload{{
+			SubStreamId:             "0",
+			Type:                    colarspb.ArrowPayloadType_SPANS,
+			Record:                  []byte{1, 2, 3},
+			CompressionDictionaryId: 1,
+		}},
+	})
+	require.True(t, errors.Is(err, ErrUnknownCompressionDictionary))
+
+	_, err = consumer.Consume(&colarspb.BatchArrowRecords{
+		CompressionDictionary: &colarspb.CompressionDictionary{},
+	})
+	require.True(t, errors.Is(err, ErrInvalidCompressionDictionary))
+}
@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/f5/otel-arrow-adapter/pkg/config"
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/assert"
+)
+
+// TestIPCCompression checks that the batches produced with each IPC
+// compression codec are decoded by a consumer without any configuration,
+// and that the codecs actually compress the records.
+func TestIPCCompression(t *testing.T) {
+	t.Parallel()
+
+	sizes := map[config.IPCCompression]int{}
+	for _, compression := range []config.IPCCompression{
+		config.IPCCompressionNone,
+		config.IPCCompressionZstd,
+		config.IPCCompressionLZ4Frame,
+	} {
+		pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		producer := NewProducerWithOptions(config.WithAllocator(pool), config.WithIPCCompression(compression))
+		consumer := NewConsumer()
+
+		ent := datagen.NewTestEntropy(12345)
+		dg := datagen.NewTracesGenerator(ent, ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes())
+		for i := 0; i < 2; i++ {
+			traces := dg.Generate(20, time.Minute)
+
+			batch, err := producer.BatchArrowRecordsFromTraces(traces)
+			require.NoError(t, err)
+			sizes[compression] += proto.Size(batch)
+
+			received, err := consumer.TracesFrom(batch)
+			require.NoError(t, err, compression)
+			require.Equal(t, 1, len(received))
+
+			assert.Equiv(
+				t,
+				[]json.Marshaler{ptraceotlp.NewExportRequestFromTraces(traces)},
+				[]json.Marshaler{ptraceotlp.NewExportRequestFromTraces(received[0])},
+			)
+		}
+		require.NoError(t, consumer.Close())
+		require.NoError(t, producer.Close())
+		pool.AssertSize(t, 0)
+	}
+
+	require.Less(t, sizes[config.IPCCompressionZstd], sizes[config.IPCCompressionNone])
+	require.Less(t, sizes[config.IPCCompressionLZ4Frame], sizes[config.IPCCompressionNone])
+}
+
+func TestIPCCompressionValidate(t *testing.T) {
+	require.NoError(t, config.IPCCompression("").Validate())
+	require.NoError(t, config.IPCCompressionLZ4Frame.Validate())
+	require.Error(t, config.IPCCompression("brotli").Validate())
+	require.Panics(t, func() {
+		NewProducerWithOptions(config.WithIPCCompression("brotli"))
+	})
+}
+
+// TestDeprecatedZstd checks that the deprecated Zstd field selects the IPC
+// compression codec when Compression is not set.
+func TestDeprecatedZstd(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, config.IPCCompressionZstd, config.DefaultConfig().IPCCompressionCodec())
+	require.Equal(t, config.IPCCompressionNone, (&config.Config{}).IPCCompressionCodec())
+	require.Equal(t, config.IPCCompressionNone, (&config.Config{Zstd: true, Compression: config.IPCCompressionNone}).IPCCompressionCodec())
+
+	conf := config.DefaultConfig()
+	conf.Zstd = false
+	require.Equal(t, config.IPCCompressionNone, conf.IPCCompressionCodec())
+
+	config.WithLZ4Frame()(conf)
+	require.Equal(t, config.IPCCompressionLZ4Frame, conf.IPCCompressionCodec())
+}
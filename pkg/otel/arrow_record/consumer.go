@@ -16,6 +16,7 @@ package arrow_record
 
 import (
 	"bytes"
+	"time"
 
 	"github.com/apache/arrow/go/v12/arrow/ipc"
 	"github.com/apache/arrow/go/v12/arrow/memory"
@@ -43,6 +44,8 @@ type ConsumerAPI interface {
 	LogsFrom(*colarspb.BatchArrowRecords) ([]plog.Logs, error)
 	TracesFrom(*colarspb.BatchArrowRecords) ([]ptrace.Traces, error)
 	MetricsFrom(*colarspb.BatchArrowRecords) ([]pmetric.Metrics, error)
+	StreamsToReset() []record_message.PayloadType
+	SubStreams() []SubStreamInfo
 	Close() error
 }
 
@@ -54,24 +57,62 @@ type Consumer struct {
 
 	memLimit uint64
 
+	// Memory used by a sub-stream above which a reset of the sub-stream is
+	// requested to the producer (0 means never).
+	resetThreshold uint64
+	// Payload types for which a reset has been requested and not yet
+	// performed by the producer.
+	pendingResets map[record_message.PayloadType]bool
+
 	tracesConfig *arrow.Config
+
+	// Size of the last protobuf message produced by the *ProtoFrom methods,
+	// used to pre-allocate the buffer of the next message.
+	protoSizeHint int
+
+	// Snapshots of the sub-streams, see SubStreams
+	subStreams *subStreamsInfo
+
+	// Decompression of the records compressed with a zstd dictionary
+	// (created with the first dictionary received)
+	dictionaries *dictionaryDecompressor
 }
 
 type streamConsumer struct {
 	bufReader   *bytes.Reader
 	ipcReader   *ipc.Reader
+	allocator   *common.LimitedAllocator
 	payloadType record_message.PayloadType
+	batchCount  uint64
 }
 
+// ConsumerOption is a functional option for the Consumer.
+type ConsumerOption func(*Consumer)
+
 // NewConsumer creates a new BatchArrowRecords consumer, i.e. a decoder consuming BatchArrowRecords and returning
 // the corresponding OTLP representation (pmetric,Metrics, plog.Logs, ptrace.Traces).
-func NewConsumer() *Consumer {
-	return &Consumer{
+func NewConsumer(options ...ConsumerOption) *Consumer {
+	c := &Consumer{
 		streamConsumers: make(map[string]*streamConsumer),
+		pendingResets:   make(map[record_message.PayloadType]bool),
 
 		// TODO: configure this limit with a functional option
 		memLimit:     70 << 20,
 		tracesConfig: arrow.DefaultConfig(),
+		subStreams:   newSubStreamsInfo(),
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// WithStreamResetThreshold sets the amount of memory (in bytes) used by a
+// sub-stream above which the consumer requests the producer to reset the
+// sub-streams of the same payload type (see StreamsToReset).
+func WithStreamResetThreshold(threshold uint64) ConsumerOption {
+	return func(c *Consumer) {
+		c.resetThreshold = threshold
 	}
 }
 
@@ -156,11 +197,107 @@ func (c *Consumer) TracesFrom(bar *colarspb.BatchArrowRecords) ([]ptrace.Traces,
 	return result, nil
 }
 
+// MetricsProtoFrom produces an array of OTLP ExportMetricsServiceRequest
+// protobuf messages from a BatchArrowRecords message. The messages are
+// directly encoded from the Arrow records, no [pmetric.Metrics] is built.
+func (c *Consumer) MetricsProtoFrom(bar *colarspb.BatchArrowRecords) ([][]byte, error) {
+	records, err := c.Consume(bar)
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+
+	result := make([][]byte, 0, len(records))
+
+	// Compute all related records (i.e. Attributes, Summaries, Histograms, ...)
+	relatedData, metricsRecord, err := metricsotlp.RelatedDataFrom(records)
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+
+	if metricsRecord != nil {
+		buf, err := metricsotlp.MetricsProtoFrom(make([]byte, 0, c.protoSizeHint), metricsRecord.Record(), relatedData)
+		if err != nil {
+			return nil, werror.Wrap(err)
+		}
+		c.protoSizeHint = len(buf)
+		result = append(result, buf)
+	}
+
+	return result, nil
+}
+
+// LogsProtoFrom produces an array of OTLP ExportLogsServiceRequest protobuf
+// messages from a BatchArrowRecords message. The messages are directly
+// encoded from the Arrow records, no [plog.Logs] is built.
+func (c *Consumer) LogsProtoFrom(bar *colarspb.BatchArrowRecords) ([][]byte, error) {
+	records, err := c.Consume(bar)
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+
+	result := make([][]byte, 0, len(records))
+
+	// Compute all related records (i.e. Attributes)
+	relatedData, logsRecord, err := logsotlp.RelatedDataFrom(records)
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+
+	if logsRecord != nil {
+		buf, err := logsotlp.LogsProtoFrom(make([]byte, 0, c.protoSizeHint), logsRecord.Record(), relatedData)
+		if err != nil {
+			return nil, werror.Wrap(err)
+		}
+		c.protoSizeHint = len(buf)
+		result = append(result, buf)
+	}
+
+	return result, nil
+}
+
+// TracesProtoFrom produces an array of OTLP ExportTraceServiceRequest
+// protobuf messages from a BatchArrowRecords message. The messages are
+// directly encoded from the Arrow records, no [ptrace.Traces] is built.
+func (c *Consumer) TracesProtoFrom(bar *colarspb.BatchArrowRecords) ([][]byte, error) {
+	records, err := c.Consume(bar)
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+
+	result := make([][]byte, 0, len(records))
+
+	// Compute all related records (i.e. Attributes, Events, and Links)
+	relatedData, tracesRecord, err := tracesotlp.RelatedDataFrom(records, c.tracesConfig)
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+
+	if tracesRecord != nil {
+		buf, err := tracesotlp.TracesProtoFrom(make([]byte, 0, c.protoSizeHint), tracesRecord.Record(), relatedData)
+		if err != nil {
+			return nil, werror.Wrap(err)
+		}
+		c.protoSizeHint = len(buf)
+		result = append(result, buf)
+	}
+
+	return result, nil
+}
+
 // Consume takes a BatchArrowRecords protobuf message and returns an array of RecordMessage.
 // Note: the records wrapped in the RecordMessage must be released after use by the caller.
 func (c *Consumer) Consume(bar *colarspb.BatchArrowRecords) ([]*record_message.RecordMessage, error) {
 	var ibes []*record_message.RecordMessage
 
+	if bar.CompressionDictionary != nil {
+		if c.dictionaries == nil {
+			c.dictionaries = newDictionaryDecompressor(c.memLimit)
+		}
+		if err := c.dictionaries.add(bar.CompressionDictionary); err != nil {
+			return nil, werror.Wrap(err)
+		}
+	}
+
 	// Transform each individual OtlpArrowPayload into RecordMessage
 	for _, payload := range bar.ArrowPayloads {
 		// Retrieves (or creates) the stream consumer for the sub-stream id defined in the BatchArrowRecords message.
@@ -173,28 +310,45 @@ func (c *Consumer) Consume(bar *colarspb.BatchArrowRecords) ([]*record_message.R
 			// changes are only additive.
 			// This will release the resources associated with the previous
 			// stream consumer.
+			// This is also how a reset requested via StreamsToReset
+			// completes.
 			for scID, sc := range c.streamConsumers {
 				if sc.payloadType == payload.Type {
 					sc.ipcReader.Release()
 					delete(c.streamConsumers, scID)
+					c.subStreams.remove(scID)
 				}
 			}
+			delete(c.pendingResets, payload.Type)
 
 			bufReader := bytes.NewReader([]byte{})
 			sc = &streamConsumer{
 				bufReader:   bufReader,
+				allocator:   common.NewLimitedAllocator(memory.NewGoAllocator(), c.memLimit),
 				payloadType: payload.Type,
 			}
 			c.streamConsumers[payload.SubStreamId] = sc
 		}
 
-		sc.bufReader.Reset(payload.Record)
+		record := payload.Record
+		if payload.CompressionDictionaryId != 0 {
+			if c.dictionaries == nil {
+				return nil, werror.WrapWithContext(ErrUnknownCompressionDictionary, map[string]interface{}{"id": payload.CompressionDictionaryId})
+			}
+			var err error
+			if record, err = c.dictionaries.decompress(payload); err != nil {
+				return nil, werror.Wrap(err)
+			}
+		}
+
+		sc.bufReader.Reset(record)
 		if sc.ipcReader == nil {
+			// The compression codec (none, ZSTD or LZ4_FRAME) is read
+			// from each IPC message, whatever the producer chose.
 			ipcReader, err := ipc.NewReader(
 				sc.bufReader,
-				ipc.WithAllocator(common.NewLimitedAllocator(memory.NewGoAllocator(), c.memLimit)),
+				ipc.WithAllocator(sc.allocator),
 				ipc.WithDictionaryDeltas(true),
-				ipc.WithZstd(),
 			)
 			if err != nil {
 				return nil, werror.Wrap(err)
@@ -209,6 +363,8 @@ func (c *Consumer) Consume(bar *colarspb.BatchArrowRecords) ([]*record_message.R
 			// or after the next call to Reader.Next().
 			rec.Retain()
 			ibes = append(ibes, record_message.NewRecordMessage(bar.BatchId, payload.GetType(), rec))
+			sc.batchCount++
+			c.subStreams.update(payload.SubStreamId, payload.Type, rec, sc.batchCount, time.Now())
 		}
 	}
 
@@ -221,6 +377,36 @@ func (c *Consumer) Consume(bar *colarspb.BatchArrowRecords) ([]*record_message.R
 	return ibes, nil
 }
 
+// StreamsToReset returns the payload types of the sub-streams whose memory
+// usage exceeds the reset threshold of the consumer. A payload type is returned
+// only once until the producer starts a new sub-stream for it.
+//
+// The state of the current sub-streams is released when the corresponding new
+// sub-streams are received, as batches encoded before the reset may still be
+// in flight.
+func (c *Consumer) StreamsToReset() []record_message.PayloadType {
+	if c.resetThreshold == 0 {
+		return nil
+	}
+
+	var payloadTypes []record_message.PayloadType
+	for _, sc := range c.streamConsumers {
+		if c.pendingResets[sc.payloadType] || sc.allocator.Inuse() < c.resetThreshold {
+			continue
+		}
+		c.pendingResets[sc.payloadType] = true
+		payloadTypes = append(payloadTypes, sc.payloadType)
+	}
+	return payloadTypes
+}
+
+// SubStreams returns a snapshot of the state of the current sub-streams of
+// the consumer. This method can be called concurrently with the other
+// methods of the consumer.
+func (c *Consumer) SubStreams() []SubStreamInfo {
+	return c.subStreams.snapshot()
+}
+
 // Close closes the consumer and all its sub-stream ipc readers.
 func (c *Consumer) Close() error {
 	for _, sc := range c.streamConsumers {
@@ -228,5 +414,9 @@ func (c *Consumer) Close() error {
 			sc.ipcReader.Release()
 		}
 	}
+	c.subStreams.clear()
+	if c.dictionaries != nil {
+		c.dictionaries.close()
+	}
 	return nil
 }
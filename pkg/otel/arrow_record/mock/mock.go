@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	v1 "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	arrow_record "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
 	gomock "github.com/golang/mock/gomock"
 	plog "go.opentelemetry.io/collector/pdata/plog"
 	pmetric "go.opentelemetry.io/collector/pdata/pmetric"
@@ -96,6 +97,50 @@ func (mr *MockProducerAPIMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockProducerAPI)(nil).Close))
 }
 
+// ReleaseBatch mocks base method.
+func (m *MockProducerAPI) ReleaseBatch(arg0 *v1.BatchArrowRecords) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReleaseBatch", arg0)
+}
+
+// ReleaseBatch indicates an expected call of ReleaseBatch.
+func (mr *MockProducerAPIMockRecorder) ReleaseBatch(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseBatch", reflect.TypeOf((*MockProducerAPI)(nil).ReleaseBatch), arg0)
+}
+
+// ResetStreams mocks base method.
+func (m *MockProducerAPI) ResetStreams(arg0 ...v1.ArrowPayloadType) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ResetStreams", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetStreams indicates an expected call of ResetStreams.
+func (mr *MockProducerAPIMockRecorder) ResetStreams(arg0 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetStreams", reflect.TypeOf((*MockProducerAPI)(nil).ResetStreams), arg0...)
+}
+
+// SubStreams mocks base method.
+func (m *MockProducerAPI) SubStreams() []arrow_record.SubStreamInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubStreams")
+	ret0, _ := ret[0].([]arrow_record.SubStreamInfo)
+	return ret0
+}
+
+// SubStreams indicates an expected call of SubStreams.
+func (mr *MockProducerAPIMockRecorder) SubStreams() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubStreams", reflect.TypeOf((*MockProducerAPI)(nil).SubStreams))
+}
+
 // MockConsumerAPI is a mock of ConsumerAPI interface.
 type MockConsumerAPI struct {
 	ctrl     *gomock.Controller
@@ -163,6 +208,34 @@ func (mr *MockConsumerAPIMockRecorder) MetricsFrom(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MetricsFrom", reflect.TypeOf((*MockConsumerAPI)(nil).MetricsFrom), arg0)
 }
 
+// StreamsToReset mocks base method.
+func (m *MockConsumerAPI) StreamsToReset() []v1.ArrowPayloadType {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamsToReset")
+	ret0, _ := ret[0].([]v1.ArrowPayloadType)
+	return ret0
+}
+
+// StreamsToReset indicates an expected call of StreamsToReset.
+func (mr *MockConsumerAPIMockRecorder) StreamsToReset() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamsToReset", reflect.TypeOf((*MockConsumerAPI)(nil).StreamsToReset))
+}
+
+// SubStreams mocks base method.
+func (m *MockConsumerAPI) SubStreams() []arrow_record.SubStreamInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubStreams")
+	ret0, _ := ret[0].([]arrow_record.SubStreamInfo)
+	return ret0
+}
+
+// SubStreams indicates an expected call of SubStreams.
+func (mr *MockConsumerAPIMockRecorder) SubStreams() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubStreams", reflect.TypeOf((*MockConsumerAPI)(nil).SubStreams))
+}
+
 // TracesFrom mocks base method.
 func (m *MockConsumerAPI) TracesFrom(arg0 *v1.BatchArrowRecords) ([]ptrace.Traces, error) {
 	m.ctrl.T.Helper()
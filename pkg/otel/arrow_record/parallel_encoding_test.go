@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+
+	"github.com/f5/otel-arrow-adapter/pkg/config"
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/assert"
+)
+
+// TestParallelEncodingMetrics checks that the batches produced with a
+// concurrent encoding are identical to the batches produced sequentially
+// (same payload order, same sub-stream ids, same bytes) and can be decoded.
+func TestParallelEncodingMetrics(t *testing.T) {
+	t.Parallel()
+
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+
+	seqProducer := NewProducerWithOptions(config.WithAllocator(pool))
+	parProducer := NewProducerWithOptions(config.WithAllocator(pool), config.WithEncodingConcurrency(4))
+	defer func() {
+		require.NoError(t, seqProducer.Close())
+		require.NoError(t, parProducer.Close())
+	}()
+
+	ent := datagen.NewTestEntropy(12345)
+	dg := datagen.NewMetricsGenerator(ent, ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes())
+	consumer := NewConsumer()
+
+	for i := 0; i < 2; i++ {
+		metrics := dg.GenerateAllKindOfMetrics(10, time.Minute)
+
+		seqBatch, err := seqProducer.BatchArrowRecordsFromMetrics(metrics)
+		require.NoError(t, err)
+		parBatch, err := parProducer.BatchArrowRecordsFromMetrics(metrics)
+		require.NoError(t, err)
+
+		require.Equal(t, len(seqBatch.ArrowPayloads), len(parBatch.ArrowPayloads))
+		for j, seqPayload := range seqBatch.ArrowPayloads {
+			parPayload := parBatch.ArrowPayloads[j]
+			require.Equal(t, seqPayload.Type, parPayload.Type)
+			require.Equal(t, seqPayload.SubStreamId, parPayload.SubStreamId)
+			require.Equal(t, seqPayload.Record, parPayload.Record)
+		}
+
+		received, err := consumer.MetricsFrom(parBatch)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(received))
+
+		assert.Equiv(
+			t,
+			[]json.Marshaler{pmetricotlp.NewExportRequestFromMetrics(metrics)},
+			[]json.Marshaler{pmetricotlp.NewExportRequestFromMetrics(received[0])},
+		)
+	}
+}
+
+// TestParallelEncodingTraces checks that the multi-level related records of
+// the traces (e.g. events and event attributes) are correctly built when the
+// encoding is concurrent.
+func TestParallelEncodingTraces(t *testing.T) {
+	t.Parallel()
+
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+
+	producer := NewProducerWithOptions(config.WithAllocator(pool), config.WithEncodingConcurrency(4))
+	defer func() {
+		require.NoError(t, producer.Close())
+	}()
+
+	tg := newResetTestTraces()
+	consumer := NewConsumer()
+
+	for i := 0; i < 2; i++ {
+		traces := tg.Generate(20, time.Minute)
+
+		batch, err := producer.BatchArrowRecordsFromTraces(traces)
+		require.NoError(t, err)
+
+		received, err := consumer.TracesFrom(batch)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(received))
+
+		assert.Equiv(
+			t,
+			[]json.Marshaler{ptraceotlp.NewExportRequestFromTraces(traces)},
+			[]json.Marshaler{ptraceotlp.NewExportRequestFromTraces(received[0])},
+		)
+	}
+}
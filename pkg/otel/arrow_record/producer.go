@@ -15,13 +15,15 @@
 package arrow_record
 
 import (
-	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
 	"github.com/apache/arrow/go/v12/arrow/ipc"
 	"github.com/apache/arrow/go/v12/arrow/memory"
 	"go.opentelemetry.io/collector/pdata/plog"
@@ -53,6 +55,9 @@ type ProducerAPI interface {
 	BatchArrowRecordsFromTraces(ptrace.Traces) (*colarspb.BatchArrowRecords, error)
 	BatchArrowRecordsFromLogs(plog.Logs) (*colarspb.BatchArrowRecords, error)
 	BatchArrowRecordsFromMetrics(pmetric.Metrics) (*colarspb.BatchArrowRecords, error)
+	ResetStreams(...record_message.PayloadType) error
+	ReleaseBatch(*colarspb.BatchArrowRecords)
+	SubStreams() []SubStreamInfo
 	Close() error
 }
 
@@ -62,11 +67,29 @@ var _ ProducerAPI = &Producer{}
 type (
 	Producer struct {
 		pool            memory.Allocator // Use a custom memory allocator
-		zstd            bool             // Use IPC ZSTD compression
+		ipcCodec        []ipc.Option     // IPC compression codec (none if empty)
 		streamProducers map[string]*streamProducer
 		nextSubStreamId int64
 		batchId         int64
 
+		// Pool of the buffers referenced by the produced ArrowPayloads
+		buffers bufferPool
+
+		// Sub-stream reset policy (0 means disabled)
+		resetAfterBatches         uint64
+		resetAfterDictionaryBytes uint64
+
+		// Max number of records IPC encoded concurrently (<= 1 means
+		// sequential)
+		encodingConcurrency int
+
+		// Compression of the records with a zstd dictionary (nil if
+		// disabled)
+		dictionaryCompressor *dictionaryCompressor
+
+		// Selection of the sort strategy of the traces (nil if disabled)
+		adaptiveSorting *adaptiveSorting
+
 		// Builder for each OTEL entities
 		metricsBuilder *metricsarrow.MetricsBuilder
 		logsBuilder    *logsarrow.LogsBuilder
@@ -79,9 +102,16 @@ type (
 
 		// General stats for the producer
 		stats *pstats.ProducerStats
+		// Reporting of the stats as OpenTelemetry metrics (nil if disabled)
+		telemetry *producerTelemetry
+		// Compression reports of the batches (nil if the stats are disabled)
+		report *reportBuilder
 
 		// Producer observer
 		observer ProducerObserver
+
+		// Snapshots of the sub-streams, see SubStreams
+		subStreams *subStreamsInfo
 	}
 
 	ProducerObserver interface {
@@ -98,12 +128,13 @@ type (
 	}
 
 	streamProducer struct {
-		output         bytes.Buffer
+		output         *payloadBuffer
 		ipcWriter      *ipc.Writer
 		subStreamId    string
 		lastProduction time.Time
 		schema         *arrow.Schema
 		payloadType    record_message.PayloadType
+		batchCount     uint64
 	}
 )
 
@@ -125,8 +156,10 @@ func NewProducerWithOptions(options ...cfg.Option) *Producer {
 	}
 
 	stats := pstats.NewProducerStats()
+	var report *reportBuilder
 	if conf.Stats {
 		stats.SchemaStatsEnabled = true
+		report = newReportBuilder()
 	}
 
 	// Record builders
@@ -153,12 +186,59 @@ func NewProducerWithOptions(options ...cfg.Option) *Producer {
 		panic(err)
 	}
 
+	var ipcCodec []ipc.Option
+	compression := conf.IPCCompressionCodec()
+	if conf.CompressionDictionarySamples > 0 {
+		// The records are compressed with the zstd dictionary, the IPC
+		// compressed buffers wouldn't compress further.
+		compression = cfg.IPCCompressionNone
+	}
+	switch compression {
+	case cfg.IPCCompressionZstd:
+		ipcCodec = []ipc.Option{ipc.WithZstd()}
+	case cfg.IPCCompressionLZ4Frame:
+		ipcCodec = []ipc.Option{ipc.WithLZ4()}
+	default:
+		if err := compression.Validate(); err != nil {
+			panic(err)
+		}
+	}
+
+	var compressor *dictionaryCompressor
+	if conf.CompressionDictionarySamples > 0 {
+		size := conf.CompressionDictionarySize
+		if size <= 0 {
+			size = cfg.DefaultCompressionDictionarySize
+		}
+		compressor = newDictionaryCompressor(conf.CompressionDictionarySamples, size, conf.CompressionDictionaryRotation)
+	}
+
+	var sorting *adaptiveSorting
+	if conf.AdaptiveSortingPeriod > 0 {
+		sorting = newAdaptiveSorting(conf)
+		stats.TracesSortStrategy = sorting.current.String()
+	}
+
+	var telemetry *producerTelemetry
+	if conf.MeterProvider != nil {
+		telemetry, err = newProducerTelemetry(conf.MeterProvider, conf.MeterAttributes)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	return &Producer{
 		pool:            conf.Pool,
-		zstd:            conf.Zstd,
+		ipcCodec:        ipcCodec,
 		streamProducers: make(map[string]*streamProducer),
 		batchId:         0,
 
+		resetAfterBatches:         conf.ResetAfterBatches,
+		resetAfterDictionaryBytes: conf.ResetAfterDictionaryBytes,
+		encodingConcurrency:       conf.EncodingConcurrency,
+		dictionaryCompressor:      compressor,
+		adaptiveSorting:           sorting,
+
 		metricsBuilder: metricsBuilder,
 		logsBuilder:    logsBuilder,
 		tracesBuilder:  tracesBuilder,
@@ -167,7 +247,10 @@ func NewProducerWithOptions(options ...cfg.Option) *Producer {
 		logsRecordBuilder:    logsRecordBuilder,
 		tracesRecordBuilder:  tracesRecordBuilder,
 
-		stats: stats,
+		stats:      stats,
+		telemetry:  telemetry,
+		report:     report,
+		subStreams: newSubStreamsInfo(),
 	}
 }
 
@@ -208,6 +291,8 @@ func (p *Producer) BatchArrowRecordsFromMetrics(metrics pmetric.Metrics) (*colar
 		return nil, werror.Wrap(err)
 	}
 	p.stats.MetricsBatchesProduced++
+	p.telemetry.reportStats(context.Background(), p.stats)
+	p.telemetry.reportSchemaEvents(context.Background(), colarspb.ArrowPayloadType_METRICS, p.metricsRecordBuilder, p.metricsBuilder.RelatedData())
 	return bar, nil
 }
 
@@ -239,11 +324,21 @@ func (p *Producer) BatchArrowRecordsFromLogs(ls plog.Logs) (*colarspb.BatchArrow
 		return nil, werror.Wrap(err)
 	}
 	p.stats.LogsBatchesProduced++
+	p.telemetry.reportStats(context.Background(), p.stats)
+	p.telemetry.reportSchemaEvents(context.Background(), colarspb.ArrowPayloadType_LOGS, p.logsRecordBuilder, p.logsBuilder.RelatedData())
 	return bar, nil
 }
 
 // BatchArrowRecordsFromTraces produces a BatchArrowRecords message from a [ptrace.Traces] messages.
 func (p *Producer) BatchArrowRecordsFromTraces(ts ptrace.Traces) (*colarspb.BatchArrowRecords, error) {
+	// The sort strategy is selected before the encoding, the stream is left
+	// untouched if the evaluation fails.
+	if p.adaptiveSorting.due() {
+		if err := p.adaptSortStrategy(ts); err != nil {
+			return nil, werror.Wrap(err)
+		}
+	}
+
 	// Builds a main Record and n related Records from the traces passed in
 	// parameter. All these Arrow records are wrapped into a BatchArrowRecords
 	// and will be released by the Producer.Produce method.
@@ -255,24 +350,49 @@ func (p *Producer) BatchArrowRecordsFromTraces(ts ptrace.Traces) (*colarspb.Batc
 		return nil, werror.Wrap(err)
 	}
 
-	rms, err := p.tracesBuilder.RelatedData().BuildRecordMessages()
+	rms, err := p.tracesRecordMessages(record)
 	if err != nil {
 		return nil, werror.Wrap(err)
 	}
 
-	schemaID := p.tracesRecordBuilder.SchemaID()
-	// The main record must be the first one to simplify the decoding
-	// in the collector.
-	rms = append([]*record_message.RecordMessage{record_message.NewTraceMessage(schemaID, record)}, rms...)
-
 	bar, err := p.Produce(rms)
 	if err != nil {
 		return nil, werror.Wrap(err)
 	}
 	p.stats.TracesBatchesProduced++
+	p.telemetry.reportStats(context.Background(), p.stats)
+	p.telemetry.reportSchemaEvents(context.Background(), colarspb.ArrowPayloadType_SPANS, p.tracesRecordBuilder, p.tracesBuilder.RelatedData())
 	return bar, nil
 }
 
+// RecordMessagesFromTraces builds the main Arrow record and the related
+// records of the traces passed in parameter without encoding them into a
+// BatchArrowRecords. The main record is the first record message.
+// Note: The records must be released by the caller (or passed to Produce).
+func (p *Producer) RecordMessagesFromTraces(ts ptrace.Traces) ([]*record_message.RecordMessage, error) {
+	record, err := recordBuilder[ptrace.Traces](func() (acommon.EntityBuilder[ptrace.Traces], error) {
+		p.tracesBuilder.RelatedData().Reset()
+		return p.tracesBuilder, nil
+	}, ts)
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+
+	return p.tracesRecordMessages(record)
+}
+
+func (p *Producer) tracesRecordMessages(record arrow.Record) ([]*record_message.RecordMessage, error) {
+	rms, err := p.tracesBuilder.RelatedData().BuildRecordMessages()
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+
+	schemaID := p.tracesRecordBuilder.SchemaID()
+	// The main record must be the first one to simplify the decoding
+	// in the collector.
+	return append([]*record_message.RecordMessage{record_message.NewTraceMessage(schemaID, record)}, rms...), nil
+}
+
 // MetricsRecordBuilderExt returns the record builder used to encode metrics.
 func (p *Producer) MetricsRecordBuilderExt() *builder.RecordBuilderExt {
 	return p.metricsRecordBuilder
@@ -316,111 +436,371 @@ func (p *Producer) Close() error {
 		}
 		p.stats.StreamProducersClosed++
 	}
+	p.subStreams.clear()
+	if p.dictionaryCompressor != nil {
+		if err := p.dictionaryCompressor.close(); err != nil {
+			return werror.Wrap(err)
+		}
+	}
+	if err := p.adaptiveSorting.close(); err != nil {
+		return werror.Wrap(err)
+	}
+	p.telemetry.reportStats(context.Background(), p.stats)
+	if err := p.telemetry.close(); err != nil {
+		return werror.Wrap(err)
+	}
+	return nil
+}
+
+// ResetStreams closes the stream producers (i.e. the IPC writers) associated
+// with the given payload types and clears the dictionaries of the
+// corresponding record builders. The next batches will be encoded on new
+// sub-streams, which lets the consumer release the state of the previous ones.
+//
+// All the payload types are reset when no payload type is specified. The
+// StreamResetsPerformed counter is only incremented when at least one stream
+// producer has been closed.
+func (p *Producer) ResetStreams(payloadTypes ...record_message.PayloadType) error {
+	if len(payloadTypes) == 0 {
+		for pt := range colarspb.ArrowPayloadType_name {
+			if colarspb.ArrowPayloadType(pt) == colarspb.ArrowPayloadType_UNKNOWN {
+				continue
+			}
+			payloadTypes = append(payloadTypes, record_message.PayloadType(pt))
+		}
+	}
+
+	reset := false
+	for _, payloadType := range payloadTypes {
+		for ssID, sp := range p.streamProducers {
+			if sp.payloadType != payloadType {
+				continue
+			}
+			if err := sp.ipcWriter.Close(); err != nil {
+				return werror.Wrap(err)
+			}
+			p.stats.StreamProducersClosed++
+			delete(p.streamProducers, ssID)
+			p.subStreams.remove(sp.subStreamId)
+			reset = true
+		}
+
+		switch payloadType {
+		case colarspb.ArrowPayloadType_METRICS:
+			p.metricsRecordBuilder.ResetDictionaries()
+		case colarspb.ArrowPayloadType_LOGS:
+			p.logsRecordBuilder.ResetDictionaries()
+		case colarspb.ArrowPayloadType_SPANS:
+			p.tracesRecordBuilder.ResetDictionaries()
+		default:
+			// Related payload types (e.g. RESOURCE_ATTRS) can be shared by
+			// several signals.
+			p.metricsBuilder.RelatedData().ResetDictionaries(payloadType)
+			p.logsBuilder.RelatedData().ResetDictionaries(payloadType)
+			p.tracesBuilder.RelatedData().ResetDictionaries(payloadType)
+		}
+	}
+	if reset {
+		p.stats.StreamResetsPerformed++
+		p.telemetry.reportStats(context.Background(), p.stats)
+	}
 	return nil
 }
 
+// ReleaseBatch returns the buffers referenced by the payloads of a
+// BatchArrowRecords produced by this producer to the pool of buffers. The
+// records of the payloads are set to nil.
+//
+// This method is optional (the buffers not released are garbage collected),
+// but it reduces the allocations at high throughput. The batch MUST NOT be
+// used after this call, e.g. a batch sent on a gRPC stream must only be
+// released once its status has been received, as gRPC doesn't allow the
+// modification of a message after SendMsg. This method can be called
+// concurrently with the other methods of the producer.
+func (p *Producer) ReleaseBatch(bar *colarspb.BatchArrowRecords) {
+	for _, payload := range bar.ArrowPayloads {
+		p.buffers.put(payload.Record)
+		payload.Record = nil
+	}
+}
+
+// SubStreams returns a snapshot of the state of the current sub-streams of
+// the producer. This method can be called concurrently with the other
+// methods of the producer.
+func (p *Producer) SubStreams() []SubStreamInfo {
+	return p.subStreams.snapshot()
+}
+
 // GetAndResetStats returns the stats and resets them.
 func (p *Producer) GetAndResetStats() pstats.ProducerStats {
+	p.telemetry.resetStats()
 	return p.stats.GetAndReset()
 }
 
+// GetAndResetReports returns the compression reports of the batches produced
+// since the last call, one per signal, and resets them. The reports are only
+// built when the stats are enabled (see config.WithStats), nil otherwise.
+func (p *Producer) GetAndResetReports() []Report {
+	if p.report == nil {
+		return nil
+	}
+	return p.report.reports(true)
+}
+
 // Produce takes a slice of RecordMessage and returns the corresponding BatchArrowRecords protobuf message.
+//
+// When the encoding concurrency of the producer is greater than 1, the records
+// are IPC encoded concurrently. The order of the payloads always follows the
+// order of the record messages.
 func (p *Producer) Produce(rms []*record_message.RecordMessage) (*colarspb.BatchArrowRecords, error) {
+	var oapl []*colarspb.ArrowPayload
+	var sps []*streamProducer
+	var err error
+
+	if p.encodingConcurrency > 1 {
+		oapl, sps, err = p.encodeConcurrently(rms)
+	} else {
+		oapl, sps, err = p.encode(rms)
+	}
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+
+	// The sub-streams reaching the limits defined by the reset policy are
+	// reset once the whole batch has been encoded.
+	var toReset []record_message.PayloadType
+	for i, sp := range sps {
+		if p.resetRequired(sp, rms[i].Record()) {
+			toReset = append(toReset, sp.payloadType)
+		}
+	}
+	p.report.update(rms, oapl)
+	for i, rm := range rms {
+		p.telemetry.reportRecord(context.Background(), rm.PayloadType(), rm.Record(), len(oapl[i].Record))
+		p.subStreams.update(sps[i].subStreamId, rm.PayloadType(), rm.Record(), sps[i].batchCount, sps[i].lastProduction)
+		rm.Record().Release()
+	}
+	if len(toReset) > 0 {
+		if err := p.ResetStreams(toReset...); err != nil {
+			return nil, werror.Wrap(err)
+		}
+	}
+
+	var dict *colarspb.CompressionDictionary
+	if p.dictionaryCompressor != nil {
+		if dict, err = p.dictionaryCompressor.compress(oapl, &p.buffers); err != nil {
+			return nil, werror.Wrap(err)
+		}
+	}
+
+	batchId := fmt.Sprintf("%d", p.batchId)
+	p.batchId++
+
+	return &colarspb.BatchArrowRecords{
+		BatchId:               batchId,
+		ArrowPayloads:         oapl,
+		CompressionDictionary: dict,
+	}, nil
+}
+
+// encode sequentially encodes the record messages on their respective
+// sub-streams.
+func (p *Producer) encode(rms []*record_message.RecordMessage) ([]*colarspb.ArrowPayload, []*streamProducer, error) {
 	oapl := make([]*colarspb.ArrowPayload, len(rms))
+	sps := make([]*streamProducer, len(rms))
 
 	for i, rm := range rms {
-		err := func() error {
+		sp, err := p.streamProducer(rm)
+		if err != nil {
+			releaseRecords(rms)
+			return nil, nil, werror.Wrap(err)
+		}
+		sps[i] = sp
+
+		oapl[i], err = p.encodeRecord(sp, rm)
+		if err != nil {
+			releaseRecords(rms)
+			return nil, nil, werror.Wrap(err)
+		}
+	}
+
+	return oapl, sps, nil
+}
+
+// encodeConcurrently encodes the record messages on their respective
+// sub-streams using up to `encodingConcurrency` goroutines. Each sub-stream
+// has its own IPC writer, so the records of a batch can be encoded
+// independently.
+func (p *Producer) encodeConcurrently(rms []*record_message.RecordMessage) ([]*colarspb.ArrowPayload, []*streamProducer, error) {
+	oapl := make([]*colarspb.ArrowPayload, len(rms))
+	sps := make([]*streamProducer, len(rms))
+	errs := make([]error, len(rms))
+
+	// The stream producers are resolved (or created) sequentially to keep
+	// the sub-stream ids deterministic.
+	for i, rm := range rms {
+		sp, err := p.streamProducer(rm)
+		if err != nil {
+			releaseRecords(rms)
+			return nil, nil, werror.Wrap(err)
+		}
+		sps[i] = sp
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, p.encodingConcurrency)
+
+	for i, rm := range rms {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, rm *record_message.RecordMessage) {
 			defer func() {
-				rm.Record().Release()
+				<-sem
+				wg.Done()
 			}()
+			oapl[i], errs[i] = p.encodeRecord(sps[i], rm)
+		}(i, rm)
+	}
+	wg.Wait()
 
-			// Retrieves (or creates) the stream Producer for the sub-stream id defined in the RecordMessage.
-			sp := p.streamProducers[rm.SubStreamId()]
-			if sp == nil {
-				// cleanup previous stream producer if any that have the same
-				// PayloadType. The reasoning is that if we have a new
-				// sub-stream ID (i.e. schema change) we should no longer use
-				// the previous stream producer for this PayloadType as schema
-				// changes are only additive.
-				// This will release the resources associated with the previous
-				// stream producer.
-				for ssID, sp := range p.streamProducers {
-					if sp.payloadType == rm.PayloadType() {
-						if err := sp.ipcWriter.Close(); err != nil {
-							return werror.Wrap(err)
-						}
-						p.stats.StreamProducersClosed++
-						delete(p.streamProducers, ssID)
-					}
-				}
-
-				var buf bytes.Buffer
-				sp = &streamProducer{
-					output:      buf,
-					subStreamId: fmt.Sprintf("%d", p.nextSubStreamId),
-					payloadType: rm.PayloadType(),
-				}
-				p.streamProducers[rm.SubStreamId()] = sp
-				p.nextSubStreamId++
-				p.stats.StreamProducersCreated++
-			}
+	for _, err := range errs {
+		if err != nil {
+			releaseRecords(rms)
+			return nil, nil, werror.Wrap(err)
+		}
+	}
 
-			sp.lastProduction = time.Now()
-			sp.schema = rm.Record().Schema()
+	return oapl, sps, nil
+}
 
-			if sp.ipcWriter == nil {
-				options := []ipc.Option{
-					ipc.WithAllocator(p.pool), // use allocator of the `Producer`
-					ipc.WithSchema(rm.Record().Schema()),
-					ipc.WithDictionaryDeltas(true), // enable dictionary deltas
-				}
-				if p.zstd {
-					options = append(options, ipc.WithZstd())
+// streamProducer retrieves (or creates) the stream producer for the sub-stream
+// id defined in the RecordMessage.
+func (p *Producer) streamProducer(rm *record_message.RecordMessage) (*streamProducer, error) {
+	sp := p.streamProducers[rm.SubStreamId()]
+	if sp == nil {
+		// cleanup previous stream producer if any that have the same
+		// PayloadType. The reasoning is that if we have a new
+		// sub-stream ID (i.e. schema change) we should no longer use
+		// the previous stream producer for this PayloadType as schema
+		// changes are only additive.
+		// This will release the resources associated with the previous
+		// stream producer.
+		for ssID, sp := range p.streamProducers {
+			if sp.payloadType == rm.PayloadType() {
+				if err := sp.ipcWriter.Close(); err != nil {
+					return nil, werror.Wrap(err)
 				}
-				sp.ipcWriter = ipc.NewWriter(&sp.output, options...)
-			}
-
-			if p.observer != nil {
-				p.observer.OnRecord(rm.Record(), rm.PayloadType())
+				p.stats.StreamProducersClosed++
+				delete(p.streamProducers, ssID)
+				p.subStreams.remove(sp.subStreamId)
 			}
+		}
 
-			err := sp.ipcWriter.Write(rm.Record())
-			if err != nil {
-				return werror.Wrap(err)
-			}
-			outputBuf := sp.output.Bytes()
-			buf := make([]byte, len(outputBuf))
-			copy(buf, outputBuf)
+		sp = &streamProducer{
+			output:      newPayloadBuffer(&p.buffers),
+			subStreamId: fmt.Sprintf("%d", p.nextSubStreamId),
+			payloadType: rm.PayloadType(),
+		}
+		p.streamProducers[rm.SubStreamId()] = sp
+		p.nextSubStreamId++
+		p.stats.StreamProducersCreated++
+	}
 
-			if p.stats.SchemaStatsEnabled {
-				// ToDo Create option to display this info
-				fmt.Printf("Record %q -> %d bytes\n", rm.PayloadType().String(), len(buf))
-			}
+	if p.observer != nil {
+		p.observer.OnRecord(rm.Record(), rm.PayloadType())
+	}
 
-			// Reset the buffer
-			sp.output.Reset()
+	return sp, nil
+}
 
-			oapl[i] = &colarspb.ArrowPayload{
-				SubStreamId: sp.subStreamId,
-				Type:        rm.PayloadType(),
-				Record:      buf,
-			}
-			return nil
-		}()
-		if err != nil {
-			return nil, werror.Wrap(err)
+// encodeRecord writes the record of the RecordMessage on the IPC writer of the
+// given stream producer and returns the corresponding ArrowPayload.
+//
+// This method only mutates the state of the stream producer, it is safe to
+// call it concurrently for distinct stream producers.
+func (p *Producer) encodeRecord(sp *streamProducer, rm *record_message.RecordMessage) (*colarspb.ArrowPayload, error) {
+	sp.lastProduction = time.Now()
+	sp.schema = rm.Record().Schema()
+
+	if sp.ipcWriter == nil {
+		options := []ipc.Option{
+			ipc.WithAllocator(p.pool), // use allocator of the `Producer`
+			ipc.WithSchema(rm.Record().Schema()),
+			ipc.WithDictionaryDeltas(true), // enable dictionary deltas
 		}
+		options = append(options, p.ipcCodec...)
+		sp.ipcWriter = ipc.NewWriter(sp.output, options...)
 	}
 
-	batchId := fmt.Sprintf("%d", p.batchId)
-	p.batchId++
+	err := sp.ipcWriter.Write(rm.Record())
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+	sp.batchCount++
 
-	return &colarspb.BatchArrowRecords{
-		BatchId:       batchId,
-		ArrowPayloads: oapl,
+	// The encoded record is detached from the output of the stream producer
+	// (no copy). The buffer is returned to the pool by ReleaseBatch.
+	buf := sp.output.detach()
+
+	return &colarspb.ArrowPayload{
+		SubStreamId: sp.subStreamId,
+		Type:        rm.PayloadType(),
+		Record:      buf,
 	}, nil
 }
 
+func releaseRecords(rms []*record_message.RecordMessage) {
+	for _, rm := range rms {
+		rm.Record().Release()
+	}
+}
+
+// resetRequired returns true if the given stream producer must be reset
+// according to the reset policy of the producer.
+func (p *Producer) resetRequired(sp *streamProducer, record arrow.Record) bool {
+	if p.resetAfterBatches > 0 && sp.batchCount >= p.resetAfterBatches {
+		return true
+	}
+	if p.resetAfterDictionaryBytes > 0 && dictionaryBytes(record) >= p.resetAfterDictionaryBytes {
+		return true
+	}
+	return false
+}
+
+// dictionaryBytes returns the size of the dictionaries referenced by the
+// columns of a record. Dictionary builders accumulate values across records,
+// so this size reflects the dictionaries maintained by the IPC writer.
+func dictionaryBytes(record arrow.Record) (size uint64) {
+	for _, column := range record.Columns() {
+		size += arrayDictionaryBytes(column)
+	}
+	return
+}
+
+func arrayDictionaryBytes(arr arrow.Array) (size uint64) {
+	switch a := arr.(type) {
+	case *array.Dictionary:
+		for _, buf := range a.Dictionary().Data().Buffers() {
+			if buf != nil {
+				size += uint64(buf.Len())
+			}
+		}
+	case *array.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			size += arrayDictionaryBytes(a.Field(i))
+		}
+	case *array.Map:
+		size += arrayDictionaryBytes(a.ListValues())
+	case *array.List:
+		size += arrayDictionaryBytes(a.ListValues())
+	case *array.SparseUnion:
+		for i := 0; i < a.NumFields(); i++ {
+			size += arrayDictionaryBytes(a.Field(i))
+		}
+	}
+	return
+}
+
 func (p *Producer) ShowStats() {
 	type TimeSchema struct {
 		time   time.Time
@@ -445,6 +825,22 @@ func (p *Producer) ShowStats() {
 	}
 	println("------")
 	p.tracesBuilder.ShowSchema()
+
+	if a := p.metricsBuilder.Analyzer(); a != nil && a.MetricCount > 0 {
+		a.ShowStats("")
+	}
+	if a := p.logsBuilder.Analyzer(); a != nil && a.LogRecordCount > 0 {
+		a.ShowStats("")
+	}
+	if a := p.tracesBuilder.Analyzer(); a != nil && a.TraceCount > 0 {
+		a.ShowStats("")
+	}
+	if p.report != nil {
+		println("\n== Compression Reports =========================================================================")
+		for _, report := range p.report.reports(false) {
+			report.ShowStats("")
+		}
+	}
 }
 
 func recordBuilder[T pmetric.Metrics | plog.Logs | ptrace.Traces](builder func() (acommon.EntityBuilder[T], error), entity T) (record arrow.Record, err error) {
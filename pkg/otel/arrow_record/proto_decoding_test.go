@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+
+	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/assert"
+)
+
+// otlpRequest is implemented by the OTLP export requests of the three
+// signals.
+type otlpRequest interface {
+	json.Marshaler
+	MarshalProto() ([]byte, error)
+	UnmarshalProto([]byte) error
+}
+
+// TestProtoDecoding checks that the protobuf messages decoded directly from
+// the Arrow records are identical to the messages marshaled from the pdata
+// decoded by the consumer, and equivalent to the original requests.
+func TestProtoDecoding(t *testing.T) {
+	t.Parallel()
+
+	ent := datagen.NewTestEntropy(12345)
+	tracesGen := datagen.NewTracesGenerator(ent, ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes())
+	logsGen := datagen.NewLogsGenerator(ent, ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes())
+	metricsGen := datagen.NewMetricsGenerator(ent, ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes())
+
+	tests := []struct {
+		name string
+		// generate returns the next request to encode.
+		generate func() otlpRequest
+		// produce encodes the request into a BatchArrowRecords.
+		produce func(*Producer, otlpRequest) (*colarspb.BatchArrowRecords, error)
+		// decode decodes the batch into pdata and marshals the result.
+		decode func(*Consumer, *colarspb.BatchArrowRecords) ([]otlpRequest, error)
+		// decodeProto decodes the batch directly into protobuf messages.
+		decodeProto func(*Consumer, *colarspb.BatchArrowRecords) ([][]byte, error)
+		newRequest  func() otlpRequest
+	}{
+		{
+			name: "traces",
+			generate: func() otlpRequest {
+				return ptraceotlp.NewExportRequestFromTraces(tracesGen.Generate(20, time.Minute))
+			},
+			produce: func(p *Producer, req otlpRequest) (*colarspb.BatchArrowRecords, error) {
+				return p.BatchArrowRecordsFromTraces(req.(ptraceotlp.ExportRequest).Traces())
+			},
+			decode: func(c *Consumer, bar *colarspb.BatchArrowRecords) ([]otlpRequest, error) {
+				traces, err := c.TracesFrom(bar)
+				reqs := make([]otlpRequest, 0, len(traces))
+				for _, t := range traces {
+					reqs = append(reqs, ptraceotlp.NewExportRequestFromTraces(t))
+				}
+				return reqs, err
+			},
+			decodeProto: (*Consumer).TracesProtoFrom,
+			newRequest:  func() otlpRequest { return ptraceotlp.NewExportRequest() },
+		},
+		{
+			name: "logs",
+			generate: func() otlpRequest {
+				return plogotlp.NewExportRequestFromLogs(logsGen.Generate(20, time.Minute))
+			},
+			produce: func(p *Producer, req otlpRequest) (*colarspb.BatchArrowRecords, error) {
+				return p.BatchArrowRecordsFromLogs(req.(plogotlp.ExportRequest).Logs())
+			},
+			decode: func(c *Consumer, bar *colarspb.BatchArrowRecords) ([]otlpRequest, error) {
+				logs, err := c.LogsFrom(bar)
+				reqs := make([]otlpRequest, 0, len(logs))
+				for _, l := range logs {
+					reqs = append(reqs, plogotlp.NewExportRequestFromLogs(l))
+				}
+				return reqs, err
+			},
+			decodeProto: (*Consumer).LogsProtoFrom,
+			newRequest:  func() otlpRequest { return plogotlp.NewExportRequest() },
+		},
+		{
+			name: "metrics",
+			generate: func() otlpRequest {
+				return pmetricotlp.NewExportRequestFromMetrics(metricsGen.GenerateAllKindOfMetrics(10, time.Minute))
+			},
+			produce: func(p *Producer, req otlpRequest) (*colarspb.BatchArrowRecords, error) {
+				return p.BatchArrowRecordsFromMetrics(req.(pmetricotlp.ExportRequest).Metrics())
+			},
+			decode: func(c *Consumer, bar *colarspb.BatchArrowRecords) ([]otlpRequest, error) {
+				metrics, err := c.MetricsFrom(bar)
+				reqs := make([]otlpRequest, 0, len(metrics))
+				for _, m := range metrics {
+					reqs = append(reqs, pmetricotlp.NewExportRequestFromMetrics(m))
+				}
+				return reqs, err
+			},
+			decodeProto: (*Consumer).MetricsProtoFrom,
+			newRequest:  func() otlpRequest { return pmetricotlp.NewExportRequest() },
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			producer := NewProducer()
+			pdataConsumer := NewConsumer()
+			protoConsumer := NewConsumer()
+			defer func() {
+				require.NoError(t, producer.Close())
+				require.NoError(t, pdataConsumer.Close())
+				require.NoError(t, protoConsumer.Close())
+			}()
+
+			// Two batches, the second one uses the dictionaries of the first.
+			for i := 0; i < 2; i++ {
+				req := tc.generate()
+
+				bar, err := tc.produce(producer, req)
+				require.NoError(t, err)
+
+				received, err := tc.decode(pdataConsumer, bar)
+				require.NoError(t, err)
+				require.Equal(t, 1, len(received))
+				expected, err := received[0].MarshalProto()
+				require.NoError(t, err)
+
+				buffers, err := tc.decodeProto(protoConsumer, bar)
+				require.NoError(t, err)
+				require.Equal(t, 1, len(buffers))
+				require.Equal(t, expected, buffers[0])
+
+				actual := tc.newRequest()
+				require.NoError(t, actual.UnmarshalProto(buffers[0]))
+				assert.Equiv(t, []json.Marshaler{req}, []json.Marshaler{actual})
+			}
+		})
+	}
+}
@@ -0,0 +1,731 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+// Compression "explain" reports of the batches produced by a Producer (see
+// config.WithStats and Producer.GetAndResetReports).
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/axiomhq/hyperloglog"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	acommon "github.com/f5/otel-arrow-adapter/pkg/otel/common/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/constants"
+	"github.com/f5/otel-arrow-adapter/pkg/record_message"
+)
+
+// Kinds of recommendations of a Report.
+const (
+	// RecommendNoDictionary is recommended for a dictionary-encoded column
+	// whose values are rarely repeated, most values add a new entry to the
+	// dictionary and the indices are pure overhead.
+	RecommendNoDictionary = "no_dictionary"
+	// RecommendDictionary is recommended for a string or binary column that
+	// is not dictionary-encoded while its values are often repeated.
+	RecommendDictionary = "dictionary"
+	// RecommendHighCardinalityAttribute is recommended for an attribute whose
+	// values are almost unique (e.g. request IDs), such attributes defeat the
+	// dictionaries of the attribute values.
+	RecommendHighCardinalityAttribute = "high_cardinality_attribute"
+)
+
+const (
+	// reportMinValues is the minimum number of values of a column (or
+	// attribute) for a recommendation.
+	reportMinValues = 100
+	// reportTopContributors is the number of columns of
+	// Report.TopContributors.
+	reportTopContributors = 10
+	// noDictionaryMaxReuse is the ratio of the values of a dictionary-encoded
+	// column found in the dictionary below which RecommendNoDictionary is
+	// recommended.
+	noDictionaryMaxReuse = 0.2
+	// dictionaryMaxCardinality is the cardinality ratio of a plain string or
+	// binary column below which RecommendDictionary is recommended.
+	dictionaryMaxCardinality = 0.1
+	// highCardinalityAttributeMin is the cardinality ratio of the values of
+	// an attribute above which RecommendHighCardinalityAttribute is
+	// recommended.
+	highCardinalityAttributeMin = 0.9
+)
+
+type (
+	// Report explains the size of the batches of a signal produced since the
+	// last call to Producer.GetAndResetReports.
+	Report struct {
+		// Signal of the batches: "metrics", "logs" or "traces".
+		Signal string `json:"signal"`
+		// Batches is the number of batches produced.
+		Batches uint64 `json:"batches"`
+		// EncodedBytes is the size of the Arrow payloads of the batches (IPC
+		// encoded, and compressed if the compression is enabled).
+		EncodedBytes uint64 `json:"encoded_bytes"`
+		// Payloads describes the payloads of each type, in the order of the
+		// payload types.
+		Payloads []PayloadReport `json:"payloads"`
+		// Columns describes the leaf columns of each payload type, sorted by
+		// payload type and path.
+		Columns []ColumnReport `json:"columns"`
+		// AttributeKeys is the distribution of the attribute keys of each
+		// attribute payload type, sorted by payload type and decreasing
+		// count.
+		AttributeKeys []AttributeKeyReport `json:"attribute_keys"`
+		// TopContributors are the largest columns in decreasing order of
+		// size.
+		TopContributors []Contributor `json:"top_contributors"`
+		// Recommendations to improve the compression ratio.
+		Recommendations []Recommendation `json:"recommendations"`
+	}
+
+	// PayloadReport describes the records of a payload type.
+	PayloadReport struct {
+		PayloadType string `json:"payload_type"`
+		// Records is the number of records produced.
+		Records uint64 `json:"records"`
+		// Rows is the total number of rows of the records.
+		Rows uint64 `json:"rows"`
+		// EncodedBytes is the size of the Arrow payloads.
+		EncodedBytes uint64 `json:"encoded_bytes"`
+		// Share is the share of the payloads in Report.EncodedBytes.
+		Share float64 `json:"share"`
+	}
+
+	// ColumnReport describes a leaf column of a payload type. The values of
+	// the nested columns (lists, maps, unions) are counted as rows.
+	ColumnReport struct {
+		PayloadType string `json:"payload_type"`
+		// Path of the column, e.g. "body.str".
+		Path string `json:"path"`
+		// DataType is the Arrow type of the column in the last record.
+		DataType string `json:"data_type"`
+		Rows     uint64 `json:"rows"`
+		Nulls    uint64 `json:"nulls"`
+		// NullRatio is Nulls / Rows.
+		NullRatio float64 `json:"null_ratio"`
+		// Cardinality is an estimate of the number of distinct non-null
+		// values.
+		Cardinality uint64 `json:"cardinality"`
+		// CardinalityRatio is Cardinality / (Rows - Nulls).
+		CardinalityRatio float64 `json:"cardinality_ratio"`
+		// Dictionary describes the dictionary encoding of the column, nil if
+		// the column has never been dictionary-encoded.
+		Dictionary *DictionaryReport `json:"dictionary,omitempty"`
+		// Bytes is the size of the Arrow buffers of the column before IPC
+		// encoding and compression, including the new dictionary entries.
+		Bytes uint64 `json:"bytes"`
+	}
+
+	// DictionaryReport describes the effectiveness of the dictionary of a
+	// column, over the records where the column is dictionary-encoded.
+	DictionaryReport struct {
+		// IndexType is the type of the indices in the last dictionary-encoded
+		// record.
+		IndexType string `json:"index_type"`
+		// Entries is the number of entries added to the dictionary, i.e. sent
+		// as dictionary deltas.
+		Entries uint64 `json:"entries"`
+		// Values is the number of non-null values encoded with the
+		// dictionary.
+		Values uint64 `json:"values"`
+		// Reuse is the ratio of the values found in the dictionary, i.e.
+		// 1 - Entries / Values.
+		Reuse float64 `json:"reuse"`
+	}
+
+	// AttributeKeyReport describes the values of an attribute key in an
+	// attribute payload type.
+	AttributeKeyReport struct {
+		PayloadType string `json:"payload_type"`
+		Key         string `json:"key"`
+		// Count is the number of attributes with this key.
+		Count uint64 `json:"count"`
+		// Share is the share of the attributes of the payload type with this
+		// key.
+		Share float64 `json:"share"`
+		// Types are the types of the values, e.g. "Str".
+		Types []string `json:"types"`
+		// Cardinality is an estimate of the number of distinct values.
+		Cardinality uint64 `json:"cardinality"`
+		// CardinalityRatio is Cardinality / Count.
+		CardinalityRatio float64 `json:"cardinality_ratio"`
+	}
+
+	// Contributor is a column and its share of the size of the columns of a
+	// signal.
+	Contributor struct {
+		PayloadType string  `json:"payload_type"`
+		Path        string  `json:"path"`
+		Bytes       uint64  `json:"bytes"`
+		Share       float64 `json:"share"`
+	}
+
+	// Recommendation is a change of the encoding of a column, or of the data
+	// of an attribute, expected to improve the compression ratio.
+	Recommendation struct {
+		// Kind of recommendation, e.g. RecommendNoDictionary.
+		Kind        string `json:"kind"`
+		PayloadType string `json:"payload_type"`
+		// Path of the column, empty for an attribute.
+		Path string `json:"path,omitempty"`
+		// Key of the attribute, empty for a column.
+		Key    string `json:"key,omitempty"`
+		Reason string `json:"reason"`
+	}
+)
+
+// reportBuilder accumulates the statistics of the reports of the batches
+// produced by a Producer, one report per signal. The statistics are updated
+// by the encoding goroutine and read by any goroutine calling
+// GetAndResetReports.
+type reportBuilder struct {
+	mu      sync.Mutex
+	signals map[string]*signalStats
+}
+
+type (
+	signalStats struct {
+		batches       uint64
+		payloads      map[record_message.PayloadType]*payloadStats
+		columns       map[columnID]*columnStats
+		attributeKeys map[columnID]*attributeKeyStats
+	}
+
+	// columnID identifies a column (or an attribute key) of a payload type.
+	columnID struct {
+		payloadType record_message.PayloadType
+		path        string
+	}
+
+	payloadStats struct {
+		records      uint64
+		rows         uint64
+		encodedBytes uint64
+	}
+
+	columnStats struct {
+		dataType   string
+		rows       uint64
+		nulls      uint64
+		bytes      uint64
+		distinct   *hyperloglog.Sketch
+		plainBytes bool // string or binary values not dictionary-encoded
+		dictionary *dictionaryStats
+	}
+
+	dictionaryStats struct {
+		indexType string
+		entries   uint64
+		values    uint64
+		// Number of entries of the dictionary in the last record, the
+		// dictionaries are accumulated across the records of a sub-stream.
+		lastLen int
+	}
+
+	attributeKeyStats struct {
+		count    uint64
+		types    map[pcommon.ValueType]bool
+		distinct *hyperloglog.Sketch
+	}
+)
+
+func newReportBuilder() *reportBuilder {
+	return &reportBuilder{signals: make(map[string]*signalStats)}
+}
+
+// update accumulates the statistics of a batch of records and of their
+// payloads. A nil reportBuilder is valid.
+func (r *reportBuilder) update(rms []*record_message.RecordMessage, payloads []*colarspb.ArrowPayload) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	signal := signalOf(rms)
+	s := r.signals[signal]
+	if s == nil {
+		s = &signalStats{
+			payloads:      make(map[record_message.PayloadType]*payloadStats),
+			columns:       make(map[columnID]*columnStats),
+			attributeKeys: make(map[columnID]*attributeKeyStats),
+		}
+		r.signals[signal] = s
+	}
+	s.batches++
+
+	var buf []byte
+	for i, rm := range rms {
+		payloadType := rm.PayloadType()
+		record := rm.Record()
+
+		ps := s.payloads[payloadType]
+		if ps == nil {
+			ps = &payloadStats{}
+			s.payloads[payloadType] = ps
+		}
+		ps.records++
+		ps.rows += uint64(record.NumRows())
+		ps.encodedBytes += uint64(len(payloads[i].Record))
+
+		fields := record.Schema().Fields()
+		for j, column := range record.Columns() {
+			walkColumns(fields[j].Name, column, func(path string, arr arrow.Array) {
+				id := columnID{payloadType: payloadType, path: path}
+				cs := s.columns[id]
+				if cs == nil {
+					cs = &columnStats{distinct: hyperloglog.New16()}
+					s.columns[id] = cs
+				}
+				buf = cs.update(arr, buf)
+			})
+		}
+
+		if strings.HasSuffix(payloadType.String(), "_ATTRS") {
+			buf = s.updateAttributeKeys(payloadType, record, buf)
+		}
+	}
+}
+
+func (c *columnStats) update(arr arrow.Array, buf []byte) []byte {
+	c.dataType = arr.DataType().String()
+	c.rows += uint64(arr.Len())
+	c.nulls += uint64(arr.NullN())
+	c.bytes += buffersBytes(arr.Data())
+
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			continue
+		}
+		buf = appendValue(buf[:0], arr, i)
+		c.distinct.Insert(buf)
+	}
+
+	dict, ok := arr.(*array.Dictionary)
+	if !ok {
+		switch arr.DataType().ID() {
+		case arrow.STRING, arrow.BINARY:
+			c.plainBytes = true
+		}
+		return buf
+	}
+
+	if c.dictionary == nil {
+		c.dictionary = &dictionaryStats{}
+	}
+	d := c.dictionary
+	d.indexType = dict.DataType().(*arrow.DictionaryType).IndexType.String()
+	d.values += uint64(dict.Len() - dict.NullN())
+
+	// Only the new entries of the dictionary are sent (dictionary deltas),
+	// a smaller dictionary comes from a new (or reset) sub-stream.
+	dictLen := dict.Dictionary().Len()
+	newEntries := dictLen
+	if dictLen >= d.lastLen {
+		newEntries = dictLen - d.lastLen
+	}
+	d.lastLen = dictLen
+	d.entries += uint64(newEntries)
+	if dictLen > 0 {
+		c.bytes += buffersBytes(dict.Dictionary().Data()) * uint64(newEntries) / uint64(dictLen)
+	}
+	return buf
+}
+
+// updateAttributeKeys accumulates the keys of the attributes of an attribute
+// record, and the distinct values of each key.
+func (s *signalStats) updateAttributeKeys(payloadType record_message.PayloadType, record arrow.Record, buf []byte) []byte {
+	columns := make(map[string]arrow.Array)
+	for i, field := range record.Schema().Fields() {
+		columns[field.Name] = record.Column(i)
+	}
+	keys, types := columns[constants.AttributeKey], columns[constants.AttributeType]
+	if keys == nil || types == nil {
+		return buf
+	}
+	typeCodes, ok := types.(*array.Uint8)
+	if !ok {
+		return buf
+	}
+
+	for i := 0; i < keys.Len(); i++ {
+		key := string(appendValue(buf[:0], keys, i))
+		id := columnID{payloadType: payloadType, path: key}
+		ks := s.attributeKeys[id]
+		if ks == nil {
+			ks = &attributeKeyStats{types: make(map[pcommon.ValueType]bool), distinct: hyperloglog.New16()}
+			s.attributeKeys[id] = ks
+		}
+		ks.count++
+
+		valueType := pcommon.ValueType(typeCodes.Value(i))
+		ks.types[valueType] = true
+		if values := columns[attributeValueColumn(valueType)]; values != nil && values.IsValid(i) {
+			buf = appendValue(append(buf[:0], byte(valueType)), values, i)
+			ks.distinct.Insert(buf)
+		}
+	}
+	return buf
+}
+
+// attributeValueColumn returns the name of the column of the attribute values
+// of the given type.
+func attributeValueColumn(valueType pcommon.ValueType) string {
+	switch valueType {
+	case pcommon.ValueTypeStr:
+		return constants.AttributeStr
+	case pcommon.ValueTypeInt:
+		return constants.AttributeInt
+	case pcommon.ValueTypeDouble:
+		return constants.AttributeDouble
+	case pcommon.ValueTypeBool:
+		return constants.AttributeBool
+	case pcommon.ValueTypeBytes:
+		return constants.AttributeBytes
+	case pcommon.ValueTypeSlice, pcommon.ValueTypeMap:
+		return constants.AttributeSer
+	default:
+		return ""
+	}
+}
+
+// reports returns the reports of the batches produced so far, sorted by
+// signal, and forgets these batches if reset is true.
+func (r *reportBuilder) reports(reset bool) []Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reports := make([]Report, 0, len(r.signals))
+	for signal, s := range r.signals {
+		reports = append(reports, s.report(signal))
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Signal < reports[j].Signal })
+
+	if reset {
+		r.signals = make(map[string]*signalStats)
+	}
+	return reports
+}
+
+func (s *signalStats) report(signal string) Report {
+	report := Report{Signal: signal, Batches: s.batches}
+
+	for _, ps := range s.payloads {
+		report.EncodedBytes += ps.encodedBytes
+	}
+	for payloadType, ps := range s.payloads {
+		report.Payloads = append(report.Payloads, PayloadReport{
+			PayloadType:  payloadType.String(),
+			Records:      ps.records,
+			Rows:         ps.rows,
+			EncodedBytes: ps.encodedBytes,
+			Share:        ratio(ps.encodedBytes, report.EncodedBytes),
+		})
+	}
+	sort.Slice(report.Payloads, func(i, j int) bool {
+		return payloadTypeOf(report.Payloads[i].PayloadType) < payloadTypeOf(report.Payloads[j].PayloadType)
+	})
+
+	var columnBytes uint64
+	for id, cs := range s.columns {
+		values := cs.rows - cs.nulls
+		// The estimate of the cardinality may exceed the number of values.
+		cardinality := cs.distinct.Estimate()
+		if cardinality > values {
+			cardinality = values
+		}
+		column := ColumnReport{
+			PayloadType:      id.payloadType.String(),
+			Path:             id.path,
+			DataType:         cs.dataType,
+			Rows:             cs.rows,
+			Nulls:            cs.nulls,
+			NullRatio:        ratio(cs.nulls, cs.rows),
+			Cardinality:      cardinality,
+			CardinalityRatio: ratio(cardinality, values),
+			Bytes:            cs.bytes,
+		}
+		if d := cs.dictionary; d != nil {
+			reuse := 0.0
+			if d.values > 0 && d.entries < d.values {
+				reuse = 1 - ratio(d.entries, d.values)
+			}
+			column.Dictionary = &DictionaryReport{
+				IndexType: d.indexType,
+				Entries:   d.entries,
+				Values:    d.values,
+				Reuse:     reuse,
+			}
+		}
+		report.Columns = append(report.Columns, column)
+		columnBytes += cs.bytes
+
+		switch {
+		case column.Dictionary != nil && column.Dictionary.Values >= reportMinValues && column.Dictionary.Reuse < noDictionaryMaxReuse:
+			report.Recommendations = append(report.Recommendations, Recommendation{
+				Kind:        RecommendNoDictionary,
+				PayloadType: column.PayloadType,
+				Path:        column.Path,
+				Reason: fmt.Sprintf("only %.1f%% of the values are found in the dictionary (%d entries for %d values)",
+					100*column.Dictionary.Reuse, column.Dictionary.Entries, column.Dictionary.Values),
+			})
+		case column.Dictionary == nil && cs.plainBytes && values >= reportMinValues && column.CardinalityRatio < dictionaryMaxCardinality:
+			report.Recommendations = append(report.Recommendations, Recommendation{
+				Kind:        RecommendDictionary,
+				PayloadType: column.PayloadType,
+				Path:        column.Path,
+				Reason:      fmt.Sprintf("about %d distinct values for %d values", cardinality, values),
+			})
+		}
+	}
+	sort.Slice(report.Columns, func(i, j int) bool {
+		return lessColumn(report.Columns[i].PayloadType, report.Columns[i].Path, report.Columns[j].PayloadType, report.Columns[j].Path)
+	})
+
+	contributors := make([]Contributor, 0, len(report.Columns))
+	for _, column := range report.Columns {
+		contributors = append(contributors, Contributor{
+			PayloadType: column.PayloadType,
+			Path:        column.Path,
+			Bytes:       column.Bytes,
+			Share:       ratio(column.Bytes, columnBytes),
+		})
+	}
+	sort.SliceStable(contributors, func(i, j int) bool { return contributors[i].Bytes > contributors[j].Bytes })
+	if len(contributors) > reportTopContributors {
+		contributors = contributors[:reportTopContributors]
+	}
+	report.TopContributors = contributors
+
+	attrsCount := make(map[record_message.PayloadType]uint64)
+	for id, ks := range s.attributeKeys {
+		attrsCount[id.payloadType] += ks.count
+	}
+	for id, ks := range s.attributeKeys {
+		cardinality := ks.distinct.Estimate()
+		if cardinality > ks.count {
+			cardinality = ks.count
+		}
+		types := make([]string, 0, len(ks.types))
+		for valueType := range ks.types {
+			types = append(types, valueType.String())
+		}
+		sort.Strings(types)
+
+		key := AttributeKeyReport{
+			PayloadType:      id.payloadType.String(),
+			Key:              id.path,
+			Count:            ks.count,
+			Share:            ratio(ks.count, attrsCount[id.payloadType]),
+			Types:            types,
+			Cardinality:      cardinality,
+			CardinalityRatio: ratio(cardinality, ks.count),
+		}
+		report.AttributeKeys = append(report.AttributeKeys, key)
+
+		if key.Count >= reportMinValues && key.CardinalityRatio > highCardinalityAttributeMin {
+			report.Recommendations = append(report.Recommendations, Recommendation{
+				Kind:        RecommendHighCardinalityAttribute,
+				PayloadType: key.PayloadType,
+				Key:         key.Key,
+				Reason:      fmt.Sprintf("about %d distinct values for %d attributes", cardinality, key.Count),
+			})
+		}
+	}
+	sort.Slice(report.AttributeKeys, func(i, j int) bool {
+		ki, kj := report.AttributeKeys[i], report.AttributeKeys[j]
+		if ki.PayloadType != kj.PayloadType {
+			return payloadTypeOf(ki.PayloadType) < payloadTypeOf(kj.PayloadType)
+		}
+		if ki.Count != kj.Count {
+			return ki.Count > kj.Count
+		}
+		return ki.Key < kj.Key
+	})
+
+	sort.Slice(report.Recommendations, func(i, j int) bool {
+		ri, rj := report.Recommendations[i], report.Recommendations[j]
+		if ri.Kind != rj.Kind {
+			return ri.Kind < rj.Kind
+		}
+		return lessColumn(ri.PayloadType, ri.Path+ri.Key, rj.PayloadType, rj.Path+rj.Key)
+	})
+
+	return report
+}
+
+// ShowStats prints the sizes of the payloads, the top contributors, the most
+// frequent attribute keys and the recommendations of the report.
+func (r *Report) ShowStats(indent string) {
+	print(acommon.Green)
+	fmt.Printf("%s%s: %d batches, %d bytes\n", indent, r.Signal, r.Batches, r.EncodedBytes)
+	print(acommon.ColorReset)
+	indent += "  "
+
+	fmt.Printf("%s%-32s|  Records|     Rows|    Bytes| Share|\n", indent, "Payload")
+	for _, p := range r.Payloads {
+		fmt.Printf("%s%-32s|%9d|%9d|%9d|%5.1f%%|\n", indent, p.PayloadType, p.Records, p.Rows, p.EncodedBytes, 100*p.Share)
+	}
+
+	fmt.Printf("%s%-48s|    Bytes| Share|Distinct|  Nulls|  Reuse|\n", indent, "Top contributors")
+	columns := make(map[string]ColumnReport, len(r.Columns))
+	for _, c := range r.Columns {
+		columns[c.PayloadType+"/"+c.Path] = c
+	}
+	for _, c := range r.TopContributors {
+		column := columns[c.PayloadType+"/"+c.Path]
+		reuse := "      -"
+		if column.Dictionary != nil {
+			reuse = fmt.Sprintf("%6.1f%%", 100*column.Dictionary.Reuse)
+		}
+		fmt.Printf("%s%-48s|%9d|%5.1f%%|%7.1f%%|%6.1f%%|%s|\n", indent, c.PayloadType+"/"+c.Path, c.Bytes, 100*c.Share,
+			100*column.CardinalityRatio, 100*column.NullRatio, reuse)
+	}
+
+	keys := append([]AttributeKeyReport(nil), r.AttributeKeys...)
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].Count > keys[j].Count })
+	if len(keys) > reportTopContributors {
+		keys = keys[:reportTopContributors]
+	}
+	fmt.Printf("%s%-48s|    Count| Share|Distinct|\n", indent, "Top attribute keys")
+	for _, k := range keys {
+		fmt.Printf("%s%-48s|%9d|%5.1f%%|%7.1f%%|\n", indent, k.PayloadType+"/"+k.Key, k.Count, 100*k.Share, 100*k.CardinalityRatio)
+	}
+
+	for _, rec := range r.Recommendations {
+		fmt.Printf("%s%s%s%s %s/%s%s: %s\n", indent, acommon.Cyan, rec.Kind, acommon.ColorReset, rec.PayloadType, rec.Path, rec.Key, rec.Reason)
+	}
+}
+
+// signalOf returns the signal of a batch from the payload type of its main
+// record.
+func signalOf(rms []*record_message.RecordMessage) string {
+	for _, rm := range rms {
+		switch rm.PayloadType() {
+		case colarspb.ArrowPayloadType_METRICS:
+			return "metrics"
+		case colarspb.ArrowPayloadType_LOGS:
+			return "logs"
+		case colarspb.ArrowPayloadType_SPANS:
+			return "traces"
+		}
+	}
+	return "unknown"
+}
+
+func payloadTypeOf(name string) record_message.PayloadType {
+	return record_message.PayloadType(colarspb.ArrowPayloadType_value[name])
+}
+
+func lessColumn(payloadTypeI, pathI, payloadTypeJ, pathJ string) bool {
+	if payloadTypeI != payloadTypeJ {
+		return payloadTypeOf(payloadTypeI) < payloadTypeOf(payloadTypeJ)
+	}
+	return pathI < pathJ
+}
+
+func ratio(n, d uint64) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// walkColumns calls fn for each leaf array of arr (arr included) with the
+// path of the corresponding field. The dictionary arrays are leaves.
+func walkColumns(path string, arr arrow.Array, fn func(path string, arr arrow.Array)) {
+	switch a := arr.(type) {
+	case *array.Struct:
+		st := a.DataType().(*arrow.StructType)
+		for i := 0; i < a.NumField(); i++ {
+			walkColumns(path+"."+st.Field(i).Name, a.Field(i), fn)
+		}
+	case *array.Map:
+		walkColumns(path, a.ListValues(), fn)
+	case *array.List:
+		walkColumns(path, a.ListValues(), fn)
+	case *array.SparseUnion:
+		ut := a.UnionType()
+		for i := 0; i < a.NumFields(); i++ {
+			walkColumns(path+"."+ut.Fields()[i].Name, a.Field(i), fn)
+		}
+	default:
+		fn(path, arr)
+	}
+}
+
+// buffersBytes returns the size of the buffers of an array, excluding its
+// children and its dictionary.
+func buffersBytes(data arrow.ArrayData) (size uint64) {
+	for _, buf := range data.Buffers() {
+		if buf != nil {
+			size += uint64(buf.Len())
+		}
+	}
+	return
+}
+
+// appendValue appends the binary representation of the i-th value of arr
+// to buf, the values of a dictionary array are resolved.
+func appendValue(buf []byte, arr arrow.Array, i int) []byte {
+	switch a := arr.(type) {
+	case *array.Dictionary:
+		return appendValue(buf, a.Dictionary(), a.GetValueIndex(i))
+	case *array.String:
+		return append(buf, a.Value(i)...)
+	case *array.Binary:
+		return append(buf, a.Value(i)...)
+	case *array.FixedSizeBinary:
+		return append(buf, a.Value(i)...)
+	case *array.Boolean:
+		if a.Value(i) {
+			return append(buf, 1)
+		}
+		return append(buf, 0)
+	case *array.Uint8:
+		return append(buf, a.Value(i))
+	case *array.Uint16:
+		return binary.LittleEndian.AppendUint16(buf, a.Value(i))
+	case *array.Uint32:
+		return binary.LittleEndian.AppendUint32(buf, a.Value(i))
+	case *array.Uint64:
+		return binary.LittleEndian.AppendUint64(buf, a.Value(i))
+	case *array.Int32:
+		return binary.LittleEndian.AppendUint32(buf, uint32(a.Value(i)))
+	case *array.Int64:
+		return binary.LittleEndian.AppendUint64(buf, uint64(a.Value(i)))
+	case *array.Float64:
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(a.Value(i)))
+	case *array.Timestamp:
+		return binary.LittleEndian.AppendUint64(buf, uint64(a.Value(i)))
+	case *array.Duration:
+		return binary.LittleEndian.AppendUint64(buf, uint64(a.Value(i)))
+	default:
+		if m, ok := arr.(interface{ GetOneForMarshal(int) interface{} }); ok {
+			return fmt.Append(buf, m.GetOneForMarshal(i))
+		}
+		return buf
+	}
+}
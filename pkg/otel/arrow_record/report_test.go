@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	arrowpb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	"github.com/f5/otel-arrow-adapter/pkg/config"
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+)
+
+// TestReports checks the consistency of the reports of the three signals.
+func TestReports(t *testing.T) {
+	t.Parallel()
+
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+
+	producer := NewProducerWithOptions(config.WithAllocator(pool), config.WithStats())
+	entropy := datagen.NewTestEntropy(42)
+	resourceAttrs, scopes := entropy.NewStandardResourceAttributes(), entropy.NewStandardInstrumentationScopes()
+	tracesGen := datagen.NewTracesGenerator(entropy, resourceAttrs, scopes)
+	logsGen := datagen.NewLogsGenerator(entropy, resourceAttrs, scopes)
+	metricsGen := datagen.NewMetricsGenerator(entropy, resourceAttrs, scopes)
+
+	encodedBytes := make(map[string]uint64)
+	logRecords := 0
+	count := func(signal string, batch *arrowpb.BatchArrowRecords) {
+		for _, payload := range batch.ArrowPayloads {
+			encodedBytes[signal] += uint64(len(payload.Record))
+		}
+	}
+	for i := 0; i < 3; i++ {
+		batch, err := producer.BatchArrowRecordsFromTraces(tracesGen.Generate(100, time.Minute))
+		require.NoError(t, err)
+		count("traces", batch)
+		logs := logsGen.Generate(100, time.Minute)
+		logRecords += logs.LogRecordCount()
+		batch, err = producer.BatchArrowRecordsFromLogs(logs)
+		require.NoError(t, err)
+		count("logs", batch)
+		batch, err = producer.BatchArrowRecordsFromMetrics(metricsGen.GenerateAllKindOfMetrics(100, time.Minute))
+		require.NoError(t, err)
+		count("metrics", batch)
+	}
+
+	reports := producer.GetAndResetReports()
+	require.Len(t, reports, 3)
+	for i, signal := range []string{"logs", "metrics", "traces"} {
+		report := reports[i]
+		require.Equal(t, signal, report.Signal)
+		require.Equal(t, uint64(3), report.Batches)
+		require.Equal(t, encodedBytes[signal], report.EncodedBytes)
+
+		var payloadBytes uint64
+		for _, payload := range report.Payloads {
+			payloadBytes += payload.EncodedBytes
+		}
+		require.Equal(t, report.EncodedBytes, payloadBytes)
+
+		require.NotEmpty(t, report.Columns)
+		for _, column := range report.Columns {
+			require.LessOrEqual(t, column.Nulls, column.Rows, column.Path)
+			require.LessOrEqual(t, column.Cardinality, column.Rows-column.Nulls, column.Path)
+			if column.Dictionary != nil {
+				require.LessOrEqual(t, column.Dictionary.Values, column.Rows, column.Path)
+			}
+		}
+
+		require.NotEmpty(t, report.TopContributors)
+		require.LessOrEqual(t, len(report.TopContributors), reportTopContributors)
+		for j := 1; j < len(report.TopContributors); j++ {
+			require.GreaterOrEqual(t, report.TopContributors[j-1].Bytes, report.TopContributors[j].Bytes)
+		}
+
+		require.NotEmpty(t, report.AttributeKeys)
+		shares := make(map[string]float64)
+		for _, key := range report.AttributeKeys {
+			shares[key.PayloadType] += key.Share
+		}
+		for payloadType, share := range shares {
+			require.InDelta(t, 1, share, 1e-9, payloadType)
+		}
+	}
+	require.Empty(t, producer.GetAndResetReports())
+
+	// The analyzers of the OTLP entities are fed with the same batches.
+	require.Equal(t, int64(3), producer.MetricsBuilder().Analyzer().ResourceMetricsStats.ScopeMetricsStats.Distribution.TotalCount())
+	require.Equal(t, int64(logRecords), producer.LogsBuilder().Analyzer().ResourceLogsStats.ScopeLogsStats.LogRecordStats.TotalCount)
+	require.Equal(t, int64(3), producer.TracesBuilder().Analyzer().TraceCount)
+
+	require.NoError(t, producer.Close())
+
+	producer = NewProducerWithOptions()
+	_, err := producer.BatchArrowRecordsFromTraces(tracesGen.Generate(10, time.Minute))
+	require.NoError(t, err)
+	require.Nil(t, producer.GetAndResetReports())
+	require.Nil(t, producer.TracesBuilder().Analyzer())
+	require.NoError(t, producer.Close())
+}
+
+// TestReportRecommendations checks the recommendations for an attribute with
+// unique values.
+func TestReportRecommendations(t *testing.T) {
+	t.Parallel()
+
+	producer := NewProducerWithOptions(config.WithStats())
+	for i := 0; i < 2; i++ {
+		traces := ptrace.NewTraces()
+		spans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+		for j := 0; j < 100; j++ {
+			span := spans.AppendEmpty()
+			span.SetName("GET /")
+			span.Attributes().PutStr("request.id", fmt.Sprintf("request-%d-%d", i, j))
+		}
+		_, err := producer.BatchArrowRecordsFromTraces(traces)
+		require.NoError(t, err)
+	}
+
+	reports := producer.GetAndResetReports()
+	require.Len(t, reports, 1)
+	report := reports[0]
+
+	require.Len(t, report.AttributeKeys, 1)
+	key := report.AttributeKeys[0]
+	require.Equal(t, "SPAN_ATTRS", key.PayloadType)
+	require.Equal(t, "request.id", key.Key)
+	require.Equal(t, uint64(200), key.Count)
+	require.Equal(t, []string{"Str"}, key.Types)
+	require.InDelta(t, 1, key.CardinalityRatio, 0.05)
+
+	require.Contains(t, report.Recommendations, Recommendation{
+		Kind:        RecommendHighCardinalityAttribute,
+		PayloadType: "SPAN_ATTRS",
+		Key:         "request.id",
+		Reason:      fmt.Sprintf("about %d distinct values for 200 attributes", key.Cardinality),
+	})
+	var kinds []string
+	for _, recommendation := range report.Recommendations {
+		if recommendation.PayloadType == "SPAN_ATTRS" && recommendation.Path == "str" {
+			kinds = append(kinds, recommendation.Kind)
+		}
+	}
+	require.Equal(t, []string{RecommendNoDictionary}, kinds)
+
+	require.NoError(t, producer.Close())
+}
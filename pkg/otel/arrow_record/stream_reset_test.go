@@ -0,0 +1,258 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+
+	arrowpb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	"github.com/f5/otel-arrow-adapter/pkg/config"
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/assert"
+	"github.com/f5/otel-arrow-adapter/pkg/record_message"
+)
+
+func newResetTestTraces() *datagen.TraceGenerator {
+	ent := datagen.NewTestEntropy(12345)
+
+	return datagen.NewTracesGenerator(
+		ent,
+		ent.NewStandardResourceAttributes(),
+		ent.NewStandardInstrumentationScopes(),
+	)
+}
+
+// subStreamIDs returns the sub-stream id of each payload type of a batch.
+func subStreamIDs(batch *arrowpb.BatchArrowRecords) map[record_message.PayloadType]string {
+	ids := make(map[record_message.PayloadType]string)
+	for _, payload := range batch.ArrowPayloads {
+		ids[payload.Type] = payload.SubStreamId
+	}
+	return ids
+}
+
+// checkRoundTrip decodes a batch with the consumer and compares the result
+// with the original traces.
+func checkRoundTrip(t *testing.T, consumer *Consumer, batch *arrowpb.BatchArrowRecords, traces ptrace.Traces) {
+	received, err := consumer.TracesFrom(batch)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(received))
+
+	assert.Equiv(
+		t,
+		[]json.Marshaler{ptraceotlp.NewExportRequestFromTraces(traces)},
+		[]json.Marshaler{ptraceotlp.NewExportRequestFromTraces(received[0])},
+	)
+}
+
+// TestProducerResetStreams checks that a reset only affects the sub-streams of
+// the requested payload types and that the consumer follows the new
+// sub-streams.
+func TestProducerResetStreams(t *testing.T) {
+	t.Parallel()
+
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+
+	producer := NewProducerWithOptions(config.WithAllocator(pool))
+	defer func() {
+		if err := producer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	consumer := NewConsumer()
+	defer func() {
+		if err := consumer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	dg := newResetTestTraces()
+
+	traces := dg.Generate(10, time.Minute)
+	batch, err := producer.BatchArrowRecordsFromTraces(traces)
+	require.NoError(t, err)
+	checkRoundTrip(t, consumer, batch, traces)
+	before := subStreamIDs(batch)
+
+	err = producer.ResetStreams(arrowpb.ArrowPayloadType_SPANS, arrowpb.ArrowPayloadType_SPAN_ATTRS)
+	require.NoError(t, err)
+
+	traces = dg.Generate(10, time.Minute)
+	batch, err = producer.BatchArrowRecordsFromTraces(traces)
+	require.NoError(t, err)
+	checkRoundTrip(t, consumer, batch, traces)
+	after := subStreamIDs(batch)
+
+	for payloadType, id := range after {
+		switch payloadType {
+		case arrowpb.ArrowPayloadType_SPANS, arrowpb.ArrowPayloadType_SPAN_ATTRS:
+			require.NotEqual(t, before[payloadType], id, "payload type %s", payloadType)
+		default:
+			require.Equal(t, before[payloadType], id, "payload type %s", payloadType)
+		}
+	}
+
+	// Reset all the payload types.
+	require.NoError(t, producer.ResetStreams())
+
+	traces = dg.Generate(10, time.Minute)
+	batch, err = producer.BatchArrowRecordsFromTraces(traces)
+	require.NoError(t, err)
+	checkRoundTrip(t, consumer, batch, traces)
+
+	for payloadType, id := range subStreamIDs(batch) {
+		require.NotEqual(t, after[payloadType], id, "payload type %s", payloadType)
+	}
+
+	// A reset closing no stream producer is not counted.
+	require.NoError(t, producer.ResetStreams(arrowpb.ArrowPayloadType_LOGS))
+
+	stats := producer.GetAndResetStats()
+	require.Equal(t, uint64(2), stats.StreamResetsPerformed)
+}
+
+// TestProducerResetAfterBatches checks the batch-based reset policy.
+func TestProducerResetAfterBatches(t *testing.T) {
+	t.Parallel()
+
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+
+	producer := NewProducerWithOptions(
+		config.WithAllocator(pool),
+		config.WithResetAfterBatches(2),
+	)
+	defer func() {
+		if err := producer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	consumer := NewConsumer()
+	defer func() {
+		if err := consumer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	dg := newResetTestTraces()
+
+	var ids []string
+	for i := 0; i < 4; i++ {
+		traces := dg.Generate(10, time.Minute)
+		batch, err := producer.BatchArrowRecordsFromTraces(traces)
+		require.NoError(t, err)
+		checkRoundTrip(t, consumer, batch, traces)
+		ids = append(ids, subStreamIDs(batch)[arrowpb.ArrowPayloadType_SPANS])
+	}
+
+	require.Equal(t, ids[0], ids[1])
+	require.NotEqual(t, ids[1], ids[2])
+	require.Equal(t, ids[2], ids[3])
+}
+
+// TestProducerResetAfterDictionaryBytes checks the dictionary size based reset
+// policy.
+func TestProducerResetAfterDictionaryBytes(t *testing.T) {
+	t.Parallel()
+
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+
+	producer := NewProducerWithOptions(
+		config.WithAllocator(pool),
+		config.WithResetAfterDictionaryBytes(1),
+	)
+	defer func() {
+		if err := producer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	consumer := NewConsumer()
+	defer func() {
+		if err := consumer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	dg := newResetTestTraces()
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		traces := dg.Generate(10, time.Minute)
+		batch, err := producer.BatchArrowRecordsFromTraces(traces)
+		require.NoError(t, err)
+		checkRoundTrip(t, consumer, batch, traces)
+		ids = append(ids, subStreamIDs(batch)[arrowpb.ArrowPayloadType_SPANS])
+	}
+
+	// The spans record always contains dictionaries, so every batch is
+	// encoded on a new sub-stream.
+	require.NotEqual(t, ids[0], ids[1])
+	require.NotEqual(t, ids[1], ids[2])
+}
+
+// TestConsumerStreamsToReset checks that the consumer requests a reset once
+// per sub-stream exceeding its threshold.
+func TestConsumerStreamsToReset(t *testing.T) {
+	t.Parallel()
+
+	producer := NewProducer()
+	defer func() {
+		if err := producer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	consumer := NewConsumer(WithStreamResetThreshold(1))
+	defer func() {
+		if err := consumer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	dg := newResetTestTraces()
+
+	require.Empty(t, consumer.StreamsToReset())
+
+	traces := dg.Generate(10, time.Minute)
+	batch, err := producer.BatchArrowRecordsFromTraces(traces)
+	require.NoError(t, err)
+	checkRoundTrip(t, consumer, batch, traces)
+
+	payloadTypes := consumer.StreamsToReset()
+	require.Contains(t, payloadTypes, arrowpb.ArrowPayloadType_SPANS)
+	// Already requested.
+	require.Empty(t, consumer.StreamsToReset())
+
+	require.NoError(t, producer.ResetStreams(payloadTypes...))
+
+	traces = dg.Generate(10, time.Minute)
+	batch, err = producer.BatchArrowRecordsFromTraces(traces)
+	require.NoError(t, err)
+	checkRoundTrip(t, consumer, batch, traces)
+
+	// The new sub-streams can be reset again.
+	require.ElementsMatch(t, payloadTypes, consumer.StreamsToReset())
+
+	// A consumer without threshold never requests a reset.
+	require.Empty(t, NewConsumer().StreamsToReset())
+}
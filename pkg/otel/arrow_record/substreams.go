@@ -0,0 +1,226 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+// Introspection of the sub-streams of a Producer or a Consumer.
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+
+	carrow "github.com/f5/otel-arrow-adapter/pkg/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/record_message"
+)
+
+// SubStreamInfo is a snapshot of the state of a sub-stream of a Producer or
+// a Consumer, used for troubleshooting.
+type SubStreamInfo struct {
+	// ID of the sub-stream, as sent in the ArrowPayloads.
+	ID string `json:"id"`
+	// PayloadType of the records of the sub-stream.
+	PayloadType string `json:"payload_type"`
+	// Schema of the last record (see [carrow.SchemaToString]).
+	Schema string `json:"schema"`
+	// Dictionaries is the number of entries of each dictionary of the last
+	// record, by field path.
+	Dictionaries map[string]int `json:"dictionaries"`
+	// Batches is the number of records of the sub-stream.
+	Batches uint64 `json:"batches"`
+	// LastUpdate is the time of the last record of the sub-stream.
+	LastUpdate time.Time `json:"last_update"`
+}
+
+// subStreamsInfo maintains the snapshots of the sub-streams of a Producer
+// or a Consumer. The snapshots are updated by the encoding (or decoding)
+// goroutine and read by any goroutine calling SubStreams.
+type subStreamsInfo struct {
+	mu         sync.Mutex
+	subStreams map[string]*subStreamInfo
+}
+
+type subStreamInfo struct {
+	SubStreamInfo
+	// The schema and the dictionaries (retained) of the last record, the
+	// Schema and Dictionaries fields are only computed when a snapshot is
+	// requested.
+	schema       *arrow.Schema
+	dictionaries []arrow.Array
+}
+
+func newSubStreamsInfo() *subStreamsInfo {
+	return &subStreamsInfo{subStreams: make(map[string]*subStreamInfo)}
+}
+
+// update records the last record of a sub-stream.
+func (s *subStreamsInfo) update(id string, payloadType record_message.PayloadType, record arrow.Record, batches uint64, lastUpdate time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ss, ok := s.subStreams[id]
+	if !ok {
+		ss = &subStreamInfo{SubStreamInfo: SubStreamInfo{ID: id, PayloadType: payloadType.String()}}
+		s.subStreams[id] = ss
+	}
+	ss.Batches = batches
+	ss.LastUpdate = lastUpdate
+	ss.schema = record.Schema()
+	ss.releaseDictionaries()
+	for _, column := range record.Columns() {
+		ss.dictionaries = appendDictionaries(ss.dictionaries, column)
+	}
+}
+
+// remove forgets a closed sub-stream.
+func (s *subStreamsInfo) remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ss, ok := s.subStreams[id]; ok {
+		ss.releaseDictionaries()
+		delete(s.subStreams, id)
+	}
+}
+
+// clear forgets all the sub-streams.
+func (s *subStreamsInfo) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ss := range s.subStreams {
+		ss.releaseDictionaries()
+	}
+	s.subStreams = make(map[string]*subStreamInfo)
+}
+
+// snapshot returns the state of the sub-streams sorted by payload type and
+// ID.
+func (s *subStreamsInfo) snapshot() []SubStreamInfo {
+	s.mu.Lock()
+	infos := make([]SubStreamInfo, 0, len(s.subStreams))
+	schemas := make([]*arrow.Schema, 0, len(s.subStreams))
+	lens := make([][]int, 0, len(s.subStreams))
+	for _, ss := range s.subStreams {
+		infos = append(infos, ss.SubStreamInfo)
+		schemas = append(schemas, ss.schema)
+		dictLens := make([]int, len(ss.dictionaries))
+		for i, dict := range ss.dictionaries {
+			dictLens[i] = dict.Len()
+		}
+		lens = append(lens, dictLens)
+	}
+	s.mu.Unlock()
+
+	// The schemas are immutable, the rendering is done outside the lock.
+	for i := range infos {
+		infos[i].Schema = carrow.SchemaToString(schemas[i], "")
+		infos[i].Dictionaries = make(map[string]int, len(lens[i]))
+		for j, path := range dictionaryPaths(schemas[i]) {
+			infos[i].Dictionaries[path] = lens[i][j]
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].PayloadType != infos[j].PayloadType {
+			return infos[i].PayloadType < infos[j].PayloadType
+		}
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
+func (ss *subStreamInfo) releaseDictionaries() {
+	for _, dict := range ss.dictionaries {
+		dict.Release()
+	}
+	ss.dictionaries = ss.dictionaries[:0]
+}
+
+// appendDictionaries appends (and retains) the dictionaries found in arr (arr
+// included), in the order of the paths returned by dictionaryPaths.
+func appendDictionaries(dicts []arrow.Array, arr arrow.Array) []arrow.Array {
+	switch a := arr.(type) {
+	case *array.Dictionary:
+		dict := a.Dictionary()
+		dict.Retain()
+		dicts = append(dicts, dict)
+	case *array.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			dicts = appendDictionaries(dicts, a.Field(i))
+		}
+	case *array.Map:
+		dicts = appendDictionaries(dicts, a.ListValues())
+	case *array.List:
+		dicts = appendDictionaries(dicts, a.ListValues())
+	case *array.SparseUnion:
+		for i := 0; i < a.NumFields(); i++ {
+			dicts = appendDictionaries(dicts, a.Field(i))
+		}
+	}
+	return dicts
+}
+
+// dictionaryPaths returns the paths of the dictionary fields of the schema,
+// with the naming of walkDictionaries.
+func dictionaryPaths(schema *arrow.Schema) []string {
+	var paths []string
+	for _, field := range schema.Fields() {
+		paths = appendDictionaryPaths(paths, field.Name, field.Type)
+	}
+	return paths
+}
+
+func appendDictionaryPaths(paths []string, path string, dt arrow.DataType) []string {
+	switch t := dt.(type) {
+	case *arrow.DictionaryType:
+		paths = append(paths, path)
+	case *arrow.StructType:
+		for _, field := range t.Fields() {
+			paths = appendDictionaryPaths(paths, path+"."+field.Name, field.Type)
+		}
+	case *arrow.MapType:
+		paths = appendDictionaryPaths(paths, path, t.ValueType())
+	case *arrow.ListType:
+		paths = appendDictionaryPaths(paths, path, t.Elem())
+	case *arrow.SparseUnionType:
+		for _, field := range t.Fields() {
+			paths = appendDictionaryPaths(paths, path+"."+field.Name, field.Type)
+		}
+	}
+	return paths
+}
+
+// walkDictionaries calls fn for each dictionary array found in arr (arr
+// included) with the path of the corresponding field.
+func walkDictionaries(path string, arr arrow.Array, fn func(path string, dict *array.Dictionary)) {
+	switch a := arr.(type) {
+	case *array.Dictionary:
+		fn(path, a)
+	case *array.Struct:
+		st := a.DataType().(*arrow.StructType)
+		for i := 0; i < a.NumField(); i++ {
+			walkDictionaries(path+"."+st.Field(i).Name, a.Field(i), fn)
+		}
+	case *array.Map:
+		walkDictionaries(path, a.ListValues(), fn)
+	case *array.List:
+		walkDictionaries(path, a.ListValues(), fn)
+	case *array.SparseUnion:
+		ut := a.UnionType()
+		for i := 0; i < a.NumFields(); i++ {
+			walkDictionaries(path+"."+ut.Fields()[i].Name, a.Field(i), fn)
+		}
+	}
+}
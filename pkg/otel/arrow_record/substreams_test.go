@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/stretchr/testify/require"
+
+	arrowpb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+)
+
+// TestSubStreams checks that the producer and the consumer report the same
+// sub-streams and that the snapshots follow the resets.
+func TestSubStreams(t *testing.T) {
+	t.Parallel()
+
+	producer := NewProducer()
+	consumer := NewConsumer()
+	defer func() {
+		require.NoError(t, producer.Close())
+		require.NoError(t, consumer.Close())
+	}()
+
+	gen := newResetTestTraces()
+	var batch *arrowpb.BatchArrowRecords
+	var err error
+	for i := 0; i < 2; i++ {
+		batch, err = producer.BatchArrowRecordsFromTraces(gen.Generate(10, time.Minute))
+		require.NoError(t, err)
+		_, err = consumer.TracesFrom(batch)
+		require.NoError(t, err)
+	}
+
+	produced := producer.SubStreams()
+	consumed := consumer.SubStreams()
+	require.Equal(t, len(batch.ArrowPayloads), len(produced))
+	require.Equal(t, len(produced), len(consumed))
+
+	ids := subStreamIDs(batch)
+	for i, info := range produced {
+		require.Equal(t, ids[arrowpb.ArrowPayloadType(arrowpb.ArrowPayloadType_value[info.PayloadType])], info.ID)
+		require.True(t, strings.HasPrefix(info.Schema, "Schema {"))
+		require.False(t, info.LastUpdate.IsZero())
+
+		require.Equal(t, info.ID, consumed[i].ID)
+		require.Equal(t, info.PayloadType, consumed[i].PayloadType)
+		require.Equal(t, info.Schema, consumed[i].Schema)
+		require.Equal(t, info.Batches, consumed[i].Batches)
+		require.Equal(t, info.Dictionaries, consumed[i].Dictionaries)
+		if info.PayloadType == arrowpb.ArrowPayloadType_SPANS.String() {
+			require.NotEmpty(t, info.Dictionaries)
+		}
+	}
+
+	// Both sides follow the reset of the spans sub-stream.
+	require.NoError(t, producer.ResetStreams(arrowpb.ArrowPayloadType_SPANS))
+	batch, err = producer.BatchArrowRecordsFromTraces(gen.Generate(10, time.Minute))
+	require.NoError(t, err)
+	_, err = consumer.TracesFrom(batch)
+	require.NoError(t, err)
+
+	ids = subStreamIDs(batch)
+	for _, infos := range [][]SubStreamInfo{producer.SubStreams(), consumer.SubStreams()} {
+		require.Equal(t, len(produced), len(infos))
+		for _, info := range infos {
+			if info.PayloadType == arrowpb.ArrowPayloadType_SPANS.String() {
+				require.Equal(t, ids[arrowpb.ArrowPayloadType_SPANS], info.ID)
+				require.Equal(t, uint64(1), info.Batches)
+			}
+		}
+	}
+}
+
+// TestDictionaryPaths checks that the paths computed from a schema match the
+// dictionaries found in the records of this schema.
+func TestDictionaryPaths(t *testing.T) {
+	t.Parallel()
+
+	dict := func(index arrow.DataType) arrow.DataType {
+		return &arrow.DictionaryType{IndexType: index, ValueType: arrow.BinaryTypes.String}
+	}
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: dict(arrow.PrimitiveTypes.Uint8)},
+		{Name: "s", Type: arrow.StructOf(
+			arrow.Field{Name: "b", Type: dict(arrow.PrimitiveTypes.Uint16)},
+			arrow.Field{Name: "l", Type: arrow.ListOf(dict(arrow.PrimitiveTypes.Uint8))},
+		)},
+		{Name: "m", Type: arrow.MapOf(arrow.BinaryTypes.String, dict(arrow.PrimitiveTypes.Uint8))},
+		{Name: "u", Type: arrow.SparseUnionOf([]arrow.Field{
+			{Name: "str", Type: dict(arrow.PrimitiveTypes.Uint8)},
+			{Name: "i64", Type: arrow.PrimitiveTypes.Int64},
+		}, []arrow.UnionTypeCode{0, 1})},
+	}, nil)
+
+	pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer pool.AssertSize(t, 0)
+	rb := array.NewRecordBuilder(pool, schema)
+	defer rb.Release()
+	record := rb.NewRecord()
+	defer record.Release()
+
+	var expected []string
+	var dicts []arrow.Array
+	for i, column := range record.Columns() {
+		walkDictionaries(schema.Field(i).Name, column, func(path string, _ *array.Dictionary) {
+			expected = append(expected, path)
+		})
+		dicts = appendDictionaries(dicts, column)
+	}
+	for _, d := range dicts {
+		d.Release()
+	}
+
+	require.Equal(t, []string{"a", "s.b", "s.l", "m.value", "u.str"}, expected)
+	require.Equal(t, expected, dictionaryPaths(schema))
+	require.Equal(t, len(expected), len(dicts))
+}
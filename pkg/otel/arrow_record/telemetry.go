@@ -0,0 +1,293 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+// Reporting of the producer stats as OpenTelemetry metrics.
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/multierr"
+
+	acommon "github.com/f5/otel-arrow-adapter/pkg/otel/common/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/common/schema/builder"
+	pstats "github.com/f5/otel-arrow-adapter/pkg/otel/stats"
+	"github.com/f5/otel-arrow-adapter/pkg/record_message"
+)
+
+const (
+	// SignalKey is the attribute identifying the signal (metrics, logs or
+	// traces) of a produced batch.
+	SignalKey = "signal"
+
+	// PayloadTypeKey is the attribute identifying the payload type of an
+	// encoded record.
+	PayloadTypeKey = "payload_type"
+
+	// FieldKey is the attribute identifying the field of a dictionary.
+	FieldKey = "field"
+
+	// SortStrategyKey is the attribute identifying the sort strategy of the
+	// traces selected by the adaptive sorting.
+	SortStrategyKey = "sort_strategy"
+
+	// SchemaEventKey is the attribute identifying the kind of a schema
+	// event, one of the SchemaEvent* values.
+	SchemaEventKey = "event"
+
+	// IndexTypeKey is the attribute identifying the new index type of a
+	// dictionary.
+	IndexTypeKey = "index_type"
+
+	// SchemaEventDictionaryOverflow is the event of a dictionary field
+	// downgraded to its value type.
+	SchemaEventDictionaryOverflow = "dictionary_overflow"
+	// SchemaEventDictionaryIndexTypeChanged is the event of a dictionary
+	// field whose index type has changed.
+	SchemaEventDictionaryIndexTypeChanged = "dictionary_index_type_changed"
+
+	telemetryScopeName = "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+	telemetryPrefix    = "arrow_producer_"
+)
+
+// producerTelemetry reports the stats of a Producer (see
+// [pstats.ProducerStats]), the schema events of its record builders, the
+// size of the encoded records and the cardinality of the dictionaries as
+// OpenTelemetry metrics.
+//
+// The cardinalities are observed per producer, the attributes of the
+// producers sharing a meter provider must be distinct (e.g. the index of
+// their stream) for the observations not to conflict.
+//
+// A nil producerTelemetry is valid and reports nothing.
+type producerTelemetry struct {
+	attrs []attribute.KeyValue
+
+	batchesProduced        metric.Int64Counter
+	streamProducersCreated metric.Int64Counter
+	streamProducersClosed  metric.Int64Counter
+	streamResets           metric.Int64Counter
+	sortStrategyChanges    metric.Int64Counter
+	schemaUpdates          metric.Int64Counter
+	dictIndexTypeChanges   metric.Int64Counter
+	dictOverflows          metric.Int64Counter
+	schemaEvents           metric.Int64Counter
+	encodedBytes           metric.Int64Counter
+	dictCardinality        metric.Int64ObservableGauge
+
+	registration metric.Registration
+
+	// Stats already reported, used to compute the deltas.
+	reported pstats.ProducerStats
+
+	// Schema events already reported. The events of a record builder are
+	// the last state of its fields, an event is reported when it appears.
+	reportedEvents map[schemaEventKey]bool
+
+	// Last cardinality observed for each dictionary (protected by mu, the
+	// callback of the observable gauge is called by the SDK).
+	mu            sync.Mutex
+	cardinalities map[dictionaryKey]int64
+}
+
+type dictionaryKey struct {
+	payloadType record_message.PayloadType
+	field       string
+}
+
+type schemaEventKey struct {
+	payloadType record_message.PayloadType
+	field       string
+	event       string
+	indexType   string
+}
+
+// relatedRecordBuilders is implemented by the RelatedData of the signals.
+type relatedRecordBuilders interface {
+	Schemas() []acommon.SchemaWithPayload
+	RecordBuilderExt(payloadType *acommon.PayloadType) *builder.RecordBuilderExt
+}
+
+// newProducerTelemetry creates the instruments of a producer from the given
+// meter provider. The given attributes are added to all the measurements.
+func newProducerTelemetry(mp metric.MeterProvider, attrs []attribute.KeyValue) (*producerTelemetry, error) {
+	meter := mp.Meter(telemetryScopeName)
+	t := &producerTelemetry{
+		attrs:          attrs,
+		reportedEvents: make(map[schemaEventKey]bool),
+		cardinalities:  make(map[dictionaryKey]int64),
+	}
+
+	var errs, err error
+	t.batchesProduced, err = meter.Int64Counter(telemetryPrefix+"batches", metric.WithDescription("Number of batches produced."))
+	errs = multierr.Append(errs, err)
+	t.streamProducersCreated, err = meter.Int64Counter(telemetryPrefix+"streams_created", metric.WithDescription("Number of stream producers created."))
+	errs = multierr.Append(errs, err)
+	t.streamProducersClosed, err = meter.Int64Counter(telemetryPrefix+"streams_closed", metric.WithDescription("Number of stream producers closed."))
+	errs = multierr.Append(errs, err)
+	t.streamResets, err = meter.Int64Counter(telemetryPrefix+"stream_resets", metric.WithDescription("Number of stream resets performed."))
+	errs = multierr.Append(errs, err)
+	t.sortStrategyChanges, err = meter.Int64Counter(telemetryPrefix+"sort_strategy_changes", metric.WithDescription("Number of changes of the sort strategy of the traces."))
+	errs = multierr.Append(errs, err)
+	t.schemaUpdates, err = meter.Int64Counter(telemetryPrefix+"schema_updates", metric.WithDescription("Number of schema updates performed."))
+	errs = multierr.Append(errs, err)
+	t.dictIndexTypeChanges, err = meter.Int64Counter(telemetryPrefix+"dictionary_index_type_changes", metric.WithDescription("Number of dictionary index type changes."))
+	errs = multierr.Append(errs, err)
+	t.dictOverflows, err = meter.Int64Counter(telemetryPrefix+"dictionary_overflows", metric.WithDescription("Number of dictionary overflows detected."))
+	errs = multierr.Append(errs, err)
+	t.schemaEvents, err = meter.Int64Counter(telemetryPrefix+"schema_events", metric.WithDescription("Number of schema events (dictionary overflows and index type changes) per field."))
+	errs = multierr.Append(errs, err)
+	t.encodedBytes, err = meter.Int64Counter(telemetryPrefix+"encoded", metric.WithDescription("Number of bytes of the IPC encoded records."), metric.WithUnit("bytes"))
+	errs = multierr.Append(errs, err)
+	t.dictCardinality, err = meter.Int64ObservableGauge(telemetryPrefix+"dictionary_cardinality", metric.WithDescription("Number of entries of the dictionaries."))
+	errs = multierr.Append(errs, err)
+	if errs != nil {
+		return nil, errs
+	}
+
+	t.registration, err = meter.RegisterCallback(t.observeCardinalities, t.dictCardinality)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// reportStats reports the variation of the producer stats since the last
+// call.
+func (t *producerTelemetry) reportStats(ctx context.Context, stats *pstats.ProducerStats) {
+	if t == nil {
+		return
+	}
+
+	add := func(counter metric.Int64Counter, current uint64, reported *uint64, attrs ...attribute.KeyValue) {
+		if current > *reported {
+			counter.Add(ctx, int64(current-*reported), metric.WithAttributes(append(attrs, t.attrs...)...))
+		}
+		*reported = current
+	}
+
+	add(t.batchesProduced, stats.MetricsBatchesProduced, &t.reported.MetricsBatchesProduced, attribute.String(SignalKey, "metrics"))
+	add(t.batchesProduced, stats.LogsBatchesProduced, &t.reported.LogsBatchesProduced, attribute.String(SignalKey, "logs"))
+	add(t.batchesProduced, stats.TracesBatchesProduced, &t.reported.TracesBatchesProduced, attribute.String(SignalKey, "traces"))
+	add(t.streamProducersCreated, stats.StreamProducersCreated, &t.reported.StreamProducersCreated)
+	add(t.streamProducersClosed, stats.StreamProducersClosed, &t.reported.StreamProducersClosed)
+	add(t.streamResets, stats.StreamResetsPerformed, &t.reported.StreamResetsPerformed)
+	add(t.sortStrategyChanges, stats.TracesSortStrategyChanges, &t.reported.TracesSortStrategyChanges, attribute.String(SortStrategyKey, stats.TracesSortStrategy))
+
+	// The record builder stats can be updated concurrently.
+	rbStats := &stats.RecordBuilderStats
+	reported := &t.reported.RecordBuilderStats
+	add(t.schemaUpdates, atomic.LoadUint64(&rbStats.SchemaUpdatesPerformed), &reported.SchemaUpdatesPerformed)
+	add(t.dictIndexTypeChanges, atomic.LoadUint64(&rbStats.DictionaryIndexTypeChanged), &reported.DictionaryIndexTypeChanged)
+	add(t.dictOverflows, atomic.LoadUint64(&rbStats.DictionaryOverflowDetected), &reported.DictionaryOverflowDetected)
+}
+
+// reportSchemaEvents reports the new schema events of the record builder of
+// the main record and of the builders of its related records.
+func (t *producerTelemetry) reportSchemaEvents(ctx context.Context, payloadType record_message.PayloadType, main *builder.RecordBuilderExt, related relatedRecordBuilders) {
+	if t == nil {
+		return
+	}
+
+	t.reportBuilderEvents(ctx, payloadType, main)
+	for _, schema := range related.Schemas() {
+		if rb := related.RecordBuilderExt(schema.PayloadType); rb != nil {
+			t.reportBuilderEvents(ctx, schema.PayloadType.PayloadType(), rb)
+		}
+	}
+}
+
+func (t *producerTelemetry) reportBuilderEvents(ctx context.Context, payloadType record_message.PayloadType, rb *builder.RecordBuilderExt) {
+	events := rb.Events()
+	report := func(key schemaEventKey) {
+		if t.reportedEvents[key] {
+			return
+		}
+		t.reportedEvents[key] = true
+		attrs := append([]attribute.KeyValue{
+			attribute.String(PayloadTypeKey, payloadType.String()),
+			attribute.String(FieldKey, key.field),
+			attribute.String(SchemaEventKey, key.event),
+		}, t.attrs...)
+		if key.indexType != "" {
+			attrs = append(attrs, attribute.String(IndexTypeKey, key.indexType))
+		}
+		t.schemaEvents.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+
+	for field := range events.DictionariesWithOverflow {
+		report(schemaEventKey{payloadType: payloadType, field: field, event: SchemaEventDictionaryOverflow})
+	}
+	for field, indexType := range events.DictionariesIndexTypeChanged {
+		report(schemaEventKey{payloadType: payloadType, field: field, event: SchemaEventDictionaryIndexTypeChanged, indexType: indexType})
+	}
+}
+
+// resetStats must be called when the producer stats are reset.
+func (t *producerTelemetry) resetStats() {
+	if t == nil {
+		return
+	}
+	t.reported.Reset()
+}
+
+// reportRecord reports the size of an encoded record and the cardinality of
+// its dictionaries.
+func (t *producerTelemetry) reportRecord(ctx context.Context, payloadType record_message.PayloadType, record arrow.Record, encodedBytes int) {
+	if t == nil {
+		return
+	}
+
+	t.encodedBytes.Add(ctx, int64(encodedBytes), metric.WithAttributes(append([]attribute.KeyValue{attribute.String(PayloadTypeKey, payloadType.String())}, t.attrs...)...))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fields := record.Schema().Fields()
+	for i, column := range record.Columns() {
+		// Dictionary builders accumulate values across records, so the
+		// dictionary of the record is the one maintained by the IPC writer.
+		walkDictionaries(fields[i].Name, column, func(path string, dict *array.Dictionary) {
+			t.cardinalities[dictionaryKey{payloadType: payloadType, field: path}] = int64(dict.Dictionary().Len())
+		})
+	}
+}
+
+func (t *producerTelemetry) observeCardinalities(_ context.Context, o metric.Observer) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, cardinality := range t.cardinalities {
+		attrs := append([]attribute.KeyValue{
+			attribute.String(PayloadTypeKey, key.payloadType.String()),
+			attribute.String(FieldKey, key.field),
+		}, t.attrs...)
+		o.ObserveInt64(t.dictCardinality, cardinality, metric.WithAttributes(attrs...))
+	}
+	return nil
+}
+
+// close unregisters the callback of the observable instruments.
+func (t *producerTelemetry) close() error {
+	if t == nil {
+		return nil
+	}
+	return t.registration.Unregister()
+}
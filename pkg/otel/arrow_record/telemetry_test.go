@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow_record
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	cfg "github.com/f5/otel-arrow-adapter/pkg/config"
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+)
+
+func TestProducerTelemetry(t *testing.T) {
+	t.Parallel()
+
+	rdr := metric.NewManualReader()
+	mp := metric.NewMeterProvider(
+		metric.WithResource(resource.Empty()),
+		metric.WithReader(rdr),
+	)
+
+	producer := NewProducerWithOptions(cfg.WithMeterProvider(mp, attribute.String("exporter", "test")))
+
+	ent := datagen.NewTestEntropy(12345)
+	tracesGen := datagen.NewTracesGenerator(ent, ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes())
+	logsGen := datagen.NewLogsGenerator(ent, ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes())
+
+	var encodedBytes int64
+	for i := 0; i < 3; i++ {
+		bar, err := producer.BatchArrowRecordsFromTraces(tracesGen.Generate(100, time.Minute))
+		require.NoError(t, err)
+		for _, payload := range bar.ArrowPayloads {
+			encodedBytes += int64(len(payload.Record))
+		}
+	}
+	bar, err := producer.BatchArrowRecordsFromLogs(logsGen.Generate(100, time.Minute))
+	require.NoError(t, err)
+	for _, payload := range bar.ArrowPayloads {
+		encodedBytes += int64(len(payload.Record))
+	}
+
+	// The dictionary cardinalities are observed until the producer is closed.
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, rdr.Collect(context.Background(), &rm))
+	cardinalities := 0
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if gauge, ok := m.Data.(metricdata.Gauge[int64]); ok {
+				require.Equal(t, "arrow_producer_dictionary_cardinality", m.Name)
+				cardinalities = len(gauge.DataPoints)
+			}
+		}
+	}
+	require.Greater(t, cardinalities, 0)
+
+	require.NoError(t, producer.Close())
+	require.NoError(t, rdr.Collect(context.Background(), &rm))
+
+	sums := map[string]int64{}
+	batches := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if data, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, dp := range data.DataPoints {
+					v, ok := dp.Attributes.Value("exporter")
+					require.True(t, ok)
+					require.Equal(t, "test", v.AsString())
+					sums[m.Name] += dp.Value
+					if signal, ok := dp.Attributes.Value(SignalKey); ok {
+						batches[signal.AsString()] = dp.Value
+					}
+				}
+			}
+		}
+	}
+
+	require.Equal(t, map[string]int64{"traces": 3, "logs": 1}, batches)
+	require.Equal(t, encodedBytes, sums["arrow_producer_encoded"])
+	require.Equal(t, sums["arrow_producer_streams_created"], sums["arrow_producer_streams_closed"])
+	require.Greater(t, sums["arrow_producer_streams_created"], int64(0))
+	require.Greater(t, sums["arrow_producer_schema_updates"], int64(0))
+	// Each index type change of this test is a new schema event.
+	require.Greater(t, sums["arrow_producer_schema_events"], int64(0))
+	require.Equal(t, sums["arrow_producer_dictionary_index_type_changes"]+sums["arrow_producer_dictionary_overflows"], sums["arrow_producer_schema_events"])
+}
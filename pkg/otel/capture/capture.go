@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capture
+
+import (
+	"bufio"
+	"errors"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	"github.com/f5/otel-arrow-adapter/pkg/werror"
+)
+
+// maxMessageSize is the maximum size of a captured message, larger than
+// the usual gRPC limits.
+const maxMessageSize = 256 << 20
+
+// Writer writes the BatchArrowRecords of a stream.
+type Writer struct {
+	w *bufio.Writer
+}
+
+// NewWriter creates a Writer writing to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Write writes a BatchArrowRecords.
+func (w *Writer) Write(bar *colarspb.BatchArrowRecords) error {
+	if _, err := protodelim.MarshalTo(w.w, bar); err != nil {
+		return werror.Wrap(err)
+	}
+	return nil
+}
+
+// Flush writes the buffered data to the underlying writer.
+func (w *Writer) Flush() error {
+	return werror.Wrap(w.w.Flush())
+}
+
+// Reader reads the BatchArrowRecords of a stream.
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader creates a Reader reading from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read reads the next BatchArrowRecords. It returns io.EOF at the end of the
+// stream.
+func (r *Reader) Read() (*colarspb.BatchArrowRecords, error) {
+	bar := &colarspb.BatchArrowRecords{}
+	err := protodelim.UnmarshalOptions{MaxSize: maxMessageSize}.UnmarshalFrom(r.r, bar)
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+	return bar, nil
+}
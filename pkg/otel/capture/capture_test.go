@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capture
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+)
+
+func TestWriteRead(t *testing.T) {
+	t.Parallel()
+
+	ent := datagen.NewTestEntropy(12345)
+	gen := datagen.NewTracesGenerator(ent, ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes())
+	producer := arrowRecord.NewProducer()
+	defer func() { require.NoError(t, producer.Close()) }()
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	var expected []*colarspb.BatchArrowRecords
+	for i := 0; i < 3; i++ {
+		bar, err := producer.BatchArrowRecordsFromTraces(gen.Generate(10, time.Second))
+		require.NoError(t, err)
+		require.NoError(t, w.Write(bar))
+		expected = append(expected, bar)
+	}
+	require.NoError(t, w.Flush())
+
+	r := NewReader(&buf)
+	for _, bar := range expected {
+		actual, err := r.Read()
+		require.NoError(t, err)
+		require.True(t, proto.Equal(bar, actual))
+	}
+	_, err := r.Read()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestTruncated(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	require.NoError(t, w.Write(&colarspb.BatchArrowRecords{BatchId: "batch"}))
+	require.NoError(t, w.Flush())
+
+	_, err := NewReader(bytes.NewReader(buf.Bytes()[:buf.Len()-1])).Read()
+	require.Error(t, err)
+	require.NotErrorIs(t, err, io.EOF)
+}
@@ -53,6 +53,11 @@ func (_ LimitError) Is(tgt error) bool {
 	return ok
 }
 
+// Inuse returns the number of bytes currently allocated.
+func (l *LimitedAllocator) Inuse() uint64 {
+	return l.inuse
+}
+
 func (l *LimitedAllocator) Allocate(size int) []byte {
 	change := uint64(size)
 	if l.inuse+change > l.limit {
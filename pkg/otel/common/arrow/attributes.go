@@ -365,6 +365,12 @@ func (c *Attributes16Accumulator) Sort() {
 	c.sorter.Reset()
 }
 
+// SetSorter replaces the sorter of the attributes, the next batches are
+// sorted and encoded with it.
+func (c *Attributes16Accumulator) SetSorter(sorter Attrs16Sorter) {
+	c.sorter = sorter
+}
+
 func (c *Attributes16Accumulator) Reset() {
 	c.attrsMapCount = 0
 	c.attrs = c.attrs[:0]
@@ -470,6 +476,12 @@ func (c *Attributes32Accumulator) Sort() {
 	c.sorter.Reset()
 }
 
+// SetSorter replaces the sorter of the attributes, the next batches are
+// sorted and encoded with it.
+func (c *Attributes32Accumulator) SetSorter(sorter Attrs32Sorter) {
+	c.sorter = sorter
+}
+
 func (c *Attributes32Accumulator) Reset() {
 	c.attrsMapCount = 0
 	c.attrs = c.attrs[:0]
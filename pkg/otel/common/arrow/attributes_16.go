@@ -85,6 +85,10 @@ type (
 		prevKey      string
 		prevValue    *pcommon.Value
 	}
+	Attrs16WithOrder struct {
+		Attrs16ByKeyValueParentId
+		order Attrs16Sorter
+	}
 )
 
 func NewAttrs16Builder(rBuilder *builder.RecordBuilderExt, payloadType *PayloadType, sorter Attrs16Sorter) *Attrs16Builder {
@@ -458,3 +462,18 @@ func (s *Attrs16ByKeyValueParentId) IsSameGroup(key string, value *pcommon.Value
 		return false
 	}
 }
+
+// Sorts the attributes in the order of another sorter
+// ====================================================
+
+// SortAttrs16With returns a sorter sorting the attributes in the order of
+// the given sorter. Whatever the order, the parent IDs are delta encoded
+// within the groups of attributes with the same key and value, i.e. the
+// encoding of SortAttrs16ByKeyValueParentId expected by the consumer.
+func SortAttrs16With(order Attrs16Sorter) *Attrs16WithOrder {
+	return &Attrs16WithOrder{order: order}
+}
+
+func (s *Attrs16WithOrder) Sort(attrs []Attr16) {
+	s.order.Sort(attrs)
+}
@@ -102,6 +102,10 @@ type (
 		prevKey      string
 		prevValue    *pcommon.Value
 	}
+	Attrs32WithOrder struct {
+		Attrs32ByKeyValueParentId
+		order Attrs32Sorter
+	}
 )
 
 func NewAttrs32Builder(rBuilder *builder.RecordBuilderExt, payloadType *PayloadType, sorter Attrs32Sorter) *Attrs32Builder {
@@ -492,3 +496,18 @@ func (s *Attrs32ByKeyValueParentId) IsSameGroup(key string, value *pcommon.Value
 		return false
 	}
 }
+
+// Sorts the attributes in the order of another sorter
+// ====================================================
+
+// SortAttrs32With returns a sorter sorting the attributes in the order of
+// the given sorter. Whatever the order, the parent IDs are delta encoded
+// within the groups of attributes with the same key and value, i.e. the
+// encoding of SortAttrs32ByKeyValueParentId expected by the consumer.
+func SortAttrs32With(order Attrs32Sorter) *Attrs32WithOrder {
+	return &Attrs32WithOrder{order: order}
+}
+
+func (s *Attrs32WithOrder) Sort(attrs []Attr32) {
+	s.order.Sort(attrs)
+}
@@ -21,6 +21,8 @@ package arrow
 // For example, `attributes` are related to `resource`, `span`, ...
 
 import (
+	"sync"
+
 	"github.com/apache/arrow/go/v12/arrow"
 
 	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
@@ -55,6 +57,12 @@ type (
 		builderExts []*builder.RecordBuilderExt
 
 		schemas []SchemaWithPayload
+
+		// levels groups the indices of the builders by their depth in the
+		// hierarchy of related records. Building a record populates the
+		// builders of its children, so the builders of a level can only be
+		// built once the previous level is complete.
+		levels [][]int
 	}
 
 	// PayloadType wraps the protobuf payload type generated from the protobuf
@@ -223,6 +231,19 @@ func (m *RelatedRecordsManager) Declare(payloadType *PayloadType, parentPayloadT
 	builderExt := builder.NewRecordBuilderExt(m.cfg.Pool, schema, config.NewDictionary(m.cfg.LimitIndexSize), m.stats)
 	builderExt.SetLabel(payloadType.SchemaPrefix())
 	rBuilder := rrBuilder(builderExt)
+
+	level := 0
+	for i, s := range m.schemas {
+		if s.PayloadType == parentPayloadType {
+			level = m.level(i) + 1
+			break
+		}
+	}
+	if level == len(m.levels) {
+		m.levels = append(m.levels, nil)
+	}
+	m.levels[level] = append(m.levels[level], len(m.builders))
+
 	m.builders = append(m.builders, rBuilder)
 	m.builderExts = append(m.builderExts, builderExt)
 	m.schemas = append(m.schemas, SchemaWithPayload{
@@ -233,26 +254,103 @@ func (m *RelatedRecordsManager) Declare(payloadType *PayloadType, parentPayloadT
 	return rBuilder
 }
 
+// BuildRecordMessages builds the related records that are not empty and
+// returns them in declaration order.
+//
+// When the encoding concurrency is greater than 1, the builders of a same
+// level are built concurrently.
 func (m *RelatedRecordsManager) BuildRecordMessages() ([]*record_message.RecordMessage, error) {
+	if m.cfg.EncodingConcurrency > 1 {
+		return m.buildRecordMessagesConcurrently(m.cfg.EncodingConcurrency)
+	}
+
 	recordMessages := make([]*record_message.RecordMessage, 0, len(m.builders))
 	for _, b := range m.builders {
 		if b.IsEmpty() {
 			continue
 		}
-		record, err := b.Build()
+		relatedDataMessage, err := buildRecordMessage(b)
 		if err != nil {
-			return nil, werror.WrapWithContext(
-				err,
-				map[string]interface{}{"schema_prefix": b.PayloadType().SchemaPrefix()},
-			)
+			return nil, err
 		}
-		schemaID := b.PayloadType().SchemaPrefix() + ":" + b.SchemaID()
-		relatedDataMessage := record_message.NewRelatedDataMessage(schemaID, record, b.PayloadType().PayloadType())
 		recordMessages = append(recordMessages, relatedDataMessage)
 	}
 	return recordMessages, nil
 }
 
+func (m *RelatedRecordsManager) buildRecordMessagesConcurrently(concurrency int) ([]*record_message.RecordMessage, error) {
+	recordMessages := make([]*record_message.RecordMessage, len(m.builders))
+	errs := make([]error, len(m.builders))
+	sem := make(chan struct{}, concurrency)
+
+	for _, level := range m.levels {
+		var wg sync.WaitGroup
+		for _, i := range level {
+			b := m.builders[i]
+			if b.IsEmpty() {
+				continue
+			}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, b RelatedRecordBuilder) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				recordMessages[i], errs[i] = buildRecordMessage(b)
+			}(i, b)
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			if err != nil {
+				releaseRecordMessages(recordMessages)
+				return nil, err
+			}
+		}
+	}
+
+	// Remove the empty slots while preserving the declaration order.
+	result := recordMessages[:0]
+	for _, rm := range recordMessages {
+		if rm != nil {
+			result = append(result, rm)
+		}
+	}
+	return result, nil
+}
+
+func (m *RelatedRecordsManager) level(builderIdx int) int {
+	for level, indices := range m.levels {
+		for _, i := range indices {
+			if i == builderIdx {
+				return level
+			}
+		}
+	}
+	return 0
+}
+
+func buildRecordMessage(b RelatedRecordBuilder) (*record_message.RecordMessage, error) {
+	record, err := b.Build()
+	if err != nil {
+		return nil, werror.WrapWithContext(
+			err,
+			map[string]interface{}{"schema_prefix": b.PayloadType().SchemaPrefix()},
+		)
+	}
+	schemaID := b.PayloadType().SchemaPrefix() + ":" + b.SchemaID()
+	return record_message.NewRelatedDataMessage(schemaID, record, b.PayloadType().PayloadType()), nil
+}
+
+func releaseRecordMessages(rms []*record_message.RecordMessage) {
+	for _, rm := range rms {
+		if rm != nil {
+			rm.Record().Release()
+		}
+	}
+}
+
 func (m *RelatedRecordsManager) Schemas() []SchemaWithPayload {
 	return m.schemas
 }
@@ -263,6 +361,16 @@ func (m *RelatedRecordsManager) Reset() {
 	}
 }
 
+// ResetDictionaries resets the dictionaries of the related record builders
+// producing the given payload type.
+func (m *RelatedRecordsManager) ResetDictionaries(payloadType record_message.PayloadType) {
+	for i, b := range m.builders {
+		if b.PayloadType().PayloadType() == payloadType {
+			m.builderExts[i].ResetDictionaries()
+		}
+	}
+}
+
 func (m *RelatedRecordsManager) Release() {
 	for _, b := range m.builders {
 		b.Release()
@@ -17,8 +17,10 @@ package otlp
 import (
 	"github.com/apache/arrow/go/v12/arrow"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	arrowutils "github.com/f5/otel-arrow-adapter/pkg/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/common/wire"
 	"github.com/f5/otel-arrow-adapter/pkg/otel/constants"
 	"github.com/f5/otel-arrow-adapter/pkg/werror"
 )
@@ -129,6 +131,41 @@ func UpdateResourceFromRecord(r pcommon.Resource, record arrow.Record, row int,
 	return
 }
 
+// EncodeResourceFromRecord appends a `Resource` field built from the content
+// of an Arrow record to the given protobuf encoder (see
+// UpdateResourceFromRecord).
+func EncodeResourceFromRecord(e *wire.Encoder, num protowire.Number, record arrow.Record, row int, resIds *ResourceIds, attrsStore *Attributes16Store) (schemaUrl string, err error) {
+	resArr, err := arrowutils.StructFromRecord(record, resIds.Resource, row)
+	if err != nil {
+		return "", werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+
+	// Read schema url
+	schemaUrl, err = arrowutils.StringFromStruct(resArr, row, resIds.SchemaUrl)
+	if err != nil {
+		return "", werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+
+	// Read dropped attributes count
+	droppedAttributesCount, err := arrowutils.U32FromStruct(resArr, row, resIds.DroppedAttributesCount)
+	if err != nil {
+		return "", werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+
+	// Read attributes
+	var attrs *pcommon.Map
+	ID, err := arrowutils.NullableU16FromStruct(resArr, row, resIds.ID)
+	if err != nil {
+		return "", werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+	if ID != nil {
+		attrs = attrsStore.AttributesByDeltaID(*ID)
+	}
+
+	wire.EncodeResource(e, num, attrs, droppedAttributesCount)
+	return
+}
+
 func ResourceIDFromRecord(record arrow.Record, row int, resIDs *ResourceIds) (uint16, error) {
 	resStruct, err := arrowutils.StructFromRecord(record, resIDs.Resource, row)
 	if err != nil {
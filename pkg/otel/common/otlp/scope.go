@@ -17,8 +17,10 @@ package otlp
 import (
 	"github.com/apache/arrow/go/v12/arrow"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	arrowutils "github.com/f5/otel-arrow-adapter/pkg/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/common/wire"
 	"github.com/f5/otel-arrow-adapter/pkg/otel/constants"
 	"github.com/f5/otel-arrow-adapter/pkg/werror"
 )
@@ -153,6 +155,47 @@ func UpdateScopeFromRecord(
 	return nil
 }
 
+// EncodeScopeFromRecord appends an `InstrumentationScope` field built from
+// the content of an Arrow record to the given protobuf encoder (see
+// UpdateScopeFromRecord).
+func EncodeScopeFromRecord(
+	e *wire.Encoder,
+	num protowire.Number,
+	record arrow.Record,
+	row int,
+	ids *ScopeIds,
+	attrsStore *Attributes16Store,
+) error {
+	scopeArray, err := arrowutils.StructFromRecord(record, ids.Scope, row)
+	if err != nil {
+		return werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+	name, err := arrowutils.StringFromStruct(scopeArray, row, ids.Name)
+	if err != nil {
+		return werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+	version, err := arrowutils.StringFromStruct(scopeArray, row, ids.Version)
+	if err != nil {
+		return werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+	droppedAttributesCount, err := arrowutils.U32FromStruct(scopeArray, row, ids.DroppedAttributesCount)
+	if err != nil {
+		return werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+
+	var attrs *pcommon.Map
+	ID, err := arrowutils.NullableU16FromStruct(scopeArray, row, ids.ID)
+	if err != nil {
+		return werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+	if ID != nil {
+		attrs = attrsStore.AttributesByDeltaID(*ID)
+	}
+
+	wire.EncodeScope(e, num, name, version, attrs, droppedAttributesCount)
+	return nil
+}
+
 func ScopeIDFromRecord(record arrow.Record, row int, IDs *ScopeIds) (uint16, error) {
 	scopeStruct, err := arrowutils.StructFromRecord(record, IDs.Scope, row)
 	if err != nil {
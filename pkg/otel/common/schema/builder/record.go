@@ -19,6 +19,7 @@ package builder
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	"github.com/apache/arrow/go/v12/arrow"
 	"github.com/apache/arrow/go/v12/arrow/array"
@@ -264,7 +265,7 @@ func (rb *RecordBuilderExt) UpdateSchema() {
 	rb.schemaID = carrow.SchemaToID(s)
 
 	rb.updateRequest.Reset()
-	rb.stats.RecordBuilderStats.SchemaUpdatesPerformed++
+	atomic.AddUint64(&rb.stats.RecordBuilderStats.SchemaUpdatesPerformed, 1)
 
 	if rb.stats.SchemaStatsEnabled {
 		println("To =====>")
@@ -272,6 +273,38 @@ func (rb *RecordBuilderExt) UpdateSchema() {
 	}
 }
 
+// ResetDictionaries clears the values accumulated by all the dictionary
+// builders of the record builder. The schema is not modified.
+//
+// This method must be called between two records, i.e. when no value is
+// pending in the underlying builders.
+func (rb *RecordBuilderExt) ResetDictionaries() {
+	for _, b := range rb.recordBuilder.Fields() {
+		resetBuilderDictionaries(b)
+	}
+}
+
+// Recursively reset the dictionary builders contained in the given builder.
+func resetBuilderDictionaries(b array.Builder) {
+	switch ab := b.(type) {
+	case array.DictionaryBuilder:
+		ab.ResetFull()
+	case *array.StructBuilder:
+		for i := 0; i < ab.NumField(); i++ {
+			resetBuilderDictionaries(ab.FieldBuilder(i))
+		}
+	case *array.MapBuilder:
+		resetBuilderDictionaries(ab.KeyBuilder())
+		resetBuilderDictionaries(ab.ItemBuilder())
+	case *array.ListBuilder:
+		resetBuilderDictionaries(ab.ValueBuilder())
+	case *array.SparseUnionBuilder:
+		for i := 0; i < ab.NumChildren(); i++ {
+			resetBuilderDictionaries(ab.Child(i))
+		}
+	}
+}
+
 // CopyDictValuesTo recursively copy the dictionary values from the source
 // record builder to the destination record builder.
 func (rb *RecordBuilderExt) copyDictValuesTo(srcRecBuilder *array.RecordBuilder, destRecBuilder *array.RecordBuilder) error {
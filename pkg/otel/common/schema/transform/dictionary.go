@@ -19,6 +19,7 @@ package transform
 
 import (
 	"math"
+	"sync/atomic"
 
 	"github.com/apache/arrow/go/v12/arrow"
 
@@ -167,11 +168,11 @@ func (t *DictionaryField) updateIndexType(stats *stats.RecordBuilderStats) {
 		t.currentIndex = 0
 		t.schemaUpdateRequest.Inc()
 		t.events.DictionariesWithOverflow[t.path] = true
-		stats.DictionaryOverflowDetected++
+		atomic.AddUint64(&stats.DictionaryOverflowDetected, 1)
 	} else if t.currentIndex != currentIndex {
 		t.schemaUpdateRequest.Inc()
 		t.events.DictionariesIndexTypeChanged[t.path] = t.indexTypes[t.currentIndex].Name()
-		stats.DictionaryIndexTypeChanged++
+		atomic.AddUint64(&stats.DictionaryIndexTypeChanged, 1)
 	}
 }
 
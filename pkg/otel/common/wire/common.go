@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+// Encoding of the OTLP messages shared by all the signals.
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of the messages defined in `common.proto` and `resource.proto`.
+const (
+	anyValueString = 1
+	anyValueBool   = 2
+	anyValueInt    = 3
+	anyValueDouble = 4
+	anyValueArray  = 5
+	anyValueKvlist = 6
+	anyValueBytes  = 7
+
+	arrayValueValues   = 1
+	kvlistValueValues  = 1
+	keyValueKey        = 1
+	keyValueValue      = 2
+	scopeName          = 1
+	scopeVersion       = 2
+	scopeAttributes    = 3
+	scopeDroppedAttrs  = 4
+	resourceAttributes = 1
+	resourceDropped    = 2
+)
+
+// EncodeAttributes appends the attributes of the given map as repeated
+// `KeyValue` fields.
+func EncodeAttributes(e *Encoder, num protowire.Number, attrs pcommon.Map) {
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		e.StartMessage(num)
+		e.String(keyValueKey, k)
+		EncodeAnyValue(e, keyValueValue, v)
+		e.EndMessage()
+		return true
+	})
+}
+
+// EncodeAnyValue appends the given value as an `AnyValue` field.
+func EncodeAnyValue(e *Encoder, num protowire.Number, value pcommon.Value) {
+	e.StartMessage(num)
+	switch value.Type() {
+	case pcommon.ValueTypeStr:
+		e.OneofString(anyValueString, value.Str())
+	case pcommon.ValueTypeBool:
+		e.OneofBool(anyValueBool, value.Bool())
+	case pcommon.ValueTypeInt:
+		e.OneofVarint(anyValueInt, uint64(value.Int()))
+	case pcommon.ValueTypeDouble:
+		e.OneofDouble(anyValueDouble, value.Double())
+	case pcommon.ValueTypeSlice:
+		e.StartMessage(anyValueArray)
+		values := value.Slice()
+		for i := 0; i < values.Len(); i++ {
+			EncodeAnyValue(e, arrayValueValues, values.At(i))
+		}
+		e.EndMessage()
+	case pcommon.ValueTypeMap:
+		e.StartMessage(anyValueKvlist)
+		EncodeAttributes(e, kvlistValueValues, value.Map())
+		e.EndMessage()
+	case pcommon.ValueTypeBytes:
+		// Like the pdata marshaler, empty bytes are not encoded.
+		e.BytesField(anyValueBytes, value.Bytes().AsRaw())
+	}
+	e.EndMessage()
+}
+
+// EncodeStrValue appends a string `AnyValue` field.
+func EncodeStrValue(e *Encoder, num protowire.Number, v string) {
+	e.StartMessage(num)
+	e.OneofString(anyValueString, v)
+	e.EndMessage()
+}
+
+// EncodeIntValue appends an int `AnyValue` field.
+func EncodeIntValue(e *Encoder, num protowire.Number, v int64) {
+	e.StartMessage(num)
+	e.OneofVarint(anyValueInt, uint64(v))
+	e.EndMessage()
+}
+
+// EncodeDoubleValue appends a double `AnyValue` field.
+func EncodeDoubleValue(e *Encoder, num protowire.Number, v float64) {
+	e.StartMessage(num)
+	e.OneofDouble(anyValueDouble, v)
+	e.EndMessage()
+}
+
+// EncodeBoolValue appends a bool `AnyValue` field.
+func EncodeBoolValue(e *Encoder, num protowire.Number, v bool) {
+	e.StartMessage(num)
+	e.OneofBool(anyValueBool, v)
+	e.EndMessage()
+}
+
+// EncodeBytesValue appends a bytes `AnyValue` field.
+func EncodeBytesValue(e *Encoder, num protowire.Number, v []byte) {
+	e.StartMessage(num)
+	e.BytesField(anyValueBytes, v)
+	e.EndMessage()
+}
+
+// EncodeResource appends a `Resource` field.
+func EncodeResource(e *Encoder, num protowire.Number, attrs *pcommon.Map, droppedAttributesCount uint32) {
+	e.StartMessage(num)
+	if attrs != nil {
+		EncodeAttributes(e, resourceAttributes, *attrs)
+	}
+	e.Varint(resourceDropped, uint64(droppedAttributesCount))
+	e.EndMessage()
+}
+
+// EncodeScope appends an `InstrumentationScope` field.
+func EncodeScope(e *Encoder, num protowire.Number, name, version string, attrs *pcommon.Map, droppedAttributesCount uint32) {
+	e.StartMessage(num)
+	e.String(scopeName, name)
+	e.String(scopeVersion, version)
+	if attrs != nil {
+		EncodeAttributes(e, scopeAttributes, *attrs)
+	}
+	e.Varint(scopeDroppedAttrs, uint64(droppedAttributesCount))
+	e.EndMessage()
+}
+
+// EncodeTraceID appends a trace id field. Like the pdata marshaler, the field
+// is always present, an empty trace id is encoded as an empty bytes field.
+func EncodeTraceID(e *Encoder, num protowire.Number, traceID pcommon.TraceID) {
+	if traceID.IsEmpty() {
+		e.OneofBytes(num, nil)
+	} else {
+		e.OneofBytes(num, traceID[:])
+	}
+}
+
+// EncodeSpanID appends a span id field. Like the pdata marshaler, the field
+// is always present, an empty span id is encoded as an empty bytes field.
+func EncodeSpanID(e *Encoder, num protowire.Number, spanID pcommon.SpanID) {
+	if spanID.IsEmpty() {
+		e.OneofBytes(num, nil)
+	} else {
+		e.OneofBytes(num, spanID[:])
+	}
+}
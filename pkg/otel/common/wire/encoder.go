@@ -0,0 +1,217 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+// A streaming encoder of the protobuf wire format.
+
+import (
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Encoder appends the fields of a protobuf message to a buffer. Embedded
+// messages are delimited by StartMessage and EndMessage, their length is
+// computed when the message is ended.
+//
+// The scalar methods follow the proto3 implicit presence rules, i.e. a field
+// set to its default value is not encoded. The Oneof* methods always encode
+// the field, they must be used for the members of a oneof and for the fields
+// declared `optional`.
+//
+// The fields must be appended in the order of their field numbers to produce
+// the canonical encoding (the one produced by the pdata marshalers).
+type Encoder struct {
+	buf []byte
+
+	// Positions of the length prefixes of the messages being encoded.
+	starts []int
+}
+
+// NewEncoder creates a new Encoder appending to the given buffer.
+func NewEncoder(buf []byte) *Encoder {
+	return &Encoder{buf: buf}
+}
+
+// Bytes returns the encoded bytes.
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+// Reset discards the encoded bytes and keeps the underlying buffer.
+func (e *Encoder) Reset() {
+	e.buf = e.buf[:0]
+	e.starts = e.starts[:0]
+}
+
+// StartMessage starts the encoding of an embedded message.
+func (e *Encoder) StartMessage(num protowire.Number) {
+	e.buf = protowire.AppendTag(e.buf, num, protowire.BytesType)
+	// One byte is reserved for the length prefix, this is enough for the
+	// messages smaller than 128 bytes.
+	e.starts = append(e.starts, len(e.buf))
+	e.buf = append(e.buf, 0)
+}
+
+// EndMessage ends the encoding of the last started embedded message.
+func (e *Encoder) EndMessage() {
+	start := e.starts[len(e.starts)-1]
+	e.starts = e.starts[:len(e.starts)-1]
+
+	size := len(e.buf) - start - 1
+	prefixSize := protowire.SizeVarint(uint64(size))
+	if prefixSize > 1 {
+		// Make room for the length prefix.
+		for i := 1; i < prefixSize; i++ {
+			e.buf = append(e.buf, 0)
+		}
+		copy(e.buf[start+prefixSize:], e.buf[start+1:start+1+size])
+	}
+	// Writes the length prefix in place (the capacity of the sub-slice is
+	// large enough, so no reallocation can happen).
+	protowire.AppendVarint(e.buf[start:start], uint64(size))
+}
+
+// String appends a string field (if not empty).
+func (e *Encoder) String(num protowire.Number, v string) {
+	if len(v) > 0 {
+		e.OneofString(num, v)
+	}
+}
+
+// BytesField appends a bytes field (if not empty).
+func (e *Encoder) BytesField(num protowire.Number, v []byte) {
+	if len(v) > 0 {
+		e.OneofBytes(num, v)
+	}
+}
+
+// Varint appends a varint field (if not zero).
+func (e *Encoder) Varint(num protowire.Number, v uint64) {
+	if v != 0 {
+		e.OneofVarint(num, v)
+	}
+}
+
+// Bool appends a bool field (if true).
+func (e *Encoder) Bool(num protowire.Number, v bool) {
+	if v {
+		e.OneofBool(num, v)
+	}
+}
+
+// Fixed32 appends a fixed32 field (if not zero).
+func (e *Encoder) Fixed32(num protowire.Number, v uint32) {
+	if v != 0 {
+		e.buf = protowire.AppendTag(e.buf, num, protowire.Fixed32Type)
+		e.buf = protowire.AppendFixed32(e.buf, v)
+	}
+}
+
+// Fixed64 appends a fixed64 field (if not zero).
+func (e *Encoder) Fixed64(num protowire.Number, v uint64) {
+	if v != 0 {
+		e.OneofFixed64(num, v)
+	}
+}
+
+// Double appends a double field (if not zero).
+func (e *Encoder) Double(num protowire.Number, v float64) {
+	if v != 0 {
+		e.OneofDouble(num, v)
+	}
+}
+
+// Sint32 appends a zigzag encoded sint32 field (if not zero).
+func (e *Encoder) Sint32(num protowire.Number, v int32) {
+	if v != 0 {
+		e.buf = protowire.AppendTag(e.buf, num, protowire.VarintType)
+		e.buf = protowire.AppendVarint(e.buf, protowire.EncodeZigZag(int64(v)))
+	}
+}
+
+// PackedFixed64 appends a packed repeated fixed64 field (if not empty).
+func (e *Encoder) PackedFixed64(num protowire.Number, vs []uint64) {
+	if len(vs) == 0 {
+		return
+	}
+	e.buf = protowire.AppendTag(e.buf, num, protowire.BytesType)
+	e.buf = protowire.AppendVarint(e.buf, uint64(len(vs)*8))
+	for _, v := range vs {
+		e.buf = protowire.AppendFixed64(e.buf, v)
+	}
+}
+
+// PackedDouble appends a packed repeated double field (if not empty).
+func (e *Encoder) PackedDouble(num protowire.Number, vs []float64) {
+	if len(vs) == 0 {
+		return
+	}
+	e.buf = protowire.AppendTag(e.buf, num, protowire.BytesType)
+	e.buf = protowire.AppendVarint(e.buf, uint64(len(vs)*8))
+	for _, v := range vs {
+		e.buf = protowire.AppendFixed64(e.buf, math.Float64bits(v))
+	}
+}
+
+// PackedVarint appends a packed repeated varint field (if not empty).
+func (e *Encoder) PackedVarint(num protowire.Number, vs []uint64) {
+	if len(vs) == 0 {
+		return
+	}
+	size := 0
+	for _, v := range vs {
+		size += protowire.SizeVarint(v)
+	}
+	e.buf = protowire.AppendTag(e.buf, num, protowire.BytesType)
+	e.buf = protowire.AppendVarint(e.buf, uint64(size))
+	for _, v := range vs {
+		e.buf = protowire.AppendVarint(e.buf, v)
+	}
+}
+
+// OneofString appends a string field.
+func (e *Encoder) OneofString(num protowire.Number, v string) {
+	e.buf = protowire.AppendTag(e.buf, num, protowire.BytesType)
+	e.buf = protowire.AppendString(e.buf, v)
+}
+
+// OneofBytes appends a bytes field.
+func (e *Encoder) OneofBytes(num protowire.Number, v []byte) {
+	e.buf = protowire.AppendTag(e.buf, num, protowire.BytesType)
+	e.buf = protowire.AppendBytes(e.buf, v)
+}
+
+// OneofVarint appends a varint field.
+func (e *Encoder) OneofVarint(num protowire.Number, v uint64) {
+	e.buf = protowire.AppendTag(e.buf, num, protowire.VarintType)
+	e.buf = protowire.AppendVarint(e.buf, v)
+}
+
+// OneofBool appends a bool field.
+func (e *Encoder) OneofBool(num protowire.Number, v bool) {
+	e.OneofVarint(num, protowire.EncodeBool(v))
+}
+
+// OneofFixed64 appends a fixed64 field.
+func (e *Encoder) OneofFixed64(num protowire.Number, v uint64) {
+	e.buf = protowire.AppendTag(e.buf, num, protowire.Fixed64Type)
+	e.buf = protowire.AppendFixed64(e.buf, v)
+}
+
+// OneofDouble appends a double field.
+func (e *Encoder) OneofDouble(num protowire.Number, v float64) {
+	e.OneofFixed64(num, math.Float64bits(v))
+}
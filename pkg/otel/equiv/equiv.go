@@ -0,0 +1,205 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package equiv
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/f5/otel-arrow-adapter/pkg/werror"
+)
+
+// Difference is the result of the comparison of two sets of OTLP entities.
+type Difference struct {
+	// Missing contains the expected vPaths absent from the actual entities.
+	Missing []string `json:"missing"`
+	// Unexpected contains the actual vPaths absent from the expected
+	// entities.
+	Unexpected []string `json:"unexpected"`
+}
+
+// Equiv returns true if the compared entities are equivalent.
+func (d *Difference) Equiv() bool {
+	return len(d.Missing) == 0 && len(d.Unexpected) == 0
+}
+
+// Diff compares two arrays of json.Marshaler. Metrics, logs, and traces requests implement json.Marshaler and are
+// considered equivalent if they have the same set of vPaths. A vPath is a path to a value in a json object. For example
+// the vPath "resource.attributes.service.name=myservice" refers to the value "myservice" in the json object
+// {"resource":{"attributes":{"service":{"name":"myservice"}}}}.
+//
+// The structure of the expected and actual json objects does not need to be exactly the same. For example, the following
+// json objects are considered equivalent:
+// [{"resource":{"attributes":{"service":"myservice", "version":"1.0"}}}]
+// [{"resource":{"attributes":{"service":"myservice"}}}, {"resource":{"attributes":{"version":"1.0"}}}]
+//
+// This concept of equivalence is useful for comparing OTLP entities before and after a conversion to/from OTLP Arrow as
+// this conversion doesn't necessarily preserve the structure of the original OTLP entity. Resource spans or scope spans
+// can be split or merged during the conversion if the semantic is preserved.
+//
+// The vPaths of the returned Difference are sorted.
+func Diff(expected []json.Marshaler, actual []json.Marshaler) (*Difference, error) {
+	expectedVPaths, err := VPaths(expected)
+	if err != nil {
+		return nil, werror.WrapWithMsg(err, "expected entities")
+	}
+	actualVPaths, err := VPaths(actual)
+	if err != nil {
+		return nil, werror.WrapWithMsg(err, "actual entities")
+	}
+	return diff(expectedVPaths, actualVPaths), nil
+}
+
+// DiffFromBytes compares two JSON objects, see Diff.
+func DiffFromBytes(expected []byte, actual []byte) (*Difference, error) {
+	expectedVPaths, err := VPathsFromBytes(expected)
+	if err != nil {
+		return nil, werror.WrapWithMsg(err, "expected entities")
+	}
+	actualVPaths, err := VPathsFromBytes(actual)
+	if err != nil {
+		return nil, werror.WrapWithMsg(err, "actual entities")
+	}
+	return diff(expectedVPaths, actualVPaths), nil
+}
+
+func diff(expected, actual []string) *Difference {
+	return &Difference{
+		Missing:    difference(expected, actual),
+		Unexpected: difference(actual, expected),
+	}
+}
+
+func difference(a, b []string) []string {
+	mb := make(map[string]struct{}, len(b))
+	for _, x := range b {
+		mb[x] = struct{}{}
+	}
+	var diff []string
+	for _, x := range a {
+		if _, found := mb[x]; !found {
+			diff = append(diff, x)
+		}
+	}
+	return diff
+}
+
+// VPaths returns the sorted set of vPaths of an array of json.Marshaler.
+func VPaths(marshaler []json.Marshaler) ([]string, error) {
+	jsonObjects, err := JSONObjects(marshaler)
+	if err != nil {
+		return nil, err
+	}
+	vPathMap := make(map[string]bool)
+
+	for i := 0; i < len(jsonObjects); i++ {
+		exportAllVPaths(jsonObjects[i], "", vPathMap)
+	}
+
+	return sortedVPaths(vPathMap), nil
+}
+
+// VPathsFromBytes returns the sorted set of vPaths of a JSON object.
+func VPathsFromBytes(json []byte) ([]string, error) {
+	jsonMap, err := jsonObjectFromBytes(json)
+	if err != nil {
+		return nil, err
+	}
+	vPathMap := make(map[string]bool)
+
+	exportAllVPaths(jsonMap, "", vPathMap)
+
+	return sortedVPaths(vPathMap), nil
+}
+
+func sortedVPaths(vPathMap map[string]bool) []string {
+	paths := make([]string, 0, len(vPathMap))
+	for vPath := range vPathMap {
+		paths = append(paths, vPath)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func exportAllVPaths(traces map[string]interface{}, currentVPath string, vPaths map[string]bool) {
+	for key, value := range traces {
+		localVPath := key
+		if currentVPath != "" {
+			localVPath = currentVPath + "." + key
+		}
+		switch v := value.(type) {
+		case []interface{}:
+			for i := 0; i < len(v); i++ {
+				// TODO: this is an approximation that is good enough for now, medium-term we should compute the index key based on a signature of the non-array fields.
+				if vMap, ok := v[i].(map[string]interface{}); ok {
+					arrayVPath := localVPath + "[_]"
+					exportAllVPaths(vMap, arrayVPath, vPaths)
+				} else {
+					arrayVPath := fmt.Sprintf("%s[%d]=%s", localVPath, i, fmt.Sprint(v[i]))
+					vPaths[arrayVPath] = true
+				}
+			}
+		case []string:
+			vPaths[localVPath+"="+strings.Join(v, ",")] = true
+		case []int64:
+			vPaths[localVPath+"="+strings.Join(strings.Fields(fmt.Sprint(v)), ",")] = true
+		case []float64:
+			vPaths[localVPath+"="+strings.Join(strings.Fields(fmt.Sprint(v)), ",")] = true
+		case []bool:
+			vPaths[localVPath+"="+strings.Join(strings.Fields(fmt.Sprint(v)), ",")] = true
+		case map[string]interface{}:
+			exportAllVPaths(v, localVPath, vPaths)
+		case string:
+			vPaths[localVPath+"="+v] = true
+		case int64:
+			vPaths[localVPath+"="+fmt.Sprintf("%d", v)] = true
+		case float64:
+			vPaths[localVPath+"="+fmt.Sprintf("%f", v)] = true
+		case bool:
+			vPaths[localVPath+"="+strconv.FormatBool(v)] = true
+		}
+	}
+}
+
+// JSONObjects converts an array of json.Marshaler to an array of Go maps
+// representing the JSON objects.
+func JSONObjects(marshaler []json.Marshaler) ([]map[string]interface{}, error) {
+	jsonObjects := make([]map[string]interface{}, 0, len(marshaler))
+
+	for i := 0; i < len(marshaler); i++ {
+		jsonBytes, err := marshaler[i].MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		jsonMap, err := jsonObjectFromBytes(jsonBytes)
+		if err != nil {
+			return nil, err
+		}
+		jsonObjects = append(jsonObjects, jsonMap)
+	}
+	return jsonObjects, nil
+}
+
+func jsonObjectFromBytes(jsonBytes []byte) (map[string]interface{}, error) {
+	var jsonMap map[string]interface{}
+	err := json.Unmarshal(jsonBytes, &jsonMap)
+	if err != nil {
+		return nil, err
+	}
+	return jsonMap, nil
+}
@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package equiv
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "service")
+	rs.Resource().Attributes().PutBool("sampled", true)
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("span")
+
+	// A split of the resources is equivalent.
+	split := ptrace.NewTraces()
+	rs.CopyTo(split.ResourceSpans().AppendEmpty())
+	rs.CopyTo(split.ResourceSpans().AppendEmpty())
+	split.ResourceSpans().At(1).ScopeSpans().RemoveIf(func(ptrace.ScopeSpans) bool { return true })
+
+	diff, err := Diff(
+		[]json.Marshaler{ptraceotlp.NewExportRequestFromTraces(traces)},
+		[]json.Marshaler{ptraceotlp.NewExportRequestFromTraces(split)},
+	)
+	require.NoError(t, err)
+	require.True(t, diff.Equiv())
+
+	// Changed values are reported as missing and unexpected vPaths.
+	changed := ptrace.NewTraces()
+	traces.CopyTo(changed)
+	changed.ResourceSpans().At(0).Resource().Attributes().PutBool("sampled", false)
+	changed.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetName("other")
+
+	diff, err = Diff(
+		[]json.Marshaler{ptraceotlp.NewExportRequestFromTraces(traces)},
+		[]json.Marshaler{ptraceotlp.NewExportRequestFromTraces(changed)},
+	)
+	require.NoError(t, err)
+	require.False(t, diff.Equiv())
+	require.Equal(t, []string{
+		"resourceSpans[_].resource.attributes[_].value.boolValue=true",
+		"resourceSpans[_].scopeSpans[_].spans[_].name=span",
+	}, diff.Missing)
+	require.Equal(t, []string{
+		"resourceSpans[_].resource.attributes[_].value.boolValue=false",
+		"resourceSpans[_].scopeSpans[_].spans[_].name=other",
+	}, diff.Unexpected)
+}
@@ -0,0 +1,238 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+// Attributes related record (SPAN_ATTRS or LOG_ATTRS) of a batch.
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/apache/arrow/go/v12/arrow/scalar"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	carrow "github.com/f5/otel-arrow-adapter/pkg/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/common/otlp"
+	"github.com/f5/otel-arrow-adapter/pkg/werror"
+)
+
+type attributes struct {
+	record arrow.Record
+	ids    *otlp.AttributeIDs
+	// Decoded parent ID of each attribute.
+	parents []uint16
+}
+
+func newAttributes(record arrow.Record) (*attributes, error) {
+	ids, err := otlp.SchemaToAttributeIDs(record.Schema())
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+	a := &attributes{record: record, ids: ids}
+
+	if _, ok := record.Column(ids.ParentID).(*array.Uint16); !ok {
+		return nil, werror.WrapWithContext(ErrInvalidIDColumn, map[string]interface{}{"type": record.Column(ids.ParentID).DataType().String()})
+	}
+
+	// The parent IDs are delta encoded within the groups of consecutive
+	// attributes sharing the same key and value (see
+	// otlp.Attrs16ParentIdDecoder).
+	a.parents = make([]uint16, record.NumRows())
+	for i := range a.parents {
+		parentID, err := carrow.U16FromRecord(record, ids.ParentID, i)
+		if err != nil {
+			return nil, werror.Wrap(err)
+		}
+		if i > 0 {
+			same, err := a.same(i-1, i)
+			if err != nil {
+				return nil, werror.Wrap(err)
+			}
+			if same {
+				parentID += a.parents[i-1]
+			}
+		}
+		a.parents[i] = parentID
+	}
+
+	return a, nil
+}
+
+// same returns true if the attributes i and j have the same key and value,
+// following carrow.Equal (maps, slices and empty values are never equal).
+func (a *attributes) same(i, j int) (bool, error) {
+	record := a.record
+
+	ki, err := carrow.StringFromRecord(record, a.ids.Key, i)
+	if err != nil {
+		return false, werror.Wrap(err)
+	}
+	kj, err := carrow.StringFromRecord(record, a.ids.Key, j)
+	if err != nil {
+		return false, werror.Wrap(err)
+	}
+	if ki != kj {
+		return false, nil
+	}
+
+	ti, err := carrow.U8FromRecord(record, a.ids.Type, i)
+	if err != nil {
+		return false, werror.Wrap(err)
+	}
+	tj, err := carrow.U8FromRecord(record, a.ids.Type, j)
+	if err != nil {
+		return false, werror.Wrap(err)
+	}
+	if ti != tj {
+		return false, nil
+	}
+
+	switch pcommon.ValueType(ti) {
+	case pcommon.ValueTypeStr:
+		vi, err := carrow.StringFromRecord(record, a.ids.Str, i)
+		if err != nil {
+			return false, werror.Wrap(err)
+		}
+		vj, err := carrow.StringFromRecord(record, a.ids.Str, j)
+		return vi == vj, werror.Wrap(err)
+	case pcommon.ValueTypeInt:
+		vi, err := carrow.I64FromRecord(record, a.ids.Int, i)
+		if err != nil {
+			return false, werror.Wrap(err)
+		}
+		vj, err := carrow.I64FromRecord(record, a.ids.Int, j)
+		return vi == vj, werror.Wrap(err)
+	case pcommon.ValueTypeDouble:
+		vi, err := carrow.F64FromRecord(record, a.ids.Double, i)
+		if err != nil {
+			return false, werror.Wrap(err)
+		}
+		vj, err := carrow.F64FromRecord(record, a.ids.Double, j)
+		return vi == vj, werror.Wrap(err)
+	case pcommon.ValueTypeBool:
+		vi, err := carrow.BoolFromRecord(record, a.ids.Bool, i)
+		if err != nil {
+			return false, werror.Wrap(err)
+		}
+		vj, err := carrow.BoolFromRecord(record, a.ids.Bool, j)
+		return vi == vj, werror.Wrap(err)
+	case pcommon.ValueTypeBytes:
+		vi, err := carrow.BinaryFromRecord(record, a.ids.Bytes, i)
+		if err != nil {
+			return false, werror.Wrap(err)
+		}
+		vj, err := carrow.BinaryFromRecord(record, a.ids.Bytes, j)
+		return bytes.Equal(vi, vj), werror.Wrap(err)
+	default:
+		return false, nil
+	}
+}
+
+// keyMask evaluates fn on the key of each attribute.
+func (a *attributes) keyMask(fn func(string) bool) ([]bool, error) {
+	return stringMask(a.record.Column(a.ids.Key), fn)
+}
+
+// typedMask returns the attributes with the given key and value type for which
+// the value column satisfies valueMask. Absent value columns never match.
+func (a *attributes) typedMask(ctx context.Context, key string, valueType pcommon.ValueType, valueMask func(arrow.Array) ([]bool, error)) ([]bool, error) {
+	var valueID int
+	switch valueType {
+	case pcommon.ValueTypeStr:
+		valueID = a.ids.Str
+	case pcommon.ValueTypeInt:
+		valueID = a.ids.Int
+	case pcommon.ValueTypeDouble:
+		valueID = a.ids.Double
+	case pcommon.ValueTypeBool:
+		valueID = a.ids.Bool
+	case pcommon.ValueTypeBytes:
+		valueID = a.ids.Bytes
+	default:
+		return nil, werror.WrapWithContext(ErrUnsupportedValueType, map[string]interface{}{"type": valueType.String()})
+	}
+
+	mask := make([]bool, a.record.NumRows())
+	if valueID == carrow.AbsentFieldID {
+		return mask, nil
+	}
+
+	keys, err := a.keyMask(func(k string) bool { return k == key })
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+	types, err := compare(ctx, "equal", a.record.Column(a.ids.Type), scalar.NewUint8Scalar(uint8(valueType)), false)
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+	values, err := valueMask(a.record.Column(valueID))
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+
+	for i := range mask {
+		mask[i] = keys[i] && types[i] && values[i]
+	}
+	return mask, nil
+}
+
+// parentsOf returns the set of parent IDs of the selected attributes.
+func (a *attributes) parentsOf(mask []bool) map[uint16]bool {
+	parents := make(map[uint16]bool)
+	for i, selected := range mask {
+		if selected {
+			parents[a.parents[i]] = true
+		}
+	}
+	return parents
+}
+
+// take returns the attributes at the given indices, with their parent IDs
+// re-encoded.
+func (a *attributes) take(ctx context.Context, pool memory.Allocator, indices []int) (arrow.Record, error) {
+	record, err := takeRecord(ctx, pool, a.record, indices, nil)
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+	defer record.Release()
+
+	b := array.NewUint16Builder(pool)
+	defer b.Release()
+	b.Reserve(len(indices))
+	for k, i := range indices {
+		parentID := a.parents[i]
+		if k > 0 {
+			same, err := a.same(indices[k-1], i)
+			if err != nil {
+				return nil, werror.Wrap(err)
+			}
+			if same {
+				parentID -= a.parents[indices[k-1]]
+			}
+		}
+		b.UnsafeAppend(parentID)
+	}
+	parents := b.NewArray()
+	defer parents.Release()
+
+	columns := make([]arrow.Array, record.NumCols())
+	copy(columns, record.Columns())
+	columns[a.ids.ParentID] = parents
+
+	return array.NewRecord(record.Schema(), columns, int64(len(indices))), nil
+}
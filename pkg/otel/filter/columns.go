@@ -0,0 +1,288 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+// Column level operations used to evaluate the predicates and to build the
+// filtered records.
+
+import (
+	"context"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/bitutil"
+	"github.com/apache/arrow/go/v12/arrow/compute"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/apache/arrow/go/v12/arrow/scalar"
+
+	carrow "github.com/f5/otel-arrow-adapter/pkg/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/werror"
+)
+
+// column returns the column of a record with the given name, or nil if the
+// column is absent (optional columns are omitted when all their values are
+// null).
+func column(record arrow.Record, name string) (arrow.Array, error) {
+	id, err := carrow.FieldIDFromSchema(record.Schema(), name)
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+	if id == carrow.AbsentFieldID {
+		return nil, nil
+	}
+	return record.Column(id), nil
+}
+
+// unpack returns the values of a dictionary array as a plain array, other
+// arrays are returned as is. The returned array must be released.
+func unpack(ctx context.Context, arr arrow.Array) (arrow.Array, error) {
+	dict, ok := arr.(*array.Dictionary)
+	if !ok {
+		arr.Retain()
+		return arr, nil
+	}
+	values, err := compute.TakeArray(ctx, dict.Dictionary(), dict.Indices())
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+	return values, nil
+}
+
+// compare compares each value of arr with value using one of the comparison
+// functions of arrow/compute ("equal", "greater_equal", ...). The null values
+// of arr are replaced by nullAs.
+func compare(ctx context.Context, fn string, arr arrow.Array, value scalar.Scalar, nullAs bool) ([]bool, error) {
+	values, err := unpack(ctx, arr)
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+	defer values.Release()
+
+	input := compute.NewDatum(values)
+	defer input.Release()
+	out, err := compute.CallFunction(ctx, fn, nil, input, compute.NewDatum(value))
+	if err != nil {
+		return nil, werror.WrapWithContext(err, map[string]interface{}{"function": fn, "type": values.DataType().String()})
+	}
+	defer out.Release()
+
+	result := out.(*compute.ArrayDatum).MakeArray().(*array.Boolean)
+	defer result.Release()
+
+	mask := make([]bool, result.Len())
+	for i := range mask {
+		if result.IsNull(i) {
+			mask[i] = nullAs
+		} else {
+			mask[i] = result.Value(i)
+		}
+	}
+	return mask, nil
+}
+
+// stringMask evaluates fn on each value of a string (or dictionary of
+// strings) array. For a dictionary, fn is evaluated once per dictionary
+// value. The null values are evaluated as empty strings.
+func stringMask(arr arrow.Array, fn func(string) bool) ([]bool, error) {
+	mask := make([]bool, arr.Len())
+
+	switch a := arr.(type) {
+	case *array.String:
+		for i := range mask {
+			mask[i] = fn(a.Value(i))
+		}
+	case *array.Dictionary:
+		values, ok := a.Dictionary().(*array.String)
+		if !ok {
+			return nil, werror.WrapWithMsg(carrow.ErrInvalidArrayType, "not a string dictionary")
+		}
+		dictMask := make([]bool, values.Len())
+		for i := range dictMask {
+			dictMask[i] = fn(values.Value(i))
+		}
+		nullAs := fn("")
+		for i := range mask {
+			if a.IsNull(i) {
+				mask[i] = nullAs
+			} else {
+				mask[i] = dictMask[a.GetValueIndex(i)]
+			}
+		}
+	default:
+		return nil, werror.WrapWithMsg(carrow.ErrInvalidArrayType, "not a string array")
+	}
+	return mask, nil
+}
+
+// selection returns the indices of the true values of a mask.
+func selection(mask []bool) []int {
+	rows := make([]int, 0, len(mask))
+	for i, selected := range mask {
+		if selected {
+			rows = append(rows, i)
+		}
+	}
+	return rows
+}
+
+// indicesArray converts indices to the array expected by the take kernel.
+func indicesArray(pool memory.Allocator, indices []int) arrow.Array {
+	b := array.NewInt32Builder(pool)
+	defer b.Release()
+	b.Reserve(len(indices))
+	for _, i := range indices {
+		b.UnsafeAppend(int32(i))
+	}
+	return b.NewArray()
+}
+
+// takeArray returns the values of arr at the given indices.
+//
+// The take kernel of arrow/compute doesn't support dictionaries, the indices
+// of a dictionary array are taken and its dictionary is kept as is. Struct
+// arrays are taken field by field. The fields listed in deltaIDs (by path)
+// are re-encoded with deltaEncodedIDs.
+func takeArray(ctx context.Context, pool memory.Allocator, path string, arr arrow.Array, indices []int, indicesArr arrow.Array, deltaIDs map[string]bool) (arrow.Array, error) {
+	if deltaIDs[path] {
+		return deltaEncodedIDs(pool, arr, indices)
+	}
+
+	switch a := arr.(type) {
+	case *array.Dictionary:
+		dictIndices, err := compute.TakeArray(ctx, a.Indices(), indicesArr)
+		if err != nil {
+			return nil, werror.Wrap(err)
+		}
+		defer dictIndices.Release()
+		return array.NewDictionaryArray(a.DataType(), dictIndices, a.Dictionary()), nil
+	case *array.Struct:
+		st := a.DataType().(*arrow.StructType)
+		children := make([]arrow.ArrayData, a.NumField())
+		defer func() {
+			for _, child := range children {
+				if child != nil {
+					child.Release()
+				}
+			}
+		}()
+		for i := 0; i < a.NumField(); i++ {
+			child, err := takeArray(ctx, pool, path+"."+st.Field(i).Name, a.Field(i), indices, indicesArr, deltaIDs)
+			if err != nil {
+				return nil, werror.Wrap(err)
+			}
+			children[i] = child.Data()
+			children[i].Retain()
+			child.Release()
+		}
+
+		var validity *memory.Buffer
+		nulls := 0
+		if a.NullN() > 0 {
+			validity = memory.NewResizableBuffer(pool)
+			defer validity.Release()
+			validity.Resize(int(bitutil.BytesForBits(int64(len(indices)))))
+			bits := validity.Bytes()
+			for k, i := range indices {
+				bitutil.SetBitTo(bits, k, a.IsValid(i))
+				if a.IsNull(i) {
+					nulls++
+				}
+			}
+		}
+
+		data := array.NewData(a.DataType(), len(indices), []*memory.Buffer{validity}, children, nulls, 0)
+		defer data.Release()
+		return array.MakeFromData(data), nil
+	default:
+		taken, err := compute.TakeArray(ctx, arr, indicesArr)
+		if err != nil {
+			return nil, werror.Wrap(err)
+		}
+		return taken, nil
+	}
+}
+
+// takeRecord returns the rows of a record at the given indices.
+func takeRecord(ctx context.Context, pool memory.Allocator, record arrow.Record, indices []int, deltaIDs map[string]bool) (arrow.Record, error) {
+	indicesArr := indicesArray(pool, indices)
+	defer indicesArr.Release()
+
+	columns := make([]arrow.Array, record.NumCols())
+	defer func() {
+		for _, c := range columns {
+			if c != nil {
+				c.Release()
+			}
+		}
+	}()
+	for i, col := range record.Columns() {
+		taken, err := takeArray(ctx, pool, record.ColumnName(i), col, indices, indicesArr, deltaIDs)
+		if err != nil {
+			return nil, werror.Wrap(err)
+		}
+		columns[i] = taken
+	}
+
+	return array.NewRecord(record.Schema(), columns, int64(len(indices))), nil
+}
+
+// absoluteIDs decodes a delta encoded ID column, i.e. the ID of a row is the
+// sum of the non null values of the column up to this row. Null values have no
+// ID (-1), they identify the rows without related data.
+func absoluteIDs(arr arrow.Array) ([]int32, error) {
+	if arr == nil {
+		return nil, nil
+	}
+	u16, ok := arr.(*array.Uint16)
+	if !ok {
+		return nil, werror.WrapWithContext(ErrInvalidIDColumn, map[string]interface{}{"type": arr.DataType().String()})
+	}
+
+	ids := make([]int32, u16.Len())
+	id := uint16(0)
+	for i := range ids {
+		if u16.IsNull(i) {
+			ids[i] = -1
+			continue
+		}
+		id += u16.Value(i)
+		ids[i] = int32(id)
+	}
+	return ids, nil
+}
+
+// deltaEncodedIDs re-encodes the rows of a delta encoded ID column at the
+// given indices, keeping the absolute value of the IDs.
+func deltaEncodedIDs(pool memory.Allocator, arr arrow.Array, indices []int) (arrow.Array, error) {
+	ids, err := absoluteIDs(arr)
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+
+	b := array.NewUint16Builder(pool)
+	defer b.Release()
+	b.Reserve(len(indices))
+	prev := uint16(0)
+	for _, i := range indices {
+		if ids[i] < 0 {
+			b.UnsafeAppendBoolToBitmap(false)
+			continue
+		}
+		id := uint16(ids[i])
+		b.UnsafeAppend(id - prev)
+		prev = id
+	}
+	return b.NewArray(), nil
+}
@@ -0,0 +1,23 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filter selects the spans or the logs of a batch of OTLP Arrow
+// records and projects their attributes without converting the batch to
+// pdata first.
+//
+// The predicates are evaluated with arrow/compute on the SPANS (or LOGS)
+// main record and on its SPAN_ATTRS (or LOG_ATTRS) related record. The
+// surviving rows are either returned as new Arrow records, which can be
+// re-encoded by the Producer, or decoded to pdata.
+package filter
@@ -0,0 +1,357 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"context"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/compute"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/apache/arrow/go/v12/arrow/scalar"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	carrow "github.com/f5/otel-arrow-adapter/pkg/arrow"
+	acommon "github.com/f5/otel-arrow-adapter/pkg/otel/common/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/constants"
+	logsotlp "github.com/f5/otel-arrow-adapter/pkg/otel/logs/otlp"
+	tracesarrow "github.com/f5/otel-arrow-adapter/pkg/otel/traces/arrow"
+	tracesotlp "github.com/f5/otel-arrow-adapter/pkg/otel/traces/otlp"
+	"github.com/f5/otel-arrow-adapter/pkg/record_message"
+	"github.com/f5/otel-arrow-adapter/pkg/werror"
+)
+
+// Filter selects the spans (or logs) of the batches of records returned by
+// Consumer.Consume and projects their attributes.
+//
+// Only the main record (SPANS or LOGS) and its attributes (SPAN_ATTRS or
+// LOG_ATTRS) are filtered. The other related records (resource and scope
+// attributes, span events and links) are kept as is, their rows are only
+// attached to the surviving spans when the batch is decoded.
+type Filter struct {
+	predicate Predicate
+	keep      map[string]bool
+	drop      map[string]bool
+	pool      memory.Allocator
+}
+
+// Option is a functional option for the Filter.
+type Option func(*Filter)
+
+// batch is the state of a batch of records during the evaluation of the
+// predicates.
+type batch struct {
+	payloadType record_message.PayloadType
+	// Main record.
+	record arrow.Record
+	// Decoded ID of each row of the main record, -1 for the rows without
+	// related data.
+	ids []int32
+	// Attributes of the main record, nil if no row has attributes.
+	attrs *attributes
+}
+
+var (
+	// Attributes related record of each main record.
+	attrsPayloadTypes = map[record_message.PayloadType]record_message.PayloadType{
+		colarspb.ArrowPayloadType_SPANS: colarspb.ArrowPayloadType_SPAN_ATTRS,
+		colarspb.ArrowPayloadType_LOGS:  colarspb.ArrowPayloadType_LOG_ATTRS,
+	}
+
+	// Payload types supported by the filter and the prefix of their
+	// sub-stream IDs (see acommon.PayloadType).
+	schemaPrefixes = map[record_message.PayloadType]string{
+		colarspb.ArrowPayloadType_RESOURCE_ATTRS:   acommon.PayloadTypes.ResourceAttrs.SchemaPrefix(),
+		colarspb.ArrowPayloadType_SCOPE_ATTRS:      acommon.PayloadTypes.ScopeAttrs.SchemaPrefix(),
+		colarspb.ArrowPayloadType_LOGS:             acommon.PayloadTypes.Logs.SchemaPrefix(),
+		colarspb.ArrowPayloadType_LOG_ATTRS:        acommon.PayloadTypes.LogRecordAttrs.SchemaPrefix(),
+		colarspb.ArrowPayloadType_SPANS:            acommon.PayloadTypes.Spans.SchemaPrefix(),
+		colarspb.ArrowPayloadType_SPAN_ATTRS:       acommon.PayloadTypes.SpanAttrs.SchemaPrefix(),
+		colarspb.ArrowPayloadType_SPAN_EVENTS:      acommon.PayloadTypes.Event.SchemaPrefix(),
+		colarspb.ArrowPayloadType_SPAN_EVENT_ATTRS: acommon.PayloadTypes.EventAttrs.SchemaPrefix(),
+		colarspb.ArrowPayloadType_SPAN_LINKS:       acommon.PayloadTypes.Link.SchemaPrefix(),
+		colarspb.ArrowPayloadType_SPAN_LINK_ATTRS:  acommon.PayloadTypes.LinkAttrs.SchemaPrefix(),
+	}
+
+	// Delta encoded ID columns of the main records.
+	deltaEncodedColumns = map[string]bool{
+		constants.ID:                            true,
+		constants.Resource + "." + constants.ID: true,
+		constants.Scope + "." + constants.ID:    true,
+	}
+)
+
+// New creates a Filter. Without options, the filter selects all the rows and
+// keeps all the attributes.
+func New(options ...Option) *Filter {
+	f := &Filter{
+		pool: memory.NewGoAllocator(),
+	}
+	for _, opt := range options {
+		opt(f)
+	}
+	return f
+}
+
+// WithPredicate selects the spans (or logs) satisfying the predicate.
+func WithPredicate(predicate Predicate) Option {
+	return func(f *Filter) {
+		f.predicate = predicate
+	}
+}
+
+// WithKeepAttributes only keeps the span (or log) attributes with one of the
+// given keys.
+func WithKeepAttributes(keys ...string) Option {
+	return func(f *Filter) {
+		f.keep = keySet(keys)
+	}
+}
+
+// WithDropAttributes drops the span (or log) attributes with one of the given
+// keys.
+func WithDropAttributes(keys ...string) Option {
+	return func(f *Filter) {
+		f.drop = keySet(keys)
+	}
+}
+
+// WithAllocator sets the allocator of the filtered records.
+func WithAllocator(pool memory.Allocator) Option {
+	return func(f *Filter) {
+		f.pool = pool
+	}
+}
+
+func keySet(keys []string) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+	return set
+}
+
+// Apply filters a batch of records returned by Consumer.Consume and returns
+// the resulting records, which can be passed to Producer.Produce.
+//
+// Note: This function consumes the records passed in parameter, the returned
+// records must be released by the caller (Producer.Produce does it).
+func (f *Filter) Apply(rms []*record_message.RecordMessage) (_ []*record_message.RecordMessage, err error) {
+	var result []*record_message.RecordMessage
+	defer func() {
+		for _, rm := range rms {
+			rm.Record().Release()
+		}
+		if err != nil {
+			for _, rm := range result {
+				rm.Record().Release()
+			}
+		}
+	}()
+
+	var main, attrs *record_message.RecordMessage
+	for _, rm := range rms {
+		if _, ok := schemaPrefixes[rm.PayloadType()]; !ok {
+			return nil, werror.WrapWithContext(ErrUnsupportedPayloadType, map[string]interface{}{"payload_type": rm.PayloadType().String()})
+		}
+		if _, ok := attrsPayloadTypes[rm.PayloadType()]; ok {
+			main = rm
+		}
+	}
+	if main == nil {
+		return nil, nil
+	}
+	for _, rm := range rms {
+		if rm.PayloadType() == attrsPayloadTypes[main.PayloadType()] {
+			attrs = rm
+		}
+	}
+
+	ctx := compute.WithAllocator(context.Background(), f.pool)
+	b := &batch{
+		payloadType: main.PayloadType(),
+		record:      main.Record(),
+	}
+	idColumn, err := column(b.record, constants.ID)
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+	if b.ids, err = absoluteIDs(idColumn); err != nil {
+		return nil, werror.Wrap(err)
+	}
+	if b.ids == nil {
+		b.ids = make([]int32, b.record.NumRows())
+		for i := range b.ids {
+			b.ids[i] = -1
+		}
+	}
+	if attrs != nil {
+		if b.attrs, err = newAttributes(attrs.Record()); err != nil {
+			return nil, werror.Wrap(err)
+		}
+	}
+
+	// Main record
+	rows := make([]int, b.record.NumRows())
+	for i := range rows {
+		rows[i] = i
+	}
+	if f.predicate != nil {
+		mask, err := f.predicate.mask(ctx, b)
+		if err != nil {
+			return nil, werror.Wrap(err)
+		}
+		rows = selection(mask)
+	}
+	record, err := takeRecord(ctx, f.pool, b.record, rows, deltaEncodedColumns)
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+	result = append(result, message(main.PayloadType(), record))
+
+	// Attributes of the surviving rows
+	if b.attrs != nil {
+		survivors := make(map[uint16]bool, len(rows))
+		for _, row := range rows {
+			if id := b.ids[row]; id >= 0 {
+				survivors[uint16(id)] = true
+			}
+		}
+		keys, err := b.attrs.keyMask(f.keepKey)
+		if err != nil {
+			return nil, werror.Wrap(err)
+		}
+		mask := make([]bool, len(keys))
+		for i := range mask {
+			mask[i] = keys[i] && survivors[b.attrs.parents[i]]
+		}
+		record, err := b.attrs.take(ctx, f.pool, selection(mask))
+		if err != nil {
+			return nil, werror.Wrap(err)
+		}
+		result = append(result, message(attrs.PayloadType(), record))
+	}
+
+	// Other related records
+	for _, rm := range rms {
+		if rm != main && rm != attrs {
+			rm.Record().Retain()
+			result = append(result, message(rm.PayloadType(), rm.Record()))
+		}
+	}
+
+	return result, nil
+}
+
+// Traces filters a batch of traces records returned by Consumer.Consume and
+// decodes the surviving spans.
+//
+// Note: This function consumes the records passed in parameter.
+func (f *Filter) Traces(rms []*record_message.RecordMessage) (ptrace.Traces, error) {
+	records, err := f.Apply(rms)
+	if err != nil {
+		return ptrace.NewTraces(), werror.Wrap(err)
+	}
+
+	relatedData, tracesRecord, err := tracesotlp.RelatedDataFrom(records, tracesarrow.DefaultConfig())
+	if err != nil {
+		return ptrace.NewTraces(), werror.Wrap(err)
+	}
+	if tracesRecord == nil {
+		return ptrace.NewTraces(), nil
+	}
+	return tracesotlp.TracesFrom(tracesRecord.Record(), relatedData)
+}
+
+// Logs filters a batch of logs records returned by Consumer.Consume and
+// decodes the surviving logs.
+//
+// Note: This function consumes the records passed in parameter.
+func (f *Filter) Logs(rms []*record_message.RecordMessage) (plog.Logs, error) {
+	records, err := f.Apply(rms)
+	if err != nil {
+		return plog.NewLogs(), werror.Wrap(err)
+	}
+
+	relatedData, logsRecord, err := logsotlp.RelatedDataFrom(records)
+	if err != nil {
+		return plog.NewLogs(), werror.Wrap(err)
+	}
+	if logsRecord == nil {
+		return plog.NewLogs(), nil
+	}
+	return logsotlp.LogsFrom(logsRecord.Record(), relatedData)
+}
+
+// keepKey returns true if the attributes with the given key are kept by the
+// projection of the filter.
+func (f *Filter) keepKey(key string) bool {
+	if f.keep != nil && !f.keep[key] {
+		return false
+	}
+	return !f.drop[key]
+}
+
+// expect returns an error if the batch is not of the given payload type.
+func (b *batch) expect(payloadType record_message.PayloadType, predicate string) error {
+	if b.payloadType != payloadType {
+		return werror.WrapWithContext(ErrUnsupportedPredicate, map[string]interface{}{"predicate": predicate, "payload_type": b.payloadType.String()})
+	}
+	return nil
+}
+
+// compare compares a column of the main record with a scalar. The null values
+// and the absent column are replaced by nullAs.
+func (b *batch) compare(ctx context.Context, name string, fn string, value scalar.Scalar, nullAs bool) ([]bool, error) {
+	col, err := column(b.record, name)
+	if err != nil {
+		return nil, werror.Wrap(err)
+	}
+	if col == nil {
+		mask := make([]bool, b.record.NumRows())
+		for i := range mask {
+			mask[i] = nullAs
+		}
+		return mask, nil
+	}
+	return compare(ctx, fn, col, value, nullAs)
+}
+
+// withAttributes returns the rows of the main record owning at least one of
+// the selected attributes.
+func (b *batch) withAttributes(attrs []bool) []bool {
+	parents := b.attrs.parentsOf(attrs)
+	mask := make([]bool, len(b.ids))
+	for i, id := range b.ids {
+		mask[i] = id >= 0 && parents[uint16(id)]
+	}
+	return mask
+}
+
+// message wraps a filtered record, its sub-stream ID is derived from its
+// schema as done by the Producer.
+func message(payloadType record_message.PayloadType, record arrow.Record) *record_message.RecordMessage {
+	schemaID := carrow.SchemaToID(record.Schema())
+	switch payloadType {
+	case colarspb.ArrowPayloadType_SPANS:
+		return record_message.NewTraceMessage(schemaID, record)
+	case colarspb.ArrowPayloadType_LOGS:
+		return record_message.NewLogsMessage(schemaID, record)
+	default:
+		return record_message.NewRelatedDataMessage(schemaPrefixes[payloadType]+":"+schemaID, record, payloadType)
+	}
+}
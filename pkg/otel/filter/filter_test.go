@@ -0,0 +1,297 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/assert"
+)
+
+// makeTraces generates traces with several resources and scopes, only the
+// spans for which keep returns true are generated.
+func makeTraces(keep func(ptrace.Span) bool, project func(pcommon.Map)) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	for r := 0; r < 2; r++ {
+		var rs ptrace.ResourceSpans
+		for s := 0; s < 2; s++ {
+			var ss ptrace.ScopeSpans
+			for i := 0; i < 12; i++ {
+				span := ptrace.NewSpan()
+				span.SetName(fmt.Sprintf("span-%d", i%4))
+				span.SetTraceID([16]byte{byte(r), byte(s), byte(i)})
+				span.SetSpanID([8]byte{byte(r), byte(s), byte(i)})
+				span.SetKind(ptrace.SpanKind(i % 3))
+				span.SetStartTimestamp(1_000_000)
+				span.SetEndTimestamp(pcommon.Timestamp(1_000_000 + i*int(time.Millisecond)))
+				if i%5 != 0 {
+					attrs := span.Attributes()
+					attrs.PutStr("tier", []string{"debug", "info", "error"}[i%3])
+					attrs.PutStr("http.route", fmt.Sprintf("/api/v%d/items", i%2+1))
+					attrs.PutInt("index", int64(i))
+					attrs.PutBool("sampled", i%2 == 0)
+				}
+				if i%4 == 1 {
+					event := span.Events().AppendEmpty()
+					event.SetName("event")
+					event.Attributes().PutStr("event.tier", "debug")
+				}
+				if keep != nil && !keep(span) {
+					continue
+				}
+				if project != nil {
+					project(span.Attributes())
+				}
+
+				if rs == (ptrace.ResourceSpans{}) {
+					rs = traces.ResourceSpans().AppendEmpty()
+					rs.Resource().Attributes().PutStr("service.name", fmt.Sprintf("service-%d", r))
+				}
+				if ss == (ptrace.ScopeSpans{}) {
+					ss = rs.ScopeSpans().AppendEmpty()
+					ss.Scope().SetName(fmt.Sprintf("scope-%d", s))
+					ss.Scope().Attributes().PutInt("scope.index", int64(s))
+				}
+				span.CopyTo(ss.Spans().AppendEmpty())
+			}
+		}
+	}
+	return traces
+}
+
+func attrStr(attrs pcommon.Map, key string) string {
+	v, ok := attrs.Get(key)
+	if !ok {
+		return ""
+	}
+	return v.AsString()
+}
+
+func TestFilterTraces(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		options []Option
+		keep    func(ptrace.Span) bool
+		project func(pcommon.Map)
+	}{
+		{
+			name: "no filter",
+		},
+		{
+			name:    "attribute equals",
+			options: []Option{WithPredicate(AttributeEquals("tier", pcommon.NewValueStr("error")))},
+			keep:    func(s ptrace.Span) bool { return attrStr(s.Attributes(), "tier") == "error" },
+		},
+		{
+			name:    "int attribute equals",
+			options: []Option{WithPredicate(AttributeEquals("index", pcommon.NewValueInt(7)))},
+			keep:    func(s ptrace.Span) bool { return attrStr(s.Attributes(), "index") == "7" },
+		},
+		{
+			name:    "bool attribute equals",
+			options: []Option{WithPredicate(AttributeEquals("sampled", pcommon.NewValueBool(true)))},
+			keep:    func(s ptrace.Span) bool { return attrStr(s.Attributes(), "sampled") == "true" },
+		},
+		{
+			name:    "attribute matches",
+			options: []Option{WithPredicate(AttributeMatches("http.route", regexp.MustCompile("^/api/v2/")))},
+			keep:    func(s ptrace.Span) bool { return attrStr(s.Attributes(), "http.route") == "/api/v2/items" },
+		},
+		{
+			name:    "span kind",
+			options: []Option{WithPredicate(SpanKindIn(ptrace.SpanKindUnspecified, ptrace.SpanKindServer))},
+			keep: func(s ptrace.Span) bool {
+				return s.Kind() == ptrace.SpanKindUnspecified || s.Kind() == ptrace.SpanKindServer
+			},
+		},
+		{
+			name:    "duration",
+			options: []Option{WithPredicate(DurationBetween(3*time.Millisecond, 8*time.Millisecond))},
+			keep: func(s ptrace.Span) bool {
+				d := s.EndTimestamp().AsTime().Sub(s.StartTimestamp().AsTime())
+				return d >= 3*time.Millisecond && d <= 8*time.Millisecond
+			},
+		},
+		{
+			name: "not debug or server",
+			options: []Option{WithPredicate(Or(
+				Not(AttributeEquals("tier", pcommon.NewValueStr("debug"))),
+				SpanKindIn(ptrace.SpanKindServer),
+			))},
+			keep: func(s ptrace.Span) bool {
+				return attrStr(s.Attributes(), "tier") != "debug" || s.Kind() == ptrace.SpanKindServer
+			},
+		},
+		{
+			name: "and",
+			options: []Option{WithPredicate(And(
+				AttributeEquals("tier", pcommon.NewValueStr("info")),
+				DurationBetween(5*time.Millisecond, 0),
+			))},
+			keep: func(s ptrace.Span) bool {
+				d := s.EndTimestamp().AsTime().Sub(s.StartTimestamp().AsTime())
+				return attrStr(s.Attributes(), "tier") == "info" && d >= 5*time.Millisecond
+			},
+		},
+		{
+			name:    "keep attributes",
+			options: []Option{WithKeepAttributes("tier", "index")},
+			project: func(attrs pcommon.Map) {
+				attrs.RemoveIf(func(k string, _ pcommon.Value) bool { return k != "tier" && k != "index" })
+			},
+		},
+		{
+			name: "drop attributes of the selected spans",
+			options: []Option{
+				WithPredicate(AttributeEquals("tier", pcommon.NewValueStr("debug"))),
+				WithDropAttributes("http.route", "sampled"),
+			},
+			keep: func(s ptrace.Span) bool { return attrStr(s.Attributes(), "tier") == "debug" },
+			project: func(attrs pcommon.Map) {
+				attrs.Remove("http.route")
+				attrs.Remove("sampled")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer pool.AssertSize(t, 0)
+			f := New(append(tc.options, WithAllocator(pool))...)
+
+			expected := ptraceotlp.NewExportRequestFromTraces(makeTraces(tc.keep, tc.project))
+			input := makeTraces(nil, nil)
+
+			// Filtered records decoded to pdata.
+			producer := arrowRecord.NewProducer()
+			defer func() { require.NoError(t, producer.Close()) }()
+			consumer := arrowRecord.NewConsumer()
+			defer func() { require.NoError(t, consumer.Close()) }()
+
+			bar, err := producer.BatchArrowRecordsFromTraces(input)
+			require.NoError(t, err)
+			records, err := consumer.Consume(bar)
+			require.NoError(t, err)
+			traces, err := f.Traces(records)
+			require.NoError(t, err)
+			assert.Equiv(t, []json.Marshaler{expected}, []json.Marshaler{ptraceotlp.NewExportRequestFromTraces(traces)})
+
+			// Filtered records re-encoded by a producer.
+			bar, err = producer.BatchArrowRecordsFromTraces(input)
+			require.NoError(t, err)
+			records, err = consumer.Consume(bar)
+			require.NoError(t, err)
+			records, err = f.Apply(records)
+			require.NoError(t, err)
+
+			reProducer := arrowRecord.NewProducer()
+			defer func() { require.NoError(t, reProducer.Close()) }()
+			bar, err = reProducer.Produce(records)
+			require.NoError(t, err)
+			tracesSlice, err := arrowRecord.NewConsumer().TracesFrom(bar)
+			require.NoError(t, err)
+			require.Equal(t, 1, len(tracesSlice))
+			assert.Equiv(t, []json.Marshaler{expected}, []json.Marshaler{ptraceotlp.NewExportRequestFromTraces(tracesSlice[0])})
+		})
+	}
+}
+
+func makeLogs(keep func(plog.LogRecord) bool) plog.Logs {
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "service")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("scope")
+	for i := 0; i < 20; i++ {
+		log := plog.NewLogRecord()
+		log.SetTimestamp(pcommon.Timestamp(i))
+		log.SetSeverityNumber(plog.SeverityNumber(i % 24))
+		log.Body().SetStr(fmt.Sprintf("message %d", i))
+		if i%3 != 0 {
+			log.Attributes().PutStr("logger", fmt.Sprintf("com.example.module%d", i%4))
+		}
+		if keep == nil || keep(log) {
+			log.CopyTo(sl.LogRecords().AppendEmpty())
+		}
+	}
+	return logs
+}
+
+func TestFilterLogs(t *testing.T) {
+	t.Parallel()
+
+	predicate := Or(
+		SeverityAtLeast(plog.SeverityNumberWarn),
+		AttributeMatches("logger", regexp.MustCompile(`module[13]$`)),
+	)
+	keep := func(log plog.LogRecord) bool {
+		logger := attrStr(log.Attributes(), "logger")
+		return log.SeverityNumber() >= plog.SeverityNumberWarn || logger == "com.example.module1" || logger == "com.example.module3"
+	}
+
+	producer := arrowRecord.NewProducer()
+	defer func() { require.NoError(t, producer.Close()) }()
+	consumer := arrowRecord.NewConsumer()
+	defer func() { require.NoError(t, consumer.Close()) }()
+
+	bar, err := producer.BatchArrowRecordsFromLogs(makeLogs(nil))
+	require.NoError(t, err)
+	records, err := consumer.Consume(bar)
+	require.NoError(t, err)
+
+	logs, err := New(WithPredicate(predicate)).Logs(records)
+	require.NoError(t, err)
+	assert.Equiv(t,
+		[]json.Marshaler{plogotlp.NewExportRequestFromLogs(makeLogs(keep))},
+		[]json.Marshaler{plogotlp.NewExportRequestFromLogs(logs)},
+	)
+}
+
+func TestUnsupportedPredicate(t *testing.T) {
+	t.Parallel()
+
+	producer := arrowRecord.NewProducer()
+	defer func() { require.NoError(t, producer.Close()) }()
+	consumer := arrowRecord.NewConsumer()
+	defer func() { require.NoError(t, consumer.Close()) }()
+
+	bar, err := producer.BatchArrowRecordsFromTraces(makeTraces(nil, nil))
+	require.NoError(t, err)
+	records, err := consumer.Consume(bar)
+	require.NoError(t, err)
+
+	_, err = New(WithPredicate(SeverityAtLeast(plog.SeverityNumberWarn))).Apply(records)
+	require.True(t, errors.Is(err, ErrUnsupportedPredicate))
+}
@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/apache/arrow/go/v12/arrow/scalar"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/constants"
+	"github.com/f5/otel-arrow-adapter/pkg/werror"
+)
+
+// Predicate selects the rows of the main record (SPANS or LOGS) of a batch.
+type Predicate interface {
+	// mask returns true for each selected row of the main record.
+	mask(ctx context.Context, b *batch) ([]bool, error)
+}
+
+type predicateFunc func(ctx context.Context, b *batch) ([]bool, error)
+
+func (f predicateFunc) mask(ctx context.Context, b *batch) ([]bool, error) {
+	return f(ctx, b)
+}
+
+// AttributeEquals selects the spans (or logs) having an attribute with the
+// given key and value. Only string, int, double, bool, and bytes values are
+// supported.
+func AttributeEquals(key string, value pcommon.Value) Predicate {
+	return predicateFunc(func(ctx context.Context, b *batch) ([]bool, error) {
+		if b.attrs == nil {
+			return make([]bool, b.record.NumRows()), nil
+		}
+
+		var s scalar.Scalar
+		switch value.Type() {
+		case pcommon.ValueTypeStr:
+			s = scalar.NewStringScalar(value.Str())
+		case pcommon.ValueTypeInt:
+			s = scalar.NewInt64Scalar(value.Int())
+		case pcommon.ValueTypeDouble:
+			s = scalar.NewFloat64Scalar(value.Double())
+		case pcommon.ValueTypeBool:
+			s = scalar.NewBooleanScalar(value.Bool())
+		case pcommon.ValueTypeBytes:
+			s = scalar.NewBinaryScalar(memory.NewBufferBytes(value.Bytes().AsRaw()), arrow.BinaryTypes.Binary)
+		default:
+			return nil, werror.WrapWithContext(ErrUnsupportedValueType, map[string]interface{}{"type": value.Type().String()})
+		}
+
+		attrs, err := b.attrs.typedMask(ctx, key, value.Type(), func(values arrow.Array) ([]bool, error) {
+			return compare(ctx, "equal", values, s, false)
+		})
+		if err != nil {
+			return nil, werror.Wrap(err)
+		}
+		return b.withAttributes(attrs), nil
+	})
+}
+
+// AttributeMatches selects the spans (or logs) having a string attribute with
+// the given key and a value matching re. The expression is evaluated once per
+// distinct value when the values are dictionary encoded.
+func AttributeMatches(key string, re *regexp.Regexp) Predicate {
+	return predicateFunc(func(ctx context.Context, b *batch) ([]bool, error) {
+		if b.attrs == nil {
+			return make([]bool, b.record.NumRows()), nil
+		}
+
+		attrs, err := b.attrs.typedMask(ctx, key, pcommon.ValueTypeStr, func(values arrow.Array) ([]bool, error) {
+			return stringMask(values, re.MatchString)
+		})
+		if err != nil {
+			return nil, werror.Wrap(err)
+		}
+		return b.withAttributes(attrs), nil
+	})
+}
+
+// SeverityAtLeast selects the logs with a severity number greater than or
+// equal to severity. Only applies to logs.
+func SeverityAtLeast(severity plog.SeverityNumber) Predicate {
+	return predicateFunc(func(ctx context.Context, b *batch) ([]bool, error) {
+		if err := b.expect(colarspb.ArrowPayloadType_LOGS, "SeverityAtLeast"); err != nil {
+			return nil, werror.Wrap(err)
+		}
+		return b.compare(ctx, constants.SeverityNumber, "greater_equal", scalar.NewInt32Scalar(int32(severity)), plog.SeverityNumberUnspecified >= severity)
+	})
+}
+
+// SpanKindIn selects the spans with one of the given kinds. Only applies to
+// spans.
+func SpanKindIn(kinds ...ptrace.SpanKind) Predicate {
+	return predicateFunc(func(ctx context.Context, b *batch) ([]bool, error) {
+		if err := b.expect(colarspb.ArrowPayloadType_SPANS, "SpanKindIn"); err != nil {
+			return nil, werror.Wrap(err)
+		}
+
+		mask := make([]bool, b.record.NumRows())
+		for _, kind := range kinds {
+			kindMask, err := b.compare(ctx, constants.KIND, "equal", scalar.NewInt32Scalar(int32(kind)), kind == ptrace.SpanKindUnspecified)
+			if err != nil {
+				return nil, werror.Wrap(err)
+			}
+			for i := range mask {
+				mask[i] = mask[i] || kindMask[i]
+			}
+		}
+		return mask, nil
+	})
+}
+
+// DurationBetween selects the spans with a duration in [min, max]. A max of 0
+// means no upper bound. Only applies to spans.
+func DurationBetween(min, max time.Duration) Predicate {
+	return predicateFunc(func(ctx context.Context, b *batch) ([]bool, error) {
+		if err := b.expect(colarspb.ArrowPayloadType_SPANS, "DurationBetween"); err != nil {
+			return nil, werror.Wrap(err)
+		}
+
+		// The durations are stored in nanoseconds (see tracesarrow.TracesSchema).
+		durationScalar := func(d time.Duration) scalar.Scalar {
+			return scalar.NewDurationScalar(arrow.Duration(d.Nanoseconds()), arrow.FixedWidthTypes.Duration_ms)
+		}
+
+		mask, err := b.compare(ctx, constants.DurationTimeUnixNano, "greater_equal", durationScalar(min), min <= 0)
+		if err != nil {
+			return nil, werror.Wrap(err)
+		}
+		if max > 0 {
+			maxMask, err := b.compare(ctx, constants.DurationTimeUnixNano, "less_equal", durationScalar(max), true)
+			if err != nil {
+				return nil, werror.Wrap(err)
+			}
+			for i := range mask {
+				mask[i] = mask[i] && maxMask[i]
+			}
+		}
+		return mask, nil
+	})
+}
+
+// And selects the rows selected by all the predicates.
+func And(predicates ...Predicate) Predicate {
+	return predicateFunc(func(ctx context.Context, b *batch) ([]bool, error) {
+		mask := make([]bool, b.record.NumRows())
+		for i := range mask {
+			mask[i] = true
+		}
+		for _, p := range predicates {
+			pMask, err := p.mask(ctx, b)
+			if err != nil {
+				return nil, werror.Wrap(err)
+			}
+			for i := range mask {
+				mask[i] = mask[i] && pMask[i]
+			}
+		}
+		return mask, nil
+	})
+}
+
+// Or selects the rows selected by at least one of the predicates.
+func Or(predicates ...Predicate) Predicate {
+	return predicateFunc(func(ctx context.Context, b *batch) ([]bool, error) {
+		mask := make([]bool, b.record.NumRows())
+		for _, p := range predicates {
+			pMask, err := p.mask(ctx, b)
+			if err != nil {
+				return nil, werror.Wrap(err)
+			}
+			for i := range mask {
+				mask[i] = mask[i] || pMask[i]
+			}
+		}
+		return mask, nil
+	})
+}
+
+// Not selects the rows not selected by the predicate.
+func Not(predicate Predicate) Predicate {
+	return predicateFunc(func(ctx context.Context, b *batch) ([]bool, error) {
+		mask, err := predicate.mask(ctx, b)
+		if err != nil {
+			return nil, werror.Wrap(err)
+		}
+		for i := range mask {
+			mask[i] = !mask[i]
+		}
+		return mask, nil
+	})
+}
@@ -19,11 +19,14 @@ package arrow
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/HdrHistogram/hdrhistogram-go"
 	"github.com/axiomhq/hyperloglog"
+	"go.opentelemetry.io/collector/pdata/plog"
 
 	carrow "github.com/f5/otel-arrow-adapter/pkg/otel/common/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/common/otlp"
 )
 
 // A log analyzer is a tool designed to generate statistics about the structure
@@ -112,21 +115,49 @@ func (t *LogsAnalyzer) ShowStats(indent string) {
 }
 
 func (r *ResourceLogsStats) UpdateWith(logs *LogsOptimized) {
-	// ToDo I will update this in a future PR. Only used for troubleshooting compression ratio issues.
-	//resLogs := logs.ResourceLogs
-	//
-	//for ID := range logs.ResourceLogsIdx {
-	//	r.ResLogsIDsDistinct.Insert([]byte(ID))
-	//}
-	//
-	//r.TotalCount += int64(len(resLogs))
-	//carrow.RequireNoError(r.Distribution.RecordValue(int64(len(resLogs))))
-	//
-	//for _, rs := range resLogs {
-	//	r.ResourceStats.UpdateWith(rs.Resource)
-	//	r.ScopeLogsStats.UpdateWith(rs.ScopeLogs, rs.ScopeLogsIdx)
-	//	r.SchemaUrlStats.UpdateWith(rs.ResourceSchemaUrl)
-	//}
+	// The logs are sorted by resource and scope, the logs of a resource (or
+	// scope) logs are contiguous.
+	prevResID := -1
+	prevScopeID := -1
+
+	resLogsCount := 0
+	scopeLogsCount := 0
+	logsPerScopeLogs := 0
+
+	s := r.ScopeLogsStats
+	for _, log := range logs.Logs {
+		resScope := log.ResScope
+		newResource := prevResID != resScope.ResourceLogsID
+		if newResource {
+			prevResID = resScope.ResourceLogsID
+			resLogsCount++
+			r.ResLogsIDsDistinct.Insert([]byte(otlp.ResourceID(resScope.Resource, resScope.ResourceSchemaUrl)))
+			r.ResourceStats.UpdateWith(resScope.Resource)
+			r.SchemaUrlStats.UpdateWith(resScope.ResourceSchemaUrl)
+		}
+
+		if newResource || prevScopeID != resScope.ScopeLogsID {
+			prevScopeID = resScope.ScopeLogsID
+			if logsPerScopeLogs > 0 {
+				carrow.RequireNoError(s.LogRecordStats.Distribution.RecordValue(int64(logsPerScopeLogs)))
+			}
+			scopeLogsCount++
+			s.ScopeLogsIDsDistinct.Insert([]byte(otlp.ScopeID(resScope.Scope, resScope.ScopeSchemaUrl)))
+			s.ScopeStats.UpdateWith(resScope.Scope)
+			s.SchemaUrlStats.UpdateWith(resScope.ScopeSchemaUrl)
+			logsPerScopeLogs = 0
+		}
+
+		s.LogRecordStats.UpdateWith(log.Log)
+		logsPerScopeLogs++
+	}
+	if logsPerScopeLogs > 0 {
+		carrow.RequireNoError(s.LogRecordStats.Distribution.RecordValue(int64(logsPerScopeLogs)))
+	}
+
+	r.TotalCount += int64(resLogsCount)
+	carrow.RequireNoError(r.Distribution.RecordValue(int64(resLogsCount)))
+	carrow.RequireNoError(s.Distribution.RecordValue(int64(scopeLogsCount)))
 }
 
 func (r *ResourceLogsStats) ShowStats(indent string) {
@@ -142,20 +173,6 @@ func (r *ResourceLogsStats) ShowStats(indent string) {
 	r.SchemaUrlStats.ShowStats(indent)
 }
 
-//func (s *ScopeLogsStats) UpdateWith(scopeLogs []*ScopeLogGroup, scopeLogsIdx map[string]int) {
-//	carrow.RequireNoError(s.Distribution.RecordValue(int64(len(scopeLogs))))
-//
-//	for ID := range scopeLogsIdx {
-//		s.ScopeLogsIDsDistinct.Insert([]byte(ID))
-//	}
-//
-//	for _, sl := range scopeLogs {
-//		s.ScopeStats.UpdateWith(sl.Scope)
-//		s.LogRecordStats.UpdateWith(sl)
-//		s.SchemaUrlStats.UpdateWith(sl.ScopeSchemaUrl)
-//	}
-//}
-
 func (s *ScopeLogsStats) ShowStats(indent string) {
 	print(carrow.Green)
 	fmt.Printf("%sScopeLogs%s  |Distinct|   Min|   Max|  Mean| Stdev|   P50|   P99|\n", indent, carrow.ColorReset)
@@ -181,23 +198,19 @@ func NewLogRecordStats() *LogRecordStats {
 	}
 }
 
-//func (s *LogRecordStats) UpdateWith(sl *ScopeLogGroup) {
-//	logs := sl.Logs
-//	carrow.RequireNoError(s.Distribution.RecordValue(int64(len(logs))))
-//
-//	for _, logRecord := range logs {
-//		s.TimeUnixNano.UpdateWith(logRecord.Timestamp())
-//		s.ObservedTimeUnixNano.UpdateWith(logRecord.ObservedTimestamp())
-//		s.Attributes.UpdateWith(logRecord.Attributes(), logRecord.DroppedAttributesCount())
-//		s.SpanID.Insert([]byte(logRecord.SpanID().String()))
-//		s.TraceID.Insert([]byte(logRecord.TraceID().String()))
-//		s.SeverityNumber.Insert([]byte(strconv.Itoa(int(logRecord.SeverityNumber()))))
-//		s.SeverityText.UpdateWith(logRecord.SeverityText())
-//		s.Body.UpdateWith(logRecord.Body())
-//	}
-//
-//	s.TotalCount += int64(len(logs))
-//}
+// UpdateWith updates the stats with a log record. The distribution of the
+// log records per scope logs is recorded by ResourceLogsStats.
+func (s *LogRecordStats) UpdateWith(logRecord plog.LogRecord) {
+	s.TimeUnixNano.UpdateWith(logRecord.Timestamp())
+	s.ObservedTimeUnixNano.UpdateWith(logRecord.ObservedTimestamp())
+	s.Attributes.UpdateWith(logRecord.Attributes(), logRecord.DroppedAttributesCount())
+	s.SpanID.Insert([]byte(logRecord.SpanID().String()))
+	s.TraceID.Insert([]byte(logRecord.TraceID().String()))
+	s.SeverityNumber.Insert([]byte(strconv.Itoa(int(logRecord.SeverityNumber()))))
+	s.SeverityText.UpdateWith(logRecord.SeverityText())
+	s.Body.UpdateWith(logRecord.Body())
+	s.TotalCount++
+}
 
 func (s *LogRecordStats) ShowStats(indent string) {
 	print(carrow.Green)
@@ -91,6 +91,9 @@ type LogsBuilder struct {
 
 	optimizer *LogsOptimizer
 	analyzer  *LogsAnalyzer
+	// Batch appended since the last record, analyzed once the record is
+	// built (the batch is appended again after a schema update).
+	pendingAnalysis *LogsOptimized
 
 	relatedData *RelatedData
 }
@@ -168,6 +171,12 @@ func (b *LogsBuilder) RelatedData() *RelatedData {
 	return b.relatedData
 }
 
+// Analyzer returns the analyzer of the batches appended to the builder, nil
+// if the statistics are not enabled (see config.WithStats).
+func (b *LogsBuilder) Analyzer() *LogsAnalyzer {
+	return b.analyzer
+}
+
 // Build builds an Arrow Record from the builder.
 //
 // Once the array is no longer needed, Release() must be called to free the
@@ -183,7 +192,10 @@ func (b *LogsBuilder) Build() (record arrow.Record, err error) {
 		if initErr != nil {
 			err = werror.Wrap(initErr)
 		}
+	} else if b.pendingAnalysis != nil {
+		b.analyzer.Analyze(b.pendingAnalysis)
 	}
+	b.pendingAnalysis = nil
 
 	return
 }
@@ -196,8 +208,7 @@ func (b *LogsBuilder) Append(logs plog.Logs) (err error) {
 
 	optimLogs := b.optimizer.Optimize(logs)
 	if b.analyzer != nil {
-		b.analyzer.Analyze(optimLogs)
-		b.analyzer.ShowStats("")
+		b.pendingAnalysis = optimLogs
 	}
 
 	attrsAccu := b.relatedData.AttrsBuilders().LogRecord().Accumulator()
@@ -0,0 +1,275 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+// Conversion of the logs Arrow records directly into the protobuf
+// representation of an OTLP `ExportLogsServiceRequest` message.
+
+import (
+	"github.com/apache/arrow/go/v12/arrow"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	arrowutils "github.com/f5/otel-arrow-adapter/pkg/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/common"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/common/otlp"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/common/wire"
+	"github.com/f5/otel-arrow-adapter/pkg/werror"
+)
+
+// Field numbers of the messages defined in `logs.proto`.
+const (
+	logsDataResourceLogs = 1
+
+	resourceLogsResource  = 1
+	resourceLogsScopeLogs = 2
+	resourceLogsSchemaUrl = 3
+
+	scopeLogsScope      = 1
+	scopeLogsLogRecords = 2
+	scopeLogsSchemaUrl  = 3
+
+	logRecordTime              = 1
+	logRecordSeverityNumber    = 2
+	logRecordSeverityText      = 3
+	logRecordBody              = 5
+	logRecordAttributes        = 6
+	logRecordDroppedAttributes = 7
+	logRecordFlags             = 8
+	logRecordTraceID           = 9
+	logRecordSpanID            = 10
+	logRecordObservedTime      = 11
+)
+
+// LogsProtoFrom appends to buf the protobuf representation of the OTLP
+// `ExportLogsServiceRequest` message encoded in the given Arrow Record and
+// returns the extended buffer. The result is identical to the protobuf
+// serialization of the [plog.Logs] returned by LogsFrom, but the pdata
+// representation of the resources, the scopes and the log records is never
+// built.
+// Note: This function consume the record.
+func LogsProtoFrom(buf []byte, record arrow.Record, relatedData *RelatedData) ([]byte, error) {
+	defer record.Release()
+
+	logRecordIDs, err := SchemaToIDs(record.Schema())
+	if err != nil {
+		return buf, werror.Wrap(err)
+	}
+
+	e := wire.NewEncoder(buf)
+	rows := int(record.NumRows())
+
+	prevResID := None
+	prevScopeID := None
+	var resSchemaUrl, scopeSchemaUrl string
+
+	for row := 0; row < rows; row++ {
+		// Process resource logs, resource, schema url (resource)
+		resID, err := otlp.ResourceIDFromRecord(record, row, logRecordIDs.Resource)
+		if err != nil {
+			return buf, werror.Wrap(err)
+		}
+		if prevResID != int(resID) {
+			if prevResID != None {
+				// Close the previous scope logs and resource logs.
+				e.String(scopeLogsSchemaUrl, scopeSchemaUrl)
+				e.EndMessage()
+				e.String(resourceLogsSchemaUrl, resSchemaUrl)
+				e.EndMessage()
+			}
+			prevResID = int(resID)
+			prevScopeID = None
+
+			e.StartMessage(logsDataResourceLogs)
+			resSchemaUrl, err = otlp.EncodeResourceFromRecord(e, resourceLogsResource, record, row, logRecordIDs.Resource, relatedData.ResAttrMapStore)
+			if err != nil {
+				return buf, werror.Wrap(err)
+			}
+		}
+
+		// Process scope logs, scope, schema url (scope)
+		scopeID, err := otlp.ScopeIDFromRecord(record, row, logRecordIDs.Scope)
+		if err != nil {
+			return buf, werror.Wrap(err)
+		}
+		if prevScopeID != int(scopeID) {
+			if prevScopeID != None {
+				e.String(scopeLogsSchemaUrl, scopeSchemaUrl)
+				e.EndMessage()
+			}
+			prevScopeID = int(scopeID)
+
+			e.StartMessage(resourceLogsScopeLogs)
+			if err = otlp.EncodeScopeFromRecord(e, scopeLogsScope, record, row, logRecordIDs.Scope, relatedData.ScopeAttrMapStore); err != nil {
+				return buf, werror.Wrap(err)
+			}
+
+			scopeSchemaUrl, err = arrowutils.StringFromRecord(record, logRecordIDs.SchemaUrl, row)
+			if err != nil {
+				return buf, werror.Wrap(err)
+			}
+		}
+
+		// Process log record fields
+		if err = encodeLogRecord(e, record, row, logRecordIDs, relatedData); err != nil {
+			return buf, werror.Wrap(err)
+		}
+	}
+
+	if prevResID != None {
+		e.String(scopeLogsSchemaUrl, scopeSchemaUrl)
+		e.EndMessage()
+		e.String(resourceLogsSchemaUrl, resSchemaUrl)
+		e.EndMessage()
+	}
+
+	return e.Bytes(), nil
+}
+
+func encodeLogRecord(e *wire.Encoder, record arrow.Record, row int, logRecordIDs *LogRecordIDs, relatedData *RelatedData) error {
+	deltaID, err := arrowutils.U16FromRecord(record, logRecordIDs.ID, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	ID := relatedData.LogRecordIDFromDelta(deltaID)
+
+	timeUnixNano, err := arrowutils.TimestampFromRecord(record, logRecordIDs.TimeUnixNano, row)
+	if err != nil {
+		return werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+	observedTimeUnixNano, err := arrowutils.TimestampFromRecord(record, logRecordIDs.ObservedTimeUnixNano, row)
+	if err != nil {
+		return werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+
+	traceID, err := arrowutils.FixedSizeBinaryFromRecord(record, logRecordIDs.TraceID, row)
+	if err != nil {
+		return werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+	if len(traceID) != 16 {
+		return werror.WrapWithContext(common.ErrInvalidTraceIDLength, map[string]interface{}{"row": row, "traceID": traceID})
+	}
+	spanID, err := arrowutils.FixedSizeBinaryFromRecord(record, logRecordIDs.SpanID, row)
+	if err != nil {
+		return werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+	if len(spanID) != 8 {
+		return werror.WrapWithContext(common.ErrInvalidSpanIDLength, map[string]interface{}{"row": row, "spanID": spanID})
+	}
+
+	severityNumber, err := arrowutils.I32FromRecord(record, logRecordIDs.SeverityNumber, row)
+	if err != nil {
+		return werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+	severityText, err := arrowutils.StringFromRecord(record, logRecordIDs.SeverityText, row)
+	if err != nil {
+		return werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+	droppedAttributesCount, err := arrowutils.U32FromRecord(record, logRecordIDs.DropAttributesCount, row)
+	if err != nil {
+		return werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+	flags, err := arrowutils.U32FromRecord(record, logRecordIDs.Flags, row)
+	if err != nil {
+		return werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+
+	var tid pcommon.TraceID
+	var sid pcommon.SpanID
+	copy(tid[:], traceID)
+	copy(sid[:], spanID)
+
+	e.StartMessage(scopeLogsLogRecords)
+	e.Fixed64(logRecordTime, uint64(timeUnixNano))
+	e.Varint(logRecordSeverityNumber, uint64(severityNumber))
+	e.String(logRecordSeverityText, severityText)
+	if err = encodeBody(e, record, row, logRecordIDs); err != nil {
+		return werror.Wrap(err)
+	}
+	if attrs := relatedData.LogRecordAttrMapStore.AttributesByID(ID); attrs != nil {
+		wire.EncodeAttributes(e, logRecordAttributes, *attrs)
+	}
+	e.Varint(logRecordDroppedAttributes, uint64(droppedAttributesCount))
+	e.Fixed32(logRecordFlags, flags)
+	wire.EncodeTraceID(e, logRecordTraceID, tid)
+	wire.EncodeSpanID(e, logRecordSpanID, sid)
+	e.Fixed64(logRecordObservedTime, uint64(observedTimeUnixNano))
+	e.EndMessage()
+
+	return nil
+}
+
+// encodeBody appends the body of a log record based on the body type.
+func encodeBody(e *wire.Encoder, record arrow.Record, row int, logRecordIDs *LogRecordIDs) error {
+	bodyStruct, err := arrowutils.StructFromRecord(record, logRecordIDs.Body, row)
+	if err != nil {
+		return werror.WrapWithContext(err, map[string]interface{}{"row": row})
+	}
+	bodyType, err := arrowutils.U8FromStruct(bodyStruct, row, logRecordIDs.BodyType)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+
+	switch pcommon.ValueType(bodyType) {
+	case pcommon.ValueTypeStr:
+		v, err := arrowutils.StringFromStruct(bodyStruct, row, logRecordIDs.BodyStr)
+		if err != nil {
+			return werror.Wrap(err)
+		}
+		wire.EncodeStrValue(e, logRecordBody, v)
+	case pcommon.ValueTypeInt:
+		v, err := arrowutils.I64FromStruct(bodyStruct, row, logRecordIDs.BodyInt)
+		if err != nil {
+			return werror.Wrap(err)
+		}
+		wire.EncodeIntValue(e, logRecordBody, v)
+	case pcommon.ValueTypeDouble:
+		v, err := arrowutils.F64FromStruct(bodyStruct, row, logRecordIDs.BodyDouble)
+		if err != nil {
+			return werror.Wrap(err)
+		}
+		wire.EncodeDoubleValue(e, logRecordBody, v)
+	case pcommon.ValueTypeBool:
+		v, err := arrowutils.BoolFromStruct(bodyStruct, row, logRecordIDs.BodyBool)
+		if err != nil {
+			return werror.Wrap(err)
+		}
+		wire.EncodeBoolValue(e, logRecordBody, v)
+	case pcommon.ValueTypeBytes:
+		v, err := arrowutils.BinaryFromStruct(bodyStruct, row, logRecordIDs.BodyBytes)
+		if err != nil {
+			return werror.Wrap(err)
+		}
+		wire.EncodeBytesValue(e, logRecordBody, v)
+	case pcommon.ValueTypeSlice, pcommon.ValueTypeMap:
+		// Complex bodies are serialized in the Arrow record, they are
+		// deserialized into a pdata value before being encoded.
+		v, err := arrowutils.BinaryFromStruct(bodyStruct, row, logRecordIDs.BodySer)
+		if err != nil {
+			return werror.Wrap(err)
+		}
+		body := pcommon.NewValueEmpty()
+		if err = common.Deserialize(v, body); err != nil {
+			return werror.Wrap(err)
+		}
+		wire.EncodeAnyValue(e, logRecordBody, body)
+	default:
+		// silently ignore unknown types to avoid DOS attacks (the body is
+		// encoded as an empty value)
+		e.StartMessage(logRecordBody)
+		e.EndMessage()
+	}
+
+	return nil
+}
@@ -14,20 +14,27 @@
  * limitations under the License.
  *
  */
-
 package arrow
 
-// ToDo the metrics analyzer is not complete yet. It is a work in progress and will be completed in a future PRs. The analyzer is only used for troubleshooting and debugging purposes.
-
 import (
 	"fmt"
 
 	"github.com/HdrHistogram/hdrhistogram-go"
 	"github.com/axiomhq/hyperloglog"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 
 	carrow "github.com/f5/otel-arrow-adapter/pkg/otel/common/arrow"
 )
 
+const None = ""
+
+// A metrics analyzer is a tool designed to generate statistics about the
+// structure and content distribution of a stream of OpenTelemetry Protocol
+// (OTLP) metrics. By using the -stats flag in the benchmark tool, the results
+// of this analysis can be conveniently displayed on the console to
+// troubleshoot compression ratio issues.
+
 type (
 	MetricsAnalyzer struct {
 		MetricCount          int64
@@ -52,18 +59,30 @@ type (
 	}
 
 	MetricsStats struct {
+		TotalCount   int64
+		Distribution *hdrhistogram.Histogram
+		Name         *carrow.StringStats
+		Description  *carrow.StringStats
+		Unit         *carrow.StringStats
+
+		// Number of metrics per type.
+		Gauges                int64
+		Sums                  int64
+		Histograms            int64
+		ExponentialHistograms int64
+		Summaries             int64
+
+		DataPoints *DataPointsStats
+	}
+
+	DataPointsStats struct {
 		TotalCount        int64
 		Distribution      *hdrhistogram.Histogram
+		StartTimeUnixNano *carrow.TimestampStats
+		TimeUnixNano      *carrow.TimestampStats
 		Attributes        *carrow.AttributesStats
-		SharedAttributes  *carrow.AttributesStats
-		TimeIntervalStats *carrow.TimeIntervalStats
-		Name              *carrow.StringStats
-		SpanID            *hyperloglog.Sketch
-		TraceID           *hyperloglog.Sketch
-		ParentSpanID      *hyperloglog.Sketch
-		Kind              *hyperloglog.Sketch
-		TraceState        *hyperloglog.Sketch
-		StatusStats       *carrow.StatusStats
+		Flags             *hyperloglog.Sketch
+		Exemplars         int64
 	}
 )
 
@@ -110,28 +129,55 @@ func (t *MetricsAnalyzer) ShowStats(indent string) {
 }
 
 func (r *ResourceMetricsStats) UpdateWith(metrics *MetricsOptimized) {
-	// ToDo adapt the analyzer to the new metrics format
-	//resMetrics := metrics.ResourceMetrics
-	//
-	//for ID := range metrics.ResourceMetricsIdx {
-	//	r.ResMetricsIDsDistinct.Insert([]byte(ID))
-	//}
-	//
-	//r.TotalCount += int64(len(resMetrics))
-	//carrow.RequireNoError(r.Distribution.RecordValue(int64(len(resMetrics))))
-	//
-	//for _, rs := range resMetrics {
-	//	r.ResourceStats.UpdateWith(rs.Resource)
-	//	r.ScopeMetricsStats.UpdateWith(rs.ScopeMetrics, rs.ScopeMetricsIdx)
-	//	r.SchemaUrlStats.UpdateWith(rs.ResourceSchemaUrl)
-	//}
+	// The metrics are sorted by resource and scope (or by type and name, the
+	// resource and scope metrics are then counted each time they change).
+	prevResID := None
+	prevScopeID := None
+
+	resMetricsCount := 0
+	scopeMetricsCount := 0
+	metricsPerScopeMetrics := 0
+
+	s := r.ScopeMetricsStats
+	for _, metric := range metrics.Metrics {
+		newResource := prevResID != metric.ResourceMetricsID
+		if newResource {
+			prevResID = metric.ResourceMetricsID
+			resMetricsCount++
+			r.ResMetricsIDsDistinct.Insert([]byte(metric.ResourceMetricsID))
+			r.ResourceStats.UpdateWith(metric.Resource)
+			r.SchemaUrlStats.UpdateWith(metric.ResourceSchemaUrl)
+		}
+
+		if newResource || prevScopeID != metric.ScopeMetricsID {
+			prevScopeID = metric.ScopeMetricsID
+			if metricsPerScopeMetrics > 0 {
+				carrow.RequireNoError(s.MetricsStats.Distribution.RecordValue(int64(metricsPerScopeMetrics)))
+			}
+			scopeMetricsCount++
+			s.ScopeMetricsIDsDistinct.Insert([]byte(metric.ScopeMetricsID))
+			s.ScopeStats.UpdateWith(metric.Scope)
+			s.SchemaUrlStats.UpdateWith(metric.ScopeSchemaUrl)
+			metricsPerScopeMetrics = 0
+		}
+
+		s.MetricsStats.UpdateWith(*metric.Metric)
+		metricsPerScopeMetrics++
+	}
+	if metricsPerScopeMetrics > 0 {
+		carrow.RequireNoError(s.MetricsStats.Distribution.RecordValue(int64(metricsPerScopeMetrics)))
+	}
+
+	r.TotalCount += int64(resMetricsCount)
+	carrow.RequireNoError(r.Distribution.RecordValue(int64(resMetricsCount)))
+	carrow.RequireNoError(s.Distribution.RecordValue(int64(scopeMetricsCount)))
 }
 
 func (r *ResourceMetricsStats) ShowStats(indent string) {
 	fmt.Printf("%s                                 |         Distribution per request        |\n", indent)
 	print(carrow.Green)
 	fmt.Printf("%sResourceMetrics%s |    Total|Distinct|   Min|   Max|  Mean| Stdev|   P50|   P99|\n", indent, carrow.ColorReset)
-	fmt.Printf("%s              |%9d|%8d|%6d|%6d|%6.1f|%6.1f|%6d|%6d|\n", indent,
+	fmt.Printf("%s                |%9d|%8d|%6d|%6d|%6.1f|%6.1f|%6d|%6d|\n", indent,
 		r.TotalCount, r.ResMetricsIDsDistinct.Estimate(), r.Distribution.Min(), r.Distribution.Max(), r.Distribution.Mean(), r.Distribution.StdDev(), r.Distribution.ValueAtQuantile(50), r.Distribution.ValueAtQuantile(99),
 	)
 	indent += "  "
@@ -140,25 +186,10 @@ func (r *ResourceMetricsStats) ShowStats(indent string) {
 	r.SchemaUrlStats.ShowStats(indent)
 }
 
-// ToDo adapt the analyzer to the new metrics format
-//func (s *ScopeMetricsStats) UpdateWith(scopeMetrics []*ScopeMetricsGroup, scopeMetricsIdx map[string]int) {
-//	carrow.RequireNoError(s.Distribution.RecordValue(int64(len(scopeMetrics))))
-//
-//	for ID := range scopeMetricsIdx {
-//		s.ScopeMetricsIDsDistinct.Insert([]byte(ID))
-//	}
-//
-//	for _, ss := range scopeMetrics {
-//		s.ScopeStats.UpdateWith(ss.Scope)
-//		s.MetricsStats.UpdateWith(ss)
-//		s.SchemaUrlStats.UpdateWith(ss.ScopeSchemaUrl)
-//	}
-//}
-
 func (s *ScopeMetricsStats) ShowStats(indent string) {
 	print(carrow.Green)
 	fmt.Printf("%sScopeMetrics%s |Distinct|   Min|   Max|  Mean| Stdev|   P50|   P99|\n", indent, carrow.ColorReset)
-	fmt.Printf("%s           |%8d|%6d|%6d|%6.1f|%6.1f|%6d|%6d|\n", indent,
+	fmt.Printf("%s             |%8d|%6d|%6d|%6.1f|%6.1f|%6d|%6d|\n", indent,
 		s.ScopeMetricsIDsDistinct.Estimate(), s.Distribution.Min(), s.Distribution.Max(), s.Distribution.Mean(), s.Distribution.StdDev(), s.Distribution.ValueAtQuantile(50), s.Distribution.ValueAtQuantile(99),
 	)
 	s.ScopeStats.ShowStats(indent + "  ")
@@ -168,70 +199,109 @@ func (s *ScopeMetricsStats) ShowStats(indent string) {
 
 func NewMetricsStats() *MetricsStats {
 	return &MetricsStats{
-		Distribution:      hdrhistogram.New(0, 1000000, 2),
-		Attributes:        carrow.NewAttributesStats(),
-		SharedAttributes:  carrow.NewAttributesStats(),
-		TimeIntervalStats: carrow.NewTimeIntervalStats(),
-		SpanID:            hyperloglog.New16(),
-		TraceID:           hyperloglog.New16(),
-		ParentSpanID:      hyperloglog.New16(),
-		Name:              carrow.NewStringStats(),
-		Kind:              hyperloglog.New16(),
-		TraceState:        hyperloglog.New16(),
-		StatusStats:       carrow.NewStatusStats(),
+		Distribution: hdrhistogram.New(0, 1000000, 2),
+		Name:         carrow.NewStringStats(),
+		Description:  carrow.NewStringStats(),
+		Unit:         carrow.NewStringStats(),
+		DataPoints:   NewDataPointsStats(),
 	}
 }
 
-// ToDo adapt the analyzer to the new metrics format
-//func (s *MetricsStats) UpdateWith(sm *ScopeMetricsGroup) {
-//	metrics := sm.Metrics
-//	carrow.RequireNoError(s.Distribution.RecordValue(int64(len(metrics))))
-
-//sharedAttrs := pcommon.NewMap()
-//sm.SharedData.sharedAttributes.CopyTo(sharedAttrs)
-//s.SharedAttributes.UpdateWith(sharedAttrs, 0)
-//
-//s.TimeIntervalStats.UpdateWithSpans(metrics)
-//
-//for _, span := range metrics {
-//	s.Attributes.UpdateWith(span.Attributes(), span.DroppedAttributesCount())
-//	s.Name.UpdateWith(span.Name())
-//	s.SpanID.Insert([]byte(span.SpanID().String()))
-//	s.TraceID.Insert([]byte(span.TraceID().String()))
-//	s.ParentSpanID.Insert([]byte(span.ParentSpanID().String()))
-//	s.Kind.Insert([]byte(span.Kind().String()))
-//	s.TraceState.Insert([]byte(span.TraceState().AsRaw()))
-//	s.Events.UpdateWith(span.Events(), span.DroppedEventsCount(), sm.SharedData.sharedEventAttributes)
-//	s.Links.UpdateWith(span.Links(), span.DroppedLinksCount(), sm.SharedData.sharedLinkAttributes)
-//
-//	b := make([]byte, 8)
-//	binary.LittleEndian.PutUint64(b, uint64(span.DroppedEventsCount()))
-//	s.DropEventsCount.Insert(b)
-//	binary.LittleEndian.PutUint64(b, uint64(span.DroppedLinksCount()))
-//	s.DropLinksCount.Insert(b)
-//	s.StatusStats.UpdateWith(span.Status())
-//}
-
-//	s.TotalCount += int64(len(metrics))
-//}
+// UpdateWith updates the stats with a metric and its data points. The
+// distribution of the metrics per scope metrics is recorded by
+// ResourceMetricsStats.
+func (s *MetricsStats) UpdateWith(metric pmetric.Metric) {
+	s.TotalCount++
+	s.Name.UpdateWith(metric.Name())
+	s.Description.UpdateWith(metric.Description())
+	s.Unit.UpdateWith(metric.Unit())
+
+	dps := s.DataPoints
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		s.Gauges++
+		dps.updateWithNumberDataPoints(metric.Gauge().DataPoints())
+	case pmetric.MetricTypeSum:
+		s.Sums++
+		dps.updateWithNumberDataPoints(metric.Sum().DataPoints())
+	case pmetric.MetricTypeHistogram:
+		s.Histograms++
+		points := metric.Histogram().DataPoints()
+		carrow.RequireNoError(dps.Distribution.RecordValue(int64(points.Len())))
+		for i := 0; i < points.Len(); i++ {
+			dp := points.At(i)
+			dps.updateWith(dp.StartTimestamp(), dp.Timestamp(), dp.Attributes(), dp.Flags(), dp.Exemplars().Len())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		s.ExponentialHistograms++
+		points := metric.ExponentialHistogram().DataPoints()
+		carrow.RequireNoError(dps.Distribution.RecordValue(int64(points.Len())))
+		for i := 0; i < points.Len(); i++ {
+			dp := points.At(i)
+			dps.updateWith(dp.StartTimestamp(), dp.Timestamp(), dp.Attributes(), dp.Flags(), dp.Exemplars().Len())
+		}
+	case pmetric.MetricTypeSummary:
+		s.Summaries++
+		points := metric.Summary().DataPoints()
+		carrow.RequireNoError(dps.Distribution.RecordValue(int64(points.Len())))
+		for i := 0; i < points.Len(); i++ {
+			dp := points.At(i)
+			dps.updateWith(dp.StartTimestamp(), dp.Timestamp(), dp.Attributes(), dp.Flags(), 0)
+		}
+	}
+}
 
 func (s *MetricsStats) ShowStats(indent string) {
 	print(carrow.Green)
-	fmt.Printf("%sSpans%s |   Total|   Min|   Max|  Mean|  Stdev|   P50|   P99|\n", indent, carrow.ColorReset)
-	fmt.Printf("%s      |%8d|%6d|%6d|%6.1f|%7.1f|%6d|%6d|\n", indent,
+	fmt.Printf("%sMetrics%s |   Total|   Min|   Max|  Mean|  Stdev|   P50|   P99|\n", indent, carrow.ColorReset)
+	fmt.Printf("%s        |%8d|%6d|%6d|%6.1f|%7.1f|%6d|%6d|\n", indent,
 		s.TotalCount, s.Distribution.Min(), s.Distribution.Max(), s.Distribution.Mean(), s.Distribution.StdDev(), s.Distribution.ValueAtQuantile(50), s.Distribution.ValueAtQuantile(99),
 	)
 	indent += "  "
-	s.TimeIntervalStats.ShowStats(indent)
+	fmt.Printf("%s%sTypes%s (Gauge=%d, Sum=%d, Histogram=%d, ExponentialHistogram=%d, Summary=%d)\n", indent, carrow.Green, carrow.ColorReset,
+		s.Gauges, s.Sums, s.Histograms, s.ExponentialHistograms, s.Summaries)
 	s.Name.ShowStats("Name", indent)
-	fmt.Printf("%s             |Distinct|   Total|%%Distinct|\n", indent)
-	fmt.Printf("%s%sSpanID%s       |%8d|%8d|%8.1f%%|\n", indent, carrow.Green, carrow.ColorReset, s.SpanID.Estimate(), s.TotalCount, 100.0*float64(s.SpanID.Estimate())/float64(s.TotalCount))
-	fmt.Printf("%s%sTraceID%s      |%8d|%8d|%8.1f%%|\n", indent, carrow.Green, carrow.ColorReset, s.TraceID.Estimate(), s.TotalCount, 100.0*float64(s.TraceID.Estimate())/float64(s.TotalCount))
-	fmt.Printf("%s%sParentSpanID%s |%8d|%8d|%8.1f%%|\n", indent, carrow.Green, carrow.ColorReset, s.ParentSpanID.Estimate(), s.TotalCount, 100.0*float64(s.ParentSpanID.Estimate())/float64(s.TotalCount))
-	fmt.Printf("%s%sKind%s (Distinct=%d)\n", indent, carrow.Green, carrow.ColorReset, s.Kind.Estimate())
-	fmt.Printf("%s%sTraceState%s (Distinct=%d)\n", indent, carrow.Green, carrow.ColorReset, s.TraceState.Estimate())
+	s.Description.ShowStats("Description", indent)
+	s.Unit.ShowStats("Unit", indent)
+	s.DataPoints.ShowStats(indent)
+}
+
+func NewDataPointsStats() *DataPointsStats {
+	return &DataPointsStats{
+		Distribution:      hdrhistogram.New(0, 1000000, 2),
+		StartTimeUnixNano: carrow.NewTimestampStats(),
+		TimeUnixNano:      carrow.NewTimestampStats(),
+		Attributes:        carrow.NewAttributesStats(),
+		Flags:             hyperloglog.New16(),
+	}
+}
 
+func (s *DataPointsStats) updateWithNumberDataPoints(points pmetric.NumberDataPointSlice) {
+	carrow.RequireNoError(s.Distribution.RecordValue(int64(points.Len())))
+	for i := 0; i < points.Len(); i++ {
+		dp := points.At(i)
+		s.updateWith(dp.StartTimestamp(), dp.Timestamp(), dp.Attributes(), dp.Flags(), dp.Exemplars().Len())
+	}
+}
+
+func (s *DataPointsStats) updateWith(startTime, time pcommon.Timestamp, attrs pcommon.Map, flags pmetric.DataPointFlags, exemplars int) {
+	s.TotalCount++
+	s.StartTimeUnixNano.UpdateWith(startTime)
+	s.TimeUnixNano.UpdateWith(time)
+	s.Attributes.UpdateWith(attrs, 0)
+	s.Flags.Insert([]byte{byte(flags)})
+	s.Exemplars += int64(exemplars)
+}
+
+func (s *DataPointsStats) ShowStats(indent string) {
+	print(carrow.Green)
+	fmt.Printf("%sDataPoints%s |   Total|   Min|   Max|  Mean|  Stdev|   P50|   P99|Exemplars|\n", indent, carrow.ColorReset)
+	fmt.Printf("%s           |%8d|%6d|%6d|%6.1f|%7.1f|%6d|%6d|%9d|\n", indent,
+		s.TotalCount, s.Distribution.Min(), s.Distribution.Max(), s.Distribution.Mean(), s.Distribution.StdDev(), s.Distribution.ValueAtQuantile(50), s.Distribution.ValueAtQuantile(99), s.Exemplars,
+	)
+	indent += "  "
+	s.StartTimeUnixNano.ShowStats("StartTimeUnixNano", indent)
+	s.TimeUnixNano.ShowStats("TimeUnixNano", indent)
+	fmt.Printf("%s%sFlags%s (Distinct=%d)\n", indent, carrow.Green, carrow.ColorReset, s.Flags.Estimate())
 	s.Attributes.ShowStats(indent, "Attributes", carrow.Green)
-	s.SharedAttributes.ShowStats(indent, "SharedAttributes", carrow.Cyan)
-	s.StatusStats.ShowStats(indent)
 }
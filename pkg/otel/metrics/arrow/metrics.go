@@ -62,6 +62,9 @@ type MetricsBuilder struct {
 
 	optimizer *MetricsOptimizer
 	analyzer  *MetricsAnalyzer
+	// Batch appended since the last record, analyzed once the record is
+	// built (the batch is appended again after a schema update).
+	pendingAnalysis *MetricsOptimized
 
 	relatedData *RelatedData
 }
@@ -130,6 +133,12 @@ func (b *MetricsBuilder) RelatedData() *RelatedData {
 	return b.relatedData
 }
 
+// Analyzer returns the analyzer of the batches appended to the builder, nil
+// if the statistics are not enabled (see config.WithStats).
+func (b *MetricsBuilder) Analyzer() *MetricsAnalyzer {
+	return b.analyzer
+}
+
 // Build builds an Arrow Record from the builder.
 //
 // Once the array is no longer needed, Release() must be called to free the
@@ -145,7 +154,10 @@ func (b *MetricsBuilder) Build() (record arrow.Record, err error) {
 		if initErr != nil {
 			err = werror.Wrap(initErr)
 		}
+	} else if b.pendingAnalysis != nil {
+		b.analyzer.Analyze(b.pendingAnalysis)
 	}
+	b.pendingAnalysis = nil
 
 	return
 }
@@ -158,8 +170,7 @@ func (b *MetricsBuilder) Append(metrics pmetric.Metrics) error {
 
 	optimizedMetrics := b.optimizer.Optimize(metrics)
 	if b.analyzer != nil {
-		b.analyzer.Analyze(optimizedMetrics)
-		b.analyzer.ShowStats("")
+		b.pendingAnalysis = optimizedMetrics
 	}
 
 	metricID := uint16(0)
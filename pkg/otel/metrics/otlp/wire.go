@@ -0,0 +1,393 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+// Conversion of the metrics Arrow records directly into the protobuf
+// representation of an OTLP `ExportMetricsServiceRequest` message.
+
+import (
+	"github.com/apache/arrow/go/v12/arrow"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	arrowutils "github.com/f5/otel-arrow-adapter/pkg/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/common/otlp"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/common/wire"
+	"github.com/f5/otel-arrow-adapter/pkg/werror"
+)
+
+// Field numbers of the messages defined in `metrics.proto`.
+const (
+	metricsDataResourceMetrics = 1
+
+	resourceMetricsResource     = 1
+	resourceMetricsScopeMetrics = 2
+	resourceMetricsSchemaUrl    = 3
+
+	scopeMetricsScope     = 1
+	scopeMetricsMetrics   = 2
+	scopeMetricsSchemaUrl = 3
+
+	metricName                 = 1
+	metricDescription          = 2
+	metricUnit                 = 3
+	metricGauge                = 5
+	metricSum                  = 7
+	metricHistogram            = 9
+	metricExponentialHistogram = 10
+	metricSummary              = 11
+
+	// Common to Gauge, Sum, Histogram, ExponentialHistogram and Summary.
+	dataPoints             = 1
+	aggregationTemporality = 2
+	sumIsMonotonic         = 3
+
+	ndpStartTime  = 2
+	ndpTime       = 3
+	ndpAsDouble   = 4
+	ndpExemplars  = 5
+	ndpAsInt      = 6
+	ndpAttributes = 7
+	ndpFlags      = 8
+
+	hdpStartTime      = 2
+	hdpTime           = 3
+	hdpCount          = 4
+	hdpSum            = 5
+	hdpBucketCounts   = 6
+	hdpExplicitBounds = 7
+	hdpExemplars      = 8
+	hdpAttributes     = 9
+	hdpFlags          = 10
+	hdpMin            = 11
+	hdpMax            = 12
+
+	ehdpAttributes = 1
+	ehdpStartTime  = 2
+	ehdpTime       = 3
+	ehdpCount      = 4
+	ehdpSum        = 5
+	ehdpScale      = 6
+	ehdpZeroCount  = 7
+	ehdpPositive   = 8
+	ehdpNegative   = 9
+	ehdpFlags      = 10
+	ehdpExemplars  = 11
+	ehdpMin        = 12
+	ehdpMax        = 13
+
+	bucketsOffset       = 1
+	bucketsBucketCounts = 2
+
+	sdpStartTime      = 2
+	sdpTime           = 3
+	sdpCount          = 4
+	sdpSum            = 5
+	sdpQuantileValues = 6
+	sdpAttributes     = 7
+	sdpFlags          = 8
+
+	quantileValueQuantile = 1
+	quantileValueValue    = 2
+
+	exemplarTime               = 2
+	exemplarAsDouble           = 3
+	exemplarSpanID             = 4
+	exemplarTraceID            = 5
+	exemplarAsInt              = 6
+	exemplarFilteredAttributes = 7
+)
+
+// MetricsProtoFrom appends to buf the protobuf representation of the OTLP
+// `ExportMetricsServiceRequest` message encoded in the given Arrow Record and
+// returns the extended buffer. The result is identical to the protobuf
+// serialization of the [pmetric.Metrics] returned by MetricsFrom, but the
+// pdata representation of the resources, the scopes and the metrics is never
+// built.
+// Note: This function consume the record.
+func MetricsProtoFrom(buf []byte, record arrow.Record, relatedData *RelatedData) ([]byte, error) {
+	defer record.Release()
+
+	metricsIDs, err := SchemaToIds(record.Schema())
+	if err != nil {
+		return buf, werror.Wrap(err)
+	}
+
+	e := wire.NewEncoder(buf)
+	rows := int(record.NumRows())
+
+	prevResID := None
+	prevScopeID := None
+	var resSchemaUrl, scopeSchemaUrl string
+
+	for row := 0; row < rows; row++ {
+		// Process resource metrics, resource, schema url (resource)
+		resID, err := otlp.ResourceIDFromRecord(record, row, metricsIDs.Resource)
+		if err != nil {
+			return buf, werror.Wrap(err)
+		}
+		if prevResID != int(resID) {
+			if prevResID != None {
+				// Close the previous scope metrics and resource metrics.
+				e.String(scopeMetricsSchemaUrl, scopeSchemaUrl)
+				e.EndMessage()
+				e.String(resourceMetricsSchemaUrl, resSchemaUrl)
+				e.EndMessage()
+			}
+			prevResID = int(resID)
+			prevScopeID = None
+
+			e.StartMessage(metricsDataResourceMetrics)
+			resSchemaUrl, err = otlp.EncodeResourceFromRecord(e, resourceMetricsResource, record, row, metricsIDs.Resource, relatedData.ResAttrMapStore)
+			if err != nil {
+				return buf, werror.Wrap(err)
+			}
+		}
+
+		// Process scope metrics, scope, schema url (scope)
+		scopeID, err := otlp.ScopeIDFromRecord(record, row, metricsIDs.Scope)
+		if err != nil {
+			return buf, werror.Wrap(err)
+		}
+		if prevScopeID != int(scopeID) {
+			if prevScopeID != None {
+				e.String(scopeMetricsSchemaUrl, scopeSchemaUrl)
+				e.EndMessage()
+			}
+			prevScopeID = int(scopeID)
+
+			e.StartMessage(resourceMetricsScopeMetrics)
+			if err = otlp.EncodeScopeFromRecord(e, scopeMetricsScope, record, row, metricsIDs.Scope, relatedData.ScopeAttrMapStore); err != nil {
+				return buf, werror.Wrap(err)
+			}
+
+			scopeSchemaUrl, err = arrowutils.StringFromRecord(record, metricsIDs.SchemaUrl, row)
+			if err != nil {
+				return buf, werror.Wrap(err)
+			}
+		}
+
+		// Process metric fields
+		if err = encodeMetric(e, record, row, metricsIDs, relatedData); err != nil {
+			return buf, werror.Wrap(err)
+		}
+	}
+
+	if prevResID != None {
+		e.String(scopeMetricsSchemaUrl, scopeSchemaUrl)
+		e.EndMessage()
+		e.String(resourceMetricsSchemaUrl, resSchemaUrl)
+		e.EndMessage()
+	}
+
+	return e.Bytes(), nil
+}
+
+func encodeMetric(e *wire.Encoder, record arrow.Record, row int, metricsIDs *MetricsIds, relatedData *RelatedData) error {
+	deltaID, err := arrowutils.U16FromRecord(record, metricsIDs.ID, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	ID := relatedData.MetricIDFromDelta(deltaID)
+
+	metricType, err := arrowutils.U8FromRecord(record, metricsIDs.MetricType, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	name, err := arrowutils.StringFromRecord(record, metricsIDs.Name, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	description, err := arrowutils.StringFromRecord(record, metricsIDs.Description, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	unit, err := arrowutils.StringFromRecord(record, metricsIDs.Unit, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	temporality, err := arrowutils.I32FromRecord(record, metricsIDs.AggregationTemporality, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	isMonotonic, err := arrowutils.BoolFromRecord(record, metricsIDs.IsMonotonic, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+
+	e.StartMessage(scopeMetricsMetrics)
+	e.String(metricName, name)
+	e.String(metricDescription, description)
+	e.String(metricUnit, unit)
+
+	switch pmetric.MetricType(metricType) {
+	case pmetric.MetricTypeGauge:
+		e.StartMessage(metricGauge)
+		dps := relatedData.NumberDataPointsStore.NumberDataPointsByID(ID)
+		for i := 0; i < dps.Len(); i++ {
+			encodeNumberDataPoint(e, dps.At(i))
+		}
+		e.EndMessage()
+	case pmetric.MetricTypeSum:
+		e.StartMessage(metricSum)
+		dps := relatedData.NumberDataPointsStore.NumberDataPointsByID(ID)
+		for i := 0; i < dps.Len(); i++ {
+			encodeNumberDataPoint(e, dps.At(i))
+		}
+		e.Varint(aggregationTemporality, uint64(temporality))
+		e.Bool(sumIsMonotonic, isMonotonic)
+		e.EndMessage()
+	case pmetric.MetricTypeHistogram:
+		e.StartMessage(metricHistogram)
+		dps := relatedData.HistogramDataPointsStore.HistogramMetricsByID(ID)
+		for i := 0; i < dps.Len(); i++ {
+			encodeHistogramDataPoint(e, dps.At(i))
+		}
+		e.Varint(aggregationTemporality, uint64(temporality))
+		e.EndMessage()
+	case pmetric.MetricTypeExponentialHistogram:
+		e.StartMessage(metricExponentialHistogram)
+		dps := relatedData.EHistogramDataPointsStore.EHistogramMetricsByID(ID)
+		for i := 0; i < dps.Len(); i++ {
+			encodeEHistogramDataPoint(e, dps.At(i))
+		}
+		e.Varint(aggregationTemporality, uint64(temporality))
+		e.EndMessage()
+	case pmetric.MetricTypeSummary:
+		e.StartMessage(metricSummary)
+		dps := relatedData.SummaryDataPointsStore.SummaryMetricsByID(ID)
+		for i := 0; i < dps.Len(); i++ {
+			encodeSummaryDataPoint(e, dps.At(i))
+		}
+		e.EndMessage()
+	default:
+		// Todo log unknown metric type
+	}
+	e.EndMessage()
+
+	return nil
+}
+
+func encodeNumberDataPoint(e *wire.Encoder, dp pmetric.NumberDataPoint) {
+	e.StartMessage(dataPoints)
+	e.Fixed64(ndpStartTime, uint64(dp.StartTimestamp()))
+	e.Fixed64(ndpTime, uint64(dp.Timestamp()))
+	encodeExemplars(e, ndpExemplars, dp.Exemplars())
+	// The `value` oneof is encoded after the exemplars like the pdata
+	// marshaler does.
+	switch dp.ValueType() {
+	case pmetric.NumberDataPointValueTypeDouble:
+		e.OneofDouble(ndpAsDouble, dp.DoubleValue())
+	case pmetric.NumberDataPointValueTypeInt:
+		e.OneofFixed64(ndpAsInt, uint64(dp.IntValue()))
+	}
+	wire.EncodeAttributes(e, ndpAttributes, dp.Attributes())
+	e.Varint(ndpFlags, uint64(dp.Flags()))
+	e.EndMessage()
+}
+
+func encodeHistogramDataPoint(e *wire.Encoder, dp pmetric.HistogramDataPoint) {
+	e.StartMessage(dataPoints)
+	e.Fixed64(hdpStartTime, uint64(dp.StartTimestamp()))
+	e.Fixed64(hdpTime, uint64(dp.Timestamp()))
+	e.Fixed64(hdpCount, dp.Count())
+	if dp.HasSum() {
+		e.OneofDouble(hdpSum, dp.Sum())
+	}
+	e.PackedFixed64(hdpBucketCounts, dp.BucketCounts().AsRaw())
+	e.PackedDouble(hdpExplicitBounds, dp.ExplicitBounds().AsRaw())
+	encodeExemplars(e, hdpExemplars, dp.Exemplars())
+	wire.EncodeAttributes(e, hdpAttributes, dp.Attributes())
+	e.Varint(hdpFlags, uint64(dp.Flags()))
+	if dp.HasMin() {
+		e.OneofDouble(hdpMin, dp.Min())
+	}
+	if dp.HasMax() {
+		e.OneofDouble(hdpMax, dp.Max())
+	}
+	e.EndMessage()
+}
+
+func encodeEHistogramDataPoint(e *wire.Encoder, dp pmetric.ExponentialHistogramDataPoint) {
+	e.StartMessage(dataPoints)
+	wire.EncodeAttributes(e, ehdpAttributes, dp.Attributes())
+	e.Fixed64(ehdpStartTime, uint64(dp.StartTimestamp()))
+	e.Fixed64(ehdpTime, uint64(dp.Timestamp()))
+	e.Fixed64(ehdpCount, dp.Count())
+	if dp.HasSum() {
+		e.OneofDouble(ehdpSum, dp.Sum())
+	}
+	e.Sint32(ehdpScale, dp.Scale())
+	e.Fixed64(ehdpZeroCount, dp.ZeroCount())
+	encodeBuckets(e, ehdpPositive, dp.Positive())
+	encodeBuckets(e, ehdpNegative, dp.Negative())
+	e.Varint(ehdpFlags, uint64(dp.Flags()))
+	encodeExemplars(e, ehdpExemplars, dp.Exemplars())
+	if dp.HasMin() {
+		e.OneofDouble(ehdpMin, dp.Min())
+	}
+	if dp.HasMax() {
+		e.OneofDouble(ehdpMax, dp.Max())
+	}
+	e.EndMessage()
+}
+
+// encodeBuckets appends the given buckets. Like the pdata marshaler, the
+// field is always present.
+func encodeBuckets(e *wire.Encoder, num protowire.Number, buckets pmetric.ExponentialHistogramDataPointBuckets) {
+	e.StartMessage(num)
+	e.Sint32(bucketsOffset, buckets.Offset())
+	e.PackedVarint(bucketsBucketCounts, buckets.BucketCounts().AsRaw())
+	e.EndMessage()
+}
+
+func encodeSummaryDataPoint(e *wire.Encoder, dp pmetric.SummaryDataPoint) {
+	e.StartMessage(dataPoints)
+	e.Fixed64(sdpStartTime, uint64(dp.StartTimestamp()))
+	e.Fixed64(sdpTime, uint64(dp.Timestamp()))
+	e.Fixed64(sdpCount, dp.Count())
+	e.Double(sdpSum, dp.Sum())
+	qvs := dp.QuantileValues()
+	for i := 0; i < qvs.Len(); i++ {
+		qv := qvs.At(i)
+		e.StartMessage(sdpQuantileValues)
+		e.Double(quantileValueQuantile, qv.Quantile())
+		e.Double(quantileValueValue, qv.Value())
+		e.EndMessage()
+	}
+	wire.EncodeAttributes(e, sdpAttributes, dp.Attributes())
+	e.Varint(sdpFlags, uint64(dp.Flags()))
+	e.EndMessage()
+}
+
+func encodeExemplars(e *wire.Encoder, num protowire.Number, exemplars pmetric.ExemplarSlice) {
+	for i := 0; i < exemplars.Len(); i++ {
+		exemplar := exemplars.At(i)
+		e.StartMessage(num)
+		e.Fixed64(exemplarTime, uint64(exemplar.Timestamp()))
+		wire.EncodeSpanID(e, exemplarSpanID, exemplar.SpanID())
+		wire.EncodeTraceID(e, exemplarTraceID, exemplar.TraceID())
+		// The `value` oneof is encoded after the trace id like the pdata
+		// marshaler does.
+		switch exemplar.ValueType() {
+		case pmetric.ExemplarValueTypeDouble:
+			e.OneofDouble(exemplarAsDouble, exemplar.DoubleValue())
+		case pmetric.ExemplarValueTypeInt:
+			e.OneofFixed64(exemplarAsInt, uint64(exemplar.IntValue()))
+		}
+		wire.EncodeAttributes(e, exemplarFilteredAttributes, exemplar.FilteredAttributes())
+		e.EndMessage()
+	}
+}
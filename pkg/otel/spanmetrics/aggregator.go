@@ -0,0 +1,461 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	carrow "github.com/f5/otel-arrow-adapter/pkg/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/common/otlp"
+	tracesotlp "github.com/f5/otel-arrow-adapter/pkg/otel/traces/otlp"
+	"github.com/f5/otel-arrow-adapter/pkg/record_message"
+	"github.com/f5/otel-arrow-adapter/pkg/werror"
+)
+
+const (
+	// ScopeName is the name of the instrumentation scope of the produced
+	// metrics.
+	ScopeName = "github.com/f5/otel-arrow-adapter/pkg/otel/spanmetrics"
+
+	// DefaultServiceKey is the resource attribute identifying the service of
+	// a span.
+	DefaultServiceKey = "service.name"
+
+	CallsMetric    = "calls"
+	DurationMetric = "duration"
+
+	SpanNameKey   = "span.name"
+	SpanKindKey   = "span.kind"
+	StatusCodeKey = "status.code"
+
+	// OverflowKey is the attribute (set to true) of the data points of the
+	// overflow series, see WithMaxSeries.
+	OverflowKey = "otel.metric.overflow"
+)
+
+// DefaultBuckets are the upper bounds of the duration histogram buckets (same
+// as the contrib spanmetrics connector).
+var DefaultBuckets = []time.Duration{
+	2 * time.Millisecond,
+	4 * time.Millisecond,
+	6 * time.Millisecond,
+	8 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	400 * time.Millisecond,
+	800 * time.Millisecond,
+	1 * time.Second,
+	1400 * time.Millisecond,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	15 * time.Second,
+}
+
+// Aggregator accumulates the number of calls and the duration histogram of
+// the spans of successive batches, grouped by service, span name, span kind
+// and status code. The metrics are cumulative since the start time of the
+// aggregator.
+//
+// An Aggregator is not safe for concurrent use.
+type Aggregator struct {
+	serviceKey string
+	buckets    []time.Duration
+	start      pcommon.Timestamp
+	maxSeries  int
+	expiration time.Duration
+
+	series map[key]*series
+	// expired is set once a series has expired.
+	expired bool
+}
+
+// Option configures an Aggregator.
+type Option func(*Aggregator)
+
+// key identifies a group of spans.
+type key struct {
+	service string
+	name    string
+	kind    ptrace.SpanKind
+	code    ptrace.StatusCode
+	// overflow identifies the series of the spans exceeding the maximum
+	// number of series, the other fields are empty.
+	overflow bool
+}
+
+var overflowKey = key{overflow: true}
+
+// series contains the aggregated values of a group of spans.
+type series struct {
+	calls uint64
+	// One count per bucket, the last one counts the durations greater than
+	// the last bucket bound.
+	counts []uint64
+	sum    time.Duration
+
+	start    pcommon.Timestamp
+	lastSeen time.Time
+}
+
+// New creates an Aggregator with the given options.
+func New(options ...Option) *Aggregator {
+	a := &Aggregator{
+		serviceKey: DefaultServiceKey,
+		buckets:    DefaultBuckets,
+		start:      pcommon.NewTimestampFromTime(time.Now()),
+		series:     make(map[key]*series),
+	}
+	for _, option := range options {
+		option(a)
+	}
+	return a
+}
+
+// WithServiceKey sets the resource attribute identifying the service of a
+// span. The spans of the resources without this attribute are grouped under
+// an empty service.
+func WithServiceKey(serviceKey string) Option {
+	return func(a *Aggregator) {
+		a.serviceKey = serviceKey
+	}
+}
+
+// WithBuckets sets the upper bounds of the duration histogram buckets.
+func WithBuckets(buckets ...time.Duration) Option {
+	return func(a *Aggregator) {
+		a.buckets = append([]time.Duration(nil), buckets...)
+		sort.Slice(a.buckets, func(i, j int) bool { return a.buckets[i] < a.buckets[j] })
+	}
+}
+
+// WithStartTime sets the start time of the cumulative metrics (the creation
+// time of the aggregator by default).
+func WithStartTime(start time.Time) Option {
+	return func(a *Aggregator) {
+		a.start = pcommon.NewTimestampFromTime(start)
+	}
+}
+
+// AddTraces aggregates the spans of pdata traces, for the callers which don't
+// have the Arrow records of the traces (converting them only to call Add is
+// slower, see BenchmarkAggregator).
+func (a *Aggregator) AddTraces(td ptrace.Traces) {
+	seen := time.Now()
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		service := ""
+		if v, found := rs.Resource().Attributes().Get(a.serviceKey); found {
+			service = v.AsString()
+		}
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				a.record(
+					key{service: service, name: span.Name(), kind: span.Kind(), code: span.Status().Code()},
+					time.Duration(span.EndTimestamp()-span.StartTimestamp()),
+					seen,
+				)
+			}
+		}
+	}
+}
+
+// WithMaxSeries limits the number of series (one per service, span name, span
+// kind and status code). The spans of the new groups beyond this limit are
+// aggregated in a single overflow series with an `otel.metric.overflow`
+// attribute. Unlimited by default (0).
+func WithMaxSeries(maxSeries int) Option {
+	return func(a *Aggregator) {
+		a.maxSeries = maxSeries
+	}
+}
+
+// WithExpiration removes the series without spans for the given duration.
+// The series are never removed by default (0).
+func WithExpiration(expiration time.Duration) Option {
+	return func(a *Aggregator) {
+		a.expiration = expiration
+	}
+}
+
+// Add aggregates the spans of a batch of records (see Consumer.Consume or
+// Producer.RecordMessagesFromTraces). The SPANS and RESOURCE_ATTRS records
+// are read, the other records are ignored.
+// Note: The records are not released by this method.
+func (a *Aggregator) Add(rms []*record_message.RecordMessage) error {
+	var spans []arrow.Record
+	resAttrs := otlp.NewAttributes16Store()
+
+	for _, rm := range rms {
+		switch rm.PayloadType() {
+		case colarspb.ArrowPayloadType_SPANS:
+			spans = append(spans, rm.Record())
+		case colarspb.ArrowPayloadType_RESOURCE_ATTRS:
+			// Attributes16StoreFrom consumes the record.
+			rm.Record().Retain()
+			if err := otlp.Attributes16StoreFrom(rm.Record(), resAttrs); err != nil {
+				return werror.Wrap(err)
+			}
+		}
+	}
+
+	seen := time.Now()
+	for _, record := range spans {
+		if err := a.addSpans(record, resAttrs, seen); err != nil {
+			return werror.Wrap(err)
+		}
+	}
+	return nil
+}
+
+func (a *Aggregator) addSpans(record arrow.Record, resAttrs *otlp.Attributes16Store, seen time.Time) error {
+	ids, err := tracesotlp.SchemaToIds(record.Schema())
+	if err != nil {
+		return werror.Wrap(err)
+	}
+
+	resIDs, err := resourceIDs(record, ids.Resource)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+
+	// The service of each resource ID.
+	services := make(map[int32]string)
+	services[-1] = ""
+
+	for row := 0; row < int(record.NumRows()); row++ {
+		var k key
+
+		resID := int32(-1)
+		if resIDs != nil {
+			resID = resIDs[row]
+		}
+		service, ok := services[resID]
+		if !ok {
+			if attrs := resAttrs.AttributesByID(uint16(resID)); attrs != nil {
+				if v, found := attrs.Get(a.serviceKey); found {
+					service = v.AsString()
+				}
+			}
+			services[resID] = service
+		}
+		k.service = service
+
+		if k.name, err = carrow.StringFromRecord(record, ids.Name, row); err != nil {
+			return werror.Wrap(err)
+		}
+		kind, err := carrow.I32FromRecord(record, ids.Kind, row)
+		if err != nil {
+			return werror.Wrap(err)
+		}
+		k.kind = ptrace.SpanKind(kind)
+		status, err := carrow.StructFromRecord(record, ids.Status.Status, row)
+		if err != nil {
+			return werror.Wrap(err)
+		}
+		if status != nil {
+			code, err := carrow.I32FromStruct(status, row, ids.Status.Code)
+			if err != nil {
+				return werror.Wrap(err)
+			}
+			k.code = ptrace.StatusCode(code)
+		}
+
+		duration, err := carrow.DurationFromRecord(record, ids.DurationTimeUnixNano, row)
+		if err != nil {
+			return werror.Wrap(err)
+		}
+
+		a.record(k, time.Duration(duration), seen)
+	}
+	return nil
+}
+
+// record adds a span duration to the series of a group, or to the overflow
+// series if the group is new and the maximum number of series is reached.
+func (a *Aggregator) record(k key, duration time.Duration, seen time.Time) {
+	s, ok := a.series[k]
+	if !ok && a.maxSeries > 0 && len(a.series) >= a.maxSeries {
+		k = overflowKey
+		s, ok = a.series[k]
+	}
+	if !ok {
+		s = &series{counts: make([]uint64, len(a.buckets)+1), start: a.start}
+		if a.expired {
+			// The series may have been exported before its expiration, its
+			// values restart from now.
+			s.start = pcommon.NewTimestampFromTime(seen)
+		}
+		a.series[k] = s
+	}
+
+	s.lastSeen = seen
+	s.calls++
+	s.sum += duration
+	// A duration equal to a bucket bound belongs to this bucket.
+	s.counts[sort.Search(len(a.buckets), func(i int) bool { return a.buckets[i] >= duration })]++
+}
+
+// resourceIDs decodes the resource IDs of a SPANS record. The `resource.id`
+// column is delta encoded, i.e. the ID of a row is the sum of the non null
+// values of the column up to this row. Null values (resources without
+// attributes) have no ID (-1). Returns nil if the column is absent.
+func resourceIDs(record arrow.Record, ids *otlp.ResourceIds) ([]int32, error) {
+	if ids.Resource == carrow.AbsentFieldID || ids.ID == carrow.AbsentFieldID {
+		return nil, nil
+	}
+	resources, ok := record.Column(ids.Resource).(*array.Struct)
+	if !ok {
+		return nil, werror.WrapWithMsg(carrow.ErrInvalidArrayType, "not a struct array")
+	}
+	u16, ok := resources.Field(ids.ID).(*array.Uint16)
+	if !ok {
+		return nil, werror.WrapWithContext(ErrInvalidIDColumn, map[string]interface{}{"type": resources.Field(ids.ID).DataType().String()})
+	}
+
+	resIDs := make([]int32, u16.Len())
+	id := uint16(0)
+	for i := range resIDs {
+		if resources.IsNull(i) || u16.IsNull(i) {
+			resIDs[i] = -1
+			continue
+		}
+		id += u16.Value(i)
+		resIDs[i] = int32(id)
+	}
+	return resIDs, nil
+}
+
+// Metrics returns the metrics aggregated so far, with the given timestamp.
+// One resource is produced per service, with a `calls` sum and a `duration`
+// histogram (in milliseconds) having one data point per span name, span kind
+// and status code. The expired series are removed beforehand (see
+// WithExpiration).
+func (a *Aggregator) Metrics(now time.Time) pmetric.Metrics {
+	metrics := pmetric.NewMetrics()
+	timestamp := pcommon.NewTimestampFromTime(now)
+
+	if a.expiration > 0 {
+		for k, s := range a.series {
+			if now.Sub(s.lastSeen) > a.expiration {
+				delete(a.series, k)
+				a.expired = true
+			}
+		}
+	}
+
+	keys := make([]key, 0, len(a.series))
+	for k := range a.series {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ki, kj := keys[i], keys[j]
+		if ki.overflow != kj.overflow {
+			// The overflow series is the last one.
+			return kj.overflow
+		}
+		if ki.service != kj.service {
+			return ki.service < kj.service
+		}
+		if ki.name != kj.name {
+			return ki.name < kj.name
+		}
+		if ki.kind != kj.kind {
+			return ki.kind < kj.kind
+		}
+		return ki.code < kj.code
+	})
+
+	bounds := make([]float64, len(a.buckets))
+	for i, b := range a.buckets {
+		bounds[i] = milliseconds(b)
+	}
+
+	var calls pmetric.NumberDataPointSlice
+	var durations pmetric.HistogramDataPointSlice
+	for i, k := range keys {
+		if i == 0 || k.service != keys[i-1].service || k.overflow {
+			rm := metrics.ResourceMetrics().AppendEmpty()
+			if k.service != "" {
+				rm.Resource().Attributes().PutStr(a.serviceKey, k.service)
+			}
+			sm := rm.ScopeMetrics().AppendEmpty()
+			sm.Scope().SetName(ScopeName)
+
+			callsMetric := sm.Metrics().AppendEmpty()
+			callsMetric.SetName(CallsMetric)
+			sum := callsMetric.SetEmptySum()
+			sum.SetIsMonotonic(true)
+			sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			calls = sum.DataPoints()
+
+			durationMetric := sm.Metrics().AppendEmpty()
+			durationMetric.SetName(DurationMetric)
+			durationMetric.SetUnit("ms")
+			histogram := durationMetric.SetEmptyHistogram()
+			histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			durations = histogram.DataPoints()
+		}
+
+		s := a.series[k]
+
+		dp := calls.AppendEmpty()
+		dp.SetStartTimestamp(s.start)
+		dp.SetTimestamp(timestamp)
+		dp.SetIntValue(int64(s.calls))
+		k.attributes(dp.Attributes())
+
+		hdp := durations.AppendEmpty()
+		hdp.SetStartTimestamp(s.start)
+		hdp.SetTimestamp(timestamp)
+		hdp.SetCount(s.calls)
+		hdp.SetSum(milliseconds(s.sum))
+		hdp.ExplicitBounds().FromRaw(bounds)
+		hdp.BucketCounts().FromRaw(s.counts)
+		k.attributes(hdp.Attributes())
+	}
+
+	return metrics
+}
+
+// attributes sets the data point attributes of a group, the span kind and
+// status code are formatted as in the contrib spanmetrics connector (e.g.
+// SPAN_KIND_SERVER, STATUS_CODE_ERROR).
+func (k key) attributes(attrs pcommon.Map) {
+	if k.overflow {
+		attrs.PutBool(OverflowKey, true)
+		return
+	}
+	attrs.PutStr(SpanNameKey, k.name)
+	attrs.PutStr(SpanKindKey, "SPAN_KIND_"+strings.ToUpper(k.kind.String()))
+	attrs.PutStr(StatusCodeKey, "STATUS_CODE_"+strings.ToUpper(k.code.String()))
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,358 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+)
+
+var (
+	start = time.Unix(1_000, 0)
+	now   = time.Unix(2_000, 0)
+)
+
+// makeTraces generates batches of traces with several resources, the datagen
+// spans last a few nanoseconds.
+func makeTraces(batches int) []ptrace.Traces {
+	ent := datagen.NewTestEntropy(12345)
+	tracesGen := datagen.NewTracesGenerator(ent, ent.NewStandardResourceAttributes(), ent.NewStandardInstrumentationScopes())
+
+	result := make([]ptrace.Traces, batches)
+	for i := range result {
+		traces := ptrace.NewTraces()
+		for r := 0; r < 4; r++ {
+			tracesGen.Generate(20, time.Second).ResourceSpans().MoveAndAppendTo(traces.ResourceSpans())
+		}
+		// A resource without attributes.
+		traces.ResourceSpans().At(0).Resource().Attributes().Clear()
+		// A resource with a service name.
+		traces.ResourceSpans().At(1).Resource().Attributes().PutStr("service.name", fmt.Sprintf("service-%d", i))
+		result[i] = traces
+	}
+	return result
+}
+
+type refKey struct {
+	service string
+	name    string
+	kind    ptrace.SpanKind
+	code    ptrace.StatusCode
+}
+
+type refSeries struct {
+	calls  uint64
+	sum    time.Duration
+	counts []uint64
+}
+
+// reference computes the span metrics of pdata traces.
+func reference(traces []ptrace.Traces, serviceKey string, buckets []time.Duration) pmetric.Metrics {
+	series := make(map[refKey]*refSeries)
+	for _, td := range traces {
+		for i := 0; i < td.ResourceSpans().Len(); i++ {
+			rs := td.ResourceSpans().At(i)
+			service := ""
+			if v, ok := rs.Resource().Attributes().Get(serviceKey); ok {
+				service = v.AsString()
+			}
+			for j := 0; j < rs.ScopeSpans().Len(); j++ {
+				spans := rs.ScopeSpans().At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					span := spans.At(k)
+					key := refKey{service: service, name: span.Name(), kind: span.Kind(), code: span.Status().Code()}
+					s, ok := series[key]
+					if !ok {
+						s = &refSeries{counts: make([]uint64, len(buckets)+1)}
+						series[key] = s
+					}
+					duration := span.EndTimestamp().AsTime().Sub(span.StartTimestamp().AsTime())
+					s.calls++
+					s.sum += duration
+					bucket := len(buckets)
+					for b := len(buckets) - 1; b >= 0 && duration <= buckets[b]; b-- {
+						bucket = b
+					}
+					s.counts[bucket]++
+				}
+			}
+		}
+	}
+
+	keys := make([]refKey, 0, len(series))
+	for k := range series {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprintf("%s\x00%s\x00%02d\x00%02d", keys[i].service, keys[i].name, keys[i].kind, keys[i].code) <
+			fmt.Sprintf("%s\x00%s\x00%02d\x00%02d", keys[j].service, keys[j].name, keys[j].kind, keys[j].code)
+	})
+
+	bounds := make([]float64, len(buckets))
+	for i, b := range buckets {
+		bounds[i] = float64(b.Nanoseconds()) / 1e6
+	}
+
+	metrics := pmetric.NewMetrics()
+	metricsByService := make(map[string]pmetric.MetricSlice)
+	for _, k := range keys {
+		ms, ok := metricsByService[k.service]
+		if !ok {
+			rm := metrics.ResourceMetrics().AppendEmpty()
+			if k.service != "" {
+				rm.Resource().Attributes().PutStr(serviceKey, k.service)
+			}
+			sm := rm.ScopeMetrics().AppendEmpty()
+			sm.Scope().SetName(ScopeName)
+			ms = sm.Metrics()
+			calls := ms.AppendEmpty()
+			calls.SetName("calls")
+			calls.SetEmptySum().SetIsMonotonic(true)
+			calls.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			duration := ms.AppendEmpty()
+			duration.SetName("duration")
+			duration.SetUnit("ms")
+			duration.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			metricsByService[k.service] = ms
+		}
+
+		attrs := pcommon.NewMap()
+		attrs.PutStr("span.name", k.name)
+		attrs.PutStr("span.kind", map[ptrace.SpanKind]string{
+			ptrace.SpanKindUnspecified: "SPAN_KIND_UNSPECIFIED",
+			ptrace.SpanKindInternal:    "SPAN_KIND_INTERNAL",
+			ptrace.SpanKindServer:      "SPAN_KIND_SERVER",
+			ptrace.SpanKindClient:      "SPAN_KIND_CLIENT",
+			ptrace.SpanKindProducer:    "SPAN_KIND_PRODUCER",
+			ptrace.SpanKindConsumer:    "SPAN_KIND_CONSUMER",
+		}[k.kind])
+		attrs.PutStr("status.code", map[ptrace.StatusCode]string{
+			ptrace.StatusCodeUnset: "STATUS_CODE_UNSET",
+			ptrace.StatusCodeOk:    "STATUS_CODE_OK",
+			ptrace.StatusCodeError: "STATUS_CODE_ERROR",
+		}[k.code])
+
+		s := series[k]
+		dp := ms.At(0).Sum().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(now))
+		dp.SetIntValue(int64(s.calls))
+		attrs.CopyTo(dp.Attributes())
+
+		hdp := ms.At(1).Histogram().DataPoints().AppendEmpty()
+		hdp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		hdp.SetTimestamp(pcommon.NewTimestampFromTime(now))
+		hdp.SetCount(s.calls)
+		hdp.SetSum(float64(s.sum.Nanoseconds()) / 1e6)
+		hdp.ExplicitBounds().FromRaw(bounds)
+		hdp.BucketCounts().FromRaw(s.counts)
+		attrs.CopyTo(hdp.Attributes())
+	}
+	return metrics
+}
+
+func requireEqualMetrics(t *testing.T, expected, actual pmetric.Metrics) {
+	marshaler := &pmetric.JSONMarshaler{}
+	expectedJSON, err := marshaler.MarshalMetrics(expected)
+	require.NoError(t, err)
+	actualJSON, err := marshaler.MarshalMetrics(actual)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedJSON), string(actualJSON))
+}
+
+func TestAggregator(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		serviceKey string
+		buckets    []time.Duration
+	}{
+		{
+			name:       "default service key",
+			serviceKey: DefaultServiceKey,
+			buckets:    DefaultBuckets,
+		},
+		{
+			name:       "hostname",
+			serviceKey: "hostname",
+			buckets:    []time.Duration{4, 1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			traces := makeTraces(3)
+			buckets := append([]time.Duration(nil), tc.buckets...)
+			sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+			expected := reference(traces, tc.serviceKey, buckets)
+
+			options := []Option{WithServiceKey(tc.serviceKey), WithBuckets(tc.buckets...), WithStartTime(start)}
+
+			// Records decoded by a consumer.
+			producer := arrowRecord.NewProducer()
+			defer func() { require.NoError(t, producer.Close()) }()
+			consumer := arrowRecord.NewConsumer()
+			defer func() { require.NoError(t, consumer.Close()) }()
+
+			aggregator := New(options...)
+			for _, td := range traces {
+				bar, err := producer.BatchArrowRecordsFromTraces(td)
+				require.NoError(t, err)
+				records, err := consumer.Consume(bar)
+				require.NoError(t, err)
+				require.NoError(t, aggregator.Add(records))
+				for _, record := range records {
+					record.Record().Release()
+				}
+			}
+			requireEqualMetrics(t, expected, aggregator.Metrics(now))
+
+			// Records built by a producer without encoding.
+			recordProducer := arrowRecord.NewProducer()
+			defer func() { require.NoError(t, recordProducer.Close()) }()
+
+			aggregator = New(options...)
+			for _, td := range traces {
+				records, err := recordProducer.RecordMessagesFromTraces(td)
+				require.NoError(t, err)
+				require.NoError(t, aggregator.Add(records))
+				for _, record := range records {
+					record.Record().Release()
+				}
+			}
+			requireEqualMetrics(t, expected, aggregator.Metrics(now))
+
+			// pdata traces.
+			aggregator = New(options...)
+			for _, td := range traces {
+				aggregator.AddTraces(td)
+			}
+			requireEqualMetrics(t, expected, aggregator.Metrics(now))
+		})
+	}
+}
+
+func TestAggregatorEmpty(t *testing.T) {
+	t.Parallel()
+
+	aggregator := New()
+	require.NoError(t, aggregator.Add(nil))
+	require.Equal(t, 0, aggregator.Metrics(now).ResourceMetrics().Len())
+}
+
+// dataPoints returns the `calls` data points of the metrics.
+func dataPoints(metrics pmetric.Metrics) []pmetric.NumberDataPoint {
+	var dps []pmetric.NumberDataPoint
+	for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
+		sum := metrics.ResourceMetrics().At(i).ScopeMetrics().At(0).Metrics().At(0).Sum()
+		for j := 0; j < sum.DataPoints().Len(); j++ {
+			dps = append(dps, sum.DataPoints().At(j))
+		}
+	}
+	return dps
+}
+
+func TestAggregatorMaxSeries(t *testing.T) {
+	t.Parallel()
+
+	traces := makeTraces(1)[0]
+	aggregator := New(WithMaxSeries(3))
+	aggregator.AddTraces(traces)
+
+	dps := dataPoints(aggregator.Metrics(now))
+	require.Len(t, dps, 4)
+
+	calls := int64(0)
+	for _, dp := range dps[:3] {
+		_, found := dp.Attributes().Get(OverflowKey)
+		require.False(t, found)
+		calls += dp.IntValue()
+	}
+	overflow, found := dps[3].Attributes().Get(OverflowKey)
+	require.True(t, found)
+	require.True(t, overflow.Bool())
+	require.Equal(t, 1, dps[3].Attributes().Len())
+	calls += dps[3].IntValue()
+	require.Equal(t, int64(traces.SpanCount()), calls)
+}
+
+func TestAggregatorExpiration(t *testing.T) {
+	t.Parallel()
+
+	traces := makeTraces(1)[0]
+	aggregator := New(WithExpiration(time.Minute), WithStartTime(start))
+	aggregator.AddTraces(traces)
+
+	dps := dataPoints(aggregator.Metrics(time.Now()))
+	require.NotEmpty(t, dps)
+	require.Equal(t, pcommon.NewTimestampFromTime(start), dps[0].StartTimestamp())
+
+	require.Empty(t, dataPoints(aggregator.Metrics(time.Now().Add(2*time.Minute))))
+
+	// The new series restart after the expiration.
+	aggregator.AddTraces(traces)
+	dps = dataPoints(aggregator.Metrics(time.Now()))
+	require.NotEmpty(t, dps)
+	require.Greater(t, dps[0].StartTimestamp(), pcommon.NewTimestampFromTime(start))
+}
+
+func BenchmarkAggregator(b *testing.B) {
+	traces := makeTraces(10)
+
+	b.Run("records", func(b *testing.B) {
+		producer := arrowRecord.NewProducer()
+		defer func() { require.NoError(b, producer.Close()) }()
+		aggregator := New()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, td := range traces {
+				records, err := producer.RecordMessagesFromTraces(td)
+				require.NoError(b, err)
+				require.NoError(b, aggregator.Add(records))
+				for _, record := range records {
+					record.Record().Release()
+				}
+			}
+		}
+	})
+
+	b.Run("traces", func(b *testing.B) {
+		aggregator := New()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, td := range traces {
+				aggregator.AddTraces(td)
+			}
+		}
+	})
+}
@@ -0,0 +1,23 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spanmetrics computes request, error and duration (RED) metrics from
+// the SPANS record of a batch of OTLP Arrow records, or from pdata traces.
+//
+// The spans are grouped by service (a resource attribute), span name, span
+// kind and status code directly from the columns of the SPANS record (`name`,
+// `kind`, `status.code` and `duration_time_unix_nano`), only the resource
+// attributes are decoded. The result is a call counter and a duration
+// histogram per group, exported as pmetric.Metrics.
+package spanmetrics
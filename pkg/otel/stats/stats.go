@@ -31,8 +31,19 @@ type (
 		TracesBatchesProduced  uint64
 		StreamProducersCreated uint64
 		StreamProducersClosed  uint64
+		StreamResetsPerformed  uint64
 		RecordBuilderStats     RecordBuilderStats
 
+		// TracesSortStrategy is the sort strategy of the traces selected by
+		// the adaptive sorting (empty if disabled), it is not reset.
+		TracesSortStrategy string
+		// TracesSortEvaluations is the number of evaluations of the
+		// candidate sort strategies.
+		TracesSortEvaluations uint64
+		// TracesSortStrategyChanges is the number of changes of the sort
+		// strategy of the traces.
+		TracesSortStrategyChanges uint64
+
 		SchemaStatsEnabled bool
 	}
 
@@ -51,6 +62,7 @@ func NewProducerStats() *ProducerStats {
 		TracesBatchesProduced:  0,
 		StreamProducersCreated: 0,
 		StreamProducersClosed:  0,
+		StreamResetsPerformed:  0,
 		RecordBuilderStats: RecordBuilderStats{
 			SchemaUpdatesPerformed:     0,
 			DictionaryIndexTypeChanged: 0,
@@ -74,6 +86,9 @@ func (s *ProducerStats) Reset() {
 	s.TracesBatchesProduced = 0
 	s.StreamProducersCreated = 0
 	s.StreamProducersClosed = 0
+	s.StreamResetsPerformed = 0
+	s.TracesSortEvaluations = 0
+	s.TracesSortStrategyChanges = 0
 	s.RecordBuilderStats.Reset()
 }
 
@@ -91,6 +106,12 @@ func (s *ProducerStats) Show(indent string) {
 	fmt.Printf("%s- Traces batches produced: %d\n", indent, s.TracesBatchesProduced)
 	fmt.Printf("%s- Stream producers created: %d\n", indent, s.StreamProducersCreated)
 	fmt.Printf("%s- Stream producers closed: %d\n", indent, s.StreamProducersClosed)
+	fmt.Printf("%s- Stream resets performed: %d\n", indent, s.StreamResetsPerformed)
+	if s.TracesSortStrategy != "" {
+		fmt.Printf("%s- Traces sort strategy: %s\n", indent, s.TracesSortStrategy)
+		fmt.Printf("%s- Traces sort evaluations: %d\n", indent, s.TracesSortEvaluations)
+		fmt.Printf("%s- Traces sort strategy changes: %d\n", indent, s.TracesSortStrategyChanges)
+	}
 	fmt.Printf("%s- RecordBuilder:\n", indent)
 	s.RecordBuilderStats.Show(indent + "  ")
 }
@@ -103,6 +103,10 @@ type (
 		prevParentID uint16
 		prevEvent    *Event
 	}
+	EventsWithOrder struct {
+		EventsByNameParentId
+		order EventSorter
+	}
 )
 
 func NewEventBuilder(rBuilder *builder.RecordBuilderExt, conf *EventConfig) *EventBuilder {
@@ -276,6 +280,12 @@ func (a *EventAccumulator) Append(spanID uint16, events ptrace.SpanEventSlice) e
 	return nil
 }
 
+// SetSorter replaces the sorter of the events, the next batches are sorted
+// and encoded with it.
+func (a *EventAccumulator) SetSorter(sorter EventSorter) {
+	a.sorter = sorter
+}
+
 func (a *EventAccumulator) Reset() {
 	a.groupCount = 0
 	a.events = a.events[:0]
@@ -371,3 +381,18 @@ func (s *EventsByNameParentId) IsSameGroup(event *Event) bool {
 
 	return s.prevEvent.Name == event.Name
 }
+
+// Sorts events in the order of another sorter.
+// ============================================
+
+// SortEventsWith returns a sorter sorting the events in the order of the
+// given sorter. Whatever the order, the parent IDs are delta encoded within
+// the groups of events with the same name, i.e. the encoding of
+// SortEventsByNameParentId expected by the consumer.
+func SortEventsWith(order EventSorter) *EventsWithOrder {
+	return &EventsWithOrder{order: order}
+}
+
+func (s *EventsWithOrder) Sort(events []*Event) {
+	s.order.Sort(events)
+}
@@ -104,6 +104,10 @@ type (
 		prevParentID uint16
 		prevLink     *Link
 	}
+	LinksWithOrder struct {
+		LinksByTraceIdParentId
+		order LinkSorter
+	}
 )
 
 func NewLinkBuilder(rBuilder *builder.RecordBuilderExt, conf *LinkConfig) *LinkBuilder {
@@ -280,6 +284,12 @@ func (a *LinkAccumulator) Append(spanID uint16, links ptrace.SpanLinkSlice) erro
 	return nil
 }
 
+// SetSorter replaces the sorter of the links, the next batches are sorted
+// and encoded with it.
+func (a *LinkAccumulator) SetSorter(sorter LinkSorter) {
+	a.sorter = sorter
+}
+
 func (a *LinkAccumulator) Reset() {
 	a.groupCount = 0
 	a.links = a.links[:0]
@@ -352,3 +362,18 @@ func (s *LinksByTraceIdParentId) IsSameGroup(link *Link) bool {
 
 	return bytes.Equal(s.prevLink.TraceID[:], link.TraceID[:])
 }
+
+// Sorts links in the order of another sorter
+// ==========================================
+
+// SortLinksWith returns a sorter sorting the links in the order of the given
+// sorter. Whatever the order, the parent IDs are delta encoded within the
+// groups of links with the same trace ID, i.e. the encoding of
+// SortLinksByTraceIdParentId expected by the consumer.
+func SortLinksWith(order LinkSorter) *LinksWithOrder {
+	return &LinksWithOrder{order: order}
+}
+
+func (s *LinksWithOrder) Sort(links []*Link) {
+	s.order.Sort(links)
+}
@@ -72,6 +72,12 @@ func NewTracesOptimizer(sorter SpanSorter) *TracesOptimizer {
 	}
 }
 
+// SetSorter replaces the sorter of the spans, the next batches are sorted
+// with it.
+func (t *TracesOptimizer) SetSorter(sorter SpanSorter) {
+	t.sorter = sorter
+}
+
 func (t *TracesOptimizer) Optimize(traces ptrace.Traces) *TracesOptimized {
 	tracesOptimized := &TracesOptimized{
 		Spans: make([]*FlattenedSpan, 0),
@@ -130,6 +130,12 @@ func (r *RelatedData) Reset() {
 	r.relatedRecordsManager.Reset()
 }
 
+// ResetDictionaries resets the dictionaries of the related record builders
+// producing the given payload type.
+func (r *RelatedData) ResetDictionaries(payloadType record_message.PayloadType) {
+	r.relatedRecordsManager.ResetDictionaries(payloadType)
+}
+
 func (r *RelatedData) SpanCount() uint16 {
 	return uint16(r.spanCount)
 }
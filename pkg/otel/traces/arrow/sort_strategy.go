@@ -0,0 +1,213 @@
+/*
+ * Copyright The OpenTelemetry Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package arrow
+
+// Sort strategies of the traces, i.e. the combinations of sort orders of the
+// spans, events, links and attributes that can be switched from one batch to
+// the next (see TracesBuilder.SetSortStrategy).
+//
+// The consumer decodes the parent IDs of the events, links and attributes
+// with the delta encoding of the default sorters, the alternative orders
+// keep this encoding (see SortEventsWith, SortLinksWith, SortAttrs16With and
+// SortAttrs32With) so the strategy can change without notifying the consumer.
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/f5/otel-arrow-adapter/pkg/otel/common/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/werror"
+)
+
+// ErrUnknownSortOrder is returned when a sort strategy refers to an unknown
+// sort order.
+var ErrUnknownSortOrder = errors.New("unknown sort order")
+
+type (
+	// SortStrategy is a combination of the sort orders of the spans, events,
+	// links and attributes of a batch of traces, identified by their names.
+	SortStrategy struct {
+		Span  string
+		Event string
+		Link  string
+		Attrs string
+	}
+
+	sortOrder[T any] struct {
+		name string
+		new  func() T
+	}
+
+	attrsSorters struct {
+		attrs16 arrow.Attrs16Sorter
+		attrs32 arrow.Attrs32Sorter
+	}
+)
+
+// Sort orders of each dimension of a sort strategy, the first one is the
+// default. All the span orders start with the resource and scope IDs, this
+// prefix is omitted from their names.
+var (
+	spanSortOrders = []sortOrder[SpanSorter]{
+		{"NameTraceId", func() SpanSorter { return SortSpansByResourceSpanIdScopeSpanIdNameTraceId() }},
+		{"NameTraceIdStartTimestamp", func() SpanSorter { return SortSpansByResourceSpanIdScopeSpanIdNameTraceIdStartTimestamp() }},
+		{"NameStartTimestamp", func() SpanSorter { return SortSpansByResourceSpanIdScopeSpanIdNameStartTimestamp() }},
+		{"TraceIdName", func() SpanSorter { return SortSpansByResourceSpanIdScopeSpanIdTraceIdName() }},
+		{"StartTimestampTraceIdName", func() SpanSorter { return SortSpansByResourceSpanIdScopeSpanIdStartTimestampTraceIdName() }},
+		{"StartTimestampNameTraceId", func() SpanSorter { return SortSpansByResourceSpanIdScopeSpanIdStartTimestampNameTraceId() }},
+	}
+
+	eventSortOrders = []sortOrder[EventSorter]{
+		{"NameParentId", func() EventSorter { return SortEventsByNameParentId() }},
+		{"NameTimeUnixNano", func() EventSorter { return SortEventsWith(SortEventsByNameTimeUnixNano()) }},
+	}
+
+	linkSortOrders = []sortOrder[LinkSorter]{
+		{"TraceIdParentId", func() LinkSorter { return SortLinksByTraceIdParentId() }},
+		// The links are accumulated in the order of their spans.
+		{"ParentId", func() LinkSorter { return SortLinksWith(UnsortedLinks()) }},
+	}
+
+	attrsSortOrders = []sortOrder[attrsSorters]{
+		{"KeyValueParentId", func() attrsSorters {
+			return attrsSorters{arrow.SortAttrs16ByKeyValueParentId(), arrow.SortAttrs32ByKeyValueParentId()}
+		}},
+		{"KeyParentIdValue", func() attrsSorters {
+			return attrsSorters{
+				arrow.SortAttrs16With(arrow.SortAttrs16ByKeyParentIdValue()),
+				arrow.SortAttrs32With(arrow.SortAttrs32ByKeyParentIdValue()),
+			}
+		}},
+		// The event and link attributes are accumulated in the order of their
+		// parents.
+		{"ParentId", func() attrsSorters {
+			return attrsSorters{
+				arrow.SortAttrs16With(arrow.SortByParentIdKeyValueAttr16()),
+				arrow.SortAttrs32With(arrow.UnsortedAttrs32()),
+			}
+		}},
+	}
+)
+
+// DefaultSortStrategy returns the sort strategy of the default configuration
+// (see NewConfig).
+func DefaultSortStrategy() SortStrategy {
+	return SortStrategy{
+		Span:  spanSortOrders[0].name,
+		Event: eventSortOrders[0].name,
+		Link:  linkSortOrders[0].name,
+		Attrs: attrsSortOrders[0].name,
+	}
+}
+
+// String returns a compact representation of the strategy, e.g.
+// "span=NameTraceId,event=NameParentId,link=TraceIdParentId,attrs=KeyValueParentId".
+func (s SortStrategy) String() string {
+	return fmt.Sprintf("span=%s,event=%s,link=%s,attrs=%s", s.Span, s.Event, s.Link, s.Attrs)
+}
+
+// Validate returns an error if a sort order of the strategy is unknown.
+func (s SortStrategy) Validate() error {
+	_, err := s.sorters()
+	return err
+}
+
+// Neighbors returns the strategies differing from this one by a single sort
+// order.
+func (s SortStrategy) Neighbors() []SortStrategy {
+	var neighbors []SortStrategy
+	for _, o := range spanSortOrders {
+		if o.name != s.Span {
+			neighbors = append(neighbors, SortStrategy{Span: o.name, Event: s.Event, Link: s.Link, Attrs: s.Attrs})
+		}
+	}
+	for _, o := range eventSortOrders {
+		if o.name != s.Event {
+			neighbors = append(neighbors, SortStrategy{Span: s.Span, Event: o.name, Link: s.Link, Attrs: s.Attrs})
+		}
+	}
+	for _, o := range linkSortOrders {
+		if o.name != s.Link {
+			neighbors = append(neighbors, SortStrategy{Span: s.Span, Event: s.Event, Link: o.name, Attrs: s.Attrs})
+		}
+	}
+	for _, o := range attrsSortOrders {
+		if o.name != s.Attrs {
+			neighbors = append(neighbors, SortStrategy{Span: s.Span, Event: s.Event, Link: s.Link, Attrs: o.name})
+		}
+	}
+	return neighbors
+}
+
+// strategySorters are new instances of the sorters of a strategy (the
+// sorters are stateful and can't be shared between builders).
+type strategySorters struct {
+	span          SpanSorter
+	event         EventSorter
+	link          LinkSorter
+	resourceAttrs arrow.Attrs16Sorter
+	scopeAttrs    arrow.Attrs16Sorter
+	spanAttrs     arrow.Attrs16Sorter
+	eventAttrs    arrow.Attrs32Sorter
+	linkAttrs     arrow.Attrs32Sorter
+}
+
+func (s SortStrategy) sorters() (*strategySorters, error) {
+	span, err := newSorter(spanSortOrders, s.Span)
+	if err != nil {
+		return nil, werror.WrapWithContext(err, map[string]interface{}{"span": s.Span})
+	}
+	event, err := newSorter(eventSortOrders, s.Event)
+	if err != nil {
+		return nil, werror.WrapWithContext(err, map[string]interface{}{"event": s.Event})
+	}
+	link, err := newSorter(linkSortOrders, s.Link)
+	if err != nil {
+		return nil, werror.WrapWithContext(err, map[string]interface{}{"link": s.Link})
+	}
+	newAttrs := func() (attrsSorters, error) { return newSorter(attrsSortOrders, s.Attrs) }
+	resourceAttrs, err := newAttrs()
+	if err != nil {
+		return nil, werror.WrapWithContext(err, map[string]interface{}{"attrs": s.Attrs})
+	}
+	scopeAttrs, _ := newAttrs()
+	spanAttrs, _ := newAttrs()
+	eventAttrs, _ := newAttrs()
+	linkAttrs, _ := newAttrs()
+
+	return &strategySorters{
+		span:          span,
+		event:         event,
+		link:          link,
+		resourceAttrs: resourceAttrs.attrs16,
+		scopeAttrs:    scopeAttrs.attrs16,
+		spanAttrs:     spanAttrs.attrs16,
+		eventAttrs:    eventAttrs.attrs32,
+		linkAttrs:     linkAttrs.attrs32,
+	}, nil
+}
+
+func newSorter[T any](orders []sortOrder[T], name string) (T, error) {
+	for _, o := range orders {
+		if o.name == name {
+			return o.new(), nil
+		}
+	}
+	var zero T
+	return zero, werror.Wrap(ErrUnknownSortOrder)
+}
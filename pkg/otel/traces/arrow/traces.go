@@ -78,6 +78,9 @@ type TracesBuilder struct {
 
 	optimizer *TracesOptimizer
 	analyzer  *TracesAnalyzer
+	// Batch appended since the last record, analyzed once the record is
+	// built (the batch is appended again after a schema update).
+	pendingAnalysis *TracesOptimized
 
 	relatedData *RelatedData
 }
@@ -145,10 +148,37 @@ func (b *TracesBuilder) init() error {
 	return nil
 }
 
+// SetSortStrategy replaces the sorters of the spans, events, links and
+// attributes, the next batches are sorted and encoded with them.
+func (b *TracesBuilder) SetSortStrategy(strategy SortStrategy) error {
+	sorters, err := strategy.sorters()
+	if err != nil {
+		return werror.Wrap(err)
+	}
+
+	b.optimizer.SetSorter(sorters.span)
+	b.relatedData.EventBuilder().Accumulator().SetSorter(sorters.event)
+	b.relatedData.LinkBuilder().Accumulator().SetSorter(sorters.link)
+	attrsBuilders := b.relatedData.AttrsBuilders()
+	attrsBuilders.Resource().Accumulator().SetSorter(sorters.resourceAttrs)
+	attrsBuilders.Scope().Accumulator().SetSorter(sorters.scopeAttrs)
+	attrsBuilders.Span().Accumulator().SetSorter(sorters.spanAttrs)
+	attrsBuilders.Event().Accumulator().SetSorter(sorters.eventAttrs)
+	attrsBuilders.Link().Accumulator().SetSorter(sorters.linkAttrs)
+
+	return nil
+}
+
 func (b *TracesBuilder) RelatedData() *RelatedData {
 	return b.relatedData
 }
 
+// Analyzer returns the analyzer of the batches appended to the builder, nil
+// if the statistics are not enabled (see config.WithStats).
+func (b *TracesBuilder) Analyzer() *TracesAnalyzer {
+	return b.analyzer
+}
+
 // Build builds an Arrow Record from the builder.
 //
 // Once the array is no longer needed, Release() must be called to free the
@@ -167,7 +197,10 @@ func (b *TracesBuilder) Build() (record arrow.Record, err error) {
 		if initErr != nil {
 			err = werror.Wrap(initErr)
 		}
+	} else if b.pendingAnalysis != nil {
+		b.analyzer.Analyze(b.pendingAnalysis)
 	}
+	b.pendingAnalysis = nil
 
 	return
 }
@@ -180,8 +213,7 @@ func (b *TracesBuilder) Append(traces ptrace.Traces) error {
 
 	optimTraces := b.optimizer.Optimize(traces)
 	if b.analyzer != nil {
-		b.analyzer.Analyze(optimTraces)
-		b.analyzer.ShowStats("")
+		b.pendingAnalysis = optimTraces
 	}
 
 	spanID := uint16(0)
@@ -0,0 +1,295 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+// Conversion of the traces Arrow records directly into the protobuf
+// representation of an OTLP `ExportTraceServiceRequest` message.
+
+import (
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	arrowutils "github.com/f5/otel-arrow-adapter/pkg/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/common"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/common/otlp"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/common/wire"
+	"github.com/f5/otel-arrow-adapter/pkg/werror"
+)
+
+// Field numbers of the messages defined in `trace.proto`.
+const (
+	tracesDataResourceSpans = 1
+
+	resourceSpansResource   = 1
+	resourceSpansScopeSpans = 2
+	resourceSpansSchemaUrl  = 3
+
+	scopeSpansScope     = 1
+	scopeSpansSpans     = 2
+	scopeSpansSchemaUrl = 3
+
+	spanTraceID           = 1
+	spanSpanID            = 2
+	spanTraceState        = 3
+	spanParentSpanID      = 4
+	spanName              = 5
+	spanKind              = 6
+	spanStartTime         = 7
+	spanEndTime           = 8
+	spanAttributes        = 9
+	spanDroppedAttributes = 10
+	spanEvents            = 11
+	spanDroppedEvents     = 12
+	spanLinks             = 13
+	spanDroppedLinks      = 14
+	spanStatus            = 15
+
+	eventTime              = 1
+	eventName              = 2
+	eventAttributes        = 3
+	eventDroppedAttributes = 4
+
+	linkTraceID           = 1
+	linkSpanID            = 2
+	linkTraceState        = 3
+	linkAttributes        = 4
+	linkDroppedAttributes = 5
+
+	statusMessage = 2
+	statusCode    = 3
+)
+
+// TracesProtoFrom appends to buf the protobuf representation of the OTLP
+// `ExportTraceServiceRequest` message encoded in the given Arrow Record and
+// returns the extended buffer. The result is identical to the protobuf
+// serialization of the [ptrace.Traces] returned by TracesFrom, but the pdata
+// representation of the resources, the scopes and the spans is never built.
+// Note: This function consume the record.
+func TracesProtoFrom(buf []byte, record arrow.Record, relatedData *RelatedData) ([]byte, error) {
+	defer record.Release()
+
+	traceIDs, err := SchemaToIds(record.Schema())
+	if err != nil {
+		return buf, werror.Wrap(err)
+	}
+
+	e := wire.NewEncoder(buf)
+	rows := int(record.NumRows())
+
+	prevResID := None
+	prevScopeID := None
+	var resSchemaUrl, scopeSchemaUrl string
+
+	for row := 0; row < rows; row++ {
+		// Process resource spans, resource, schema url (resource)
+		resID, err := otlp.ResourceIDFromRecord(record, row, traceIDs.Resource)
+		if err != nil {
+			return buf, werror.Wrap(err)
+		}
+		if prevResID != int(resID) {
+			if prevResID != None {
+				// Close the previous scope spans and resource spans.
+				e.String(scopeSpansSchemaUrl, scopeSchemaUrl)
+				e.EndMessage()
+				e.String(resourceSpansSchemaUrl, resSchemaUrl)
+				e.EndMessage()
+			}
+			prevResID = int(resID)
+			prevScopeID = None
+
+			e.StartMessage(tracesDataResourceSpans)
+			resSchemaUrl, err = otlp.EncodeResourceFromRecord(e, resourceSpansResource, record, row, traceIDs.Resource, relatedData.ResAttrMapStore)
+			if err != nil {
+				return buf, werror.Wrap(err)
+			}
+		}
+
+		// Process scope spans, scope, schema url (scope)
+		scopeID, err := otlp.ScopeIDFromRecord(record, row, traceIDs.Scope)
+		if err != nil {
+			return buf, werror.Wrap(err)
+		}
+		if prevScopeID != int(scopeID) {
+			if prevScopeID != None {
+				e.String(scopeSpansSchemaUrl, scopeSchemaUrl)
+				e.EndMessage()
+			}
+			prevScopeID = int(scopeID)
+
+			e.StartMessage(resourceSpansScopeSpans)
+			if err = otlp.EncodeScopeFromRecord(e, scopeSpansScope, record, row, traceIDs.Scope, relatedData.ScopeAttrMapStore); err != nil {
+				return buf, werror.Wrap(err)
+			}
+
+			scopeSchemaUrl, err = arrowutils.StringFromRecord(record, traceIDs.SchemaUrl, row)
+			if err != nil {
+				return buf, werror.Wrap(err)
+			}
+		}
+
+		// Process span fields
+		if err = encodeSpan(e, record, row, traceIDs, relatedData); err != nil {
+			return buf, werror.Wrap(err)
+		}
+	}
+
+	if prevResID != None {
+		e.String(scopeSpansSchemaUrl, scopeSchemaUrl)
+		e.EndMessage()
+		e.String(resourceSpansSchemaUrl, resSchemaUrl)
+		e.EndMessage()
+	}
+
+	return e.Bytes(), nil
+}
+
+func encodeSpan(e *wire.Encoder, record arrow.Record, row int, traceIDs *SpanIDs, relatedData *RelatedData) error {
+	deltaID, err := arrowutils.U16FromRecord(record, traceIDs.ID, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	ID := relatedData.SpanIDFromDelta(deltaID)
+
+	traceID, err := arrowutils.FixedSizeBinaryFromRecord(record, traceIDs.TraceID, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	if len(traceID) != 16 {
+		return werror.WrapWithContext(common.ErrInvalidTraceIDLength, map[string]interface{}{"traceID": traceID})
+	}
+	spanID, err := arrowutils.FixedSizeBinaryFromRecord(record, traceIDs.SpanID, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	if len(spanID) != 8 {
+		return werror.WrapWithContext(common.ErrInvalidSpanIDLength, map[string]interface{}{"spanID": spanID})
+	}
+	traceState, err := arrowutils.StringFromRecord(record, traceIDs.TraceState, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	parentSpanID, err := arrowutils.FixedSizeBinaryFromRecord(record, traceIDs.ParentSpanID, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	if parentSpanID != nil && len(parentSpanID) != 8 {
+		return werror.WrapWithContext(common.ErrInvalidSpanIDLength, map[string]interface{}{"parentSpanID": parentSpanID})
+	}
+	name, err := arrowutils.StringFromRecord(record, traceIDs.Name, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	kind, err := arrowutils.I32FromRecord(record, traceIDs.Kind, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	startTimeUnixNano, err := arrowutils.TimestampFromRecord(record, traceIDs.StartTimeUnixNano, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	durationNano, err := arrowutils.DurationFromRecord(record, traceIDs.DurationTimeUnixNano, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	endTimeUnixNano := startTimeUnixNano.ToTime(arrow.Nanosecond).Add(time.Duration(durationNano))
+	droppedAttributesCount, err := arrowutils.U32FromRecord(record, traceIDs.DropAttributesCount, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	droppedEventsCount, err := arrowutils.U32FromRecord(record, traceIDs.DropEventsCount, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	droppedLinksCount, err := arrowutils.U32FromRecord(record, traceIDs.DropLinksCount, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	var statusMsg string
+	var statusCd int32
+	statusArr, err := arrowutils.StructFromRecord(record, traceIDs.Status.Status, row)
+	if err != nil {
+		return werror.Wrap(err)
+	}
+	if statusArr != nil {
+		// Status exists
+		statusMsg, err = arrowutils.StringFromStruct(statusArr, row, traceIDs.Status.Message)
+		if err != nil {
+			return werror.Wrap(err)
+		}
+		statusCd, err = arrowutils.I32FromStruct(statusArr, row, traceIDs.Status.Code)
+		if err != nil {
+			return werror.Wrap(err)
+		}
+	}
+
+	var tid pcommon.TraceID
+	var sid pcommon.SpanID
+	var psid pcommon.SpanID
+
+	copy(tid[:], traceID)
+	copy(sid[:], spanID)
+	copy(psid[:], parentSpanID)
+
+	e.StartMessage(scopeSpansSpans)
+	wire.EncodeTraceID(e, spanTraceID, tid)
+	wire.EncodeSpanID(e, spanSpanID, sid)
+	e.String(spanTraceState, traceState)
+	wire.EncodeSpanID(e, spanParentSpanID, psid)
+	e.String(spanName, name)
+	e.Varint(spanKind, uint64(kind))
+	e.Fixed64(spanStartTime, uint64(startTimeUnixNano))
+	e.Fixed64(spanEndTime, uint64(endTimeUnixNano.UnixNano()))
+	if attrs := relatedData.SpanAttrMapStore.AttributesByID(ID); attrs != nil {
+		wire.EncodeAttributes(e, spanAttributes, *attrs)
+	}
+	e.Varint(spanDroppedAttributes, uint64(droppedAttributesCount))
+	for _, event := range relatedData.SpanEventsStore.EventsByID(ID) {
+		encodeEvent(e, *event)
+	}
+	e.Varint(spanDroppedEvents, uint64(droppedEventsCount))
+	for _, link := range relatedData.SpanLinksStore.LinksByID(ID) {
+		encodeLink(e, *link)
+	}
+	e.Varint(spanDroppedLinks, uint64(droppedLinksCount))
+	e.StartMessage(spanStatus)
+	e.String(statusMessage, statusMsg)
+	e.Varint(statusCode, uint64(statusCd))
+	e.EndMessage()
+	e.EndMessage()
+
+	return nil
+}
+
+func encodeEvent(e *wire.Encoder, event ptrace.SpanEvent) {
+	e.StartMessage(spanEvents)
+	e.Fixed64(eventTime, uint64(event.Timestamp()))
+	e.String(eventName, event.Name())
+	wire.EncodeAttributes(e, eventAttributes, event.Attributes())
+	e.Varint(eventDroppedAttributes, uint64(event.DroppedAttributesCount()))
+	e.EndMessage()
+}
+
+func encodeLink(e *wire.Encoder, link ptrace.SpanLink) {
+	e.StartMessage(spanLinks)
+	wire.EncodeTraceID(e, linkTraceID, link.TraceID())
+	wire.EncodeSpanID(e, linkSpanID, link.SpanID())
+	e.String(linkTraceState, link.TraceState().AsRaw())
+	wire.EncodeAttributes(e, linkAttributes, link.Attributes())
+	e.Varint(linkDroppedAttributes, uint64(link.DroppedAttributesCount()))
+	e.EndMessage()
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright The OpenTelemetry Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package zstddict builds the zstd dictionaries shared by a sender and a
+// receiver to compress small messages, which have too little content to be
+// compressed efficiently on their own.
+package zstddict
+
+import (
+	"bytes"
+
+	"github.com/klauspost/compress/dict"
+	"github.com/klauspost/compress/zstd"
+)
+
+// magic starts the dictionaries in the zstd format, the other dictionaries
+// are raw content dictionaries.
+var magic = []byte{0x37, 0xa4, 0x30, 0xec}
+
+// ID is the dictionary id used when there is a single dictionary, any
+// non-zero value is fine.
+const ID = 1
+
+// Train returns a zstd dictionary of at most size bytes trained on the
+// samples, with the given id. When the samples have no content in common to
+// train on, it returns a raw content dictionary (see RawContentDictionary)
+// instead.
+func Train(samples [][]byte, size int, id uint32) (data []byte) {
+	defer func() {
+		// The builder panics on samples too small or too uniform to
+		// train on.
+		if recover() != nil {
+			data = RawContentDictionary(samples, size)
+		}
+	}()
+
+	data, err := dict.BuildZstdDict(samples, dict.Options{
+		MaxDictSize: size,
+		// Minimum length of the indexed sequences, 5 suits the Arrow
+		// payloads best.
+		HashBytes:  5,
+		ZstdDictID: id,
+	})
+	if err != nil {
+		return RawContentDictionary(samples, size)
+	}
+	return data
+}
+
+// EncoderDict returns the encoder option compressing with the dictionary
+// data, in the zstd format or a raw content dictionary.
+func EncoderDict(id uint32, data []byte) zstd.EOption {
+	if bytes.HasPrefix(data, magic) {
+		return zstd.WithEncoderDict(data)
+	}
+	return zstd.WithEncoderDictRaw(id, data)
+}
+
+// DecoderDict returns the decoder option decompressing with the dictionary
+// data, in the zstd format or a raw content dictionary.
+func DecoderDict(id uint32, data []byte) zstd.DOption {
+	if bytes.HasPrefix(data, magic) {
+		return zstd.WithDecoderDicts(data)
+	}
+	return zstd.WithDecoderDictRaw(id, data)
+}
+
+// RawContentDictionary returns a raw content dictionary of at most size bytes,
+// the concatenation of the samples (no training). The most recent samples are
+// the most likely to match the next messages, they are kept first and placed
+// at the end of the dictionary where the matches are the cheapest.
+func RawContentDictionary(samples [][]byte, size int) []byte {
+	total := 0
+	first := len(samples)
+	for first > 0 && total < size {
+		first--
+		total += len(samples[first])
+	}
+
+	dict := make([]byte, 0, total)
+	for _, sample := range samples[first:] {
+		dict = append(dict, sample...)
+	}
+	if len(dict) > size {
+		dict = dict[len(dict)-size:]
+	}
+	return dict
+}
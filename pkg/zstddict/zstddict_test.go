@@ -0,0 +1,72 @@
+/*
+ * Copyright The OpenTelemetry Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package zstddict
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrain(t *testing.T) {
+	t.Parallel()
+
+	var samples [][]byte
+	for i := 0; i < 100; i++ {
+		samples = append(samples, []byte(fmt.Sprintf(
+			`{"resource":{"service.name":"frontend","host.name":"host-%d"},"spans":[{"name":"GET /api/v1/users/%d","kind":"SPAN_KIND_SERVER","status":{"code":%d}},{"name":"SELECT users","kind":"SPAN_KIND_CLIENT","status":{"code":%d}}]}`,
+			i%5, i, i%3, i%2,
+		)))
+	}
+	dict := Train(samples, 1<<10, 42)
+	_, err := zstd.InspectDictionary(dict)
+	require.NoError(t, err)
+	roundTrip(t, 42, dict, samples[0])
+
+	// A single sample has no content in common to train on, the training
+	// fails and the samples are used as a raw content dictionary.
+	dict = Train(samples[:1], 1<<10, 42)
+	require.Equal(t, samples[0], dict)
+	roundTrip(t, 42, dict, samples[0])
+}
+
+func roundTrip(t *testing.T, id uint32, dict, data []byte) {
+	encoder, err := zstd.NewWriter(nil, EncoderDict(id, dict))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, encoder.Close()) }()
+	decoder, err := zstd.NewReader(nil, DecoderDict(id, dict))
+	require.NoError(t, err)
+	defer decoder.Close()
+
+	decoded, err := decoder.DecodeAll(encoder.EncodeAll(data, nil), nil)
+	require.NoError(t, err)
+	require.Equal(t, data, decoded)
+}
+
+func TestRawContentDictionary(t *testing.T) {
+	t.Parallel()
+
+	samples := [][]byte{[]byte("aaaa"), []byte("bbbb"), []byte("cccc")}
+	require.Equal(t, []byte("aaaabbbbcccc"), RawContentDictionary(samples, 100))
+	// The most recent samples are kept.
+	require.Equal(t, []byte("bbcccc"), RawContentDictionary(samples, 6))
+	require.Equal(t, []byte("cccc"), RawContentDictionary(samples, 4))
+	require.Empty(t, RawContentDictionary(nil, 4))
+}
@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/f5/otel-arrow-adapter/pkg/benchmark"
+)
+
+var help = flag.Bool("help", false, "Show help")
+
+// Command line comparing the results of a benchmark run to the results of a
+// baseline run:
+//
+//	benchmark_compare [flags] <baseline results> <current results>
+//
+// The exit status is 1 if a result regressed above its threshold.
+func main() {
+	sizeThreshold := flag.Float64("threshold", 0.02, "Max relative increase of the sizes (and decrease of the compression ratios)")
+	timeThreshold := flag.Float64("time_threshold", 0.25, "Max relative increase of the durations")
+	all := flag.Bool("all", false, "Show all the results, not only the regressions")
+
+	// Parse the flag
+	flag.Parse()
+
+	// Usage Demo
+	if *help {
+		flag.Usage()
+		os.Exit(0)
+	}
+	if flag.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: benchmark_compare [flags] <baseline results> <current results>")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	baseline, err := benchmark.ReadResultsFile(flag.Arg(0))
+	if err != nil {
+		log.Fatal("read baseline: ", err)
+	}
+	current, err := benchmark.ReadResultsFile(flag.Arg(1))
+	if err != nil {
+		log.Fatal("read results: ", err)
+	}
+
+	comparisons, missing := benchmark.CompareResults(baseline, current, benchmark.Thresholds{
+		Time: *timeThreshold,
+		Size: *sizeThreshold,
+	})
+
+	regressions := 0
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Result", "Baseline", "Current", "Change (+ is worse)", ""})
+	table.SetAutoWrapText(false)
+	for _, c := range comparisons {
+		status := ""
+		if c.Regression {
+			regressions++
+			status = "REGRESSION"
+		}
+		if !c.Regression && !*all {
+			continue
+		}
+		table.Append([]string{
+			c.Key,
+			formatValue(c.Baseline, c.Unit),
+			formatValue(c.Current, c.Unit),
+			fmt.Sprintf("%+.1f%%", c.Change*100),
+			status,
+		})
+	}
+	if table.NumLines() > 0 {
+		table.Render()
+	}
+
+	for _, key := range missing {
+		fmt.Printf("missing in one of the runs: %s\n", key)
+	}
+	fmt.Printf("%d results compared, %d regressions\n", len(comparisons), regressions)
+	if regressions > 0 {
+		os.Exit(1)
+	}
+}
+
+func formatValue(value float64, unit string) string {
+	switch unit {
+	case benchmark.UnitSeconds:
+		return fmt.Sprintf("%.3fms", value*1000)
+	case benchmark.UnitBytes:
+		return strconv.FormatFloat(value, 'f', 0, 64) + " B"
+	default:
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	}
+}
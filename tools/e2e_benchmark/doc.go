@@ -15,5 +15,7 @@
  *
  */
 
-// Package main contains a CLI tool used to extract the first n spans from a trace file (protobuf file).
+// Package main contains a CLI tool benchmarking the OTLP exporter and
+// receiver end to end, over an in-memory gRPC connection, with OTel Arrow
+// and with plain OTLP gRPC.
 package main
@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"go.opentelemetry.io/collector/config/configcompression"
+
+	"github.com/f5/otel-arrow-adapter/collector/e2ebench"
+	"github.com/f5/otel-arrow-adapter/pkg/benchmark/dataset"
+	"github.com/f5/otel-arrow-adapter/pkg/config"
+)
+
+var help = flag.Bool("help", false, "Show help")
+
+// Command line running the same batches through the exporter and the
+// receiver with plain OTLP gRPC and with OTel Arrow, and printing the
+// results side by side:
+//
+//	e2e_benchmark -signal traces -input ./data/otlp_traces.pb -batch_size 1000
+//
+// Without -input, the batches are generated.
+func main() {
+	signal := flag.String("signal", "traces", "Signal of the batches: traces, logs or metrics")
+	input := flag.String("input", "", "Dataset file, synthetic batches are generated if empty")
+	batchSize := flag.Int("batch_size", 1000, "Number of items (spans, log records or data points) per batch")
+	numBatches := flag.Int("batches", 100, "Max number of batches")
+	numStreams := flag.Int("num_streams", 1, "Number of Arrow streams of the exporter")
+	concurrency := flag.Int("concurrency", 1, "Number of concurrent exports")
+	iterations := flag.Int("iterations", 1, "Number of times the batches are exported")
+	compression := flag.String("compression", string(configcompression.Zstd), "gRPC compression: none, gzip, snappy or zstd")
+	payloadCompression := flag.String("payload_compression", string(config.IPCCompressionZstd), "Arrow IPC compression: none, zstd or lz4_frame")
+
+	// Parse the flag
+	flag.Parse()
+
+	// Usage Demo
+	if *help {
+		flag.Usage()
+		os.Exit(0)
+	}
+	if *batchSize < 1 || *numBatches < 1 {
+		log.Fatal("-batch_size and -batches must be positive")
+	}
+
+	if err := config.IPCCompression(*payloadCompression).Validate(); err != nil {
+		log.Fatal(err)
+	}
+	codec := configcompression.CompressionType(*compression)
+	if codec == "none" {
+		codec = ""
+	}
+	configs := []e2ebench.Config{
+		{Name: "OTLP"},
+		{Name: "OTel Arrow", Arrow: true},
+	}
+	for i := range configs {
+		configs[i].NumStreams = *numStreams
+		configs[i].Concurrency = *concurrency
+		configs[i].Iterations = *iterations
+		configs[i].Compression = codec
+		configs[i].PayloadCompression = config.IPCCompression(*payloadCompression)
+	}
+
+	ctx := context.Background()
+	var run func(cfg e2ebench.Config) (*e2ebench.Result, error)
+	switch *signal {
+	case "traces":
+		var ds dataset.TraceDataset = dataset.NewFakeTraceDataset(*batchSize * *numBatches)
+		if *input != "" {
+			ds = dataset.NewRealTraceDataset(*input, []string{"trace_id"})
+		}
+		traces := batches(ds.Len(), ds.Traces, *batchSize, *numBatches)
+		run = func(cfg e2ebench.Config) (*e2ebench.Result, error) {
+			return e2ebench.RunTraces(ctx, cfg, traces)
+		}
+	case "logs":
+		var ds dataset.LogsDataset = dataset.NewFakeLogsDataset(*batchSize * *numBatches)
+		if *input != "" {
+			ds = dataset.NewRealLogsDataset(*input)
+		}
+		logs := batches(ds.Len(), ds.Logs, *batchSize, *numBatches)
+		run = func(cfg e2ebench.Config) (*e2ebench.Result, error) {
+			return e2ebench.RunLogs(ctx, cfg, logs)
+		}
+	case "metrics":
+		var ds dataset.MetricsDataset = dataset.NewFakeMetricsDataset(*batchSize * *numBatches)
+		if *input != "" {
+			ds = dataset.NewRealMetricsDataset(*input)
+		}
+		metrics := batches(ds.Len(), ds.Metrics, *batchSize, *numBatches)
+		run = func(cfg e2ebench.Config) (*e2ebench.Result, error) {
+			return e2ebench.RunMetrics(ctx, cfg, metrics)
+		}
+	default:
+		log.Fatalf("unknown signal %q", *signal)
+	}
+
+	results := make([]*e2ebench.Result, 0, len(configs))
+	for _, cfg := range configs {
+		result, err := run(cfg)
+		if err != nil {
+			log.Fatalf("%s: %v", cfg.Name, err)
+		}
+		results = append(results, result)
+	}
+	fmt.Printf("%s, batch size %d, %d streams, concurrency %d, compression %s, payload compression %s\n",
+		*signal, *batchSize, *numStreams, *concurrency, *compression, *payloadCompression)
+	e2ebench.WriteResults(os.Stdout, results)
+}
+
+// batches returns up to n batches of the given size, the last items of the
+// dataset are dropped if there aren't enough for a batch.
+func batches[T any](items int, get func(offset, size int) []T, size, n int) []T {
+	var result []T
+	for offset := 0; offset+size <= items && n > 0; offset += size {
+		result = append(result, get(offset, size)...)
+		n--
+	}
+	if result == nil {
+		log.Fatalf("the dataset has less than %d items", size)
+	}
+	return result
+}
@@ -24,21 +24,25 @@ import (
 	"math"
 	"math/big"
 	"os"
-	"path"
 
-	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/plog"
 
+	"github.com/f5/otel-arrow-adapter/pkg/benchmark/dataset"
 	"github.com/f5/otel-arrow-adapter/pkg/datagen"
 )
 
 var help = flag.Bool("help", false, "Show help")
 var outputFile = "./data/otlp_logs.pb"
 var batchSize = 100000
+var profileFile = ""
+var seed int64
 
 func main() {
 	// Define the flags.
-	flag.StringVar(&outputFile, "output", outputFile, "Output file")
+	flag.StringVar(&outputFile, "output", outputFile, "Output file (.pb, .json or .arrow, optionally followed by .gz or .zst)")
 	flag.IntVar(&batchSize, "batchsize", batchSize, "Batch size")
+	flag.StringVar(&profileFile, "profile", profileFile, "Workload profile (.yaml or .json, see tools/profiles)")
+	flag.Int64Var(&seed, "seed", seed, "Seed of the generator, the same seed generates the same data (random if 0)")
 
 	// Parse the flag
 	flag.Parse()
@@ -50,31 +54,30 @@ func main() {
 	}
 
 	// Generate the dataset.
-	v, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
-	if err != nil {
-		log.Fatalf("Failed to generate random number - %v", err)
-	}
-
-	entropy := datagen.NewTestEntropy(v.Int64())
-	generator := datagen.NewLogsGenerator(entropy, entropy.NewStandardResourceAttributes(), entropy.NewStandardInstrumentationScopes())
-	request := plogotlp.NewExportRequestFromLogs(generator.Generate(batchSize, 100))
-
-	// Marshal the request to bytes.
-	msg, err := request.MarshalProto()
-	if err != nil {
-		log.Fatal("marshaling error: ", err)
+	if seed == 0 {
+		v, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+		if err != nil {
+			log.Fatalf("Failed to generate random number - %v", err)
+		}
+		seed = v.Int64()
 	}
 
-	// Write protobuf to file
-	if _, err := os.Stat(outputFile); os.IsNotExist(err) {
-		err = os.MkdirAll(path.Dir(outputFile), 0700)
+	entropy := datagen.NewTestEntropy(seed)
+	var logs plog.Logs
+	if profileFile != "" {
+		profile, err := datagen.LoadProfile(profileFile)
 		if err != nil {
-			log.Fatal("error creating directory: ", err)
+			log.Fatal(err)
 		}
+		logs = datagen.NewProfileGenerator(entropy, profile).GenerateLogs(batchSize)
+	} else {
+		generator := datagen.NewLogsGenerator(entropy, entropy.NewStandardResourceAttributes(), entropy.NewStandardInstrumentationScopes())
+		logs = generator.Generate(batchSize, 100)
 	}
 
-	err = os.WriteFile(outputFile, msg, 0600)
-	if err != nil {
+	// Write the dataset, the format and the compression are given by the
+	// extensions of the output file.
+	if err := dataset.WriteLogs(outputFile, []plog.Logs{logs}); err != nil {
 		log.Fatal("write error: ", err)
 	}
 }
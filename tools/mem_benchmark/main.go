@@ -21,17 +21,22 @@ import (
 	"fmt"
 	"math"
 	"runtime"
+	"time"
 
 	"github.com/apache/arrow/go/v12/arrow"
 	"github.com/apache/arrow/go/v12/arrow/memory"
 	"github.com/dustin/go-humanize"
 
+	cfg "github.com/f5/otel-arrow-adapter/pkg/config"
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
 	"github.com/f5/otel-arrow-adapter/pkg/otel/common/schema/builder"
 	config "github.com/f5/otel-arrow-adapter/pkg/otel/common/schema/config"
 	logs "github.com/f5/otel-arrow-adapter/pkg/otel/logs/arrow"
 	metrics "github.com/f5/otel-arrow-adapter/pkg/otel/metrics/arrow"
 	"github.com/f5/otel-arrow-adapter/pkg/otel/stats"
 	traces "github.com/f5/otel-arrow-adapter/pkg/otel/traces/arrow"
+	"github.com/f5/otel-arrow-adapter/pkg/record_message"
 )
 
 const (
@@ -70,6 +75,7 @@ func main() {
 	Report("METRICS", metrics.MetricsSchema)
 	Report("LOGS", logs.LogsSchema)
 	Report("TRACES", traces.TracesSchema)
+	ReportProducer("PRODUCER", 100)
 }
 
 var DictConfig = config.NewDictionary(math.MaxUint16)
@@ -114,6 +120,88 @@ func Report(name string, schema *arrow.Schema) {
 	})
 }
 
+// ReportProducer reports the memory usage of Producer.Produce (i.e. the IPC
+// encoding of the records) for a sequence of trace batches, with and without
+// releasing the payload buffers of the produced batches.
+func ReportProducer(name string, batchCount int) {
+	entropy := datagen.NewTestEntropy(int64(42))
+	generator := datagen.NewTracesGenerator(entropy, entropy.NewStandardResourceAttributes(), entropy.NewStandardInstrumentationScopes())
+
+	// Capture the records of a batch so only their encoding is measured.
+	recorder := &recordsRecorder{}
+	producer := arrow_record.NewProducer()
+	producer.SetObserver(recorder)
+	if _, err := producer.BatchArrowRecordsFromTraces(generator.Generate(1000, time.Minute)); err != nil {
+		panic(err)
+	}
+	if err := producer.Close(); err != nil {
+		panic(err)
+	}
+	defer recorder.Release()
+
+	println("--------------------------------------------------")
+	fmt.Printf("%s%s - Memory usage%s\n", ColorGreen, name, ColorReset)
+
+	for _, zstd := range []bool{true, false} {
+		for _, release := range []bool{false, true} {
+			options := []cfg.Option{cfg.WithZstd()}
+			label := fmt.Sprintf("%d x Producer.Produce(...)", batchCount)
+			if !zstd {
+				options = []cfg.Option{cfg.WithNoZstd()}
+				label += " - no zstd"
+			}
+			if release {
+				label += " + Producer.ReleaseBatch(...)"
+			}
+
+			producer := arrow_record.NewProducerWithOptions(options...)
+			ReportMemUsageOf(label, func() {
+				for i := 0; i < batchCount; i++ {
+					bar, err := producer.Produce(recorder.RecordMessages())
+					if err != nil {
+						panic(err)
+					}
+					if release {
+						producer.ReleaseBatch(bar)
+					}
+				}
+			})
+			if err := producer.Close(); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+// recordsRecorder is a ProducerObserver retaining the records observed.
+type recordsRecorder struct {
+	records      []arrow.Record
+	payloadTypes []record_message.PayloadType
+}
+
+func (r *recordsRecorder) OnRecord(record arrow.Record, payloadType record_message.PayloadType) {
+	record.Retain()
+	r.records = append(r.records, record)
+	r.payloadTypes = append(r.payloadTypes, payloadType)
+}
+
+// RecordMessages returns a RecordMessage for each record observed. The records
+// are retained as they are released by Producer.Produce.
+func (r *recordsRecorder) RecordMessages() []*record_message.RecordMessage {
+	rms := make([]*record_message.RecordMessage, len(r.records))
+	for i, record := range r.records {
+		record.Retain()
+		rms[i] = record_message.NewRelatedDataMessage(r.payloadTypes[i].String(), record, r.payloadTypes[i])
+	}
+	return rms
+}
+
+func (r *recordsRecorder) Release() {
+	for _, record := range r.records {
+		record.Release()
+	}
+}
+
 func ReportMemUsageOf(name string, fn func()) {
 	runtime.GC()
 
@@ -17,7 +17,9 @@ package main
 import (
 	"flag"
 	"fmt"
+	"log"
 	"os"
+	"strings"
 
 	"github.com/dustin/go-humanize"
 
@@ -25,6 +27,7 @@ import (
 	"github.com/f5/otel-arrow-adapter/pkg/benchmark/dataset"
 	"github.com/f5/otel-arrow-adapter/pkg/benchmark/profileable/arrow"
 	"github.com/f5/otel-arrow-adapter/pkg/benchmark/profileable/otlp"
+	cfg "github.com/f5/otel-arrow-adapter/pkg/config"
 	"github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
 )
 
@@ -39,6 +42,30 @@ func main() {
 	// dataset. This flag is disabled by default.
 	stats := flag.Bool("stats", false, "stats mode")
 
+	// The -proto flag profiles an additional OTel Arrow benchmark decoding the
+	// Arrow records directly into OTLP protobuf bytes (i.e. without pdata).
+	protoOutput := flag.Bool("proto", false, "proto output mode")
+
+	// The -results flag writes the results of the benchmark to a JSON file (or
+	// a CSV file with the .csv extension) to compare them with the results of
+	// another run (see the benchmark_compare tool).
+	resultsFile := flag.String("results", "", "results file (.json or .csv)")
+
+	// The -compression flag sets the compression algorithms of the serialized
+	// batches (comma separated), the OTLP and OTel Arrow benchmarks are
+	// profiled with each of them.
+	compressionNames := flag.String("compression", "zstd", "compression algorithms (comma separated): "+strings.Join(benchmark.CompressionAlgorithmNames, ", "))
+
+	// The -ipc_compression flag sets the Arrow IPC compression codec of the
+	// OTel Arrow benchmarks (none, zstd or lz4_frame).
+	ipcCompression := flag.String("ipc_compression", string(cfg.IPCCompressionNone), "Arrow IPC compression codec")
+
+	// The -compression_dictionary flag profiles an additional OTel Arrow
+	// benchmark compressing the Arrow payloads with a zstd dictionary trained
+	// from the given number of payloads (0 disables it). Small batch sizes,
+	// for which this mode is designed, are added to the profiled ones.
+	compressionDictionary := flag.Int("compression_dictionary", 0, "number of payloads used to train a zstd dictionary (0 disables it)")
+
 	// Parse the flag
 	flag.Parse()
 
@@ -54,8 +81,17 @@ func main() {
 		inputFiles = append(inputFiles, "./data/otlp_metrics.pb")
 	}
 
+	algorithms, err := benchmark.ParseCompressionAlgorithms(*compressionNames)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.IPCCompression(*ipcCompression).Validate(); err != nil {
+		log.Fatal(err)
+	}
+
 	conf := &benchmark.Config{
-		Compression: false,
+		Compression:    cfg.IPCCompression(*ipcCompression) != cfg.IPCCompressionNone,
+		IPCCompression: cfg.IPCCompression(*ipcCompression),
 	}
 	if *stats {
 		conf.Stats = true
@@ -64,11 +100,18 @@ func main() {
 	warmUpIter := uint64(1)
 	observer := arrow_record.NewConsoleObserver(500, 1)
 
+	results := benchmark.NewResults()
+
 	// Performance comparison for each input file
 	for i := range inputFiles {
 		// Compare the performance between the standard OTLP representation and the OTLP Arrow representation.
-		profiler := benchmark.NewProfiler([]int{128, 1024, 2048, 4096}, "output/metrics_benchmark.log", warmUpIter)
-		compressionAlgo := benchmark.Zstd()
+		batchSizes := []int{128, 1024, 2048, 4096}
+		if *compressionDictionary > 0 {
+			batchSizes = append([]int{10, 50}, batchSizes...)
+		}
+		profiler := benchmark.NewProfiler(batchSizes, "output/metrics_benchmark.log", warmUpIter)
+		compressionAlgo := algorithms[0]()
+		conf.CompressionAlgorithm = algorithms[0]()
 		maxIter := uint64(3)
 		ds := dataset.NewRealMetricsDataset(inputFiles[i])
 		profiler.Printf("Dataset '%s' (%s) loaded\n", inputFiles[i], humanize.Bytes(uint64(ds.SizeInBytes())))
@@ -89,6 +132,46 @@ func main() {
 			panic(fmt.Errorf("expected no error, got %v", err))
 		}
 
+		// Profile the other compression algorithms.
+		for _, newAlgorithm := range algorithms[1:] {
+			otlpMetrics := otlp.NewMetricsProfileable(ds, newAlgorithm())
+			algoConf := *conf
+			algoConf.CompressionAlgorithm = newAlgorithm()
+			otlpArrowMetrics := arrow.NewMetricsProfileable([]string{"stream mode"}, ds, &algoConf)
+			if err := profiler.Profile(otlpMetrics, maxIter); err != nil {
+				panic(fmt.Errorf("expected no error, got %v", err))
+			}
+			if err := profiler.Profile(otlpArrowMetrics, maxIter); err != nil {
+				panic(fmt.Errorf("expected no error, got %v", err))
+			}
+			benchmark.OtlpArrowConversionSection.CustomColumnFor(otlpMetrics).
+				MetricNotApplicable()
+		}
+
+		// If the compression dictionary mode is enabled, run the OTel Arrow
+		// benchmark with the payloads compressed with a zstd dictionary (the
+		// serialized batches are not compressed again).
+		if *compressionDictionary > 0 {
+			dictConf := *conf
+			dictConf.CompressionDictionarySamples = *compressionDictionary
+			dictConf.CompressionAlgorithm = benchmark.NoCompression()
+			otlpArrowMetrics := arrow.NewMetricsProfileable([]string{"stream mode", "zstd dictionary"}, ds, &dictConf)
+			if err := profiler.Profile(otlpArrowMetrics, maxIter); err != nil {
+				panic(fmt.Errorf("expected no error, got %v", err))
+			}
+		}
+
+		// If the proto output mode is enabled,
+		// run the OTLP Arrow benchmark with a direct decoding to protobuf.
+		if *protoOutput {
+			protoConf := *conf
+			protoConf.ProtoOutput = true
+			otlpArrowMetrics := arrow.NewMetricsProfileable([]string{"stream mode", "proto output"}, ds, &protoConf)
+			if err := profiler.Profile(otlpArrowMetrics, maxIter); err != nil {
+				panic(fmt.Errorf("expected no error, got %v", err))
+			}
+		}
+
 		// If the unary RPC mode is enabled,
 		// run the OTLP Arrow benchmark in unary RPC mode.
 		if *unaryRpcPtr {
@@ -115,10 +198,17 @@ func main() {
 		profiler.Printf("- #metrics: %d\n", ds.Len())
 
 		profiler.PrintResults(maxIter)
+		results.Add(inputFiles[i], profiler, maxIter)
 
 		profiler.ExportMetricsTimesCSV(fmt.Sprintf("%d_metrics_benchmark_results", i))
 		profiler.ExportMetricsBytesCSV(fmt.Sprintf("%d_metrics_benchmark_results", i))
 
 		ds.ShowStats()
 	}
+
+	if *resultsFile != "" {
+		if err := results.WriteFile(*resultsFile); err != nil {
+			log.Fatal("write results: ", err)
+		}
+	}
 }
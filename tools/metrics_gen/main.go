@@ -21,21 +21,25 @@ import (
 	"math"
 	"math/big"
 	"os"
-	"path"
 
+	"github.com/f5/otel-arrow-adapter/pkg/benchmark/dataset"
 	"github.com/f5/otel-arrow-adapter/pkg/datagen"
 
-	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 )
 
 var help = flag.Bool("help", false, "Show help")
 var outputFile = "./data/otlp_metrics.pb"
 var batchSize = 10000
+var profileFile = ""
+var seed int64
 
 func main() {
 	// Define the flags.
-	flag.StringVar(&outputFile, "output", outputFile, "Output file")
+	flag.StringVar(&outputFile, "output", outputFile, "Output file (.pb, .json or .arrow, optionally followed by .gz or .zst)")
 	flag.IntVar(&batchSize, "batchsize", batchSize, "Batch size")
+	flag.StringVar(&profileFile, "profile", profileFile, "Workload profile (.yaml or .json, see tools/profiles)")
+	flag.Int64Var(&seed, "seed", seed, "Seed of the generator, the same seed generates the same data (random if 0)")
 
 	// Parse the flag
 	flag.Parse()
@@ -47,30 +51,30 @@ func main() {
 	}
 
 	// Generate the dataset.
-	v, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
-	if err != nil {
-		log.Fatalf("Failed to generate random number - %v", err)
-	}
-	entropy := datagen.NewTestEntropy(v.Int64())
-
-	generator := datagen.NewMetricsGenerator(entropy, entropy.NewStandardResourceAttributes(), entropy.NewStandardInstrumentationScopes())
-	request := pmetricotlp.NewExportRequestFromMetrics(generator.GenerateAllKindOfMetrics(batchSize, 100))
-
-	// Marshal the request to bytes.
-	msg, err := request.MarshalProto()
-	if err != nil {
-		log.Fatal("marshaling error: ", err)
+	if seed == 0 {
+		v, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+		if err != nil {
+			log.Fatalf("Failed to generate random number - %v", err)
+		}
+		seed = v.Int64()
 	}
+	entropy := datagen.NewTestEntropy(seed)
 
-	// Write protobuf to file
-	if _, err := os.Stat(outputFile); os.IsNotExist(err) {
-		err = os.MkdirAll(path.Dir(outputFile), 0700)
+	var metrics pmetric.Metrics
+	if profileFile != "" {
+		profile, err := datagen.LoadProfile(profileFile)
 		if err != nil {
-			log.Fatal("error creating directory: ", err)
+			log.Fatal(err)
 		}
+		metrics = datagen.NewProfileGenerator(entropy, profile).GenerateMetrics(batchSize)
+	} else {
+		generator := datagen.NewMetricsGenerator(entropy, entropy.NewStandardResourceAttributes(), entropy.NewStandardInstrumentationScopes())
+		metrics = generator.GenerateAllKindOfMetrics(batchSize, 100)
 	}
-	err = os.WriteFile(outputFile, msg, 0600)
-	if err != nil {
+
+	// Write the dataset, the format and the compression are given by the
+	// extensions of the output file.
+	if err := dataset.WriteMetrics(outputFile, []pmetric.Metrics{metrics}); err != nil {
 		log.Fatal("write error: ", err)
 	}
 }
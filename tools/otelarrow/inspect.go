@@ -0,0 +1,460 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/klauspost/compress/zstd"
+	"github.com/olekukonko/tablewriter"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+
+	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	carrow "github.com/f5/otel-arrow-adapter/pkg/arrow"
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+	"github.com/f5/otel-arrow-adapter/pkg/otel/capture"
+)
+
+// Dump modes of the inspect command.
+const (
+	dumpNone  = "none"
+	dumpTable = "table"
+	dumpJSON  = "json"
+)
+
+// zstdFrameOverhead is the size of the uncompressed length prefixing each
+// compressed buffer of an Arrow IPC body.
+const zstdFrameOverhead = 8
+
+func inspectCommand(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	input := fs.String("input", "", "Captured OTLP Arrow stream (required, can also be passed as argument)")
+	dump := fs.String("dump", dumpNone, "Dump the records as tables (table), the decoded batches as OTLP JSON (json) or nothing (none)")
+	maxRows := fs.Int("max_rows", 10, "Maximum number of rows of a table dump")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *input == "" && fs.NArg() == 1 {
+		*input = fs.Arg(0)
+	}
+	if *input == "" {
+		fs.Usage()
+		return errors.New("missing input file")
+	}
+	switch *dump {
+	case dumpNone, dumpTable, dumpJSON:
+	default:
+		return fmt.Errorf("invalid dump mode %q", *dump)
+	}
+
+	f, err := os.Open(*input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ins, err := newInspector(os.Stdout, *dump, *maxRows)
+	if err != nil {
+		return err
+	}
+	defer ins.close()
+
+	reader := capture.NewReader(f)
+	for {
+		bar, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("batch #%d: %w", ins.batches, err)
+		}
+		if err := ins.inspect(bar); err != nil {
+			return fmt.Errorf("batch #%d (%q): %w", ins.batches, bar.BatchId, err)
+		}
+	}
+	return ins.render(os.Stdout)
+}
+
+// inspector replays a captured stream through a Consumer and collects the
+// statistics of each payload type and sub-stream.
+type inspector struct {
+	out     io.Writer
+	dump    string
+	maxRows int
+
+	consumer *arrowRecord.Consumer
+	// jsonConsumer decodes the batches to OTLP (json dump), it has its own
+	// state.
+	jsonConsumer *arrowRecord.Consumer
+	encoder      *zstd.Encoder
+
+	batches      int
+	payloads     int
+	wireBytes    int64
+	payloadTypes map[colarspb.ArrowPayloadType]*payloadTypeStats
+}
+
+// payloadTypeStats contains the sub-streams of a payload type, in the order
+// of the stream. The producer starts a new sub-stream when the schema of the
+// payload type changes (or when the sub-stream is reset).
+type payloadTypeStats struct {
+	payloadType colarspb.ArrowPayloadType
+	subStreams  []*subStreamStats
+	byID        map[string]*subStreamStats
+}
+
+// subStreamStats contains the statistics of a sub-stream.
+type subStreamStats struct {
+	id        string
+	batches   int
+	rows      int64
+	wireBytes int64
+
+	columns []*columnStats
+	byPath  map[string]*columnStats
+
+	// The columns added, removed and with a different type compared to the
+	// previous sub-stream of the same payload type.
+	initial                 bool
+	added, removed, changed []string
+}
+
+// columnStats contains the statistics of a column. The columns of the
+// structs and of the items of the lists are reported individually, the
+// items are identified by a "[]" suffix.
+type columnStats struct {
+	path     string
+	dataType string
+
+	// The bytes of the buffers of the column (excluding its children and
+	// its dictionary), uncompressed and compressed with zstd. The
+	// compressed size is estimated by compressing each buffer like the
+	// Arrow IPC writer.
+	uncompressed int64
+	compressed   int64
+
+	// The last number of entries of the dictionary, the number of records
+	// adding entries to the dictionary (deltas) or replacing it by a
+	// smaller one (resets), and the size of the last dictionary.
+	dictionary  bool
+	dictEntries int
+	dictDeltas  int
+	dictResets  int
+	dictBytes   int64
+}
+
+func newInspector(out io.Writer, dump string, maxRows int) (*inspector, error) {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inspector{
+		out:          out,
+		dump:         dump,
+		maxRows:      maxRows,
+		consumer:     arrowRecord.NewConsumer(),
+		jsonConsumer: arrowRecord.NewConsumer(),
+		encoder:      encoder,
+		payloadTypes: make(map[colarspb.ArrowPayloadType]*payloadTypeStats),
+	}, nil
+}
+
+func (i *inspector) close() {
+	_ = i.consumer.Close()
+	_ = i.jsonConsumer.Close()
+	_ = i.encoder.Close()
+}
+
+// inspect replays a batch.
+func (i *inspector) inspect(bar *colarspb.BatchArrowRecords) error {
+	records, err := i.consumer.Consume(bar)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, rm := range records {
+			rm.Record().Release()
+		}
+	}()
+	if len(records) != len(bar.ArrowPayloads) {
+		return fmt.Errorf("%d records decoded out of %d payloads", len(records), len(bar.ArrowPayloads))
+	}
+
+	i.batches++
+	for k, payload := range bar.ArrowPayloads {
+		record := records[k].Record()
+		i.payloads++
+		i.wireBytes += int64(len(payload.Record))
+
+		ss := i.subStream(payload.Type, payload.SubStreamId, record)
+		ss.batches++
+		ss.rows += record.NumRows()
+		ss.wireBytes += int64(len(payload.Record))
+		for c, column := range record.Columns() {
+			walkArray(record.ColumnName(c), column, func(path string, arr arrow.Array) {
+				i.observe(ss, path, arr)
+			})
+		}
+
+		if i.dump == dumpTable {
+			name := fmt.Sprintf("%s sub-stream %s batch %s", payload.Type, payload.SubStreamId, bar.BatchId)
+			carrow.FprintRecord(i.out, name, record, i.maxRows, ss.batches, ss.batches)
+		}
+	}
+
+	if i.dump == dumpJSON {
+		return i.dumpJSON(bar)
+	}
+	return nil
+}
+
+// subStream returns the statistics of a sub-stream, created with the
+// schema of record if the sub-stream is new.
+func (i *inspector) subStream(payloadType colarspb.ArrowPayloadType, id string, record arrow.Record) *subStreamStats {
+	pt, ok := i.payloadTypes[payloadType]
+	if !ok {
+		pt = &payloadTypeStats{payloadType: payloadType, byID: make(map[string]*subStreamStats)}
+		i.payloadTypes[payloadType] = pt
+	}
+	if ss, ok := pt.byID[id]; ok {
+		return ss
+	}
+
+	ss := &subStreamStats{id: id, byPath: make(map[string]*columnStats)}
+	for c, column := range record.Columns() {
+		walkArray(record.ColumnName(c), column, func(path string, arr arrow.Array) {
+			ss.column(path, arr)
+		})
+	}
+
+	if len(pt.subStreams) == 0 {
+		ss.initial = true
+	} else {
+		prev := pt.subStreams[len(pt.subStreams)-1]
+		for _, c := range ss.columns {
+			if prevColumn, ok := prev.byPath[c.path]; !ok {
+				ss.added = append(ss.added, c.path)
+			} else if prevColumn.dataType != c.dataType {
+				ss.changed = append(ss.changed, c.path)
+			}
+		}
+		for _, c := range prev.columns {
+			if _, ok := ss.byPath[c.path]; !ok {
+				ss.removed = append(ss.removed, c.path)
+			}
+		}
+	}
+
+	pt.subStreams = append(pt.subStreams, ss)
+	pt.byID[id] = ss
+	return ss
+}
+
+// column returns the statistics of a column, created if needed.
+func (ss *subStreamStats) column(path string, arr arrow.Array) *columnStats {
+	c, ok := ss.byPath[path]
+	if !ok {
+		c = &columnStats{path: path, dataType: arr.DataType().String()}
+		ss.columns = append(ss.columns, c)
+		ss.byPath[path] = c
+	}
+	return c
+}
+
+// observe adds the buffers and the dictionary of a column to its
+// statistics.
+func (i *inspector) observe(ss *subStreamStats, path string, arr arrow.Array) {
+	c := ss.column(path, arr)
+	uncompressed, compressed := i.bufferBytes(arr.Data())
+	c.uncompressed += uncompressed
+	c.compressed += compressed
+
+	dict, ok := arr.(*array.Dictionary)
+	if !ok {
+		return
+	}
+	entries := dict.Dictionary().Len()
+	if c.dictionary {
+		if entries > c.dictEntries {
+			c.dictDeltas++
+		} else if entries < c.dictEntries {
+			c.dictResets++
+		}
+	}
+	c.dictionary = true
+	c.dictEntries = entries
+	c.dictBytes, _ = i.bufferBytes(dict.Dictionary().Data())
+}
+
+// bufferBytes returns the size of the buffers of an array (excluding its
+// children), uncompressed and compressed.
+func (i *inspector) bufferBytes(data arrow.ArrayData) (uncompressed, compressed int64) {
+	for _, buf := range data.Buffers() {
+		if buf == nil || buf.Len() == 0 {
+			continue
+		}
+		uncompressed += int64(buf.Len())
+		compressed += int64(len(i.encoder.EncodeAll(buf.Bytes(), nil)) + zstdFrameOverhead)
+	}
+	return uncompressed, compressed
+}
+
+// dumpJSON writes the OTLP requests decoded from a batch, one per line.
+func (i *inspector) dumpJSON(bar *colarspb.BatchArrowRecords) error {
+	var requests []json.Marshaler
+	for _, payload := range bar.ArrowPayloads {
+		switch payload.Type {
+		case colarspb.ArrowPayloadType_SPANS:
+			traces, err := i.jsonConsumer.TracesFrom(bar)
+			if err != nil {
+				return err
+			}
+			for _, t := range traces {
+				requests = append(requests, ptraceotlp.NewExportRequestFromTraces(t))
+			}
+		case colarspb.ArrowPayloadType_LOGS:
+			logs, err := i.jsonConsumer.LogsFrom(bar)
+			if err != nil {
+				return err
+			}
+			for _, l := range logs {
+				requests = append(requests, plogotlp.NewExportRequestFromLogs(l))
+			}
+		case colarspb.ArrowPayloadType_METRICS:
+			metrics, err := i.jsonConsumer.MetricsFrom(bar)
+			if err != nil {
+				return err
+			}
+			for _, m := range metrics {
+				requests = append(requests, pmetricotlp.NewExportRequestFromMetrics(m))
+			}
+		default:
+			// Related records, decoded with their main record.
+			continue
+		}
+		break
+	}
+
+	for _, request := range requests {
+		data, err := request.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(i.out, "%s\n", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// render writes the report.
+func (i *inspector) render(w io.Writer) error {
+	fmt.Fprintf(w, "\nBatches: %d, payloads: %d, wire bytes: %d\n", i.batches, i.payloads, i.wireBytes)
+
+	payloadTypes := make([]*payloadTypeStats, 0, len(i.payloadTypes))
+	for _, pt := range i.payloadTypes {
+		payloadTypes = append(payloadTypes, pt)
+	}
+	sort.Slice(payloadTypes, func(a, b int) bool { return payloadTypes[a].payloadType < payloadTypes[b].payloadType })
+
+	for _, pt := range payloadTypes {
+		fmt.Fprintf(w, "\n%s\n", pt.payloadType)
+		for _, ss := range pt.subStreams {
+			fmt.Fprintf(w, "\n  Sub-stream %q: %d batches, %d rows, %d wire bytes, %d columns", ss.id, ss.batches, ss.rows, ss.wireBytes, len(ss.columns))
+			if ss.initial {
+				fmt.Fprintln(w, " (initial schema)")
+			} else {
+				fmt.Fprintf(w, " (schema changes: %s)\n", schemaChanges(ss))
+			}
+
+			table := tablewriter.NewWriter(w)
+			table.SetHeader([]string{"Column", "Type", "Bytes", "Zstd bytes", "Dict entries", "Dict deltas", "Dict resets", "Dict bytes"})
+			table.SetAutoWrapText(false)
+			table.SetColumnAlignment([]int{
+				tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT,
+				tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT,
+				tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT,
+			})
+			for _, c := range ss.columns {
+				row := []string{c.path, c.dataType, strconv.FormatInt(c.uncompressed, 10), strconv.FormatInt(c.compressed, 10), "", "", "", ""}
+				if c.dictionary {
+					row[4] = strconv.Itoa(c.dictEntries)
+					row[5] = strconv.Itoa(c.dictDeltas)
+					row[6] = strconv.Itoa(c.dictResets)
+					row[7] = strconv.FormatInt(c.dictBytes, 10)
+				}
+				table.Append(row)
+			}
+			table.Render()
+		}
+	}
+	return nil
+}
+
+// schemaChanges describes the schema changes of a sub-stream, e.g.
+// "+int, -str, ~key" for an added, a removed and a changed column.
+func schemaChanges(ss *subStreamStats) string {
+	var changes []string
+	for _, path := range ss.added {
+		changes = append(changes, "+"+path)
+	}
+	for _, path := range ss.removed {
+		changes = append(changes, "-"+path)
+	}
+	for _, path := range ss.changed {
+		changes = append(changes, "~"+path)
+	}
+	if len(changes) == 0 {
+		return "none"
+	}
+	return strings.Join(changes, ", ")
+}
+
+// walkArray calls fn for each column of an array. The fields of the structs
+// and unions are walked with a "." separator, the items of the lists (and
+// maps) with a "[]" suffix. The structs themselves are not reported.
+func walkArray(path string, arr arrow.Array, fn func(path string, arr arrow.Array)) {
+	switch a := arr.(type) {
+	case *array.Struct:
+		st := a.DataType().(*arrow.StructType)
+		for f := 0; f < a.NumField(); f++ {
+			walkArray(path+"."+st.Field(f).Name, a.Field(f), fn)
+		}
+	case array.ListLike:
+		fn(path, arr)
+		walkArray(path+"[]", a.ListValues(), fn)
+	case array.Union:
+		fn(path, arr)
+		fields := a.UnionType().Fields()
+		for f := 0; f < a.NumFields(); f++ {
+			walkArray(path+"."+fields[f].Name, a.Field(f), fn)
+		}
+	default:
+		fn(path, arr)
+	}
+}
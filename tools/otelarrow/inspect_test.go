@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	colarspb "github.com/f5/otel-arrow-adapter/api/experimental/arrow/v1"
+	arrowRecord "github.com/f5/otel-arrow-adapter/pkg/otel/arrow_record"
+)
+
+func testTraces(spans int, intAttr bool) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	ss := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty()
+	for i := 0; i < spans; i++ {
+		span := ss.Spans().AppendEmpty()
+		span.SetName("span")
+		span.Attributes().PutStr("str", "value")
+		if intAttr {
+			span.Attributes().PutInt("int", int64(i))
+		}
+	}
+	return traces
+}
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+
+	producer := arrowRecord.NewProducer()
+	defer func() { require.NoError(t, producer.Close()) }()
+
+	var out bytes.Buffer
+	ins, err := newInspector(&out, dumpJSON, 10)
+	require.NoError(t, err)
+	defer ins.close()
+
+	// The int attribute of the third batch changes the schema of the span
+	// attributes.
+	for _, traces := range []ptrace.Traces{testTraces(3, false), testTraces(4, false), testTraces(5, true)} {
+		bar, err := producer.BatchArrowRecordsFromTraces(traces)
+		require.NoError(t, err)
+		require.NoError(t, ins.inspect(bar))
+	}
+	require.Equal(t, 3, ins.batches)
+
+	spans := ins.payloadTypes[colarspb.ArrowPayloadType_SPANS]
+	require.NotNil(t, spans)
+	var rows int64
+	for _, ss := range spans.subStreams {
+		rows += ss.rows
+	}
+	require.Equal(t, int64(12), rows)
+
+	attrs := ins.payloadTypes[colarspb.ArrowPayloadType_SPAN_ATTRS]
+	require.NotNil(t, attrs)
+	require.Len(t, attrs.subStreams, 2)
+	require.True(t, attrs.subStreams[0].initial)
+	require.Equal(t, 2, attrs.subStreams[0].batches)
+	require.Contains(t, attrs.subStreams[1].added, "int")
+	require.Contains(t, schemaChanges(attrs.subStreams[1]), "+int")
+
+	// One OTLP JSON request per batch.
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	require.Contains(t, lines[2], `"intValue":"4"`)
+
+	out.Reset()
+	require.NoError(t, ins.render(&out))
+	require.Contains(t, out.String(), "SPAN_ATTRS")
+	require.Contains(t, out.String(), "schema changes: +int")
+}
+
+func TestWalkArray(t *testing.T) {
+	t.Parallel()
+
+	producer := arrowRecord.NewProducer()
+	defer func() { require.NoError(t, producer.Close()) }()
+	bar, err := producer.BatchArrowRecordsFromTraces(testTraces(2, true))
+	require.NoError(t, err)
+
+	ins, err := newInspector(&bytes.Buffer{}, dumpNone, 10)
+	require.NoError(t, err)
+	defer ins.close()
+	require.NoError(t, ins.inspect(bar))
+
+	for _, pt := range ins.payloadTypes {
+		for _, ss := range pt.subStreams {
+			for _, c := range ss.columns {
+				require.NotContains(t, c.dataType, "struct<", "struct %s must be flattened", c.path)
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	errMissingInput  = errors.New("missing input file")
+	errMissingOutput = errors.New("missing output file")
+)
+
+// newFlagSet creates the flag set of a command with the -signal flag.
+func newFlagSet(name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	signalName := fs.String("signal", "traces", "Signal of the dataset: traces, logs or metrics")
+	return fs, signalName
+}
+
+// parse parses the flags of a command and returns its signal.
+func parse(fs *flag.FlagSet, signalName *string, args []string) (commandSet, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	s, ok := signals[*signalName]
+	if !ok {
+		return nil, fmt.Errorf("unknown signal %q", *signalName)
+	}
+	return s, nil
+}
+
+// inputOutput parses the -input and -output flags of a command.
+func inputOutput(fs *flag.FlagSet) (input, output *string) {
+	input = fs.String("input", "", "Input file (required)")
+	output = fs.String("output", "", "Output file (required)")
+	return input, output
+}
+
+func checkInputOutput(input, output string) error {
+	if input == "" {
+		return errMissingInput
+	}
+	if output == "" {
+		return errMissingOutput
+	}
+	return nil
+}
+
+func headCommand(args []string) error {
+	fs, signalName := newFlagSet("head")
+	input, output := inputOutput(fs)
+	n := fs.Int("n", 10000, "Number of items (spans are sorted by trace ID)")
+	s, err := parse(fs, signalName, args)
+	if err != nil {
+		return err
+	}
+	if err := checkInputOutput(*input, *output); err != nil {
+		return err
+	}
+	return s.head(*input, *output, *n)
+}
+
+func sampleCommand(args []string) error {
+	fs, signalName := newFlagSet("sample")
+	input, output := inputOutput(fs)
+	ratio := fs.Float64("ratio", 0.1, "Ratio of items (or traces) kept")
+	by := fs.String("by", "item", "Sample by item or by trace (traces and logs, the items without trace ID are sampled by item)")
+	seed := fs.Int64("seed", 1, "Seed of the sampling by item")
+	s, err := parse(fs, signalName, args)
+	if err != nil {
+		return err
+	}
+	if err := checkInputOutput(*input, *output); err != nil {
+		return err
+	}
+	if *ratio < 0 || *ratio > 1 {
+		return fmt.Errorf("ratio %v out of [0, 1]", *ratio)
+	}
+	if *by != "item" && *by != "trace" {
+		return fmt.Errorf("invalid sampling %q, expected item or trace", *by)
+	}
+	return s.sample(*input, *output, newSampler(*ratio, *by == "trace", *seed))
+}
+
+func mergeCommand(args []string) error {
+	fs, signalName := newFlagSet("merge")
+	output := fs.String("output", "", "Output file (required)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: otlpds merge [flags] <input>...")
+		fs.PrintDefaults()
+	}
+	s, err := parse(fs, signalName, args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errMissingInput
+	}
+	if *output == "" {
+		return errMissingOutput
+	}
+	return s.merge(fs.Args(), *output)
+}
+
+func splitCommand(args []string) error {
+	fs, signalName := newFlagSet("split")
+	input := fs.String("input", "", "Input file (required)")
+	output := fs.String("output", "", `Output files, with a %d verb replaced by the index of the file, e.g. "traces-%03d.pb" (required)`)
+	n := fs.Int("n", 10000, "Number of items per file")
+	s, err := parse(fs, signalName, args)
+	if err != nil {
+		return err
+	}
+	if err := checkInputOutput(*input, *output); err != nil {
+		return err
+	}
+	if !strings.Contains(*output, "%") {
+		return fmt.Errorf("output %q has no %%d verb", *output)
+	}
+	if *n <= 0 {
+		return fmt.Errorf("invalid number of items %d", *n)
+	}
+	return s.split(*input, *output, *n)
+}
+
+func statsCommand(args []string) error {
+	fs, signalName := newFlagSet("stats")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: otlpds stats [flags] <input>...")
+		fs.PrintDefaults()
+	}
+	s, err := parse(fs, signalName, args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errMissingInput
+	}
+	for _, input := range fs.Args() {
+		if err := s.stats(os.Stdout, input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func convertCommand(args []string) error {
+	fs, signalName := newFlagSet("convert")
+	input, output := inputOutput(fs)
+	batchSize := fs.Int("batch_size", 0, "Number of items per batch of an Arrow output (spans are sorted by trace ID), 0 writes a single batch")
+	s, err := parse(fs, signalName, args)
+	if err != nil {
+		return err
+	}
+	if err := checkInputOutput(*input, *output); err != nil {
+		return err
+	}
+	return s.convert(*input, *output, *batchSize)
+}
@@ -0,0 +1,24 @@
+/*
+ * Copyright The OpenTelemetry Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package main contains a CLI tool to manipulate OTLP datasets of traces,
+// logs and metrics.
+//
+// The datasets are OTLP protobuf, OTLP JSON (".json") or captured OTLP
+// Arrow streams (".arrow"), optionally compressed with gzip (".gz") or zstd
+// (".zst"), see dataset.FileFormat.
+package main
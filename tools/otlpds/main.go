@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+const usage = `Usage: otlpds <command> [arguments]
+
+Commands:
+  head     extract the first items of a dataset
+  sample   sample the items of a dataset by trace or by ratio
+  merge    merge several datasets
+  split    split a dataset in datasets of n items
+  stats    report the content of datasets
+  convert  convert a dataset to another format
+
+The items are the spans, the log records or the metrics of the dataset. The
+format and the compression of the files are given by their extensions:
+.pb (OTLP protobuf, default), .json (OTLP JSON), .arrow (captured OTLP Arrow
+stream), followed by .gz (gzip) or .zst (zstd).
+
+Run "otlpds <command> -help" for the arguments of a command.
+`
+
+var commands = map[string]func(args []string) error{
+	"head":    headCommand,
+	"sample":  sampleCommand,
+	"merge":   mergeCommand,
+	"split":   splitCommand,
+	"stats":   statsCommand,
+	"convert": convertCommand,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if os.Args[1] == "help" || os.Args[1] == "-help" || os.Args[1] == "-h" {
+		fmt.Print(usage)
+		return
+	}
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	err := command(os.Args[2:])
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
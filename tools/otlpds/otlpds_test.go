@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/f5/otel-arrow-adapter/pkg/benchmark/dataset"
+	"github.com/f5/otel-arrow-adapter/pkg/datagen"
+)
+
+func writeTestTraces(t *testing.T, file string, spans int) ptrace.Traces {
+	entropy := datagen.NewTestEntropy(int64(42))
+	generator := datagen.NewTracesGenerator(entropy, entropy.NewStandardResourceAttributes(), entropy.NewStandardInstrumentationScopes())
+	traces := generator.Generate(spans, time.Minute)
+	require.NoError(t, dataset.WriteTraces(file, []ptrace.Traces{traces}))
+	return traces
+}
+
+func readSpanCount(t *testing.T, file string) int {
+	traces, _, err := dataset.ReadTraces(file)
+	require.NoError(t, err)
+	return traces.SpanCount()
+}
+
+func TestHeadSplitMerge(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "traces.pb.zst")
+	writeTestTraces(t, input, 100)
+
+	require.NoError(t, headCommand([]string{"-input", input, "-output", filepath.Join(dir, "head.json"), "-n", "30"}))
+	require.Equal(t, 30, readSpanCount(t, filepath.Join(dir, "head.json")))
+
+	require.NoError(t, splitCommand([]string{"-input", input, "-output", filepath.Join(dir, "split-%d.pb.gz"), "-n", "40"}))
+	for i, expected := range []int{40, 40, 20} {
+		require.Equal(t, expected, readSpanCount(t, filepath.Join(dir, fmt.Sprintf("split-%d.pb.gz", i))))
+	}
+
+	output := filepath.Join(dir, "merged.arrow")
+	require.NoError(t, mergeCommand([]string{"-output", output,
+		filepath.Join(dir, "split-0.pb.gz"), filepath.Join(dir, "split-1.pb.gz"), filepath.Join(dir, "split-2.pb.gz")}))
+	require.Equal(t, 100, readSpanCount(t, output))
+
+	require.ErrorIs(t, mergeCommand([]string{"-output", output}), errMissingInput)
+	require.ErrorIs(t, headCommand([]string{"-input", input}), errMissingOutput)
+	require.Error(t, splitCommand([]string{"-input", input, "-output", "split.pb"}))
+}
+
+func TestSample(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "traces.pb")
+	traces := writeTestTraces(t, input, 500)
+
+	output := filepath.Join(dir, "sampled.pb")
+	require.NoError(t, sampleCommand([]string{"-input", input, "-output", output, "-by", "trace", "-ratio", "0.5"}))
+	sampled, _, err := dataset.ReadTraces(output)
+	require.NoError(t, err)
+	require.Greater(t, sampled.SpanCount(), 0)
+	require.Less(t, sampled.SpanCount(), traces.SpanCount())
+
+	// The traces are kept or dropped as a whole.
+	s := newSampler(0.5, true, 1)
+	expected := 0
+	rss := traces.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		sss := rss.At(i).ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			spans := sss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if s.keep(spans.At(k).TraceID()) {
+					expected++
+				}
+			}
+		}
+	}
+	require.Equal(t, expected, sampled.SpanCount())
+
+	require.NoError(t, sampleCommand([]string{"-input", input, "-output", output, "-ratio", "0"}))
+	require.Equal(t, 0, readSpanCount(t, output))
+
+	require.Error(t, sampleCommand([]string{"-input", input, "-output", output, "-ratio", "2"}))
+	require.ErrorIs(t, metricsSignal.sampleData(pmetric.NewMetrics(), newSampler(0.5, true, 1)), errMetricsByTrace)
+}
+
+func TestSamplerByTrace(t *testing.T) {
+	t.Parallel()
+
+	s1 := newSampler(0.3, true, 1)
+	s2 := newSampler(0.3, true, 2)
+	kept := 0
+	for i := 0; i < 1000; i++ {
+		traceID := pcommon.TraceID([16]byte{byte(i), byte(i >> 8), 1})
+		require.Equal(t, s1.keep(traceID), s2.keep(traceID))
+		if s1.keep(traceID) {
+			kept++
+		}
+	}
+	require.InDelta(t, 300, kept, 60)
+}
+
+func TestConvertStats(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "traces.json.gz")
+	writeTestTraces(t, input, 50)
+
+	output := filepath.Join(dir, "traces.arrow.zst")
+	require.NoError(t, convertCommand([]string{"-input", input, "-output", output, "-batch_size", "20"}))
+	require.Equal(t, 50, readSpanCount(t, output))
+
+	var buf bytes.Buffer
+	require.NoError(t, tracesSignal.stats(&buf, output))
+	require.Contains(t, buf.String(), "OTLP Arrow, zstd")
+	require.Contains(t, buf.String(), "spans: 50")
+}
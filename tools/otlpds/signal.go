@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"math/rand"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/f5/otel-arrow-adapter/pkg/benchmark/dataset"
+)
+
+// commandSet implements the commands for a signal.
+type commandSet interface {
+	head(input, output string, n int) error
+	sample(input, output string, s *sampler) error
+	merge(inputs []string, output string) error
+	split(input, output string, n int) error
+	stats(w io.Writer, input string) error
+	convert(input, output string, batchSize int) error
+}
+
+var signals = map[string]commandSet{
+	"traces":  tracesSignal,
+	"logs":    logsSignal,
+	"metrics": metricsSignal,
+}
+
+// signal implements the commands for the data type T of a signal
+// (ptrace.Traces, plog.Logs or pmetric.Metrics).
+type signal[T any] struct {
+	// items is the name of the items of the signal.
+	items string
+
+	// read and write are the dataset functions of the signal, e.g.
+	// dataset.ReadTraces and dataset.WriteTraces.
+	read  func(file string) (T, int, error)
+	write func(file string, batches []T) error
+	// load loads a file as a dataset, it returns the number of items of the
+	// dataset and the function returning a range of items.
+	load func(file string) (int, func(offset, size int) []T)
+
+	newData func() T
+	// moveTo moves the resources of src to dest.
+	moveTo func(src, dest T)
+	// sampleData removes the items not kept by the sampler.
+	sampleData func(data T, s *sampler) error
+	// count returns the statistics of data.
+	count func(data T) datasetStats
+}
+
+// datasetStats contains the statistics reported by the stats command.
+type datasetStats struct {
+	resources  int
+	scopes     int
+	items      int
+	dataPoints int
+	// traces is the number of distinct trace IDs, 0 for metrics.
+	traces     int
+	protoBytes int
+}
+
+func (s *signal[T]) head(input, output string, n int) error {
+	length, items := s.load(input)
+	if n > length {
+		n = length
+	}
+	return s.write(output, items(0, n))
+}
+
+func (s *signal[T]) sample(input, output string, smp *sampler) error {
+	data, _, err := s.read(input)
+	if err != nil {
+		return err
+	}
+	if err := s.sampleData(data, smp); err != nil {
+		return err
+	}
+	return s.write(output, []T{data})
+}
+
+func (s *signal[T]) merge(inputs []string, output string) error {
+	merged := s.newData()
+	for _, input := range inputs {
+		data, _, err := s.read(input)
+		if err != nil {
+			return err
+		}
+		s.moveTo(data, merged)
+	}
+	return s.write(output, []T{merged})
+}
+
+func (s *signal[T]) split(input, output string, n int) error {
+	length, items := s.load(input)
+	for i, offset := 0, 0; offset < length; i, offset = i+1, offset+n {
+		size := n
+		if offset+size > length {
+			size = length - offset
+		}
+		if err := s.write(fmt.Sprintf(output, i), items(offset, size)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *signal[T]) stats(w io.Writer, input string) error {
+	data, size, err := s.read(input)
+	if err != nil {
+		return err
+	}
+	st := s.count(data)
+
+	format, compression := dataset.FileFormat(input)
+	fmt.Fprintf(w, "%s (%s, %s)\n", input, formatNames[format], compressionNames[compression])
+	fmt.Fprintf(w, "  decompressed bytes: %d, OTLP protobuf bytes: %d\n", size, st.protoBytes)
+	fmt.Fprintf(w, "  resources: %d, scopes: %d, %s: %d", st.resources, st.scopes, s.items, st.items)
+	if st.dataPoints > 0 {
+		fmt.Fprintf(w, ", data points: %d", st.dataPoints)
+	}
+	if st.traces > 0 {
+		fmt.Fprintf(w, ", traces: %d", st.traces)
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+func (s *signal[T]) convert(input, output string, batchSize int) error {
+	if batchSize <= 0 {
+		data, _, err := s.read(input)
+		if err != nil {
+			return err
+		}
+		return s.write(output, []T{data})
+	}
+
+	length, items := s.load(input)
+	var batches []T
+	for offset := 0; offset < length; offset += batchSize {
+		size := batchSize
+		if offset+size > length {
+			size = length - offset
+		}
+		batches = append(batches, items(offset, size)...)
+	}
+	return s.write(output, batches)
+}
+
+var formatNames = map[dataset.Format]string{
+	dataset.FormatProto: "OTLP protobuf",
+	dataset.FormatJSON:  "OTLP JSON",
+	dataset.FormatArrow: "OTLP Arrow",
+}
+
+var compressionNames = map[dataset.Compression]string{
+	dataset.CompressionNone: "uncompressed",
+	dataset.CompressionGzip: "gzip",
+	dataset.CompressionZstd: "zstd",
+}
+
+// sampler decides which items are kept by the sample command.
+type sampler struct {
+	ratio   float64
+	byTrace bool
+	rand    *rand.Rand
+}
+
+func newSampler(ratio float64, byTrace bool, seed int64) *sampler {
+	return &sampler{
+		ratio:   ratio,
+		byTrace: byTrace,
+		rand:    rand.New(rand.NewSource(seed)), //nolint:gosec // sampling isn't security sensitive
+	}
+}
+
+// keep returns true if an item with the given trace ID is kept. When
+// sampling by trace, the decision only depends on the trace ID so that all
+// the spans and logs of a trace are kept or dropped together, even across
+// datasets.
+func (s *sampler) keep(traceID pcommon.TraceID) bool {
+	if !s.byTrace || traceID.IsEmpty() {
+		return s.rand.Float64() < s.ratio
+	}
+	h := fnv.New64a()
+	_, _ = h.Write(traceID[:])
+	return s.ratio >= 1 || float64(h.Sum64()) < s.ratio*math.MaxUint64
+}
@@ -0,0 +1,165 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/f5/otel-arrow-adapter/pkg/benchmark/dataset"
+)
+
+var errMetricsByTrace = errors.New("metrics can't be sampled by trace")
+
+var tracesSignal = &signal[ptrace.Traces]{
+	items: "spans",
+	read:  dataset.ReadTraces,
+	write: dataset.WriteTraces,
+	load: func(file string) (int, func(offset, size int) []ptrace.Traces) {
+		ds := dataset.NewRealTraceDataset(file, []string{"trace_id"})
+		return ds.Len(), ds.Traces
+	},
+	newData: ptrace.NewTraces,
+	moveTo: func(src, dest ptrace.Traces) {
+		src.ResourceSpans().MoveAndAppendTo(dest.ResourceSpans())
+	},
+	sampleData: func(td ptrace.Traces, s *sampler) error {
+		td.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+			rs.ScopeSpans().RemoveIf(func(ss ptrace.ScopeSpans) bool {
+				ss.Spans().RemoveIf(func(span ptrace.Span) bool {
+					return !s.keep(span.TraceID())
+				})
+				return ss.Spans().Len() == 0
+			})
+			return rs.ScopeSpans().Len() == 0
+		})
+		return nil
+	},
+	count: func(td ptrace.Traces) datasetStats {
+		st := datasetStats{protoBytes: (&ptrace.ProtoMarshaler{}).TracesSize(td)}
+		traceIDs := make(map[pcommon.TraceID]struct{})
+		rss := td.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			st.resources++
+			sss := rss.At(i).ScopeSpans()
+			for j := 0; j < sss.Len(); j++ {
+				st.scopes++
+				spans := sss.At(j).Spans()
+				st.items += spans.Len()
+				for k := 0; k < spans.Len(); k++ {
+					traceIDs[spans.At(k).TraceID()] = struct{}{}
+				}
+			}
+		}
+		st.traces = len(traceIDs)
+		return st
+	},
+}
+
+var logsSignal = &signal[plog.Logs]{
+	items: "log records",
+	read:  dataset.ReadLogs,
+	write: dataset.WriteLogs,
+	load: func(file string) (int, func(offset, size int) []plog.Logs) {
+		ds := dataset.NewRealLogsDataset(file)
+		return ds.Len(), ds.Logs
+	},
+	newData: plog.NewLogs,
+	moveTo: func(src, dest plog.Logs) {
+		src.ResourceLogs().MoveAndAppendTo(dest.ResourceLogs())
+	},
+	sampleData: func(ld plog.Logs, s *sampler) error {
+		ld.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+			rl.ScopeLogs().RemoveIf(func(sl plog.ScopeLogs) bool {
+				sl.LogRecords().RemoveIf(func(lr plog.LogRecord) bool {
+					return !s.keep(lr.TraceID())
+				})
+				return sl.LogRecords().Len() == 0
+			})
+			return rl.ScopeLogs().Len() == 0
+		})
+		return nil
+	},
+	count: func(ld plog.Logs) datasetStats {
+		st := datasetStats{protoBytes: (&plog.ProtoMarshaler{}).LogsSize(ld)}
+		traceIDs := make(map[pcommon.TraceID]struct{})
+		rls := ld.ResourceLogs()
+		for i := 0; i < rls.Len(); i++ {
+			st.resources++
+			sls := rls.At(i).ScopeLogs()
+			for j := 0; j < sls.Len(); j++ {
+				st.scopes++
+				lrs := sls.At(j).LogRecords()
+				st.items += lrs.Len()
+				for k := 0; k < lrs.Len(); k++ {
+					if traceID := lrs.At(k).TraceID(); !traceID.IsEmpty() {
+						traceIDs[traceID] = struct{}{}
+					}
+				}
+			}
+		}
+		st.traces = len(traceIDs)
+		return st
+	},
+}
+
+var metricsSignal = &signal[pmetric.Metrics]{
+	items: "metrics",
+	read:  dataset.ReadMetrics,
+	write: dataset.WriteMetrics,
+	load: func(file string) (int, func(offset, size int) []pmetric.Metrics) {
+		ds := dataset.NewRealMetricsDataset(file)
+		return ds.Len(), ds.Metrics
+	},
+	newData: pmetric.NewMetrics,
+	moveTo: func(src, dest pmetric.Metrics) {
+		src.ResourceMetrics().MoveAndAppendTo(dest.ResourceMetrics())
+	},
+	sampleData: func(md pmetric.Metrics, s *sampler) error {
+		if s.byTrace {
+			return errMetricsByTrace
+		}
+		md.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+			rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+				sm.Metrics().RemoveIf(func(m pmetric.Metric) bool {
+					return !s.keep(pcommon.NewTraceIDEmpty())
+				})
+				return sm.Metrics().Len() == 0
+			})
+			return rm.ScopeMetrics().Len() == 0
+		})
+		return nil
+	},
+	count: func(md pmetric.Metrics) datasetStats {
+		st := datasetStats{
+			protoBytes: (&pmetric.ProtoMarshaler{}).MetricsSize(md),
+			dataPoints: md.DataPointCount(),
+		}
+		rms := md.ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			st.resources++
+			sms := rms.At(i).ScopeMetrics()
+			for j := 0; j < sms.Len(); j++ {
+				st.scopes++
+				st.items += sms.At(j).Metrics().Len()
+			}
+		}
+		return st
+	},
+}
@@ -17,7 +17,9 @@ package main
 import (
 	"flag"
 	"fmt"
+	"log"
 	"os"
+	"strings"
 
 	"github.com/dustin/go-humanize"
 
@@ -25,6 +27,7 @@ import (
 	"github.com/f5/otel-arrow-adapter/pkg/benchmark/dataset"
 	"github.com/f5/otel-arrow-adapter/pkg/benchmark/profileable/arrow"
 	"github.com/f5/otel-arrow-adapter/pkg/benchmark/profileable/otlp"
+	cfg "github.com/f5/otel-arrow-adapter/pkg/config"
 )
 
 var help = flag.Bool("help", false, "Show help")
@@ -38,6 +41,35 @@ func main() {
 	// dataset. This flag is disabled by default.
 	stats := flag.Bool("stats", false, "stats mode")
 
+	// The -proto flag profiles an additional OTel Arrow benchmark decoding the
+	// Arrow records directly into OTLP protobuf bytes (i.e. without pdata).
+	protoOutput := flag.Bool("proto", false, "proto output mode")
+
+	// The -results flag writes the results of the benchmark to a JSON file (or
+	// a CSV file with the .csv extension) to compare them with the results of
+	// another run (see the benchmark_compare tool).
+	resultsFile := flag.String("results", "", "results file (.json or .csv)")
+
+	// The -compression flag sets the compression algorithms of the serialized
+	// batches (comma separated), the OTLP and OTel Arrow benchmarks are
+	// profiled with each of them.
+	compressionNames := flag.String("compression", "zstd", "compression algorithms (comma separated): "+strings.Join(benchmark.CompressionAlgorithmNames, ", "))
+
+	// The -ipc_compression flag sets the Arrow IPC compression codec of the
+	// OTel Arrow benchmarks (none, zstd or lz4_frame).
+	ipcCompression := flag.String("ipc_compression", string(cfg.IPCCompressionNone), "Arrow IPC compression codec")
+
+	// The -compression_dictionary flag profiles an additional OTel Arrow
+	// benchmark compressing the Arrow payloads with a zstd dictionary trained
+	// from the given number of payloads (0 disables it). Small batch sizes,
+	// for which this mode is designed, are added to the profiled ones.
+	compressionDictionary := flag.Int("compression_dictionary", 0, "number of payloads used to train a zstd dictionary (0 disables it)")
+
+	// The -adaptive_sorting flag profiles an additional OTel Arrow benchmark
+	// selecting the sort strategy of the traces every given number of batches
+	// (0 disables it).
+	adaptiveSorting := flag.Uint64("adaptive_sorting", 0, "number of batches between two selections of the sort strategy (0 disables it)")
+
 	// Parse the flag
 	flag.Parse()
 
@@ -53,21 +85,37 @@ func main() {
 		inputFiles = append(inputFiles, "./data/otlp_traces.pb")
 	}
 
+	algorithms, err := benchmark.ParseCompressionAlgorithms(*compressionNames)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.IPCCompression(*ipcCompression).Validate(); err != nil {
+		log.Fatal(err)
+	}
+
 	conf := &benchmark.Config{
-		Compression: false,
+		Compression:    cfg.IPCCompression(*ipcCompression) != cfg.IPCCompressionNone,
+		IPCCompression: cfg.IPCCompression(*ipcCompression),
 	}
 	if *stats {
 		conf.Stats = true
 	}
 
+	results := benchmark.NewResults()
+
 	// Compare the performance for each input file
 	for i := range inputFiles {
 		// Compare the performance between the standard OTLP representation and the OTLP Arrow representation.
-		profiler := benchmark.NewProfiler([]int{128, 1024, 2048, 4096}, "output/trace_benchmark.log", 2)
+		batchSizes := []int{128, 1024, 2048, 4096}
+		if *compressionDictionary > 0 {
+			batchSizes = append([]int{10, 50}, batchSizes...)
+		}
+		profiler := benchmark.NewProfiler(batchSizes, "output/trace_benchmark.log", 2)
 		//profiler := benchmark.NewProfiler([]int{5000}, "output/trace_benchmark.log", 2)
 		// profiler := benchmark.NewProfiler([]int{10 /*100, 1000, 2000, 5000,*/, 10000}, "output/trace_benchmark.log", 2)
 		//profiler := benchmark.NewProfiler([]int{1000}, "output/trace_benchmark.log", 2)
-		compressionAlgo := benchmark.Zstd()
+		compressionAlgo := algorithms[0]()
+		conf.CompressionAlgorithm = algorithms[0]()
 		maxIter := uint64(1)
 		ds := dataset.NewRealTraceDataset(inputFiles[i], []string{"trace_id"})
 		//ds.Resize(5000)
@@ -88,6 +136,57 @@ func main() {
 			panic(fmt.Errorf("expected no error, got %v", err))
 		}
 
+		// Profile the other compression algorithms.
+		for _, newAlgorithm := range algorithms[1:] {
+			otlpTraces := otlp.NewTraceProfileable(ds, newAlgorithm())
+			algoConf := *conf
+			algoConf.CompressionAlgorithm = newAlgorithm()
+			otlpArrowTraces := arrow.NewTraceProfileable([]string{"stream mode"}, ds, &algoConf)
+			if err := profiler.Profile(otlpTraces, maxIter); err != nil {
+				panic(fmt.Errorf("expected no error, got %v", err))
+			}
+			if err := profiler.Profile(otlpArrowTraces, maxIter); err != nil {
+				panic(fmt.Errorf("expected no error, got %v", err))
+			}
+			benchmark.OtlpArrowConversionSection.CustomColumnFor(otlpTraces).
+				MetricNotApplicable()
+		}
+
+		// If the compression dictionary mode is enabled, run the OTel Arrow
+		// benchmark with the payloads compressed with a zstd dictionary (the
+		// serialized batches are not compressed again).
+		if *compressionDictionary > 0 {
+			dictConf := *conf
+			dictConf.CompressionDictionarySamples = *compressionDictionary
+			dictConf.CompressionAlgorithm = benchmark.NoCompression()
+			otlpArrowTraces := arrow.NewTraceProfileable([]string{"stream mode", "zstd dictionary"}, ds, &dictConf)
+			if err := profiler.Profile(otlpArrowTraces, maxIter); err != nil {
+				panic(fmt.Errorf("expected no error, got %v", err))
+			}
+		}
+
+		// If the adaptive sorting is enabled, run the OTel Arrow benchmark
+		// with the sort strategy selected from trial encodings.
+		if *adaptiveSorting > 0 {
+			sortConf := *conf
+			sortConf.AdaptiveSortingPeriod = *adaptiveSorting
+			otlpArrowTraces := arrow.NewTraceProfileable([]string{"stream mode", "adaptive sorting"}, ds, &sortConf)
+			if err := profiler.Profile(otlpArrowTraces, maxIter); err != nil {
+				panic(fmt.Errorf("expected no error, got %v", err))
+			}
+		}
+
+		// If the proto output mode is enabled,
+		// run the OTLP Arrow benchmark with a direct decoding to protobuf.
+		if *protoOutput {
+			protoConf := *conf
+			protoConf.ProtoOutput = true
+			otlpArrowTraces := arrow.NewTraceProfileable([]string{"stream mode", "proto output"}, ds, &protoConf)
+			if err := profiler.Profile(otlpArrowTraces, maxIter); err != nil {
+				panic(fmt.Errorf("expected no error, got %v", err))
+			}
+		}
+
 		// If the unary RPC mode is enabled,
 		// run the OTLP Arrow benchmark in unary RPC mode.
 		if *unaryRpcPtr {
@@ -115,10 +214,17 @@ func main() {
 		profiler.Printf("- size: %s\n", humanize.Bytes(uint64(ds.SizeInBytes())))
 
 		profiler.PrintResults(maxIter)
+		results.Add(inputFiles[i], profiler, maxIter)
 
 		profiler.ExportMetricsTimesCSV(fmt.Sprintf("%d_traces_benchmark_results", i))
 		profiler.ExportMetricsBytesCSV(fmt.Sprintf("%d_traces_benchmark_results", i))
 
 		ds.ShowStats()
 	}
+
+	if *resultsFile != "" {
+		if err := results.WriteFile(*resultsFile); err != nil {
+			log.Fatal("write results: ", err)
+		}
+	}
 }
@@ -21,22 +21,26 @@ import (
 	"math"
 	"math/big"
 	"os"
-	"path"
 
+	"github.com/f5/otel-arrow-adapter/pkg/benchmark/dataset"
 	"github.com/f5/otel-arrow-adapter/pkg/datagen"
 
-	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 )
 
 var help = flag.Bool("help", false, "Show help")
 var outputFile = "./data/otlp_traces.pb"
 var batchSize = 50000
+var profileFile = ""
+var seed int64
 
 // This tool generates a trace dataset in the OpenTelemetry Protocol format from a fake traces generator.
 func main() {
 	// Define the flags.
-	flag.StringVar(&outputFile, "output", outputFile, "Output file")
+	flag.StringVar(&outputFile, "output", outputFile, "Output file (.pb, .json or .arrow, optionally followed by .gz or .zst)")
 	flag.IntVar(&batchSize, "batchsize", batchSize, "Batch size")
+	flag.StringVar(&profileFile, "profile", profileFile, "Workload profile (.yaml or .json, see tools/profiles)")
+	flag.Int64Var(&seed, "seed", seed, "Seed of the generator, the same seed generates the same data (random if 0)")
 
 	// Parse the flag
 	flag.Parse()
@@ -48,29 +52,35 @@ func main() {
 	}
 
 	// Generate the dataset.
-	v, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
-	if err != nil {
-		log.Fatalf("Failed to generate random number - %v", err)
-	}
-	entropy := datagen.NewTestEntropy(v.Int64())
-	generator := datagen.NewTracesGenerator(entropy, entropy.NewStandardResourceAttributes(), entropy.NewStandardInstrumentationScopes())
-	request := ptraceotlp.NewExportRequestFromTraces(generator.Generate(batchSize, 100))
-
-	// Marshal the request to bytes.
-	msg, err := request.MarshalProto()
-	if err != nil {
-		log.Fatal("marshaling error: ", err)
+	if seed == 0 {
+		v, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+		if err != nil {
+			log.Fatalf("Failed to generate random number - %v", err)
+		}
+		seed = v.Int64()
 	}
-
-	// Write protobuf to file
-	if _, err := os.Stat(outputFile); os.IsNotExist(err) {
-		err = os.MkdirAll(path.Dir(outputFile), 0700)
+	entropy := datagen.NewTestEntropy(seed)
+	var traces ptrace.Traces
+	if profileFile != "" {
+		profile, err := datagen.LoadProfile(profileFile)
 		if err != nil {
-			log.Fatal("error creating directory: ", err)
+			log.Fatal(err)
+		}
+		generator := datagen.NewProfileGenerator(entropy, profile)
+		if profile.Traces.ServiceGraph != nil {
+			// The batch size is a number of spans emitted when they end.
+			traces = generator.GenerateSpans(batchSize)
+		} else {
+			traces = generator.GenerateTraces(batchSize)
 		}
+	} else {
+		generator := datagen.NewTracesGenerator(entropy, entropy.NewStandardResourceAttributes(), entropy.NewStandardInstrumentationScopes())
+		traces = generator.Generate(batchSize, 100)
 	}
-	err = os.WriteFile(outputFile, msg, 0600)
-	if err != nil {
+
+	// Write the dataset, the format and the compression are given by the
+	// extensions of the output file.
+	if err := dataset.WriteTraces(outputFile, []ptrace.Traces{traces}); err != nil {
 		log.Fatal("write error: ", err)
 	}
 }